@@ -0,0 +1,184 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/admission/v1"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+var (
+	activityWebhookName       = "activity-webhook"
+	activityWebhookConfigName = "activity.auto-pause.minikube.sigs.k8s.io"
+	activityPath              = "/activity"
+)
+
+// activityURL is the url of the auto-pause daemon that user activity is reported to
+var activityURL *string
+
+// systemNamespaces are the namespaces of the cluster itself, requests in them are not user activity
+var systemNamespaces = []string{"kube-system", "kube-node-lease", "kube-public", "auto-pause"}
+
+// systemResources change all the time without any user activity
+var systemResources = map[string]bool{"leases": true, "events": true, "endpoints": true, "endpointslices": true, "tokenreviews": true, "subjectaccessreviews": true}
+
+// activityHandler reports the user requests to the auto-pause daemon, and always admits them
+func activityHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("error: %v", err)
+		return
+	}
+
+	admReq := v1.AdmissionReview{}
+	admResp := v1.AdmissionReview{}
+	if _, _, err := deserializer.Decode(body, nil, &admReq); err != nil || admReq.Request == nil {
+		log.Printf("Could not decode body: %v", err)
+		admResp.Response = &v1.AdmissionResponse{Allowed: true}
+	} else {
+		if userActivity(admReq.Request) {
+			go reportActivity()
+		}
+		admResp.Response = &v1.AdmissionResponse{Allowed: true, UID: admReq.Request.UID}
+	}
+
+	admResp.APIVersion = "admission.k8s.io/v1"
+	admResp.Kind = "AdmissionReview"
+	resp, err := json.Marshal(admResp)
+	if err != nil {
+		log.Printf("error marshalling decision: %v", err)
+	}
+	if _, err := w.Write(resp); err != nil {
+		log.Printf("error writing response %v", err)
+	}
+}
+
+// userActivity returns whether a request is made by a user, and not by the controllers and nodes of the cluster
+func userActivity(req *v1.AdmissionRequest) bool {
+	if req.DryRun != nil && *req.DryRun {
+		return false
+	}
+	for _, ns := range systemNamespaces {
+		if req.Namespace == ns {
+			return false
+		}
+	}
+	if systemResources[req.Resource.Resource] {
+		return false
+	}
+
+	user := req.UserInfo.Username
+	switch {
+	case strings.HasPrefix(user, "system:serviceaccount:"):
+		// service accounts of the workloads of users are activity
+		ns := strings.SplitN(strings.TrimPrefix(user, "system:serviceaccount:"), ":", 2)[0]
+		for _, s := range systemNamespaces {
+			if ns == s {
+				return false
+			}
+		}
+		return true
+	case strings.HasPrefix(user, "system:"):
+		// nodes, controllers, scheduler and apiserver
+		return false
+	}
+	return true
+}
+
+// reportActivity posts to the auto-pause daemon, failures only delay the pause
+func reportActivity() {
+	c := http.Client{Timeout: 5 * time.Second}
+	resp, err := c.Post(*activityURL, "text/plain", nil)
+	if err != nil {
+		log.Printf("failed to report activity: %v", err)
+		return
+	}
+	resp.Body.Close()
+}
+
+// registerActivityWebhook registers the webhook that is called for the changes made by users,
+// the reads and watches of controllers do not reach it
+func registerActivityWebhook(clientset *kubernetes.Clientset, caCert []byte) {
+	client := clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations()
+	_, err := client.Get(context.TODO(), activityWebhookName, metav1.GetOptions{})
+	if err == nil {
+		if err2 := client.Delete(context.TODO(), activityWebhookName, metav1.DeleteOptions{}); err2 != nil {
+			klog.Fatal(err2)
+		}
+	}
+
+	// the activity is only a hint, the requests of users must not fail with the webhook
+	failurePolicy := admissionv1.Ignore
+	sideEffects := admissionv1.SideEffectClassNoneOnDryRun
+	timeout := int32(2)
+	webhookConfig := &admissionv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: activityWebhookName,
+		},
+		Webhooks: []admissionv1.ValidatingWebhook{
+			{
+				Name: activityWebhookConfigName,
+				Rules: []admissionv1.RuleWithOperations{
+					{
+						Operations: []admissionv1.OperationType{admissionv1.Create, admissionv1.Update, admissionv1.Delete, admissionv1.Connect},
+						Rule: admissionv1.Rule{
+							APIGroups:   []string{"*"},
+							APIVersions: []string{"*"},
+							Resources:   []string{"*/*"},
+						},
+					},
+				},
+				FailurePolicy: &failurePolicy,
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{
+							Key:      "kubernetes.io/metadata.name",
+							Operator: metav1.LabelSelectorOpNotIn,
+							Values:   systemNamespaces,
+						},
+					},
+				},
+				ClientConfig: admissionv1.WebhookClientConfig{
+					Service: &admissionv1.ServiceReference{
+						Namespace: "auto-pause",
+						Name:      "webhook",
+						Path:      &activityPath,
+					},
+					CABundle: caCert,
+				},
+				AdmissionReviewVersions: []string{"v1"},
+				SideEffects:             &sideEffects,
+				TimeoutSeconds:          &timeout,
+			},
+		},
+	}
+	if _, err := client.Create(context.TODO(), webhookConfig, metav1.CreateOptions{}); err != nil {
+		klog.Fatalf("Activity webhook creation failed with %s", err)
+	}
+	log.Println("ACTIVITY WEBHOOK CREATED")
+}
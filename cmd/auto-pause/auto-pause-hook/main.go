@@ -156,8 +156,10 @@ func addEnv(target, envVars []corev1.EnvVar, basePath string) (patch []jsonpatch
 func main() {
 	addr := flag.String("addr", ":8080", "address to serve on")
 	targetIP = flag.String("targetIP", "192.168.49.2", "The reverse proxy IP")
+	activityURL = flag.String("activityURL", "http://192.168.49.2:8080/activity", "The url of the auto-pause daemon that user activity is reported to")
 
 	http.HandleFunc("/", handler)
+	http.HandleFunc(activityPath, activityHandler)
 
 	flag.Parse()
 
@@ -169,6 +171,7 @@ func main() {
 		TLSConfig: configTLS(clientset, serverCert, serverKey),
 	}
 	go selfRegistration(clientset, cacert)
+	go registerActivityWebhook(clientset, cacert)
 	err := server.ListenAndServeTLS("", "")
 	if err != nil {
 		klog.Fatalf("Start https server failed with %s", err)
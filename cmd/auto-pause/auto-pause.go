@@ -17,129 +17,286 @@ limitations under the License.
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/cruntime"
-	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/out"
-	"k8s.io/minikube/pkg/minikube/reason"
 	"k8s.io/minikube/pkg/minikube/style"
 )
 
-var unpauseRequests = make(chan struct{})
-var done = make(chan struct{})
-var mu sync.Mutex
-
-var runtimePaused bool
-var version = "0.0.1"
+var version = "0.0.2"
 
 var runtime = flag.String("container-runtime", "docker", "Container runtime to use for (un)pausing")
+var interval = flag.Duration("interval", constants.AutoPauseInterval, "Duration of inactivity before the cluster is paused")
+var listenAddress = flag.String("listen-address", constants.AutoPauseListenAddress, "Address to listen on for unpause and status requests")
+var namespaces = flag.String("namespaces", "kube-system", "Comma separated list of namespaces to pause")
+var internalCIDRs = flag.String("internal-cidrs", "10.244.0.0/16", "Comma separated CIDRs of the clients inside the cluster, whose connections do not count as activity")
+
+// maxCheckPeriod is the longest time between two checks for inactivity
+const maxCheckPeriod = 10 * time.Second
+
+// autoPause tracks the activity of the apiserver, and pauses the cluster once it is idle
+type autoPause struct {
+	mu sync.Mutex
+
+	interval   time.Duration
+	namespaces []string
+
+	// internal reports whether a client address is inside the cluster: the connections of
+	// controllers and probes unpause the cluster, but do not keep it from pausing
+	internal func(ip net.IP) bool
+
+	// pause, unpause and paused act on the container runtime, they are replaced in tests
+	pause   func(namespaces []string) (int, error)
+	unpause func() (int, error)
+	paused  func(namespaces []string) (bool, error)
+
+	runtimePaused bool
+	lastActivity  time.Time
+	requests      int64
+	failures      int
+	lastError     error
+}
 
 func main() {
 	flag.Parse()
 
-	// TODO: #10595 make this configurable
-	const interval = time.Minute * 1
-
-	// Check current state
-	alreadyPaused()
+	ap := &autoPause{
+		interval:     *interval,
+		namespaces:   strings.Split(*namespaces, ","),
+		internal:     internalClients(strings.Split(*internalCIDRs, ",")),
+		pause:        runPause,
+		unpause:      runUnpause,
+		paused:       checkPaused,
+		lastActivity: time.Now(),
+	}
+	ap.init()
 
-	// channel for incoming messages
+	// a single ticker checks for inactivity, instead of a timer for each request
+	period := ap.interval
+	if period > maxCheckPeriod {
+		period = maxCheckPeriod
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
 	go func() {
-		for {
-			// On each iteration new timer is created
-			select {
-			// TODO: #10596 make it memory-leak proof
-			case <-time.After(interval):
-				runPause()
-			case <-unpauseRequests:
-				fmt.Printf("Got request\n")
-				if runtimePaused {
-					runUnpause()
-				}
-
-				done <- struct{}{}
-			}
+		for now := range ticker.C {
+			ap.checkIdle(now)
 		}
 	}()
 
-	http.HandleFunc("/", handler) // each request calls handler
-	fmt.Printf("Starting auto-pause server %s at port 8080 \n", version)
-	log.Fatal(http.ListenAndServe("0.0.0.0:8080", nil))
+	http.HandleFunc("/status", ap.statusHandler)
+	http.HandleFunc("/activity", ap.activityHandler) // requests of users inside the cluster, reported by the auto-pause-hook
+	http.HandleFunc("/", ap.handler)                 // each connection proxied to the apiserver calls handler
+	fmt.Printf("Starting auto-pause server %s at %s (interval %s, namespaces %s)\n", version, *listenAddress, ap.interval, ap.namespaces)
+	log.Fatal(http.ListenAndServe(*listenAddress, nil))
 }
 
-// handler echoes the Path component of the requested URL.
-func handler(w http.ResponseWriter, r *http.Request) {
-	unpauseRequests <- struct{}{}
-	<-done
+// handler unpauses the cluster if needed for a connection to the apiserver, the path is the address of the client.
+// Only the connections of clients outside the cluster are activity.
+func (ap *autoPause) handler(w http.ResponseWriter, r *http.Request) {
+	ip := net.ParseIP(strings.TrimPrefix(r.URL.Path, "/"))
+	external := ip == nil || !ap.internal(ip)
+	if err := ap.connection(time.Now(), external); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
 	fmt.Fprintf(w, "allow")
 }
 
-func runPause() {
-	mu.Lock()
-	defer mu.Unlock()
-	if runtimePaused {
+// activityHandler records a request of a user inside the cluster
+func (ap *autoPause) activityHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-
-	r := command.NewExecRunner(true)
-
-	cr, err := cruntime.New(cruntime.Config{Type: *runtime, Runner: r})
-	if err != nil {
-		exit.Error(reason.InternalNewRuntime, "Failed runtime", err)
+	if err := ap.connection(time.Now(), true); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
 	}
+	w.WriteHeader(http.StatusNoContent)
+}
 
-	uids, err := cluster.Pause(cr, r, []string{"kube-system"})
+// statusHandler writes the auto-pause status as json
+func (ap *autoPause) statusHandler(w http.ResponseWriter, r *http.Request) {
+	b, err := json.Marshal(ap.status())
 	if err != nil {
-		exit.Error(reason.GuestPause, "Pause", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(b); err != nil {
+		log.Printf("failed to write status: %v", err)
 	}
+}
 
-	runtimePaused = true
+// init checks whether the cluster is already paused
+func (ap *autoPause) init() {
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
 
-	out.Step(style.Unpause, "Paused {{.count}} containers", out.V{"count": len(uids)})
+	paused, err := ap.paused(ap.namespaces)
+	if err != nil {
+		ap.fail(errors.Wrap(err, "check paused"))
+		return
+	}
+	ap.runtimePaused = paused
+	out.Step(style.Check, "containers paused status: {{.paused}}", out.V{"paused": ap.runtimePaused})
 }
 
-func runUnpause() {
-	fmt.Println("unpausing...")
-	mu.Lock()
-	defer mu.Unlock()
+// connection records a connection to the apiserver at the given time, unpausing the cluster if it is paused.
+// Only activity postpones the next pause, the connections of controllers and probes inside the cluster do not.
+func (ap *autoPause) connection(now time.Time, activity bool) error {
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
 
-	r := command.NewExecRunner(true)
+	ap.requests++
+	if activity {
+		ap.lastActivity = now
+	}
+	if !ap.runtimePaused {
+		return nil
+	}
 
-	cr, err := cruntime.New(cruntime.Config{Type: *runtime, Runner: r})
+	n, err := ap.unpause()
 	if err != nil {
-		exit.Error(reason.InternalNewRuntime, "Failed runtime", err)
+		ap.fail(errors.Wrap(err, "unpause"))
+		return ap.lastError
 	}
+	ap.runtimePaused = false
+	ap.failures = 0
+	// the cluster stays unpaused for an interval, even if it was woken up from inside
+	ap.lastActivity = now
+	out.Step(style.Unpause, "Unpaused {{.count}} containers", out.V{"count": n})
+	return nil
+}
 
-	uids, err := cluster.Unpause(cr, r, nil)
+// checkIdle pauses the cluster if there was no apiserver activity for the interval.
+// Failures are retried on the next check, as the cluster stays idle.
+func (ap *autoPause) checkIdle(now time.Time) {
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
+
+	if ap.runtimePaused || now.Sub(ap.lastActivity) < ap.interval {
+		return
+	}
+
+	n, err := ap.pause(ap.namespaces)
 	if err != nil {
-		exit.Error(reason.GuestUnpause, "Unpause", err)
+		ap.fail(errors.Wrap(err, "pause"))
+		return
 	}
-	runtimePaused = false
+	ap.runtimePaused = true
+	ap.failures = 0
+	out.Step(style.Pause, "Paused {{.count}} containers after {{.idle}} of inactivity", out.V{"count": n, "idle": now.Sub(ap.lastActivity).Round(time.Second)})
+}
+
+// fail records an error, which is reported by the status endpoint
+func (ap *autoPause) fail(err error) {
+	ap.failures++
+	ap.lastError = err
+	log.Printf("auto-pause failure %d: %v", ap.failures, err)
+}
 
-	out.Step(style.Unpause, "Unpaused {{.count}} containers", out.V{"count": len(uids)})
+// status returns the current auto-pause status
+func (ap *autoPause) status() cluster.AutoPauseStatus {
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
+
+	st := cluster.AutoPauseStatus{
+		Paused:       ap.runtimePaused,
+		Interval:     ap.interval.String(),
+		Namespaces:   ap.namespaces,
+		LastActivity: ap.lastActivity,
+		Requests:     ap.requests,
+		Failures:     ap.failures,
+	}
+	if ap.lastError != nil {
+		st.LastError = ap.lastError.Error()
+	}
+	return st
 }
 
-func alreadyPaused() {
-	mu.Lock()
-	defer mu.Unlock()
+// internalClients returns whether an address is in one of the cidrs or is an address of this node
+func internalClients(cidrs []string) func(net.IP) bool {
+	var nets []*net.IPNet
+	for _, c := range cidrs {
+		_, n, err := net.ParseCIDR(strings.TrimSpace(c))
+		if err != nil {
+			log.Printf("ignoring invalid internal cidr %q: %v", c, err)
+			continue
+		}
+		nets = append(nets, n)
+	}
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, a := range addrs {
+			n, ok := a.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			ip := n.IP
+			if ip4 := ip.To4(); ip4 != nil {
+				ip = ip4
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+		}
+	} else {
+		log.Printf("failed to list the addresses of the node: %v", err)
+	}
+
+	return func(ip net.IP) bool {
+		for _, n := range nets {
+			if n.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+}
 
+func newRuntime() (cruntime.Manager, command.Runner, error) {
 	r := command.NewExecRunner(true)
 	cr, err := cruntime.New(cruntime.Config{Type: *runtime, Runner: r})
 	if err != nil {
-		exit.Error(reason.InternalNewRuntime, "Failed runtime", err)
+		return nil, nil, errors.Wrap(err, "runtime")
+	}
+	return cr, r, nil
+}
+
+func runPause(namespaces []string) (int, error) {
+	cr, r, err := newRuntime()
+	if err != nil {
+		return 0, err
+	}
+	uids, err := cluster.Pause(cr, r, namespaces)
+	return len(uids), err
+}
+
+func runUnpause() (int, error) {
+	cr, r, err := newRuntime()
+	if err != nil {
+		return 0, err
 	}
+	uids, err := cluster.Unpause(cr, r, nil)
+	return len(uids), err
+}
 
-	runtimePaused, err = cluster.CheckIfPaused(cr, []string{"kube-system"})
+func checkPaused(namespaces []string) (bool, error) {
+	cr, _, err := newRuntime()
 	if err != nil {
-		exit.Error(reason.GuestCheckPaused, "Fail check if container paused", err)
+		return false, err
 	}
-	out.Step(style.Check, "containers paused status: {{.paused}}", out.V{"paused": runtimePaused})
+	return cluster.CheckIfPaused(cr, namespaces)
 }
@@ -0,0 +1,191 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeRuntime counts the pause and unpause calls, failing the first failures calls
+type fakeRuntime struct {
+	pauses   int
+	unpauses int
+	failures int
+}
+
+func (f *fakeRuntime) pause([]string) (int, error) {
+	f.pauses++
+	if f.failures > 0 {
+		f.failures--
+		return 0, fmt.Errorf("pause failed")
+	}
+	return 1, nil
+}
+
+func (f *fakeRuntime) unpause() (int, error) {
+	f.unpauses++
+	if f.failures > 0 {
+		f.failures--
+		return 0, fmt.Errorf("unpause failed")
+	}
+	return 1, nil
+}
+
+func newFakeAutoPause(f *fakeRuntime, start time.Time) *autoPause {
+	return &autoPause{
+		interval:     time.Minute,
+		namespaces:   []string{"kube-system"},
+		pause:        f.pause,
+		unpause:      f.unpause,
+		paused:       func([]string) (bool, error) { return false, nil },
+		internal:     func(net.IP) bool { return false },
+		lastActivity: start,
+	}
+}
+
+func TestAutoPauseIdle(t *testing.T) {
+	start := time.Now()
+	f := &fakeRuntime{}
+	ap := newFakeAutoPause(f, start)
+
+	ap.checkIdle(start.Add(30 * time.Second))
+	if f.pauses != 0 {
+		t.Fatalf("paused before the interval elapsed")
+	}
+
+	// activity postpones the pause
+	if err := ap.connection(start.Add(45*time.Second), true); err != nil {
+		t.Fatalf("connection: %v", err)
+	}
+	ap.checkIdle(start.Add(90 * time.Second))
+	if f.pauses != 0 {
+		t.Fatalf("paused less than an interval after the last activity")
+	}
+
+	ap.checkIdle(start.Add(105 * time.Second))
+	if f.pauses != 1 || !ap.status().Paused {
+		t.Fatalf("expected the cluster to be paused once, pauses = %d, status = %+v", f.pauses, ap.status())
+	}
+
+	// already paused: no more pauses
+	ap.checkIdle(start.Add(200 * time.Second))
+	if f.pauses != 1 {
+		t.Fatalf("paused an already paused cluster, pauses = %d", f.pauses)
+	}
+
+	if err := ap.connection(start.Add(210*time.Second), true); err != nil {
+		t.Fatalf("connection: %v", err)
+	}
+	if f.unpauses != 1 || ap.status().Paused {
+		t.Fatalf("expected the cluster to be unpaused once, unpauses = %d, status = %+v", f.unpauses, ap.status())
+	}
+	if got := ap.status().Requests; got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+func TestAutoPauseRetry(t *testing.T) {
+	start := time.Now()
+	f := &fakeRuntime{failures: 2}
+	ap := newFakeAutoPause(f, start)
+
+	ap.checkIdle(start.Add(time.Minute))
+	st := ap.status()
+	if st.Paused || st.Failures != 1 || st.LastError == "" {
+		t.Fatalf("expected a reported pause failure, got status %+v", st)
+	}
+
+	ap.checkIdle(start.Add(2 * time.Minute))
+	ap.checkIdle(start.Add(3 * time.Minute))
+	st = ap.status()
+	if !st.Paused || st.Failures != 0 || f.pauses != 3 {
+		t.Fatalf("expected the pause to be retried until it succeeds, pauses = %d, status = %+v", f.pauses, st)
+	}
+
+	f.failures = 1
+	if err := ap.connection(start.Add(4*time.Minute), true); err == nil {
+		t.Fatalf("expected the unpause failure to be returned")
+	}
+	if !ap.status().Paused {
+		t.Fatalf("expected the cluster to still be paused after a failed unpause")
+	}
+	if err := ap.connection(start.Add(5*time.Minute), true); err != nil {
+		t.Fatalf("connection: %v", err)
+	}
+	if ap.status().Paused {
+		t.Fatalf("expected the unpause to be retried on the next request")
+	}
+}
+
+func TestAutoPauseInternalConnections(t *testing.T) {
+	start := time.Now()
+	f := &fakeRuntime{}
+	ap := newFakeAutoPause(f, start)
+	ap.internal = internalClients([]string{"10.244.0.0/16"})
+
+	// connections of controllers inside the cluster do not postpone the pause
+	if err := ap.connection(start.Add(45*time.Second), false); err != nil {
+		t.Fatalf("connection: %v", err)
+	}
+	ap.checkIdle(start.Add(time.Minute))
+	if f.pauses != 1 {
+		t.Fatalf("expected internal connections not to postpone the pause, pauses = %d", f.pauses)
+	}
+
+	// but they unpause the cluster, which then stays unpaused for an interval
+	if err := ap.connection(start.Add(70*time.Second), false); err != nil {
+		t.Fatalf("connection: %v", err)
+	}
+	ap.checkIdle(start.Add(100 * time.Second))
+	if f.unpauses != 1 || f.pauses != 1 {
+		t.Fatalf("expected the cluster to be unpaused for an interval, pauses = %d, unpauses = %d", f.pauses, f.unpauses)
+	}
+
+	tests := []struct {
+		path     string
+		activity bool
+	}{
+		{"/10.244.1.5", false},
+		{"/127.0.0.1", false},
+		{"/192.168.49.1", true},
+		{"/unknown", true},
+	}
+	for _, tc := range tests {
+		ap.lastActivity = start
+		now := time.Now()
+		rec := httptest.NewRecorder()
+		ap.handler(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if rec.Body.String() != "allow" {
+			t.Errorf("%s: body = %q, want allow", tc.path, rec.Body.String())
+		}
+		if got := !ap.lastActivity.Before(now); got != tc.activity {
+			t.Errorf("%s: activity = %v, want %v", tc.path, got, tc.activity)
+		}
+	}
+
+	ap.lastActivity = start
+	rec := httptest.NewRecorder()
+	ap.activityHandler(rec, httptest.NewRequest(http.MethodPost, "/activity", nil))
+	if rec.Code != http.StatusNoContent || ap.lastActivity.Equal(start) {
+		t.Errorf("activity report: code = %d, last activity = %v", rec.Code, ap.lastActivity)
+	}
+}
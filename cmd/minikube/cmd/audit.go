@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/audit"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+)
+
+var (
+	auditCommand string
+	auditVersion string
+	auditSince   string
+	auditUntil   string
+	auditOutput  string
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Shows the commands recorded in the audit log",
+	Long: `Shows the minikube commands recorded in the audit log, along with their duration and exit status.
+Use --profile and --user to only show the commands run against a profile or by a user.`,
+	Example: `minikube audit --profile minikube --since 24h
+minikube audit --command start --output json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		f, err := auditFilter(cmd, time.Now())
+		if err != nil {
+			exit.Message(reason.Usage, err.Error())
+		}
+
+		entries, err := audit.Query(f)
+		if err != nil {
+			exit.Error(reason.HostAudit, "Unable to read the audit log", err)
+		}
+
+		switch strings.ToLower(auditOutput) {
+		case "table":
+			out.String("%s", audit.EntriesToASCIITable(entries))
+		case "json":
+			b, err := json.Marshal(entries)
+			if err != nil {
+				exit.Error(reason.InternalJSONMarshal, "Unable to marshal the audit log to JSON", err)
+			}
+			out.String("%s\n", string(b))
+		case "cloudevents":
+			if len(entries) > 0 {
+				out.String("%s\n", audit.EntriesToCloudEvents(entries))
+			}
+		default:
+			exit.Message(reason.Usage, fmt.Sprintf("invalid output format: %s. Valid values: 'table', 'json', 'cloudevents'", auditOutput))
+		}
+	},
+}
+
+// auditFilter returns the audit log filter from the command flags.
+// The global --profile and --user flags only filter the log when they are set explicitly.
+func auditFilter(cmd *cobra.Command, now time.Time) (audit.Filter, error) {
+	f := audit.Filter{
+		Command: auditCommand,
+		Version: auditVersion,
+	}
+	if cmd.Flags().Changed(config.ProfileName) {
+		f.Profile = viper.GetString(config.ProfileName)
+	}
+	if cmd.Flags().Changed(config.UserFlag) {
+		f.User = viper.GetString(config.UserFlag)
+	}
+
+	var err error
+	if f.Since, err = parseAuditTime(auditSince, now); err != nil {
+		return f, fmt.Errorf("invalid --since value: %v", err)
+	}
+	if f.Until, err = parseAuditTime(auditUntil, now); err != nil {
+		return f, fmt.Errorf("invalid --until value: %v", err)
+	}
+	if !f.Since.IsZero() && !f.Until.IsZero() && f.Until.Before(f.Since) {
+		return f, fmt.Errorf("--until must not be before --since")
+	}
+	return f, nil
+}
+
+// parseAuditTime parses a duration relative to now (such as 2h), or an absolute time in RFC3339 or the audit log format.
+func parseAuditTime(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(constants.TimeFormat, s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is neither a duration nor a time in RFC3339 format", s)
+}
+
+func init() {
+	auditCmd.Flags().StringVar(&auditCommand, "command", "", "Only show the given minikube command, such as start")
+	auditCmd.Flags().StringVar(&auditVersion, "version", "", "Only show the commands run by the given minikube version")
+	auditCmd.Flags().StringVar(&auditSince, "since", "", "Only show the commands started after this time, either a duration such as 2h or a time in RFC3339 format")
+	auditCmd.Flags().StringVar(&auditUntil, "until", "", "Only show the commands started before this time, either a duration such as 2h or a time in RFC3339 format")
+	auditCmd.Flags().StringVarP(&auditOutput, "output", "o", "table", "Format to print the audit log in. One of: table, json, cloudevents")
+}
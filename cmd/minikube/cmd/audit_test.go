@@ -0,0 +1,47 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAuditTime(t *testing.T) {
+	now := time.Date(2021, 2, 4, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"", time.Time{}, false},
+		{"2h", now.Add(-2 * time.Hour), false},
+		{"2021-02-03T15:30:33Z", time.Date(2021, 2, 3, 15, 30, 33, 0, time.UTC), false},
+		{"Wed, 03 Feb 2021 15:30:33 UTC", time.Date(2021, 2, 3, 15, 30, 33, 0, time.UTC), false},
+		{"yesterday", time.Time{}, true},
+	}
+	for _, tc := range tests {
+		got, err := parseAuditTime(tc.value, now)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parseAuditTime(%q) error = %v; wantErr %t", tc.value, err, tc.wantErr)
+			continue
+		}
+		if !got.Equal(tc.want) {
+			t.Errorf("parseAuditTime(%q) = %v; want %v", tc.value, got, tc.want)
+		}
+	}
+}
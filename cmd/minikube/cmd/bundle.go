@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	cmdConfig "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bundle"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/detect"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/version"
+)
+
+var (
+	bundleKubernetesVersion string
+	bundleContainerRuntime  string
+	bundleDriver            string
+	bundleAddons            []string
+	bundleOutput            string
+)
+
+// bundleCmd represents the bundle command
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Create and import offline bundles, to start clusters without network access",
+	Long: `Create and import offline bundles: archives of everything "minikube start" downloads for a version of Kubernetes, a container runtime and a driver.
+A bundle is created on a machine with network access, and imported into the cache of machines with no network at all, where "minikube start" then runs offline.`,
+}
+
+// bundleCreateCmd represents the bundle create command
+var bundleCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Download everything needed to start a cluster and write it to a bundle",
+	Long: `Download everything "minikube start" needs to start a cluster with the given Kubernetes version, container runtime and driver to the cache,
+as "minikube start --download-only" would, along with the images of the given addons, and write it all to one archive.
+The driver itself, and the docker daemon of the docker driver, must be installed on the offline machine.`,
+	Example: "minikube bundle create --kubernetes-version v1.20.7 --container-runtime containerd --driver kvm2 --addons metrics-server,ingress",
+	Run: func(cmd *cobra.Command, args []string) {
+		if bundleDriver == "" {
+			exit.Message(reason.Usage, "Please specify the driver the bundle is for with --driver")
+		}
+		if !bundle.Supported(bundleDriver) {
+			exit.Message(reason.DrvUnsupportedBundle, "The {{.driver}} driver can not start clusters from a bundle", out.V{"driver": bundleDriver})
+		}
+		for _, a := range bundleAddons {
+			if _, ok := assets.Addons[a]; !ok {
+				exit.Message(reason.Usage, "The addon {{.name}} does not exist, see \"minikube addons list\"", out.V{"name": a})
+			}
+		}
+
+		o := bundle.Options{
+			KubernetesVersion: normalizeKubernetesVersion(bundleKubernetesVersion),
+			ContainerRuntime:  bundleRuntime(bundleContainerRuntime),
+			Driver:            bundleDriver,
+			Addons:            bundleAddons,
+		}
+		file := bundleOutput
+		if file == "" {
+			file = bundle.DefaultFile(o)
+		}
+
+		m, err := bundle.Cache(o)
+		if err != nil {
+			exit.Error(reason.InetBundleCreate, "Failed to download the files of the bundle", err)
+		}
+		out.Step(style.Provisioning, "Writing {{.count}} files to {{.file}} ...", out.V{"count": len(m.Files), "file": file})
+		if err := bundle.Create(m, file); err != nil {
+			exit.Error(reason.HostBundle, "Failed to write the bundle", err)
+		}
+		out.Step(style.Success, "Bundle written to {{.file}}, import it with \"minikube bundle import {{.file}}\"", out.V{"file": file})
+	},
+}
+
+// bundleImportCmd represents the bundle import command
+var bundleImportCmd = &cobra.Command{
+	Use:     "import FILE",
+	Short:   "Import a bundle into the cache, to start clusters without network access",
+	Long:    `Extract the files of a bundle created by "minikube bundle create" into the cache of the minikube home directory, and add the images of its addons to the images "minikube start" loads from the cache.`,
+	Example: "minikube bundle import minikube-v1.20.0-v1.20.7-containerd-kvm2.tar.gz",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		out.Step(style.Provisioning, "Importing {{.file}} ...", out.V{"file": args[0]})
+		m, err := bundle.Import(args[0])
+		if err != nil {
+			exit.Error(reason.HostBundle, "Failed to import the bundle", err)
+		}
+		if m.MinikubeVersion != version.GetVersion() {
+			out.WarningT("The bundle was created by minikube {{.bundle}}, its ISO or base image may not be the ones of minikube {{.version}}", out.V{"bundle": m.MinikubeVersion, "version": version.GetVersion()})
+		}
+		if m.Arch != "" && m.Arch != detect.EffectiveArch() {
+			out.WarningT("The bundle was created for {{.bundle}}, not {{.arch}}", out.V{"bundle": m.Arch, "arch": detect.EffectiveArch()})
+		}
+		if len(m.Images) > 0 {
+			if err := cmdConfig.AddToConfigMap(cacheImageConfigKey, m.Images); err != nil {
+				exit.Error(reason.InternalAddConfig, "Failed to update config", err)
+			}
+		}
+
+		out.Step(style.Success, "Imported {{.count}} files, start a cluster offline with:", out.V{"count": len(m.Files)})
+		out.Styled(style.Command, "minikube start {{.flags}}", out.V{"flags": bundleStartFlags(m)})
+	},
+}
+
+// bundleRuntime validates a container runtime, with the spelling of the cluster config
+func bundleRuntime(runtime string) string {
+	runtime = strings.ToLower(runtime)
+	if runtime == "cri-o" {
+		return constants.CRIO
+	}
+	for _, r := range cruntime.ValidRuntimes() {
+		if runtime == r || runtime == constants.CRIO {
+			return runtime
+		}
+	}
+	exit.Message(reason.Usage, `Invalid Container Runtime: "{{.runtime}}". Valid runtimes are: {{.validOptions}}`, out.V{"runtime": runtime, "validOptions": strings.Join(cruntime.ValidRuntimes(), ", ")})
+	return ""
+}
+
+// bundleStartFlags returns the flags of "minikube start" to start a cluster from a bundle
+func bundleStartFlags(m *bundle.Manifest) string {
+	flags := fmt.Sprintf("--driver=%s --kubernetes-version=%s --container-runtime=%s", m.Driver, m.KubernetesVersion, m.ContainerRuntime)
+	if len(m.Addons) > 0 {
+		flags += " --addons=" + strings.Join(m.Addons, ",")
+	}
+	return flags
+}
+
+func init() {
+	bundleCreateCmd.Flags().StringVar(&bundleKubernetesVersion, "kubernetes-version", "", fmt.Sprintf("The Kubernetes version of the bundle (ex: v1.2.3, 'stable' for %s, 'latest' for %s). Defaults to 'stable'.", constants.DefaultKubernetesVersion, constants.NewestKubernetesVersion))
+	bundleCreateCmd.Flags().StringVar(&bundleContainerRuntime, "container-runtime", constants.DefaultContainerRuntime, "The container runtime of the bundle. Valid options: docker, cri-o, containerd")
+	bundleCreateCmd.Flags().StringVar(&bundleDriver, "driver", "", "The driver of the bundle: docker, or a VM driver")
+	bundleCreateCmd.Flags().StringSliceVar(&bundleAddons, "addons", nil, "Addons to include the images of, comma separated")
+	bundleCreateCmd.Flags().StringVarP(&bundleOutput, "output", "o", "", "The file to write the bundle to. Defaults to minikube-<minikube version>-<kubernetes version>-<runtime>-<driver>.tar.gz")
+
+	bundleCmd.AddCommand(bundleCreateCmd)
+	bundleCmd.AddCommand(bundleImportCmd)
+}
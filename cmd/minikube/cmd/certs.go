@@ -0,0 +1,217 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/klog/v2"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var (
+	rotateCA     bool
+	rotateCACert string
+	rotateCAKey  string
+)
+
+// certsCmd represents the certs command
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "List and rotate the certificates of the cluster",
+	Long: `List and rotate the certificates of the cluster: the ones minikube signs with its CAs, kept in the minikube home,
+and the ones kubeadm manages on the control-plane nodes.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube certs [list|rotate]")
+	},
+}
+
+// certsListCmd represents the certs list command
+var certsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the certificates of the cluster with their SANs and expiry",
+	Long: `List the certificates of the cluster with their SANs and expiry.
+The certificates kubeadm manages are read from the running control-plane nodes.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		api, cc := mustload.Partial(ClusterFlagValue())
+		defer api.Close()
+
+		certs, err := bootstrapper.LocalCerts(cc.Name)
+		if err != nil {
+			exit.Error(reason.GuestCert, "Failed to read the certificates", err)
+		}
+		for _, n := range cc.Nodes {
+			if !n.ControlPlane {
+				continue
+			}
+			machineName := config.MachineName(*cc, n)
+			st, err := machine.Status(api, machineName)
+			if err != nil || st != state.Running.String() {
+				klog.Infof("not listing the certs of %s, status: %s, err: %v", machineName, st, err)
+				continue
+			}
+			h, err := machine.GetHost(api, *cc, n)
+			if err != nil {
+				exit.Error(reason.GuestLoadHost, "Error getting host", err)
+			}
+			runner, err := machine.CommandRunner(h)
+			if err != nil {
+				exit.Error(reason.InternalCommandRunner, "Failed to get command runner", err)
+			}
+			nc, err := bootstrapper.NodeCerts(runner, machineName)
+			if err != nil {
+				exit.Error(reason.GuestCert, "Failed to read the certificates", err)
+			}
+			certs = append(certs, nc...)
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Name", "Node", "Issuer", "Subject", "SANs", "Expires"})
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, c := range certs {
+			name := c.Name
+			if c.IsCA {
+				name += " (CA)"
+			}
+			nodeName := c.Node
+			if nodeName == "" {
+				nodeName = "host"
+			}
+			expires := c.NotAfter.Format(constants.TimeFormat)
+			if c.ExpiresWithin(constants.CertExpiryWarning) {
+				expires += " !"
+			}
+			table.Append([]string{name, nodeName, c.Issuer, c.Subject, strings.Join(c.SANs, ", "), expires})
+		}
+		table.Render()
+
+		if len(bootstrapper.ExpiringCerts(certs, constants.CertExpiryWarning)) > 0 {
+			out.Styled(style.Tip, `The certificates marked with "!" expire soon, to issue them again run: "{{.cmd}}"`, out.V{"cmd": mustload.ExampleCmd(cc.Name, "certs rotate")})
+		}
+	},
+}
+
+// certsRotateCmd represents the certs rotate command
+var certsRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Issue the certificates of the cluster again and restart its control plane to load them",
+	Long: `Issue the certificates of the cluster again, valid for --cert-expiration of the cluster:
+the apiserver, client and proxy-client certificates minikube signs, copied to every running node,
+and the ones kubeadm manages on the control-plane nodes, whose components are restarted. The kubeconfig is updated.
+With --ca, the CAs of minikube are generated again first, or installed from the CA of the organization the cluster is signed with.
+They are shared by all the profiles, so they can only be rotated when the cluster is the only one.
+With --ca-cert and --ca-key, the cluster is signed with the given CA of the organization from then on.`,
+	Example: `minikube certs rotate
+minikube certs rotate --ca
+minikube certs rotate --ca-cert intermediate-ca.pem --ca-key intermediate-ca-key.pem`,
+	Run: func(cmd *cobra.Command, args []string) {
+		co := mustload.Running(ClusterFlagValue())
+		defer co.API.Close()
+		cc := co.Config
+
+		if rotateCACert != "" || rotateCAKey != "" {
+			viper.Set(caCert, rotateCACert)
+			viper.Set(caKey, rotateCAKey)
+			validateCAFlags()
+			cc.CACert = viper.GetString(caCert)
+			cc.CAKey = viper.GetString(caKey)
+			rotateCA = true
+		}
+		if rotateCA && len(cc.Nodes) > 1 {
+			exit.Message(reason.Usage, "The CAs can not be rotated in a multi-node cluster, whose other nodes do not trust the new ones")
+		}
+		// the CAs are shared by all the profiles, whose clusters would no longer trust the new ones
+		if rotateCA && cc.CACert != "" {
+			viper.Set(caCert, cc.CACert)
+			validateSharedCA(cc.Name)
+		} else if rotateCA {
+			if others := otherProfiles(cc.Name); len(others) > 0 {
+				exit.Message(reason.Usage, `The CAs of minikube are shared by all the profiles, the clusters of {{.profiles}} would no longer work with new ones. Delete them first, or set MINIKUBE_HOME to a separate directory for this cluster`, out.V{"profiles": strings.Join(others, ", ")})
+			}
+		}
+		if err := bootstrapper.RemoveProfileCerts(cc.Name, rotateCA); err != nil {
+			exit.Error(reason.GuestCert, "Failed to remove the certificates", err)
+		}
+		if err := config.SaveProfile(cc.Name, cc); err != nil {
+			exit.Error(reason.HostSaveProfile, "Unable to save the cluster config", err)
+		}
+
+		for _, n := range cc.Nodes {
+			machineName := config.MachineName(*cc, n)
+			st, err := machine.Status(co.API, machineName)
+			if err != nil || st != state.Running.String() {
+				klog.Infof("not rotating the certs of %s, status: %s, err: %v", machineName, st, err)
+				out.WarningT(`Node "{{.name}}" is not running, its certificates will be copied at start`, out.V{"name": machineName})
+				continue
+			}
+			h, err := machine.GetHost(co.API, *cc, n)
+			if err != nil {
+				exit.Error(reason.GuestLoadHost, "Error getting host", err)
+			}
+			runner, err := machine.CommandRunner(h)
+			if err != nil {
+				exit.Error(reason.InternalCommandRunner, "Failed to get command runner", err)
+			}
+			bs, err := cluster.Bootstrapper(co.API, viper.GetString(cmdcfg.Bootstrapper), *cc, runner)
+			if err != nil {
+				exit.Error(reason.InternalBootstrapper, "Failed to get bootstrapper", err)
+			}
+
+			out.Step(style.Provisioning, `Rotating the certificates of node "{{.name}}" ...`, out.V{"name": machineName})
+			if n.ControlPlane {
+				err = bs.RotateCerts(*cc, n)
+			} else {
+				err = bs.SetupCerts(*cc, n)
+			}
+			if err != nil {
+				exit.Error(reason.GuestCert, "Failed to rotate the certificates", err)
+			}
+		}
+
+		if err := node.UpdateKubeconfig(co.CP.Host, *cc, *co.CP.Node); err != nil {
+			exit.Error(reason.HostKubeconfigUpdate, "Failed to update kubeconfig", err)
+		}
+		out.Step(style.Check, `The certificates of "{{.name}}" were rotated`, out.V{"name": cc.Name})
+	},
+}
+
+func init() {
+	certsRotateCmd.Flags().BoolVar(&rotateCA, "ca", false, "Generate the CAs of minikube again before the certificates. They are shared by all the profiles.")
+	certsRotateCmd.Flags().StringVar(&rotateCACert, "ca-cert", "", "File of the PEM encoded CA certificate of the organization to sign the cluster with from now on, implies --ca. Requires --ca-key.")
+	certsRotateCmd.Flags().StringVar(&rotateCAKey, "ca-key", "", "File of the PEM encoded private key of the CA certificate set with --ca-cert.")
+
+	certsCmd.AddCommand(certsListCmd)
+	certsCmd.AddCommand(certsRotateCmd)
+}
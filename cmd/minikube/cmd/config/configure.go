@@ -20,6 +20,8 @@ import (
 	"io/ioutil"
 	"net"
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"k8s.io/minikube/pkg/addons"
@@ -232,6 +234,55 @@ var addonsConfigureCmd = &cobra.Command{
 				out.ErrT(style.Fatal, "Failed to save config {{.profile}}", out.V{"profile": profile})
 			}
 
+		case "auto-pause":
+			profile := ClusterFlagValue()
+			_, cfg := mustload.Partial(profile)
+
+			intervalValidator := func(s string) bool {
+				d, err := time.ParseDuration(s)
+				return err == nil && d > 0
+			}
+			// the proxy runs in a pod, it reaches the daemon on an address of the node but not on loopback
+			addressValidator := func(s string) bool {
+				host, port, err := net.SplitHostPort(s)
+				if err != nil || port == "" {
+					return false
+				}
+				ip := net.ParseIP(host)
+				return host == "" || (ip != nil && !ip.IsLoopback())
+			}
+			namespacesValidator := func(s string) bool {
+				for _, ns := range strings.Split(s, ",") {
+					if strings.TrimSpace(ns) == "" {
+						return false
+					}
+				}
+				return true
+			}
+
+			interval := AskForStaticValidatedValue("-- Enter the duration of inactivity before pausing (example: 1m): ", intervalValidator)
+			cfg.KubernetesConfig.AutoPauseInterval, _ = time.ParseDuration(interval)
+			cfg.KubernetesConfig.AutoPauseAddress = AskForStaticValidatedValue("-- Enter the address the auto-pause daemon listens on (example: 0.0.0.0:8080): ", addressValidator)
+			namespaces := AskForStaticValidatedValue("-- Enter the comma separated namespaces to pause (example: kube-system): ", namespacesValidator)
+			cfg.KubernetesConfig.AutoPauseNamespaces = nil
+			for _, ns := range strings.Split(namespaces, ",") {
+				cfg.KubernetesConfig.AutoPauseNamespaces = append(cfg.KubernetesConfig.AutoPauseNamespaces, strings.TrimSpace(ns))
+			}
+
+			if err := config.SaveProfile(profile, cfg); err != nil {
+				out.ErrT(style.Fatal, "Failed to save config {{.profile}}", out.V{"profile": profile})
+			}
+
+			// Re-apply the auto-pause addon when it is enabled, so the daemon and proxy use the new settings
+			if cfg.Addons["auto-pause"] {
+				if err := addons.EnableOrDisableAddon(cfg, "auto-pause", "true"); err != nil {
+					out.ErrT(style.Fatal, "Failed to configure auto-pause {{.profile}}", out.V{"profile": profile})
+				}
+				if err := addons.RestartAutoPause(cfg); err != nil {
+					out.ErrT(style.Fatal, "Failed to restart auto-pause {{.profile}}", out.V{"profile": profile})
+				}
+			}
+
 		default:
 			out.FailureT("{{.name}} has no available configuration options", out.V{"name": addon})
 			return
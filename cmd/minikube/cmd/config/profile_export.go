@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+)
+
+var exportOutput string
+
+var profileExportCmd = &cobra.Command{
+	Use:     "export [MINIKUBE_PROFILE_NAME]",
+	Short:   "Export a profile as a cluster definition file",
+	Long:    "Export a profile as a cluster definition file, which creates an identical cluster with 'minikube start --config'. Exports the current profile if no profile name is provided.",
+	Example: "minikube profile export > cluster.yaml\nminikube start -p copy --config cluster.yaml",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 1 {
+			exit.Message(reason.Usage, "usage: minikube profile export [MINIKUBE_PROFILE_NAME]")
+		}
+		profile := ClusterFlagValue()
+		if len(args) == 1 {
+			profile = args[0]
+		}
+
+		cc, err := config.Load(profile)
+		if err != nil {
+			if config.IsNotExist(err) {
+				exit.Message(reason.Usage, `Profile "{{.profile}}" not found. Run "minikube profile list" to view all profiles.`, out.V{"profile": profile})
+			}
+			exit.Error(reason.HostConfigLoad, "Unable to load config", err)
+		}
+		cf := config.NewClusterFile(cc)
+		cf.Spec.Addons = addonSpecs(cc)
+
+		var b []byte
+		switch strings.ToLower(exportOutput) {
+		case "yaml":
+			b, err = config.MarshalClusterFile(cf)
+		case "json":
+			b, err = json.MarshalIndent(cf, "", "  ")
+			b = append(b, '\n')
+		default:
+			exit.Message(reason.Usage, fmt.Sprintf("invalid output format: %s. Valid values: 'yaml', 'json'", exportOutput))
+		}
+		if err != nil {
+			exit.Error(reason.InternalJSONMarshal, "Unable to marshal cluster definition", err)
+		}
+		out.String("%s", string(b))
+	},
+}
+
+// addonSpecs returns the enabled addons of the cluster, along with the custom images and registries they use
+func addonSpecs(cc *config.ClusterConfig) []config.AddonSpec {
+	var names []string
+	for name, enabled := range cc.Addons {
+		if enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var specs []config.AddonSpec
+	for _, name := range names {
+		spec := config.AddonSpec{Name: name}
+		if addon, ok := assets.Addons[name]; ok {
+			spec.Images = customImages(addon.Images, cc.CustomAddonImages)
+			spec.Registries = customImages(addon.Images, cc.CustomAddonRegistries)
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// customImages returns the entries of custom whose image name is used by the addon
+func customImages(addonImages map[string]string, custom map[string]string) map[string]string {
+	var images map[string]string
+	for name := range addonImages {
+		if c, ok := custom[name]; ok {
+			if images == nil {
+				images = map[string]string{}
+			}
+			images[name] = c
+		}
+	}
+	return images
+}
+
+func init() {
+	profileExportCmd.Flags().StringVarP(&exportOutput, "output", "o", "yaml", "The output format. One of 'yaml', 'json'")
+	ProfileCmd.AddCommand(profileExportCmd)
+}
@@ -204,11 +204,18 @@ func printProfilesJSON() {
 	}
 }
 
+// profilesOrDefault returns the profiles with the secrets of their registries masked, or an empty list
 func profilesOrDefault(profiles []*config.Profile) []*config.Profile {
-	if profiles != nil {
-		return profiles
+	redacted := []*config.Profile{}
+	for _, p := range profiles {
+		r := *p
+		if p.Config != nil {
+			cc := p.Config.Redacted()
+			r.Config = &cc
+		}
+		redacted = append(redacted, &r)
 	}
-	return []*config.Profile{}
+	return redacted
 }
 
 func init() {
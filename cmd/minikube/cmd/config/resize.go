@@ -0,0 +1,165 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// the minimums of "minikube start"
+	minResizeCPUs   = 2
+	minResizeMemory = 1800
+)
+
+var (
+	resizeCPUs     int
+	resizeMemory   string
+	resizeDiskSize string
+)
+
+var configResizeCmd = &cobra.Command{
+	Use:   "resize",
+	Short: "Changes the CPUs, memory and disk size of an existing cluster",
+	Long: `Changes the CPUs, memory and disk size of the nodes of an existing cluster in place, and saves them to the cluster config.
+The resources set on single nodes with "minikube node add" are replaced by the ones requested.
+The docker and podman drivers update the limits of the running containers, the disk size does not apply to them.
+The kvm2 driver stops the VMs to resize them, the disk can only grow. Run "minikube start" to restart the cluster afterwards.`,
+	Example: "minikube config resize --cpus=4 --memory=8g\nminikube config resize -p kvm --disk-size=40g",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			exit.Message(reason.Usage, "usage: minikube config resize [--cpus=N] [--memory=SIZE] [--disk-size=SIZE]")
+		}
+		if resizeCPUs == 0 && resizeMemory == "" && resizeDiskSize == "" {
+			exit.Message(reason.Usage, "Specify at least one of --cpus, --memory or --disk-size")
+		}
+
+		api, cc := mustload.Partial(ClusterFlagValue())
+		defer api.Close()
+
+		if !driver.IsKIC(cc.Driver) && !driver.IsKVM(cc.Driver) {
+			exit.Message(reason.DrvUnsupportedResize, `The "{{.driver}}" driver does not support resizing, please delete the cluster and start it with the new resources`, out.V{"driver": cc.Driver})
+		}
+		if driver.IsKIC(cc.Driver) && resizeDiskSize != "" {
+			out.WarningT("The {{.driver}} driver does not limit the disk size of the nodes, --disk-size is ignored", out.V{"driver": cc.Driver})
+			resizeDiskSize = ""
+		}
+
+		updated, err := resized(*cc, resizeCPUs, resizeMemory, resizeDiskSize)
+		if err != nil {
+			exit.Message(reason.Usage, "Unable to resize the cluster: {{.error}}", out.V{"error": err})
+		}
+		if sameResources(*cc, updated) {
+			out.Step(style.Check, `The cluster "{{.cluster}}" already has these resources`, out.V{"cluster": cc.Name})
+			return
+		}
+
+		restart := false
+		for _, n := range updated.Nodes {
+			out.Step(style.Provisioning, `Resizing node "{{.name}}" to {{.cpus}} CPUs, {{.memory}}MB of memory and {{.disk}}MB of disk ...`, out.V{"name": config.MachineName(updated, n), "cpus": config.NodeCPUs(updated, n), "memory": config.NodeMemory(updated, n), "disk": config.NodeDiskSize(updated, n)})
+			stopped, err := machine.Resize(api, updated, n)
+			if err != nil {
+				exit.Error(reason.GuestResize, "Unable to resize the node", err)
+			}
+			restart = restart || stopped
+		}
+
+		if err := config.SaveProfile(updated.Name, &updated); err != nil {
+			exit.Error(reason.HostSaveProfile, "Unable to save the cluster config", err)
+		}
+		if restart {
+			out.Step(style.Tip, `To restart the cluster with the new resources, run: "{{.cmd}}"`, out.V{"cmd": mustload.ExampleCmd(updated.Name, "start")})
+			return
+		}
+		out.Step(style.Ready, `The cluster "{{.cluster}}" was resized`, out.V{"cluster": updated.Name})
+	},
+}
+
+// resized returns the config of the cluster with the resources requested, unset ones are kept.
+// The resources requested replace the ones of single nodes.
+func resized(cc config.ClusterConfig, cpus int, memory string, diskSize string) (config.ClusterConfig, error) {
+	cc.Nodes = append([]config.Node{}, cc.Nodes...)
+	if cpus != 0 {
+		if cpus < minResizeCPUs {
+			return cc, fmt.Errorf("requested cpu count %d is less than the minimum allowed of %d", cpus, minResizeCPUs)
+		}
+		cc.CPUs = cpus
+		for i := range cc.Nodes {
+			cc.Nodes[i].CPUs = 0
+		}
+	}
+	if memory != "" {
+		mb, err := util.CalculateSizeInMB(memory)
+		if err != nil {
+			return cc, fmt.Errorf("invalid memory size %q: %v", memory, err)
+		}
+		if mb < minResizeMemory {
+			return cc, fmt.Errorf("requested memory allocation %dMB is less than the usable minimum of %dMB", mb, minResizeMemory)
+		}
+		cc.Memory = mb
+		for i := range cc.Nodes {
+			cc.Nodes[i].Memory = 0
+		}
+	}
+	if diskSize != "" {
+		mb, err := util.CalculateSizeInMB(diskSize)
+		if err != nil {
+			return cc, fmt.Errorf("invalid disk size %q: %v", diskSize, err)
+		}
+		current := cc.DiskSize
+		for i, n := range cc.Nodes {
+			if config.NodeDiskSize(cc, n) > current {
+				current = config.NodeDiskSize(cc, n)
+			}
+			cc.Nodes[i].DiskSize = 0
+		}
+		if mb < current {
+			return cc, fmt.Errorf("the disk can only grow, requested %dMB is less than the current %dMB", mb, current)
+		}
+		cc.DiskSize = mb
+	}
+	return cc, nil
+}
+
+// sameResources returns whether all the nodes of the clusters have the same resources
+func sameResources(cc config.ClusterConfig, updated config.ClusterConfig) bool {
+	for i, n := range cc.Nodes {
+		u := updated.Nodes[i]
+		if config.NodeCPUs(cc, n) != config.NodeCPUs(updated, u) || config.NodeMemory(cc, n) != config.NodeMemory(updated, u) || config.NodeDiskSize(cc, n) != config.NodeDiskSize(updated, u) {
+			return false
+		}
+	}
+	return true
+}
+
+func init() {
+	configResizeCmd.Flags().IntVar(&resizeCPUs, "cpus", 0, "Number of CPUs allocated to each node")
+	configResizeCmd.Flags().StringVar(&resizeMemory, "memory", "", "Amount of RAM allocated to each node (format: <number>[<unit>], where unit = b, k, m or g)")
+	configResizeCmd.Flags().StringVar(&resizeDiskSize, "disk-size", "", "Disk size allocated to each node, which can only grow (format: <number>[<unit>], where unit = b, k, m or g)")
+	ConfigCmd.AddCommand(configResizeCmd)
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestResizedNodes(t *testing.T) {
+	cc := config.ClusterConfig{Name: "pools", CPUs: 2, Memory: 2200, DiskSize: 20000, Nodes: []config.Node{
+		{Name: "", ControlPlane: true, Worker: true},
+		{Name: "m02", Worker: true, CPUs: 8, Memory: 16384, DiskSize: 40000},
+	}}
+
+	updated, err := resized(cc, 4, "", "")
+	if err != nil {
+		t.Fatalf("resized() failed: %v", err)
+	}
+	for _, n := range updated.Nodes {
+		if got := config.NodeCPUs(updated, n); got != 4 {
+			t.Errorf("CPUs of node %q = %d; want 4", n.Name, got)
+		}
+	}
+	if got := config.NodeMemory(updated, updated.Nodes[1]); got != 16384 {
+		t.Errorf("memory of node m02 = %d; want its own 16384", got)
+	}
+	if cc.Nodes[1].CPUs != 8 {
+		t.Errorf("resized() changed the nodes of the original config")
+	}
+	if sameResources(cc, updated) {
+		t.Errorf("sameResources() = true after resizing the CPUs")
+	}
+	if !sameResources(cc, cc) {
+		t.Errorf("sameResources() of the same config = false")
+	}
+
+	if _, err := resized(cc, 0, "", "30g"); err == nil {
+		t.Errorf("resized() shrinking the disk of node m02 succeeded; want an error")
+	}
+	updated, err = resized(cc, 0, "", "50g")
+	if err != nil {
+		t.Fatalf("resized() failed: %v", err)
+	}
+	if updated.DiskSize != 51200 || updated.Nodes[1].DiskSize != 0 {
+		t.Errorf("resized() disk sizes = %d, %d; want 51200 for the cluster and none for node m02", updated.DiskSize, updated.Nodes[1].DiskSize)
+	}
+}
@@ -17,16 +17,14 @@ limitations under the License.
 package cmd
 
 import (
-	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
-	"fmt"
+	"io/ioutil"
 	"os"
-	pt "path"
+	"path/filepath"
 	"strings"
 
 	"k8s.io/klog/v2"
-	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/machine"
@@ -38,96 +36,144 @@ import (
 
 // placeholders for flag values
 var (
-	srcPath string
-	dstPath string
-	dstNode string
+	cpMode  string
+	cpOwner string
 )
 
+// remotePath is a path, on the node named node, or on the host if node is empty
+type remotePath struct {
+	node string
+	path string
+}
+
 // cpCmd represents the cp command, similar to docker cp
 var cpCmd = &cobra.Command{
-	Use:   "cp <source file path> <target node name>:<target file absolute path>",
-	Short: "Copy the specified file into minikube",
-	Long: "Copy the specified file into minikube, it will be saved at path <target file absolute path> in your minikube.\n" +
+	Use:   "cp [<source node name>:]<source path> [<target node name>:]<target path>",
+	Short: "Copy files and directories into, out of, or between minikube nodes",
+	Long: "Copy the specified file or directory into minikube, out of a minikube node, or between two minikube nodes. Directories are copied recursively, and file modes are kept unless --mode is given.\n" +
+		"If no node name is given for either path, the target is the control plane node.\n" +
 		"Example Command : \"minikube cp a.txt /home/docker/b.txt\"\n" +
-		"                  \"minikube cp a.txt minikube-m02:/home/docker/b.txt\"\n",
+		"                  \"minikube cp a.txt minikube-m02:/home/docker/b.txt\"\n" +
+		"                  \"minikube cp minikube-m02:/home/docker/b.txt b.txt\"\n" +
+		"                  \"minikube cp minikube:/etc/kubernetes minikube-m02:/home/docker/kubernetes\"\n" +
+		"                  \"minikube cp --mode 0600 --owner docker:docker dir /home/docker/dir\"\n",
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) != 2 {
 			exit.Message(reason.Usage, `Please specify the path to copy: 
 	minikube cp <source file path> <target file absolute path> (example: "minikube cp a/b.txt /copied.txt")`)
 		}
 
-		srcPath = args[0]
-		dstPath = args[1]
-
-		// if destination path is not a absolute path, trying to parse with <node>:<abs path> format
-		if !strings.HasPrefix(dstPath, "/") {
-			if sp := strings.SplitN(dstPath, ":", 2); len(sp) == 2 {
-				dstNode = sp[0]
-				dstPath = sp[1]
-			}
+		src := newRemotePath(args[0])
+		dst := newRemotePath(args[1])
+		if src.node == "" && dst.node == "" {
+			// backwards compatibility: copy to the control plane when neither path names a node
+			dst.node = ClusterFlagValue()
 		}
+		validateArgs(src, dst)
 
-		validateArgs(srcPath, dstPath)
-
-		fa, err := assets.NewFileAsset(srcPath, pt.Dir(dstPath), pt.Base(dstPath), "0644")
-		if err != nil {
-			out.ErrLn("%v", errors.Wrap(err, "getting file asset"))
-			os.Exit(1)
+		opts := machine.CopyOptions{Mode: cpMode, Owner: cpOwner}
+		if err := opts.ValidateMode(); err != nil {
+			exit.Message(reason.Usage, "{{.error}}", out.V{"error": err})
 		}
-		defer func() {
-			if err := fa.Close(); err != nil {
-				klog.Warningf("error closing the file %s: %v", fa.GetSourcePath(), err)
-			}
-		}()
 
 		co := mustload.Running(ClusterFlagValue())
-		var runner command.Runner
-		if dstNode == "" {
-			runner = co.CP.Runner
-		} else {
-			n, _, err := node.Retrieve(*co.Config, dstNode)
-			if err != nil {
-				exit.Message(reason.GuestNodeRetrieve, "Node {{.nodeName}} does not exist.", out.V{"nodeName": dstNode})
-			}
 
-			h, err := machine.GetHost(co.API, *co.Config, *n)
-			if err != nil {
-				exit.Error(reason.GuestLoadHost, "Error getting host", err)
+		switch {
+		case src.node == "":
+			if err := machine.CopyToNode(nodeCommandRunner(&co, dst.node), src.path, dst.path, opts); err != nil {
+				exit.Error(reason.GuestCopy, "Failed to copy files to the node", err)
 			}
-
-			runner, err = machine.CommandRunner(h)
+		case dst.node == "":
+			if err := machine.CopyFromNode(nodeCommandRunner(&co, src.node), src.path, dst.path, opts); err != nil {
+				exit.Error(reason.GuestCopy, "Failed to copy files from the node", err)
+			}
+		default:
+			tmp, err := ioutil.TempDir("", "minikube-cp")
 			if err != nil {
-				exit.Error(reason.InternalCommandRunner, "Failed to get command runner", err)
+				exit.Error(reason.GuestCopy, "Failed to create a temporary directory", err)
+			}
+			defer func() {
+				if err := os.RemoveAll(tmp); err != nil {
+					klog.Warningf("error removing %s: %v", tmp, err)
+				}
+			}()
+			local := filepath.Join(tmp, "cp")
+			if err := machine.CopyFromNode(nodeCommandRunner(&co, src.node), src.path, local, machine.CopyOptions{}); err != nil {
+				exit.Error(reason.GuestCopy, "Failed to copy files from the node", err)
+			}
+			if err := machine.CopyToNode(nodeCommandRunner(&co, dst.node), local, dst.path, opts); err != nil {
+				exit.Error(reason.GuestCopy, "Failed to copy files to the node", err)
 			}
-		}
-
-		if err = runner.Copy(fa); err != nil {
-			exit.Error(reason.InternalCommandRunner, fmt.Sprintf("Fail to copy file %s", fa.GetSourcePath()), err)
 		}
 	},
 }
 
 func init() {
+	cpCmd.Flags().StringVar(&cpMode, "mode", "", "Permissions of the copied files, as an octal mode such as 0644. Defaults to the permissions of the source files.")
+	cpCmd.Flags().StringVar(&cpOwner, "owner", "", "Owner of the files copied to a node, in the form user[:group]. Defaults to root.")
 }
 
-func validateArgs(srcPath string, dstPath string) {
-	if srcPath == "" {
-		exit.Message(reason.Usage, "Source {{.path}} can not be empty", out.V{"path": srcPath})
+// newRemotePath parses a path in the [<node>:]<path> format
+func newRemotePath(path string) *remotePath {
+	// absolute paths (including windows paths, such as C:\a.txt) are never prefixed with a node name
+	if strings.HasPrefix(path, "/") || filepath.IsAbs(path) {
+		return &remotePath{path: path}
+	}
+	if sp := strings.SplitN(path, ":", 2); len(sp) == 2 && sp[0] != "" {
+		return &remotePath{node: sp[0], path: sp[1]}
+	}
+	return &remotePath{path: path}
+}
+
+// nodeCommandRunner returns a command runner for the node with the given name
+func nodeCommandRunner(co *mustload.ClusterController, nodeName string) command.Runner {
+	if nodeName == co.Config.Name || nodeName == co.CP.Node.Name {
+		return co.CP.Runner
+	}
+
+	n, _, err := node.Retrieve(*co.Config, nodeName)
+	if err != nil {
+		exit.Message(reason.GuestNodeRetrieve, "Node {{.nodeName}} does not exist.", out.V{"nodeName": nodeName})
+	}
+
+	h, err := machine.GetHost(co.API, *co.Config, *n)
+	if err != nil {
+		exit.Error(reason.GuestLoadHost, "Error getting host", err)
 	}
 
-	if dstPath == "" {
-		exit.Message(reason.Usage, "Target {{.path}} can not be empty", out.V{"path": dstPath})
+	runner, err := machine.CommandRunner(h)
+	if err != nil {
+		exit.Error(reason.InternalCommandRunner, "Failed to get command runner", err)
 	}
+	return runner
+}
 
-	if _, err := os.Stat(srcPath); err != nil {
-		if os.IsNotExist(err) {
-			exit.Message(reason.HostPathMissing, "Cannot find directory {{.path}} for copy", out.V{"path": srcPath})
-		} else {
-			exit.Error(reason.HostPathStat, "stat failed", err)
+func validateArgs(src *remotePath, dst *remotePath) {
+	if src.path == "" {
+		exit.Message(reason.Usage, "Source {{.path}} can not be empty", out.V{"path": src.path})
+	}
+
+	if dst.path == "" {
+		exit.Message(reason.Usage, "Target {{.path}} can not be empty", out.V{"path": dst.path})
+	}
+
+	if src.node == "" {
+		if _, err := os.Stat(src.path); err != nil {
+			if os.IsNotExist(err) {
+				exit.Message(reason.HostPathMissing, "Cannot find directory {{.path}} for copy", out.V{"path": src.path})
+			} else {
+				exit.Error(reason.HostPathStat, "stat failed", err)
+			}
 		}
+	} else if !strings.HasPrefix(src.path, "/") {
+		exit.Message(reason.Usage, `<source node path> must be an absolute Path. Relative Path is not allowed (example: "minikube-m02:/home/docker/copied.txt")`)
 	}
 
-	if !strings.HasPrefix(dstPath, "/") {
+	if dst.node != "" && !strings.HasPrefix(dst.path, "/") {
 		exit.Message(reason.Usage, `<target file absolute path> must be an absolute Path. Relative Path is not allowed (example: "/home/docker/copied.txt")`)
 	}
+
+	if dst.node == "" && cpOwner != "" {
+		exit.Message(reason.Usage, "The --owner flag can only be used when copying to a node")
+	}
 }
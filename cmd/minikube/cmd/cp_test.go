@@ -0,0 +1,39 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+)
+
+func TestNewRemotePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want remotePath
+	}{
+		{"a.txt", remotePath{path: "a.txt"}},
+		{"/home/docker/a.txt", remotePath{path: "/home/docker/a.txt"}},
+		{"minikube-m02:/home/docker/a.txt", remotePath{node: "minikube-m02", path: "/home/docker/a.txt"}},
+		{":a.txt", remotePath{path: ":a.txt"}},
+	}
+	for _, tc := range tests {
+		got := newRemotePath(tc.path)
+		if *got != tc.want {
+			t.Errorf("newRemotePath(%q) = %+v, want %+v", tc.path, *got, tc.want)
+		}
+	}
+}
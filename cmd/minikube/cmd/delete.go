@@ -46,9 +46,12 @@ import (
 	"k8s.io/minikube/pkg/minikube/kubeconfig"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mount"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/out/register"
+	"k8s.io/minikube/pkg/minikube/portforward"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/snapshot"
 	"k8s.io/minikube/pkg/minikube/style"
 )
 
@@ -91,6 +94,15 @@ var hostAndDirsDeleter = func(api libmachine.API, cc *config.ClusterConfig, prof
 	if err := killMountProcess(); err != nil {
 		out.FailureT("Failed to kill mount process: {{.error}}", out.V{"error": err})
 	}
+	if err := mount.StopAll(profileName); err != nil {
+		out.FailureT("Failed to kill mount process: {{.error}}", out.V{"error": err})
+	}
+	// the proxy containers are on the network of the cluster, which is removed with the nodes
+	if cc != nil && driver.IsKIC(cc.Driver) {
+		if err := portforward.RemoveAll(cc.Driver, profileName); err != nil {
+			out.FailureT("Failed to remove port forwards: {{.error}}", out.V{"error": err})
+		}
+	}
 
 	deleteHosts(api, cc)
 
@@ -98,6 +110,11 @@ var hostAndDirsDeleter = func(api libmachine.API, cc *config.ClusterConfig, prof
 	deleteProfileDirectory(profileName)
 	deleteMachineDirectories(cc)
 
+	// the disks of kvm2 machines are backed by their snapshots, which are only deleted with them
+	if err := snapshot.DeleteAll(profileName); err != nil {
+		out.FailureT("Failed to delete snapshots: {{.error}}", out.V{"error": err})
+	}
+
 	if err := deleteConfig(profileName); err != nil {
 		return err
 	}
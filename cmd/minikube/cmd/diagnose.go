@@ -0,0 +1,65 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/diagnose"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var (
+	// diagnoseOutput is the file the bundle is written to
+	diagnoseOutput string
+	// diagnoseLines is how many lines to collect from each log
+	diagnoseLines int
+)
+
+// diagnoseCmd represents the diagnose command
+var diagnoseCmd = &cobra.Command{
+	Use:   "diagnose",
+	Short: "Collects a diagnostic bundle of the cluster, to attach to bug reports",
+	Long: `Collects a diagnostic bundle of the cluster as a gzipped tarball: for every node the kubelet and container runtime journals and the logs of all containers,
+the description of the failed pods, the node conditions, the cluster config, the last start log, the audit log and information about the driver. Secrets are redacted.`,
+	Example: "minikube diagnose --output bundle.tar.gz",
+	Run: func(cmd *cobra.Command, args []string) {
+		if diagnoseLines <= 0 {
+			exit.Message(reason.Usage, "The number of lines must be greater than 0")
+		}
+
+		api, cc := mustload.Partial(ClusterFlagValue())
+		file := diagnoseOutput
+		if file == "" {
+			file = diagnose.DefaultFile(cc.Name)
+		}
+
+		out.Step(style.Documentation, "Collecting diagnostics of profile {{.profile}} ...", out.V{"profile": cc.Name})
+		if err := diagnose.Write(api, cc, diagnose.Options{Lines: diagnoseLines}, file); err != nil {
+			exit.Error(reason.HostDiagnose, "Failed to write diagnostic bundle", err)
+		}
+		out.Step(style.Success, "Diagnostic bundle written to {{.file}}, please review it before attaching it to an issue", out.V{"file": file})
+	},
+}
+
+func init() {
+	diagnoseCmd.Flags().StringVar(&diagnoseOutput, "output", "", "The file to write the bundle to. Defaults to <profile>-diagnose-<time>.tar.gz")
+	diagnoseCmd.Flags().IntVarP(&diagnoseLines, "length", "n", 10000, "Number of lines to collect from each journal and container log")
+}
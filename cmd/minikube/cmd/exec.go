@@ -0,0 +1,240 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var (
+	execAllNodes bool
+	execStdin    bool
+)
+
+// execCmd represents the exec command
+var execCmd = &cobra.Command{
+	Use:   "exec -- COMMAND [args...]",
+	Short: "Run a command on one or all nodes of the cluster",
+	Long: `Run a non-interactive command on a node of the cluster, or on all of them at once with --all-nodes.
+The command is run directly, without a shell, and the same way whatever the driver. With --all-nodes, each line of output is prefixed by the name of its node.
+minikube exits with the exit code of the command, the highest one of the nodes with --all-nodes.`,
+	Example: `minikube exec -- uptime
+minikube exec -n minikube-m02 -- cat /etc/os-release
+minikube exec --all-nodes -- sudo crictl ps
+tar -c manifests | minikube exec -i -- tar -x -C /tmp`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "usage: minikube exec [--node=NAME | --all-nodes] [-i] -- COMMAND [args...]")
+		}
+		if execAllNodes && nodeName != "" {
+			exit.Message(reason.Usage, "The --node and --all-nodes flags can not be used together")
+		}
+
+		co := mustload.Running(ClusterFlagValue())
+		nodes := targetNodes(co, execAllNodes)
+
+		var stdin io.Reader
+		if execStdin {
+			stdin = os.Stdin
+		}
+		if code := runOnNodes(co, nodes, args, stdin, execAllNodes); code != 0 {
+			exit.Code(code)
+		}
+	},
+}
+
+// targetNodes returns all the nodes of the cluster, or the one selected with --node, the primary control plane by default
+func targetNodes(co mustload.ClusterController, all bool) []config.Node {
+	if all {
+		return co.Config.Nodes
+	}
+	if nodeName == "" {
+		return []config.Node{*co.CP.Node}
+	}
+	n, _, err := node.Retrieve(*co.Config, nodeName)
+	if err != nil {
+		exit.Message(reason.GuestNodeRetrieve, "Node {{.nodeName}} does not exist.", out.V{"nodeName": nodeName})
+	}
+	return []config.Node{*n}
+}
+
+// runOnNodes runs args on the nodes concurrently, with their output prefixed by the name of the node if prefix is set.
+// stdin is streamed to a single node, and read before being sent to each of several nodes.
+// It returns the exit status to exit with: 0 if the command succeeded on all the nodes, else the highest exit code.
+func runOnNodes(co mustload.ClusterController, nodes []config.Node, args []string, stdin io.Reader, prefix bool) int {
+	var input []byte
+	if stdin != nil && len(nodes) > 1 {
+		b, err := ioutil.ReadAll(stdin)
+		if err != nil {
+			exit.Error(reason.HostReadStdin, "Unable to read stdin", err)
+		}
+		input = b
+	}
+
+	var outMu, errMu sync.Mutex
+	codes := make([]int, len(nodes))
+	var wg sync.WaitGroup
+	for i, n := range nodes {
+		wg.Add(1)
+		go func(i int, n config.Node) {
+			defer wg.Done()
+			name := config.MachineName(*co.Config, n)
+
+			c := exec.Command(args[0], args[1:]...)
+			c.Stdout = os.Stdout
+			c.Stderr = os.Stderr
+			if stdin != nil {
+				// the runners of container nodes allocate a tty for a terminal output, which the streamed input is not
+				c.Stdout = struct{ io.Writer }{os.Stdout}
+				c.Stderr = struct{ io.Writer }{os.Stderr}
+			}
+			if prefix {
+				stdout := newPrefixWriter(os.Stdout, &outMu, name)
+				stderr := newPrefixWriter(os.Stderr, &errMu, name)
+				defer stdout.Flush()
+				defer stderr.Flush()
+				c.Stdout = stdout
+				c.Stderr = stderr
+			}
+			switch {
+			case input != nil:
+				c.Stdin = bytes.NewReader(input)
+			case stdin != nil:
+				c.Stdin = stdin
+			}
+
+			codes[i] = runOnNode(co, n, c)
+		}(i, n)
+	}
+	wg.Wait()
+
+	var failed []string
+	for i, code := range codes {
+		if code != 0 {
+			failed = append(failed, fmt.Sprintf("%s (exit code %d)", config.MachineName(*co.Config, nodes[i]), code))
+		}
+	}
+	if len(failed) > 0 && len(nodes) > 1 {
+		out.ErrT(style.Failure, "The command failed on {{.failed}} of {{.total}} nodes: {{.nodes}}", out.V{"failed": len(failed), "total": len(nodes), "nodes": strings.Join(failed, ", ")})
+	}
+	return highestExitCode(codes)
+}
+
+// runOnNode runs c on node n, and returns its exit code
+func runOnNode(co mustload.ClusterController, n config.Node, c *exec.Cmd) int {
+	name := config.MachineName(*co.Config, n)
+	h, err := machine.GetHost(co.API, *co.Config, n)
+	if err != nil {
+		out.ErrLn("%s: %v", name, err)
+		return 1
+	}
+	r, err := machine.CommandRunner(h)
+	if err != nil {
+		out.ErrLn("%s: %v", name, err)
+		return 1
+	}
+	rr, err := r.RunCmd(c)
+	if err == nil {
+		return 0
+	}
+	if rr != nil && rr.ExitCode != 0 {
+		// the output of the command was already written
+		return rr.ExitCode
+	}
+	out.ErrLn("%s: %v", name, err)
+	return 1
+}
+
+// highestExitCode returns the highest of the exit codes, or 0 if they are all 0
+func highestExitCode(codes []int) int {
+	highest := 0
+	for _, code := range codes {
+		if code > highest {
+			highest = code
+		}
+	}
+	return highest
+}
+
+// prefixWriter writes each line written to it to w, preceded by the name of a node.
+// Writers of several nodes share mu, so that their lines do not interleave.
+type prefixWriter struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	prefix []byte
+	line   []byte
+}
+
+func newPrefixWriter(w io.Writer, mu *sync.Mutex, name string) *prefixWriter {
+	return &prefixWriter{w: w, mu: mu, prefix: []byte(fmt.Sprintf("[%s] ", name))}
+}
+
+// Write implements io.Writer, holding the last line until it is complete
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.line = append(p.line, b...)
+	for {
+		i := bytes.IndexByte(p.line, '\n')
+		if i < 0 {
+			break
+		}
+		if err := p.writeLine(p.line[:i+1]); err != nil {
+			return 0, err
+		}
+		p.line = p.line[i+1:]
+	}
+	return len(b), nil
+}
+
+// Flush writes the last line, if it did not end with a newline
+func (p *prefixWriter) Flush() {
+	if len(p.line) == 0 {
+		return
+	}
+	_ = p.writeLine(append(p.line, '\n'))
+	p.line = nil
+}
+
+func (p *prefixWriter) writeLine(line []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err := p.w.Write(append(append([]byte{}, p.prefix...), line...))
+	return err
+}
+
+func init() {
+	execCmd.Flags().StringVarP(&nodeName, "node", "n", "", "The node to run the command on. Defaults to the primary control plane.")
+	execCmd.Flags().BoolVar(&execAllNodes, "all-nodes", false, "Run the command on all the nodes concurrently, prefixing the output with the node names")
+	execCmd.Flags().BoolVarP(&execStdin, "stdin", "i", false, "Pass stdin to the command. With --all-nodes, stdin is read entirely and passed to each node.")
+}
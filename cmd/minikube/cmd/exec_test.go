@@ -0,0 +1,57 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	var b bytes.Buffer
+	var mu sync.Mutex
+	w := newPrefixWriter(&b, &mu, "minikube-m02")
+	for _, s := range []string{"Linux ", "minikube-m02\nup 3 ", "days\n\n", "no newline"} {
+		if _, err := w.Write([]byte(s)); err != nil {
+			t.Fatalf("Write(%q): %v", s, err)
+		}
+	}
+	w.Flush()
+
+	want := "[minikube-m02] Linux minikube-m02\n[minikube-m02] up 3 days\n[minikube-m02] \n[minikube-m02] no newline\n"
+	if got := b.String(); got != want {
+		t.Errorf("prefixWriter wrote %q, want %q", got, want)
+	}
+}
+
+func TestHighestExitCode(t *testing.T) {
+	tests := []struct {
+		codes []int
+		want  int
+	}{
+		{[]int{0, 0, 0}, 0},
+		{[]int{0, 1, 0}, 1},
+		{[]int{2, 1, 127}, 127},
+		{nil, 0},
+	}
+	for _, tc := range tests {
+		if got := highestExitCode(tc.codes); got != tc.want {
+			t.Errorf("highestExitCode(%v) = %d, want %d", tc.codes, got, tc.want)
+		}
+	}
+}
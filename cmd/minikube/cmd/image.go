@@ -17,20 +17,30 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 
+	units "github.com/docker/go-units"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/image"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
 	docker "k8s.io/minikube/third_party/go-dockerclient"
 )
@@ -51,6 +61,9 @@ var (
 	dockerFile string
 	buildEnv   []string
 	buildOpt   []string
+	imgNode    string
+	imgFormat  string
+	imgFilters []string
 )
 
 func saveFile(r io.Reader) (string, error) {
@@ -231,6 +244,8 @@ var listImageCmd = &cobra.Command{
 	Short: "List images",
 	Example: `
 $ minikube image ls
+
+$ minikube image ls --format table --filter reference=k8s.gcr.io/*
 `,
 	Aliases: []string{"list"},
 	Run: func(cmd *cobra.Command, args []string) {
@@ -239,9 +254,272 @@ $ minikube image ls
 			exit.Error(reason.Usage, "loading profile", err)
 		}
 
-		if err := machine.ListImages(profile); err != nil {
+		opts, err := listImagesOptions(imgFilters)
+		if err != nil {
+			exit.Message(reason.Usage, "Invalid image filter: {{.error}}", out.V{"error": err})
+		}
+
+		images, err := machine.ListImages(profile, imgNode, opts)
+		if err != nil {
 			exit.Error(reason.GuestImageList, "Failed to list images", err)
 		}
+
+		infos := imageInfos(images)
+		switch strings.ToLower(imgFormat) {
+		case "short":
+			printImagesShort(infos)
+		case "table":
+			printImagesTable(infos)
+		case "json":
+			j, err := json.Marshal(infos)
+			if err != nil {
+				exit.Error(reason.InternalJSONMarshal, "images json failure", err)
+			}
+			out.Ln(string(j))
+		case "yaml":
+			y, err := yaml.Marshal(infos)
+			if err != nil {
+				exit.Error(reason.InternalYamlMarshal, "images yaml failure", err)
+			}
+			out.Ln(string(y))
+		default:
+			exit.Message(reason.Usage, "invalid format: {{.format}}. Valid values: 'short', 'table', 'json', 'yaml'", out.V{"format": imgFormat})
+		}
+	},
+}
+
+// imageInfo is an image, along with the nodes holding it
+type imageInfo struct {
+	cruntime.ListImage `yaml:",inline"`
+	Nodes              []string `json:"nodes" yaml:"nodes"`
+}
+
+// listImagesOptions parses the image list filters, in the format key=value
+func listImagesOptions(filters []string) (cruntime.ListImagesOptions, error) {
+	opts := cruntime.ListImagesOptions{}
+	for _, f := range filters {
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) != 2 {
+			return opts, fmt.Errorf("%q, expected key=value", f)
+		}
+		switch kv[0] {
+		case "reference":
+			opts.Reference = kv[1]
+		case "dangling":
+			dangling, err := strconv.ParseBool(kv[1])
+			if err != nil {
+				return opts, fmt.Errorf("%q: %v", f, err)
+			}
+			opts.Dangling = dangling
+		default:
+			return opts, fmt.Errorf("%q, valid keys are 'reference' and 'dangling'", f)
+		}
+	}
+	return opts, nil
+}
+
+// imageInfos merges the images listed on each node by image ID
+func imageInfos(images map[string][]cruntime.ListImage) []*imageInfo {
+	machines := []string{}
+	for m := range images {
+		machines = append(machines, m)
+	}
+	sort.Strings(machines)
+
+	infos := []*imageInfo{}
+	index := map[string]*imageInfo{}
+	for _, m := range machines {
+		for _, img := range images[m] {
+			info, ok := index[img.ID]
+			if !ok {
+				info = &imageInfo{ListImage: img, Nodes: []string{}}
+				info.RepoTags = append([]string{}, img.RepoTags...)
+				info.RepoDigests = append([]string{}, img.RepoDigests...)
+				index[img.ID] = info
+				infos = append(infos, info)
+			} else {
+				info.RepoTags = mergeStrings(info.RepoTags, img.RepoTags)
+				info.RepoDigests = mergeStrings(info.RepoDigests, img.RepoDigests)
+			}
+			info.Nodes = append(info.Nodes, m)
+		}
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return imageName(infos[i]) > imageName(infos[j])
+	})
+	return infos
+}
+
+// mergeStrings appends the strings from b that are not already in a
+func mergeStrings(a []string, b []string) []string {
+	for _, s := range b {
+		found := false
+		for _, v := range a {
+			if v == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			a = append(a, s)
+		}
+	}
+	return a
+}
+
+// imageName returns the first tag of the image, or its ID if it has none
+func imageName(info *imageInfo) string {
+	if len(info.RepoTags) > 0 {
+		return info.RepoTags[0]
+	}
+	return info.ID
+}
+
+func printImagesShort(infos []*imageInfo) {
+	tags := []string{}
+	for _, info := range infos {
+		tags = append(tags, info.RepoTags...)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(tags)))
+	for _, tag := range tags {
+		out.Ln(tag)
+	}
+}
+
+func printImagesTable(infos []*imageInfo) {
+	var data [][]string
+	for _, info := range infos {
+		id := strings.TrimPrefix(info.ID, "sha256:")
+		if len(id) > 12 {
+			id = id[:12]
+		}
+		size := units.HumanSize(float64(info.Size))
+		nodes := strings.Join(info.Nodes, ", ")
+		if len(info.RepoTags) == 0 {
+			data = append(data, []string{"<none>", "<none>", id, size, nodes})
+			continue
+		}
+		for _, tag := range info.RepoTags {
+			repo, t := tag, "<none>"
+			if i := strings.LastIndex(tag, ":"); i > strings.LastIndex(tag, "/") {
+				repo, t = tag[:i], tag[i+1:]
+			}
+			data = append(data, []string{repo, t, id, size, nodes})
+		}
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Image", "Tag", "Image ID", "Size", "Nodes"})
+	table.SetAutoFormatHeaders(false)
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(data)
+	table.Render()
+}
+
+// saveImageCmd represents the image save command
+var saveImageCmd = &cobra.Command{
+	Use:     "save IMAGE ARCHIVE | -",
+	Short:   "Save a image from minikube",
+	Long:    "Save a image from minikube to a tarball on the host, or to stdout",
+	Example: "minikube image save image image.tar\nminikube image save image - > image.tar",
+	Args:    cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		profile, err := config.LoadProfile(viper.GetString(config.ProfileName))
+		if err != nil {
+			exit.Error(reason.Usage, "loading profile", err)
+		}
+
+		img := args[0]
+		dst := args[1]
+		if dst == "-" {
+			if err := saveImageToStdout(img, profile); err != nil {
+				exit.Error(reason.GuestImageSave, "Failed to save image", err)
+			}
+			return
+		}
+
+		if err := machine.SaveImage(img, dst, profile, imgNode); err != nil {
+			exit.Error(reason.GuestImageSave, "Failed to save image", err)
+		}
+	},
+}
+
+// saveImageToStdout saves img to a temporary file, which is written to stdout then removed before returning:
+// exit does not run the deferred calls
+func saveImageToStdout(img string, profile *config.Profile) error {
+	tmp, err := ioutil.TempFile("", "save.*.tar")
+	if err != nil {
+		return errors.Wrap(err, "creating temporary file")
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := machine.SaveImage(img, tmp.Name(), profile, imgNode); err != nil {
+		return err
+	}
+
+	f, err := os.Open(tmp.Name())
+	if err != nil {
+		return errors.Wrap(err, "opening saved image")
+	}
+	defer f.Close()
+	_, err = io.Copy(os.Stdout, f)
+	return errors.Wrap(err, "writing image to stdout")
+}
+
+var tagImageCmd = &cobra.Command{
+	Use:   "tag SOURCE TARGET",
+	Short: "Tag images",
+	Example: `
+$ minikube image tag source target
+`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		profile, err := config.LoadProfile(viper.GetString(config.ProfileName))
+		if err != nil {
+			exit.Error(reason.Usage, "loading profile", err)
+		}
+		if err := machine.TagImage(args[0], args[1], profile); err != nil {
+			exit.Error(reason.GuestImageTag, "Failed to tag image", err)
+		}
+	},
+}
+
+var pushImageCmd = &cobra.Command{
+	Use:   "push IMAGE [IMAGE...]",
+	Short: "Push images",
+	Example: `
+$ minikube image push busybox
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		profile, err := config.LoadProfile(viper.GetString(config.ProfileName))
+		if err != nil {
+			exit.Error(reason.Usage, "loading profile", err)
+		}
+		if err := machine.PushImages(args, profile); err != nil {
+			exit.Error(reason.GuestImagePush, "Failed to push images", err)
+		}
+	},
+}
+
+var pullImageCmd = &cobra.Command{
+	Use:   "pull IMAGE [IMAGE...]",
+	Short: "Pull images",
+	Example: `
+$ minikube image pull busybox
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		profile, err := config.LoadProfile(viper.GetString(config.ProfileName))
+		if err != nil {
+			exit.Error(reason.Usage, "loading profile", err)
+		}
+		if err := machine.PullImages(args, profile); err != nil {
+			exit.Error(reason.GuestImagePull, "Failed to pull images", err)
+		}
 	},
 }
 
@@ -258,5 +536,13 @@ func init() {
 	buildImageCmd.Flags().StringArrayVar(&buildEnv, "build-env", nil, "Environment variables to pass to the build. (format: key=value)")
 	buildImageCmd.Flags().StringArrayVar(&buildOpt, "build-opt", nil, "Specify arbitrary flags to pass to the build. (format: key=value)")
 	imageCmd.AddCommand(buildImageCmd)
+	listImageCmd.Flags().StringVar(&imgFormat, "format", "short", "Format output. One of: short|table|json|yaml")
+	listImageCmd.Flags().StringArrayVar(&imgFilters, "filter", nil, "Filter the listed images. (format: key=value, keys: reference, dangling)")
+	listImageCmd.Flags().StringVarP(&imgNode, "node", "n", "", "The node to list images on. Defaults to all running nodes.")
 	imageCmd.AddCommand(listImageCmd)
+	saveImageCmd.Flags().StringVarP(&imgNode, "node", "n", "", "The node to save the image from. Defaults to the first running node that has the image.")
+	imageCmd.AddCommand(saveImageCmd)
+	imageCmd.AddCommand(tagImageCmd)
+	imageCmd.AddCommand(pushImageCmd)
+	imageCmd.AddCommand(pullImageCmd)
 }
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/minikube/pkg/minikube/cruntime"
+)
+
+func TestListImagesOptions(t *testing.T) {
+	tests := []struct {
+		filters []string
+		want    cruntime.ListImagesOptions
+		wantErr bool
+	}{
+		{nil, cruntime.ListImagesOptions{}, false},
+		{[]string{"reference=k8s.gcr.io/*"}, cruntime.ListImagesOptions{Reference: "k8s.gcr.io/*"}, false},
+		{[]string{"dangling=true", "reference=busybox"}, cruntime.ListImagesOptions{Reference: "busybox", Dangling: true}, false},
+		{[]string{"dangling=maybe"}, cruntime.ListImagesOptions{}, true},
+		{[]string{"reference"}, cruntime.ListImagesOptions{}, true},
+		{[]string{"label=foo"}, cruntime.ListImagesOptions{}, true},
+	}
+	for _, tc := range tests {
+		got, err := listImagesOptions(tc.filters)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("listImagesOptions(%q) expected error, got nil", tc.filters)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("listImagesOptions(%q) unexpected error: %v", tc.filters, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("listImagesOptions(%q) = %+v, want %+v", tc.filters, got, tc.want)
+		}
+	}
+}
+
+func TestImageInfos(t *testing.T) {
+	busybox := cruntime.ListImage{ID: "sha256:busybox", RepoTags: []string{"docker.io/library/busybox:latest"}, RepoDigests: []string{}, Size: 1240000}
+	tagged := cruntime.ListImage{ID: "sha256:busybox", RepoTags: []string{"docker.io/library/busybox:test"}, RepoDigests: []string{}, Size: 1240000}
+	pause := cruntime.ListImage{ID: "sha256:pause", RepoTags: []string{"k8s.gcr.io/pause:3.4.1"}, RepoDigests: []string{}, Size: 683000}
+
+	images := map[string][]cruntime.ListImage{
+		"minikube":     {busybox, pause},
+		"minikube-m02": {tagged},
+	}
+	want := []*imageInfo{
+		{
+			ListImage: cruntime.ListImage{ID: "sha256:pause", RepoTags: []string{"k8s.gcr.io/pause:3.4.1"}, RepoDigests: []string{}, Size: 683000},
+			Nodes:     []string{"minikube"},
+		},
+		{
+			ListImage: cruntime.ListImage{ID: "sha256:busybox", RepoTags: []string{"docker.io/library/busybox:latest", "docker.io/library/busybox:test"}, RepoDigests: []string{}, Size: 1240000},
+			Nodes:     []string{"minikube", "minikube-m02"},
+		},
+	}
+	got := imageInfos(images)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("imageInfos() unexpected results, diff (-want +got): %s", diff)
+	}
+	// the input must not be modified when merging tags
+	if len(images["minikube"][0].RepoTags) != 1 {
+		t.Errorf("imageInfos() modified its input: %v", images["minikube"][0].RepoTags)
+	}
+}
@@ -21,18 +21,23 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mount"
 	"k8s.io/minikube/pkg/minikube/mustload"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
@@ -45,6 +50,8 @@ const (
 	nineP               = "9p"
 	defaultMountVersion = "9p2000.L"
 	defaultMsize        = 262144
+	// remountInterval is how often a mount is checked, to mount it again after a reboot of the node
+	remountInterval = 10 * time.Second
 )
 
 // placeholders for flag values
@@ -58,6 +65,8 @@ var (
 	mSize        int
 	options      []string
 	mode         uint
+	background   bool
+	watchMount   bool
 )
 
 // supportedFilesystems is a map of filesystem types to not warn against.
@@ -67,7 +76,9 @@ var supportedFilesystems = map[string]bool{nineP: true}
 var mountCmd = &cobra.Command{
 	Use:   "mount [flags] <source directory>:<target directory>",
 	Short: "Mounts the specified directory into minikube",
-	Long:  `Mounts the specified directory into minikube.`,
+	Long: `Mounts the specified directory into minikube.
+
+With --background, the mount is saved in the profile and served in the background. Background mounts are listed by "minikube mount list", stopped by "minikube mount stop", and re-established by "minikube start".`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if isKill {
 			if err := killMountProcess(); err != nil {
@@ -107,6 +118,30 @@ var mountCmd = &cobra.Command{
 			exit.Message(reason.Usage, `'none' driver does not support 'minikube mount' command`)
 		}
 
+		if background {
+			if mountIP != "" && net.ParseIP(mountIP) == nil {
+				exit.Message(reason.IfMountIP, "error parsing the input ip address for mount")
+			}
+			source, err := filepath.Abs(hostPath)
+			if err != nil {
+				exit.Error(reason.HostPathStat, "Unable to get the absolute path of the mount", err)
+			}
+			startBackgroundMount(co.Config, config.MountConfig{
+				Source:  source,
+				Target:  vmPath,
+				IP:      mountIP,
+				Type:    mountType,
+				UID:     uid,
+				GID:     gid,
+				Version: mountVersion,
+				MSize:   mSize,
+				Mode:    uint32(mode),
+				Options: options,
+				Watch:   watchMount,
+			})
+			return
+		}
+
 		var ip net.IP
 		var err error
 		if mountIP == "" {
@@ -194,6 +229,16 @@ var mountCmd = &cobra.Command{
 			exit.Error(reason.GuestMount, "mount failed", err)
 		}
 		out.Step(style.Success, "Successfully mounted {{.sourcePath}} to {{.destinationPath}}", out.V{"sourcePath": hostPath, "destinationPath": vmPath})
+		go remountOnReboot(co, ip.String(), vmPath, cfg)
+		if watchMount {
+			out.Infof("Propagating the changes of {{.path}} to the guest", out.V{"path": hostPath})
+			go func() {
+				runner := func() command.Runner { return controlPlaneRunner(co.API, co.Config) }
+				if err := mount.Watch(hostPath, vmPath, runner, nil); err != nil {
+					out.FailureT("Failed to watch {{.path}}: {{.error}}", out.V{"path": hostPath, "error": err})
+				}
+			}()
+		}
 		out.Ln("")
 		out.Styled(style.Notice, "NOTE: This process must stay alive for the mount to be accessible ...")
 		wg.Wait()
@@ -210,6 +255,66 @@ func init() {
 	mountCmd.Flags().UintVar(&mode, "mode", 0o755, "File permissions used for the mount")
 	mountCmd.Flags().StringSliceVar(&options, "options", []string{}, "Additional mount options, such as cache=fscache")
 	mountCmd.Flags().IntVar(&mSize, "msize", defaultMsize, "The number of bytes to use for 9p packet payload")
+	mountCmd.Flags().BoolVar(&watchMount, "watch", false, "Touch the files changed on the host inside the guest, so that file watchers in the guest are notified")
+	mountCmd.Flags().BoolVar(&background, "background", false, "Save the mount in the profile and serve it in the background, it is re-established by 'minikube start'")
+}
+
+// startBackgroundMount saves m in the cluster config, replacing the mount of the same target, and starts its mount server
+func startBackgroundMount(cc *config.ClusterConfig, m config.MountConfig) {
+	if i := mount.Find(cc.Mounts, m.Target); i != -1 {
+		if err := mount.Stop(cc.Name, m.Target); err != nil {
+			exit.Error(reason.HostKillMountProc, "Error stopping the previous mount", err)
+		}
+		cc.Mounts[i] = m
+	} else {
+		cc.Mounts = append(cc.Mounts, m)
+	}
+	if err := config.SaveProfile(cc.Name, cc); err != nil {
+		exit.Error(reason.HostSaveProfile, "Failed to save config", err)
+	}
+
+	pid, err := mount.Start(cc.Name, m)
+	if err != nil {
+		exit.Error(reason.GuestMount, "Error starting mount", err)
+	}
+	out.Step(style.Mounting, "Mounting host path {{.sourcePath}} into VM as {{.destinationPath}} in the background (pid {{.pid}})", out.V{"sourcePath": m.Source, "destinationPath": m.Target, "pid": pid})
+	out.Infof("Logs are written to {{.path}}", out.V{"path": mount.LogFile(cc.Name, m.Target)})
+}
+
+// restoreMounts starts the background mount servers of the cluster which are not running, such as after a reboot of the host
+func restoreMounts(cc *config.ClusterConfig) {
+	for _, st := range mount.List(cc) {
+		if st.Running {
+			continue
+		}
+		out.Step(style.Mounting, "Restoring mount {{.name}} ...", out.V{"name": mount.String(st.MountConfig)})
+		if _, err := mount.Start(cc.Name, st.MountConfig); err != nil {
+			out.FailureT("Failed to restore mount {{.name}}: {{.error}}", out.V{"name": mount.String(st.MountConfig), "error": err})
+		}
+	}
+}
+
+// remountOnReboot periodically checks that target is mounted in the control plane, and mounts it again
+// when it is not, such as after a reboot of the node
+func remountOnReboot(co mustload.ClusterController, source string, target string, cfg *cluster.MountConfig) {
+	for range time.Tick(remountInterval) {
+		r := controlPlaneRunner(co.API, co.Config)
+		if r == nil {
+			continue
+		}
+		mounted, err := cluster.Mounted(r, target)
+		if err != nil {
+			klog.Warningf("unable to check mount %s: %v", target, err)
+			continue
+		}
+		if mounted {
+			continue
+		}
+		out.Step(style.Mounting, "Mounting {{.path}} again, as it is no longer mounted ...", out.V{"path": target})
+		if err := cluster.Mount(r, source, target, cfg); err != nil {
+			out.FailureT("Failed to mount {{.path}}: {{.error}}", out.V{"path": target, "error": err})
+		}
+	}
 }
 
 // getPort asks the kernel for a free open port that is ready to use
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mount"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var mountOutput string
+
+var mountListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the background mounts of the cluster",
+	Long:  "List the mounts started with 'minikube mount --background', and whether their mount server is running.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube mount list")
+		}
+
+		cname := ClusterFlagValue()
+		_, cc := mustload.Partial(cname)
+		mounts := mount.List(cc)
+
+		switch strings.ToLower(mountOutput) {
+		case "table":
+			if len(mounts) == 0 {
+				out.Styled(style.Empty, `No background mounts found for profile "{{.profile}}"`, out.V{"profile": cname})
+				return
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Source", "Target", "Type", "Status", "PID"})
+			table.SetAutoFormatHeaders(false)
+			table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+			table.SetCenterSeparator("|")
+			for _, m := range mounts {
+				status, pid := "Stopped", ""
+				if m.Running {
+					status, pid = "Running", strconv.Itoa(m.PID)
+				}
+				table.Append([]string{m.Source, m.Target, m.Type, status, pid})
+			}
+			table.Render()
+		case "json":
+			b, err := json.Marshal(mounts)
+			if err != nil {
+				exit.Error(reason.InternalJSONMarshal, "Unable to marshal mounts to JSON", err)
+			}
+			out.String("%s\n", string(b))
+		default:
+			exit.Message(reason.Usage, fmt.Sprintf("invalid output format: %s. Valid values: 'table', 'json'", mountOutput))
+		}
+	},
+}
+
+func init() {
+	mountListCmd.Flags().StringVarP(&mountOutput, "output", "o", "table", "The output format. One of 'json', 'table'")
+	mountCmd.AddCommand(mountListCmd)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mount"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var stopAllMounts bool
+
+var mountStopCmd = &cobra.Command{
+	Use:     "stop [TARGET_DIRECTORY]",
+	Short:   "Stop background mounts of the cluster",
+	Long:    "Stop the mount server of a background mount, unmount it, and remove it from the profile so that it is not re-established by 'minikube start'.",
+	Example: "minikube mount stop /vm-home\nminikube mount stop --all",
+	Run: func(cmd *cobra.Command, args []string) {
+		if (len(args) != 1) == !stopAllMounts {
+			exit.Message(reason.Usage, "Usage: minikube mount stop [TARGET_DIRECTORY | --all]")
+		}
+
+		api, cc := mustload.Partial(ClusterFlagValue())
+		var targets []string
+		if stopAllMounts {
+			for _, m := range cc.Mounts {
+				targets = append(targets, m.Target)
+			}
+		} else {
+			if mount.Find(cc.Mounts, args[0]) == -1 {
+				exit.Message(reason.Usage, `No background mount of "{{.path}}" found for profile "{{.profile}}"`, out.V{"path": args[0], "profile": cc.Name})
+			}
+			targets = append(targets, args[0])
+		}
+
+		runner := controlPlaneRunner(api, cc)
+		for _, target := range targets {
+			if err := mount.Stop(cc.Name, target); err != nil {
+				exit.Error(reason.HostKillMountProc, "Error stopping mount", err)
+			}
+			if runner != nil {
+				if err := cluster.Unmount(runner, target); err != nil {
+					out.FailureT("Failed unmount: {{.error}}", out.V{"error": err})
+				}
+			}
+			i := mount.Find(cc.Mounts, target)
+			cc.Mounts = append(cc.Mounts[:i], cc.Mounts[i+1:]...)
+			out.Step(style.Unmount, "Stopped mount of {{.path}}", out.V{"path": target})
+		}
+		if err := config.SaveProfile(cc.Name, cc); err != nil {
+			exit.Error(reason.HostSaveProfile, "Failed to save config", err)
+		}
+	},
+}
+
+func init() {
+	mountStopCmd.Flags().BoolVar(&stopAllMounts, "all", false, "Stop all the background mounts of the cluster")
+	mountCmd.AddCommand(mountStopCmd)
+}
+
+// controlPlaneRunner returns a command runner of the control plane, or nil if it is not running
+func controlPlaneRunner(api libmachine.API, cc *config.ClusterConfig) command.Runner {
+	cp, err := config.PrimaryControlPlane(cc)
+	if err != nil {
+		klog.Warningf("unable to find control plane: %v", err)
+		return nil
+	}
+	machineName := config.MachineName(*cc, cp)
+	if st, err := machine.Status(api, machineName); err != nil || st != state.Running.String() {
+		klog.Infof("control plane is not running, status: %q, error: %v", st, err)
+		return nil
+	}
+	h, err := machine.LoadHost(api, machineName)
+	if err != nil {
+		klog.Warningf("unable to load host %s: %v", machineName, err)
+		return nil
+	}
+	r, err := machine.CommandRunner(h)
+	if err != nil {
+		klog.Warningf("unable to get command runner for %s: %v", machineName, err)
+		return nil
+	}
+	return r
+}
@@ -17,8 +17,11 @@ limitations under the License.
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/minikube/cni"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
@@ -28,11 +31,19 @@ import (
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
 	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util"
 )
 
 var (
-	cp     bool
-	worker bool
+	cp            bool
+	worker        bool
+	nodeIPAddr    string
+	addNodeLabels []string
+	addNodeTaints []string
+
+	addNodeCPUs     int
+	addNodeMemory   string
+	addNodeDiskSize string
 )
 
 var nodeAddCmd = &cobra.Command{
@@ -47,6 +58,45 @@ var nodeAddCmd = &cobra.Command{
 			out.FailureT("none driver does not support multi-node clusters")
 		}
 
+		// the other nodes reach the control planes through the virtual IP of HA clusters
+		if cp && !config.IsHA(*cc) {
+			exit.Message(reason.Usage, `Control-plane nodes can only be added to HA clusters, created with "minikube start --ha"`)
+		}
+
+		if nodeIPAddr != "" {
+			if !driver.IsKIC(cc.Driver) {
+				exit.Message(reason.Usage, "The --static-ip flag is only supported by the docker and podman drivers")
+			}
+			if err := oci.ValidateStaticIP(nodeIPAddr, cc.Subnet); err != nil {
+				exit.Message(reason.Usage, "Sorry, the IP provided with the --static-ip flag is invalid: {{.error}}", out.V{"error": err})
+			}
+			for _, n := range cc.Nodes {
+				if n.IP == nodeIPAddr || n.StaticIP == nodeIPAddr {
+					exit.Message(reason.Usage, "The IP {{.ip}} is already used by another node of the cluster", out.V{"ip": nodeIPAddr})
+				}
+			}
+		}
+
+		labels, err := config.ParseNodeLabels(addNodeLabels)
+		if err != nil {
+			exit.Message(reason.Usage, "{{.error}}", out.V{"error": err})
+		}
+		if err := config.ValidateNodeTaints(addNodeTaints); err != nil {
+			exit.Message(reason.Usage, "{{.error}}", out.V{"error": err})
+		}
+
+		if (addNodeCPUs != 0 || addNodeMemory != "" || addNodeDiskSize != "") && !driver.IsKIC(cc.Driver) && !driver.IsKVM(cc.Driver) {
+			exit.Message(reason.Usage, "The --cpus, --memory and --disk-size flags are only supported by the docker, podman and kvm2 drivers")
+		}
+		if driver.IsKIC(cc.Driver) && addNodeDiskSize != "" {
+			out.WarningT("The {{.driver}} driver does not limit the disk size of the nodes, --disk-size is ignored", out.V{"driver": cc.Driver})
+			addNodeDiskSize = ""
+		}
+		nodeCPUs, nodeMemory, nodeDiskSize, err := parseNodeResources(addNodeCPUs, addNodeMemory, addNodeDiskSize)
+		if err != nil {
+			exit.Message(reason.Usage, "{{.error}}", out.V{"error": err})
+		}
+
 		name := node.Name(len(cc.Nodes) + 1)
 
 		out.Step(style.Happy, "Adding node {{.name}} to cluster {{.cluster}}", out.V{"name": name, "cluster": cc.Name})
@@ -57,6 +107,12 @@ var nodeAddCmd = &cobra.Command{
 			Worker:            worker,
 			ControlPlane:      cp,
 			KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
+			StaticIP:          nodeIPAddr,
+			Labels:            labels,
+			Taints:            addNodeTaints,
+			CPUs:              nodeCPUs,
+			Memory:            nodeMemory,
+			DiskSize:          nodeDiskSize,
 		}
 
 		// Make sure to decrease the default amount of memory we use per VM if this is the first worker node
@@ -85,10 +141,46 @@ var nodeAddCmd = &cobra.Command{
 	},
 }
 
+// parseNodeResources parses the CPUs, memory and disk size requested for a node, unset ones are 0
+func parseNodeResources(cpuCount int, mem string, diskSize string) (int, int, int, error) {
+	if cpuCount != 0 && cpuCount < minimumCPUS {
+		return 0, 0, 0, fmt.Errorf("requested cpu count %d is less than the minimum allowed of %d", cpuCount, minimumCPUS)
+	}
+	memMB := 0
+	if mem != "" {
+		mb, err := util.CalculateSizeInMB(mem)
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("invalid memory size %q: %v", mem, err)
+		}
+		if mb < minUsableMem {
+			return 0, 0, 0, fmt.Errorf("requested memory allocation %dMB is less than the usable minimum of %dMB", mb, minUsableMem)
+		}
+		memMB = mb
+	}
+	diskMB := 0
+	if diskSize != "" {
+		mb, err := util.CalculateSizeInMB(diskSize)
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("invalid disk size %q: %v", diskSize, err)
+		}
+		if mb < minimumDiskSize {
+			return 0, 0, 0, fmt.Errorf("requested disk size %dMB is less than the minimum of %dMB", mb, minimumDiskSize)
+		}
+		diskMB = mb
+	}
+	return cpuCount, memMB, diskMB, nil
+}
+
 func init() {
 	// TODO(https://github.com/kubernetes/minikube/issues/7366): We should figure out which minikube start flags to actually import
-	nodeAddCmd.Flags().BoolVar(&cp, "control-plane", false, "If true, the node added will also be a control plane in addition to a worker.")
+	nodeAddCmd.Flags().BoolVar(&cp, "control-plane", false, "If true, the node added will also be a control plane in addition to a worker. The cluster must be created with \"minikube start --ha\".")
 	nodeAddCmd.Flags().BoolVar(&worker, "worker", true, "If true, the added node will be marked for work. Defaults to true.")
+	nodeAddCmd.Flags().StringVar(&nodeIPAddr, staticIP, "", "Static IP of the node on the network of the cluster (docker and podman drivers only). If left empty, the next free address is used.")
+	nodeAddCmd.Flags().StringSliceVar(&addNodeLabels, nodeLabels, nil, "Kubernetes labels of the node, formatted as key=value, may be repeated. Applied at every start, a node-role.kubernetes.io/<role>= label gives the node a role.")
+	nodeAddCmd.Flags().StringSliceVar(&addNodeTaints, nodeTaints, nil, "Kubernetes taints of the node, formatted as key[=value]:effect where effect is NoSchedule, PreferNoSchedule or NoExecute, may be repeated.")
+	nodeAddCmd.Flags().IntVar(&addNodeCPUs, cpus, 0, "Number of CPUs allocated to the node (docker, podman and kvm2 drivers only). Defaults to the one of the cluster.")
+	nodeAddCmd.Flags().StringVar(&addNodeMemory, memory, "", "Amount of RAM allocated to the node (docker, podman and kvm2 drivers only, format: <number>[<unit>], where unit = b, k, m or g). Defaults to the one of the cluster.")
+	nodeAddCmd.Flags().StringVar(&addNodeDiskSize, humanReadableDiskSize, "", "Disk size allocated to the node (kvm2 driver only, format: <number>[<unit>], where unit = b, k, m or g). Defaults to the one of the cluster.")
 	nodeAddCmd.Flags().Bool(deleteOnFailure, false, "If set, delete the current cluster if start fails and try again. Defaults to false.")
 
 	nodeCmd.AddCommand(nodeAddCmd)
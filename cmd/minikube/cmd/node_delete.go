@@ -43,6 +43,9 @@ var nodeDeleteCmd = &cobra.Command{
 		name := args[0]
 
 		co := mustload.Healthy(ClusterFlagValue())
+		if n, _, err := node.Retrieve(*co.Config, name); err == nil && config.IsPrimaryControlPlane(*co.Config, *n) {
+			exit.Message(reason.Usage, `The primary control-plane node cannot be deleted, run "minikube delete" to delete the cluster`)
+		}
 		out.Step(style.DeletingHost, "Deleting node {{.name}} from cluster {{.cluster}}", out.V{"name": name, "cluster": co.Config.Name})
 
 		n, err := node.Delete(*co.Config, name)
@@ -23,6 +23,7 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/mustload"
 	"k8s.io/minikube/pkg/minikube/reason"
@@ -31,7 +32,7 @@ import (
 var nodeListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List nodes.",
-	Long:  "List existing minikube nodes.",
+	Long:  "List existing minikube nodes, with their IP, CPUs, memory and disk size.",
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) != 0 {
 			exit.Message(reason.Usage, "Usage: minikube node list")
@@ -48,7 +49,12 @@ var nodeListCmd = &cobra.Command{
 
 		for _, n := range cc.Nodes {
 			machineName := config.MachineName(*cc, n)
-			fmt.Printf("%s\t%s\n", machineName, n.IP)
+			resources := fmt.Sprintf("%d CPUs\t%dMB memory", config.NodeCPUs(*cc, n), config.NodeMemory(*cc, n))
+			// the containers of the kic drivers share the disk of the host
+			if !driver.IsKIC(cc.Driver) {
+				resources += fmt.Sprintf("\t%dMB disk", config.NodeDiskSize(*cc, n))
+			}
+			fmt.Printf("%s\t%s\t%s\n", machineName, n.IP, resources)
 		}
 		os.Exit(0)
 	},
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/portforward"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// portCmd represents the set of port subcommands
+var portCmd = &cobra.Command{
+	Use:   "port",
+	Short: "Forward ports of the host to the nodes of a running cluster",
+	Long: `Forward ports of the host to the nodes of a running cluster, without recreating them as --ports requires (docker and podman drivers only).
+Each port is forwarded by a proxy container, which is stopped by "minikube stop" and started again by "minikube start".`,
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube port [add|remove|list]")
+	},
+}
+
+// validatePortForwardDriver exits unless the driver of the cluster supports port forwards
+func validatePortForwardDriver(cc *config.ClusterConfig) {
+	if !driver.IsKIC(cc.Driver) {
+		exit.Message(reason.DrvUnsupportedPortForward, `The "{{.driver}}" driver does not support forwarding ports, only the docker and podman drivers do`, out.V{"driver": cc.Driver})
+	}
+}
+
+// restorePortForwards starts the proxy containers of the port forwards of a cluster again
+func restorePortForwards(cc *config.ClusterConfig) {
+	if !driver.IsKIC(cc.Driver) {
+		return
+	}
+	for _, st := range portforward.List(cc) {
+		if st.Running {
+			continue
+		}
+		out.Step(style.Connectivity, "Restoring port forward {{.port}} ...", out.V{"port": portforward.String(st.PortForward)})
+		if err := portforward.Start(cc, st.PortForward); err != nil {
+			out.FailureT("Failed to restore port forward {{.port}}: {{.error}}", out.V{"port": portforward.String(st.PortForward), "error": err})
+		}
+	}
+}
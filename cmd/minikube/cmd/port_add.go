@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/portforward"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var portAddCmd = &cobra.Command{
+	Use:   "add [LISTEN_ADDRESS:]HOST_PORT:NODE_PORT[/PROTOCOL]...",
+	Short: "Forward ports of the host to a node of the cluster",
+	Long: `Forward ports of the host to a node of the cluster, the primary control plane by default.
+The protocol is tcp or udp, tcp by default. The ports listen on the --listen-address of the cluster, 127.0.0.1 by default.`,
+	Example: "minikube port add 8080:30080\nminikube port add -n minikube-m02 0.0.0.0:5353:53/udp",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Usage: minikube port add [LISTEN_ADDRESS:]HOST_PORT:NODE_PORT[/PROTOCOL]...")
+		}
+
+		co := mustload.Running(ClusterFlagValue())
+		cc := co.Config
+		validatePortForwardDriver(cc)
+
+		if nodeName != "" {
+			if _, _, err := node.Retrieve(*cc, nodeName); err != nil {
+				exit.Message(reason.GuestNodeRetrieve, "Node {{.nodeName}} does not exist.", out.V{"nodeName": nodeName})
+			}
+		}
+
+		var pfs []config.PortForward
+		for _, arg := range args {
+			pf, err := portforward.Parse(arg)
+			if err != nil {
+				exit.Message(reason.Usage, "Invalid port forward: {{.error}}", out.V{"error": err})
+			}
+			if portforward.Find(cc.PortForwards, pf.HostPort, pf.Protocol) != -1 || portforward.Find(pfs, pf.HostPort, pf.Protocol) != -1 {
+				exit.Message(reason.Usage, "The host port {{.port}}/{{.protocol}} is already forwarded", out.V{"port": pf.HostPort, "protocol": pf.Protocol})
+			}
+			pf.Node = nodeName
+			pfs = append(pfs, pf)
+		}
+
+		for _, pf := range pfs {
+			out.Step(style.Connectivity, "Forwarding port {{.port}} ...", out.V{"port": portforward.String(pf)})
+			if err := portforward.Start(cc, pf); err != nil {
+				exit.Error(reason.GuestPortForward, "Unable to forward the port", err)
+			}
+			cc.PortForwards = append(cc.PortForwards, pf)
+			if err := config.SaveProfile(cc.Name, cc); err != nil {
+				exit.Error(reason.HostSaveProfile, "Unable to save the cluster config", err)
+			}
+		}
+	},
+}
+
+func init() {
+	portAddCmd.Flags().StringVarP(&nodeName, "node", "n", "", "The node to forward the ports to. Defaults to the primary control plane.")
+	portCmd.AddCommand(portAddCmd)
+}
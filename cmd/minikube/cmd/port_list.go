@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/portforward"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var portOutput string
+
+var portListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the forwarded ports of the cluster",
+	Long:  "List the ports of the host forwarded with 'minikube port add', and whether their proxy container is running.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube port list")
+		}
+
+		api, cc := mustload.Partial(ClusterFlagValue())
+		defer api.Close()
+		validatePortForwardDriver(cc)
+		pfs := portforward.List(cc)
+
+		switch strings.ToLower(portOutput) {
+		case "table":
+			if len(pfs) == 0 {
+				out.Styled(style.Empty, `No forwarded ports found for profile "{{.profile}}"`, out.V{"profile": cc.Name})
+				return
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Host Port", "Node", "Node Port", "Protocol", "Status"})
+			table.SetAutoFormatHeaders(false)
+			table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+			table.SetCenterSeparator("|")
+			for _, pf := range pfs {
+				host := strconv.Itoa(pf.HostPort)
+				if pf.ListenAddress != "" {
+					host = pf.ListenAddress + ":" + host
+				}
+				node := pf.Node
+				if node == "" {
+					cp, err := config.PrimaryControlPlane(cc)
+					if err == nil {
+						node = config.MachineName(*cc, cp)
+					}
+				}
+				status := "Stopped"
+				if pf.Running {
+					status = "Running"
+				}
+				table.Append([]string{host, node, strconv.Itoa(pf.NodePort), pf.Protocol, status})
+			}
+			table.Render()
+		case "json":
+			b, err := json.Marshal(pfs)
+			if err != nil {
+				exit.Error(reason.InternalJSONMarshal, "Unable to marshal port forwards to JSON", err)
+			}
+			out.String("%s\n", string(b))
+		default:
+			exit.Message(reason.Usage, fmt.Sprintf("invalid output format: %s. Valid values: 'table', 'json'", portOutput))
+		}
+	},
+}
+
+func init() {
+	portListCmd.Flags().StringVarP(&portOutput, "output", "o", "table", "The output format. One of 'json', 'table'")
+	portCmd.AddCommand(portListCmd)
+}
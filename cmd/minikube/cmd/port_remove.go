@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/portforward"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var portRemoveCmd = &cobra.Command{
+	Use:     "remove HOST_PORT[/PROTOCOL]...",
+	Short:   "Stop forwarding ports of the host to the cluster",
+	Long:    "Remove the proxy containers of forwarded ports of the host, and remove them from the profile so that they are not started again by 'minikube start'.",
+	Example: "minikube port remove 8080\nminikube port remove 5353/udp",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Usage: minikube port remove HOST_PORT[/PROTOCOL]...")
+		}
+
+		api, cc := mustload.Partial(ClusterFlagValue())
+		defer api.Close()
+		validatePortForwardDriver(cc)
+
+		for _, arg := range args {
+			port, protocol := arg, "tcp"
+			if i := strings.LastIndex(arg, "/"); i >= 0 {
+				port, protocol = arg[:i], strings.ToLower(arg[i+1:])
+			}
+			hostPort, err := strconv.Atoi(port)
+			if err != nil {
+				exit.Message(reason.Usage, "Invalid host port {{.port}}", out.V{"port": arg})
+			}
+			i := portforward.Find(cc.PortForwards, hostPort, protocol)
+			if i == -1 {
+				exit.Message(reason.Usage, `The host port {{.port}} is not forwarded for profile "{{.profile}}"`, out.V{"port": arg, "profile": cc.Name})
+			}
+
+			pf := cc.PortForwards[i]
+			out.Step(style.Deleted, "Removing port forward {{.port}} ...", out.V{"port": portforward.String(pf)})
+			if err := portforward.Remove(cc, pf); err != nil {
+				exit.Error(reason.GuestPortForward, "Unable to remove the port forward", err)
+			}
+			cc.PortForwards = append(cc.PortForwards[:i:i], cc.PortForwards[i+1:]...)
+			if err := config.SaveProfile(cc.Name, cc); err != nil {
+				exit.Error(reason.HostSaveProfile, "Unable to save the cluster config", err)
+			}
+		}
+	},
+}
+
+func init() {
+	portCmd.AddCommand(portRemoveCmd)
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var (
+	registryMirrors  []string
+	registryUsername string
+	registryPassword string
+	registryToken    string
+	registryCACert   string
+
+	registryPasswordStdin bool
+	registryTokenStdin    bool
+)
+
+// registriesCmd represents the registries command
+var registriesCmd = &cobra.Command{
+	Use:   "registries",
+	Short: "Configure the mirrors, credentials and CA certificates of container registries",
+	Long: `Configure the mirrors, credentials and CA certificates of the container registries the nodes pull images from.
+The config is saved to the profile and applied to the container runtime of all the nodes, at start and on change.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube registries [set|unset|list]")
+	},
+}
+
+// registriesSetCmd represents the registries set command
+var registriesSetCmd = &cobra.Command{
+	Use:   "set HOST",
+	Short: "Set the mirrors, credentials and CA certificates of a registry",
+	Long: `Set the mirrors, credentials and CA certificates of a registry, replacing its previous config.
+HOST is the host and optional port of the registry, docker.io for Docker Hub. Mirrors are tried in order before the registry.
+The CA certificates verify the registry and its mirrors. A token is an identity token, which the docker runtime does not support.
+The docker runtime only supports the mirrors of docker.io.`,
+	Example: `minikube registries set docker.io --mirror https://mirror.gcr.io
+minikube registries set registry.example.com:5000 --username user --password-stdin --ca-cert ca.pem < password.txt
+echo "$QUAY_TOKEN" | minikube registries set quay.io --mirror https://quay-mirror.example.com --token-stdin`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if registryPasswordStdin && registryTokenStdin {
+			exit.Message(reason.Usage, "Only one of --password-stdin and --token-stdin can be set")
+		}
+		if registryPasswordStdin {
+			if registryPassword != "" {
+				exit.Message(reason.Usage, "--password and --password-stdin are mutually exclusive")
+			}
+			registryPassword = readSecretStdin(os.Stdin)
+		}
+		if registryTokenStdin {
+			if registryToken != "" {
+				exit.Message(reason.Usage, "--token and --token-stdin are mutually exclusive")
+			}
+			registryToken = readSecretStdin(os.Stdin)
+		}
+
+		r, err := newRegistry(args[0], registryMirrors, registryUsername, registryPassword, registryToken, registryCACert)
+		if err != nil {
+			exit.Message(reason.Usage, "Invalid registry config: {{.error}}", out.V{"error": err})
+		}
+
+		api, cc := mustload.Partial(ClusterFlagValue())
+		defer api.Close()
+		if r.Token != "" && cc.KubernetesConfig.ContainerRuntime == "docker" {
+			exit.Message(reason.Usage, "The docker runtime does not support tokens, please use --username and --password")
+		}
+		if len(r.Mirrors) > 0 && r.Host != "docker.io" && cc.KubernetesConfig.ContainerRuntime == "docker" {
+			exit.Message(reason.Usage, "The docker runtime only supports mirrors of docker.io, not of {{.host}}", out.V{"host": r.Host})
+		}
+
+		regs := []config.Registry{}
+		for _, reg := range cc.Registries {
+			if reg.Host != r.Host {
+				regs = append(regs, reg)
+			}
+		}
+		applyRegistries(api, cc, append(regs, r))
+		out.Step(style.Check, `The registry "{{.host}}" was configured`, out.V{"host": r.Host})
+	},
+}
+
+// registriesUnsetCmd represents the registries unset command
+var registriesUnsetCmd = &cobra.Command{
+	Use:     "unset HOST",
+	Short:   "Remove the config of a registry",
+	Example: "minikube registries unset registry.example.com:5000",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		api, cc := mustload.Partial(ClusterFlagValue())
+		defer api.Close()
+
+		regs := []config.Registry{}
+		for _, reg := range cc.Registries {
+			if reg.Host != args[0] {
+				regs = append(regs, reg)
+			}
+		}
+		if len(regs) == len(cc.Registries) {
+			exit.Message(reason.Usage, `The registry "{{.host}}" is not configured, see "minikube registries list"`, out.V{"host": args[0]})
+		}
+		applyRegistries(api, cc, regs)
+		out.Step(style.Deleted, `The config of the registry "{{.host}}" was removed`, out.V{"host": args[0]})
+	},
+}
+
+// registriesListCmd represents the registries list command
+var registriesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the configured registries, without their secrets",
+	Run: func(cmd *cobra.Command, args []string) {
+		_, cc := mustload.Partial(ClusterFlagValue())
+		if len(cc.Registries) == 0 {
+			out.Styled(style.Empty, `No registries configured for profile "{{.profile}}"`, out.V{"profile": cc.Name})
+			return
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Host", "Mirrors", "Auth", "CA Certificates"})
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, r := range cc.Registries {
+			auth := "none"
+			if r.Username != "" {
+				auth = "basic (" + r.Username + ")"
+			} else if r.Token != "" {
+				auth = "token"
+			}
+			ca := "no"
+			if r.CACert != "" {
+				ca = "yes"
+			}
+			table.Append([]string{r.Host, strings.Join(r.Mirrors, ", "), auth, ca})
+		}
+		table.Render()
+	},
+}
+
+// newRegistry validates the config of a registry
+func newRegistry(host string, mirrors []string, username, password, token, caFile string) (config.Registry, error) {
+	r := config.Registry{Host: host, Username: username, Password: password, Token: token}
+	if host == "" || strings.Contains(host, "/") {
+		return r, fmt.Errorf("%q is not a registry host, expected HOST[:PORT]", host)
+	}
+	for _, m := range mirrors {
+		u, err := url.Parse(m)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return r, fmt.Errorf("%q is not a mirror URL, expected http(s)://HOST[:PORT][/PATH]", m)
+		}
+		r.Mirrors = append(r.Mirrors, strings.TrimSuffix(m, "/"))
+	}
+	if (username == "") != (password == "") {
+		return r, fmt.Errorf("--username and --password must be set together")
+	}
+	if username != "" && token != "" {
+		return r, fmt.Errorf("--token can not be set with --username and --password")
+	}
+	if caFile != "" {
+		b, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return r, err
+		}
+		if block, _ := pem.Decode(b); block == nil || block.Type != "CERTIFICATE" {
+			return r, fmt.Errorf("%s does not contain PEM encoded certificates", caFile)
+		}
+		r.CACert = string(b)
+	}
+	if len(r.Mirrors) == 0 && username == "" && token == "" && caFile == "" {
+		return r, fmt.Errorf("set at least one of --mirror, --username and --password, --token or --ca-cert")
+	}
+	return r, nil
+}
+
+// applyRegistries saves the registries to the cluster config and applies them to the running nodes
+func applyRegistries(api libmachine.API, cc *config.ClusterConfig, regs []config.Registry) {
+	dockerHubMirrors := fmt.Sprint(registryHubMirrors(cc.Registries))
+	cc.Registries = regs
+	if err := config.SaveProfile(cc.Name, cc); err != nil {
+		exit.Error(reason.HostSaveProfile, "Unable to save the cluster config", err)
+	}
+
+	for _, n := range cc.Nodes {
+		machineName := config.MachineName(*cc, n)
+		st, err := machine.Status(api, machineName)
+		if err != nil || st != state.Running.String() {
+			klog.Infof("not configuring the registries of %s, status: %s, err: %v", machineName, st, err)
+			continue
+		}
+		h, err := machine.GetHost(api, *cc, n)
+		if err != nil {
+			exit.Error(reason.GuestLoadHost, "Error getting host", err)
+		}
+		runner, err := machine.CommandRunner(h)
+		if err != nil {
+			exit.Error(reason.InternalCommandRunner, "Failed to get command runner", err)
+		}
+		out.Step(style.Provisioning, `Configuring the registries of node "{{.name}}" ...`, out.V{"name": machineName})
+		if err := node.ConfigureRegistries(runner, *cc); err != nil {
+			exit.Error(reason.RuntimeRegistries, "Failed to configure the registries", err)
+		}
+	}
+
+	if cc.KubernetesConfig.ContainerRuntime == "docker" && dockerHubMirrors != fmt.Sprint(registryHubMirrors(regs)) {
+		out.WarningT(`The docker runtime loads the mirrors of Docker Hub at start, run "{{.cmd}}" to apply them`, out.V{"cmd": mustload.ExampleCmd(cc.Name, "start")})
+	}
+}
+
+// readSecretStdin reads a password or token from the standard input, so that it does not show in the process list, the shell history or the audit log
+func readSecretStdin(r io.Reader) string {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		exit.Error(reason.Usage, "Unable to read from the standard input", err)
+	}
+	secret := strings.TrimRight(string(b), "\r\n")
+	if secret == "" {
+		exit.Message(reason.Usage, "The standard input is empty")
+	}
+	return secret
+}
+
+// registryHubMirrors returns the mirrors of Docker Hub of the registries
+func registryHubMirrors(regs []config.Registry) []string {
+	for _, r := range regs {
+		if r.Host == cruntime.DockerHub {
+			return r.Mirrors
+		}
+	}
+	return nil
+}
+
+func init() {
+	registriesSetCmd.Flags().StringSliceVar(&registryMirrors, "mirror", nil, "URLs of mirrors of the registry, tried in order before it")
+	registriesSetCmd.Flags().StringVar(&registryUsername, "username", "", "Username to authenticate to the registry with")
+	registriesSetCmd.Flags().StringVar(&registryPassword, "password", "", "Password to authenticate to the registry with")
+	registriesSetCmd.Flags().BoolVar(&registryPasswordStdin, "password-stdin", false, "Read the password from the standard input")
+	registriesSetCmd.Flags().StringVar(&registryToken, "token", "", "Identity token to authenticate to the registry with, instead of a username and password")
+	registriesSetCmd.Flags().BoolVar(&registryTokenStdin, "token-stdin", false, "Read the identity token from the standard input")
+	registriesSetCmd.Flags().StringVar(&registryCACert, "ca-cert", "", "File of PEM encoded CA certificates to verify the registry and its mirrors with")
+
+	registriesCmd.AddCommand(registriesSetCmd)
+	registriesCmd.AddCommand(registriesUnsetCmd)
+	registriesCmd.AddCommand(registriesListCmd)
+}
@@ -78,7 +78,9 @@ var RootCmd = &cobra.Command{
 // Execute adds all child commands to the root command sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	defer audit.Log(time.Now())
+	start := time.Now()
+	audit.SetStartTime(start)
+	defer audit.Log(start)
 
 	// Check whether this is a windows binary (.exe) running inisde WSL.
 	if runtime.GOOS == "windows" && detect.IsMicrosoftWSL() {
@@ -154,6 +156,7 @@ func Execute() {
 
 	if err := RootCmd.Execute(); err != nil {
 		// Cobra already outputs the error, typically because the user provided an unknown command.
+		audit.LogExit(reason.ExProgramUsage, reason.Usage.ID)
 		defer os.Exit(reason.ExProgramUsage)
 	}
 }
@@ -225,6 +228,8 @@ func init() {
 				podmanEnvCmd,
 				cacheCmd,
 				imageCmd,
+				bundleCmd,
+				registriesCmd,
 			},
 		},
 		{
@@ -233,6 +238,8 @@ func init() {
 				configCmd.AddonsCmd,
 				configCmd.ConfigCmd,
 				configCmd.ProfileCmd,
+				certsCmd,
+				snapshotCmd,
 				updateContextCmd,
 			},
 		},
@@ -241,6 +248,7 @@ func init() {
 			Commands: []*cobra.Command{
 				serviceCmd,
 				tunnelCmd,
+				portCmd,
 			},
 		},
 		{
@@ -248,6 +256,7 @@ func init() {
 			Commands: []*cobra.Command{
 				mountCmd,
 				sshCmd,
+				execCmd,
 				kubectlCmd,
 				nodeCmd,
 				cpCmd,
@@ -260,6 +269,8 @@ func init() {
 				sshHostCmd,
 				ipCmd,
 				logsCmd,
+				auditCmd,
+				diagnoseCmd,
 				updateCheckCmd,
 				versionCmd,
 				optionsCmd,
@@ -0,0 +1,168 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/snapshot"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var snapshotOutput string
+
+// snapshotCmd represents the set of snapshot subcommands
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save and restore snapshots of a cluster",
+	Long:  "Save the state of a cluster (node disks, etcd, configuration and certificates) as a snapshot, and restore the cluster to it later.",
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube snapshot [save|restore|list|delete]")
+	},
+}
+
+var snapshotSaveCmd = &cobra.Command{
+	Use:     "save SNAPSHOT_NAME",
+	Short:   "Save a snapshot of the cluster",
+	Long:    "Save a snapshot of the cluster. Containers of the docker and podman drivers are paused while saving, the cluster has to be stopped for the kvm2 driver.",
+	Example: "minikube snapshot save known-good",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube snapshot save SNAPSHOT_NAME")
+		}
+		if err := snapshot.ValidateName(args[0]); err != nil {
+			exit.Message(reason.Usage, err.Error())
+		}
+
+		api, cc := mustload.Partial(ClusterFlagValue())
+		if !snapshot.Supported(cc.Driver) {
+			exit.Message(reason.DrvUnsupportedSnapshot, "Snapshots are not supported by the {{.driver}} driver", out.V{"driver": cc.Driver})
+		}
+
+		out.Step(style.Caching, `Saving snapshot "{{.name}}" of profile "{{.profile}}" ...`, out.V{"name": args[0], "profile": cc.Name})
+		if _, err := snapshot.Save(api, cc, args[0]); err != nil {
+			exit.Error(reason.GuestSnapshot, "Failed to save snapshot", err)
+		}
+		out.Step(style.Success, `Saved snapshot "{{.name}}"`, out.V{"name": args[0]})
+	},
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:     "restore SNAPSHOT_NAME",
+	Short:   "Restore the cluster to a snapshot",
+	Long:    "Stop the cluster and restore its node disks, configuration and certificates to a snapshot. The restored cluster is started with 'minikube start'.",
+	Example: "minikube snapshot restore known-good && minikube start",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube snapshot restore SNAPSHOT_NAME")
+		}
+
+		api, cc := mustload.Partial(ClusterFlagValue())
+		out.Step(style.Resetting, `Restoring profile "{{.profile}}" to snapshot "{{.name}}" ...`, out.V{"name": args[0], "profile": cc.Name})
+		if _, err := snapshot.Restore(api, cc, args[0]); err != nil {
+			if errors.Is(err, snapshot.ErrNotFound) {
+				exit.Message(reason.Usage, `Snapshot "{{.name}}" does not exist for profile "{{.profile}}"`, out.V{"name": args[0], "profile": cc.Name})
+			}
+			exit.Error(reason.GuestSnapshot, "Failed to restore snapshot", err)
+		}
+		out.Step(style.Success, `Restored snapshot "{{.name}}"`, out.V{"name": args[0]})
+		out.Styled(style.Tip, `To start the restored cluster, run: "minikube start -p {{.profile}}"`, out.V{"profile": cc.Name})
+	},
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the snapshots of the cluster",
+	Long:  "List the snapshots saved for the cluster.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube snapshot list")
+		}
+
+		cname := ClusterFlagValue()
+		snapshots, err := snapshot.List(cname)
+		if err != nil {
+			exit.Error(reason.HostSnapshot, "Failed to list snapshots", err)
+		}
+
+		switch strings.ToLower(snapshotOutput) {
+		case "table":
+			if len(snapshots) == 0 {
+				out.Styled(style.Empty, `No snapshots found for profile "{{.profile}}"`, out.V{"profile": cname})
+				return
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Name", "Driver", "Kubernetes Version", "Nodes", "Created"})
+			table.SetAutoFormatHeaders(false)
+			table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+			table.SetCenterSeparator("|")
+			for _, s := range snapshots {
+				table.Append([]string{s.Name, s.Driver, s.KubernetesVersion, strconv.Itoa(len(s.Machines)), s.Created.Format(constants.TimeFormat)})
+			}
+			table.Render()
+		case "json":
+			b, err := json.Marshal(snapshots)
+			if err != nil {
+				exit.Error(reason.InternalJSONMarshal, "Unable to marshal snapshots to JSON", err)
+			}
+			out.String("%s\n", string(b))
+		default:
+			exit.Message(reason.Usage, fmt.Sprintf("invalid output format: %s. Valid values: 'table', 'json'", snapshotOutput))
+		}
+	},
+}
+
+var snapshotDeleteCmd = &cobra.Command{
+	Use:     "delete SNAPSHOT_NAME",
+	Short:   "Delete a snapshot of the cluster",
+	Long:    "Delete a snapshot of the cluster, along with the saved node disks.",
+	Example: "minikube snapshot delete known-good",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube snapshot delete SNAPSHOT_NAME")
+		}
+
+		cname := ClusterFlagValue()
+		if err := snapshot.Delete(cname, args[0]); err != nil {
+			if errors.Is(err, snapshot.ErrNotFound) {
+				exit.Message(reason.Usage, `Snapshot "{{.name}}" does not exist for profile "{{.profile}}"`, out.V{"name": args[0], "profile": cname})
+			}
+			exit.Error(reason.HostSnapshot, "Failed to delete snapshot", err)
+		}
+		out.Step(style.Deleted, `Deleted snapshot "{{.name}}"`, out.V{"name": args[0]})
+	},
+}
+
+func init() {
+	snapshotListCmd.Flags().StringVarP(&snapshotOutput, "output", "o", "table", "The output format. One of 'json', 'table'")
+	snapshotCmd.AddCommand(snapshotSaveCmd)
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+	snapshotCmd.AddCommand(snapshotListCmd)
+	snapshotCmd.AddCommand(snapshotDeleteCmd)
+}
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -31,13 +32,19 @@ import (
 	"k8s.io/minikube/pkg/minikube/reason"
 )
 
-var nativeSSHClient bool
+var (
+	nativeSSHClient bool
+	sshAllNodes     bool
+)
 
 // sshCmd represents the docker-ssh command
 var sshCmd = &cobra.Command{
 	Use:   "ssh",
 	Short: "Log into the minikube environment (for debugging)",
-	Long:  "Log into or run a command on a machine with SSH; similar to 'docker-machine ssh'.",
+	Long:  "Log into or run a command on a machine with SSH; similar to 'docker-machine ssh'. With --all-nodes, the command is run on every node concurrently, with each line of output prefixed by the name of its node.",
+	Example: `minikube ssh
+minikube ssh -n minikube-m02 -- df -h
+minikube ssh --all-nodes -- "sudo systemctl is-active kubelet"`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cname := ClusterFlagValue()
 		co := mustload.Running(cname)
@@ -45,6 +52,20 @@ var sshCmd = &cobra.Command{
 			exit.Message(reason.Usage, "'none' driver does not support 'minikube ssh' command")
 		}
 
+		if sshAllNodes {
+			if len(args) == 0 {
+				exit.Message(reason.Usage, "The --all-nodes flag requires a command to run: minikube ssh --all-nodes -- COMMAND")
+			}
+			if nodeName != "" {
+				exit.Message(reason.Usage, "The --node and --all-nodes flags can not be used together")
+			}
+			// like ssh, the arguments make up a command line run by the shell of the node
+			if code := runOnNodes(co, co.Config.Nodes, []string{"/bin/bash", "-c", strings.Join(args, " ")}, nil, true); code != 0 {
+				exit.Code(code)
+			}
+			return
+		}
+
 		var err error
 		var n *config.Node
 		if nodeName == "" {
@@ -69,4 +90,5 @@ var sshCmd = &cobra.Command{
 func init() {
 	sshCmd.Flags().BoolVar(&nativeSSHClient, "native-ssh", true, "Use native Golang SSH client (default true). Set to 'false' to use the command line 'ssh' command when accessing the docker machine. Useful for the machine drivers when they will not start with 'Waiting for SSH'.")
 	sshCmd.Flags().StringVarP(&nodeName, "node", "n", "", "The node to ssh into. Defaults to the primary control plane.")
+	sshCmd.Flags().BoolVar(&sshAllNodes, "all-nodes", false, "Run the command on all the nodes concurrently, prefixing the output with the node names")
 }
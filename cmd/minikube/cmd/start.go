@@ -26,6 +26,7 @@ import (
 	"os"
 	"os/exec"
 	"os/user"
+	"path/filepath"
 	"regexp"
 	"runtime"
 	"sort"
@@ -46,6 +47,7 @@ import (
 	"k8s.io/klog/v2"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
 	"k8s.io/minikube/pkg/minikube/config"
@@ -134,6 +136,12 @@ func platform() string {
 
 // runStart handles the executes the flow of "minikube start"
 func runStart(cmd *cobra.Command, args []string) {
+	cf, err := applyClusterFile(cmd)
+	if err != nil {
+		exit.Message(reason.Usage, "Unable to use cluster file: {{.error}}", out.V{"error": err})
+	}
+	clusterFile = cf
+
 	register.SetEventLogPath(localpath.EventLog(ClusterFlagValue()))
 	ctx := context.Background()
 	out.SetJSON(outputFormat == "json")
@@ -179,6 +187,9 @@ func runStart(cmd *cobra.Command, args []string) {
 	}
 
 	if existing != nil {
+		if clusterFile != nil {
+			out.WarningT("The cluster file only applies to new clusters, profile '{{.name}}' already exists: to recreate it, run 'minikube delete -p {{.name}}'", out.V{"name": existing.Name})
+		}
 		upgradeExistingConfig(cmd, existing)
 	} else {
 		validateProfileName()
@@ -268,6 +279,9 @@ func runStart(cmd *cobra.Command, args []string) {
 		exit.Error(reason.GuestStart, "failed to start node", err)
 	}
 
+	restoreMounts(starter.Cfg)
+	restorePortForwards(starter.Cfg)
+
 	if err := showKubectlInfo(kubeconfig, starter.Node.KubernetesVersion, starter.Cfg.Name); err != nil {
 		klog.Errorf("kubectl info: %v", err)
 	}
@@ -283,6 +297,9 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 	}
 
 	validateFlags(cmd, driverName)
+	if existing == nil {
+		validateSharedCA(ClusterFlagValue())
+	}
 	validateUser(driverName)
 	if driverName == oci.Docker {
 		validateDockerStorageDriver(driverName)
@@ -344,6 +361,34 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 }
 
 func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.ClusterConfig) (*kubeconfig.Settings, error) {
+	if existing == nil && viper.GetBool(ha) {
+		vip, err := node.HAVIP(*starter.Cfg, *starter.Node)
+		if err != nil {
+			return nil, errors.Wrap(err, "choosing virtual IP")
+		}
+		klog.Infof("virtual IP of the control planes: %s", vip)
+		starter.Cfg.KubernetesConfig.APIServerHAVIP = vip
+	} else if existing != nil && viper.GetBool(ha) && !config.IsHA(*existing) {
+		out.WarningT(`The cluster {{.cluster}} already exists which means the --ha parameter will be ignored. To create a HA cluster, run "minikube delete" first.`, out.V{"cluster": existing.Name})
+	}
+
+	if existing == nil && starter.Cfg.CACert != "" {
+		if err := bootstrapper.InstallCA(starter.Cfg.CACert, starter.Cfg.CAKey); err != nil {
+			return nil, errors.Wrap(err, "installing CA")
+		}
+	}
+
+	// the API server of the primary control plane needs the quorum of etcd, the other control planes come back first
+	if existing != nil && config.IsHA(*existing) {
+		for _, n := range existing.Nodes {
+			if n.ControlPlane && !config.IsPrimaryControlPlane(*existing, n) {
+				if err := node.RestoreControlPlane(starter.Cfg, n); err != nil {
+					klog.Warningf("unable to restore control plane %s, continuing anyway: %v", n.Name, err)
+				}
+			}
+		}
+	}
+
 	kubeconfig, err := node.Start(starter, true)
 	if err != nil {
 		kubeconfig, err = maybeDeleteAndRetry(cmd, *starter.Cfg, *starter.Node, starter.ExistingAddons, err)
@@ -364,31 +409,35 @@ func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.
 		if driver.BareMetal(starter.Cfg.Driver) {
 			exit.Message(reason.DrvUnsupportedMulti, "The none driver is not compatible with multi-node clusters.")
 		} else {
+			// the other nodes are created, provisioned and joined concurrently
+			var others []config.Node
 			if existing == nil {
 				for i := 1; i < numNodes; i++ {
-					nodeName := node.Name(i + 1)
 					n := config.Node{
-						Name:              nodeName,
+						Name:              clusterFileNodeName(i),
 						Worker:            true,
-						ControlPlane:      false,
+						ControlPlane:      isControlPlaneNode(i),
+						StaticIP:          nodeStaticIP(i),
 						KubernetesVersion: starter.Cfg.KubernetesConfig.KubernetesVersion,
+						Labels:            nodeLabelsOf(i),
+						Taints:            nodeTaintsOf(i),
 					}
-					out.Ln("") // extra newline for clarity on the command line
-					err := node.Add(starter.Cfg, n, viper.GetBool(deleteOnFailure))
-					if err != nil {
-						return nil, errors.Wrap(err, "adding node")
+					if driver.IsKIC(starter.Cfg.Driver) || driver.IsKVM(starter.Cfg.Driver) {
+						nodeResourcesOf(i).Apply(&n)
 					}
+					others = append(others, n)
 				}
 			} else {
 				for _, n := range existing.Nodes {
-					if !n.ControlPlane {
-						err := node.Add(starter.Cfg, n, viper.GetBool(deleteOnFailure))
-						if err != nil {
-							return nil, errors.Wrap(err, "adding node")
-						}
+					if !config.IsPrimaryControlPlane(*existing, n) {
+						others = append(others, n)
 					}
 				}
 			}
+			out.Ln("") // extra newline for clarity on the command line
+			if err := node.AddNodes(starter.Cfg, others, viper.GetBool(deleteOnFailure)); err != nil {
+				return nil, errors.Wrap(err, "adding nodes")
+			}
 		}
 	}
 
@@ -503,8 +552,9 @@ func maybeDeleteAndRetry(cmd *cobra.Command, existing config.ClusterConfig, n co
 				return nil, err
 			}
 
-			k, err := node.Start(s, n.ControlPlane)
-			if n.ControlPlane {
+			primary := config.IsPrimaryControlPlane(cc, n)
+			k, err := node.Start(s, primary)
+			if primary {
 				kubeconfig = k
 			}
 			if err != nil {
@@ -1087,6 +1137,20 @@ func validateCPUCount(drvName string) {
 
 // validateFlags validates the supplied flags against known bad combinations
 func validateFlags(cmd *cobra.Command, drvName string) {
+	if viper.GetBool(ha) {
+		if driver.BareMetal(drvName) {
+			exit.Message(reason.DrvUnsupportedMulti, "The none driver is not compatible with multi-node clusters.")
+		}
+		// the host only reaches the forwarded port of a single node, which would not fail over to the other control planes
+		if driver.NeedsPortForward(drvName) {
+			exit.Message(reason.DrvUnsupportedHA, "HA clusters are not supported by the {{.driver}} driver on this host, as the virtual IP of the control planes is not reachable from it", out.V{"driver": drvName})
+		}
+		// the cluster file defines the control planes itself
+		if clusterFile == nil && viper.GetInt(nodes) < haControlPlanes {
+			viper.Set(nodes, haControlPlanes)
+		}
+	}
+
 	if cmd.Flags().Changed(humanReadableDiskSize) {
 		diskSizeMB, err := util.CalculateSizeInMB(viper.GetString(humanReadableDiskSize))
 		if err != nil {
@@ -1114,6 +1178,21 @@ func validateFlags(cmd *cobra.Command, drvName string) {
 		validateListenAddress(viper.GetString(listenAddress))
 	}
 
+	validateNetworkFlags(drvName)
+
+	if cmd.Flags().Changed(certExpiration) && viper.GetDuration(certExpiration) <= 0 {
+		exit.Message(reason.Usage, "The --cert-expiration duration must be positive, got {{.duration}}", out.V{"duration": viper.GetDuration(certExpiration)})
+	}
+
+	validateCAFlags()
+
+	if _, err := config.ParseNodeLabels(viper.GetStringSlice(nodeLabels)); err != nil {
+		exit.Message(reason.Usage, "{{.error}}", out.V{"error": err})
+	}
+	if err := config.ValidateNodeTaints(viper.GetStringSlice(nodeTaints)); err != nil {
+		exit.Message(reason.Usage, "{{.error}}", out.V{"error": err})
+	}
+
 	if cmd.Flags().Changed(imageRepository) {
 		viper.Set(imageRepository, validateImageRepository(viper.GetString(imageRepository)))
 	}
@@ -1314,6 +1393,45 @@ func validateListenAddress(listenAddr string) {
 	}
 }
 
+// validateNetworkFlags validates --subnet, --static-ip and --extra-network, only supported by the docker and podman drivers
+func validateNetworkFlags(drvName string) {
+	sn := viper.GetString(subnet)
+	ip := viper.GetString(staticIP)
+	extra := viper.GetStringSlice(extraNetwork)
+	if sn == "" && ip == "" && len(extra) == 0 {
+		return
+	}
+	if !driver.IsKIC(drvName) {
+		exit.Message(reason.Usage, "The --subnet, --static-ip and --extra-network flags are only supported by the docker and podman drivers")
+	}
+
+	if sn != "" {
+		if err := oci.ValidateSubnet(sn); err != nil {
+			exit.Message(reason.Usage, "Sorry, the subnet provided with the --subnet flag is invalid: {{.error}}", out.V{"error": err})
+		}
+	}
+	if ip != "" {
+		if sn == "" {
+			sn = oci.StaticIPSubnet(ip)
+		}
+		// the other nodes get the following addresses, which must be in the subnet as well
+		last := nodeStaticIP(viper.GetInt(nodes) - 1)
+		for _, addr := range []string{ip, last} {
+			if err := oci.ValidateStaticIP(addr, sn); err != nil {
+				exit.Message(reason.Usage, "Sorry, the IP provided with the --static-ip flag is invalid: {{.error}}", out.V{"error": err})
+			}
+		}
+		if n := viper.GetString(network); n == "bridge" || n == "podman" {
+			exit.Message(reason.Usage, "The --static-ip flag is not supported on the default {{.network}} network", out.V{"network": n})
+		}
+	}
+	for _, n := range extra {
+		if n == viper.GetString(network) || (viper.GetString(network) == "" && n == ClusterFlagValue()) {
+			exit.Message(reason.Usage, "The network {{.network}} passed with --extra-network is already the network of the cluster", out.V{"network": n})
+		}
+	}
+}
+
 // This function validates that the --insecure-registry follows one of the following formats:
 // "<ip>[:<port>]" "<hostname>[:<port>]" "<network>/<netmask>"
 func validateInsecureRegistry() {
@@ -1378,6 +1496,14 @@ func createNode(cc config.ClusterConfig, kubeNodeName string, existing *config.C
 		Name:              kubeNodeName,
 		ControlPlane:      true,
 		Worker:            true,
+		Labels:            nodeLabelsOf(0),
+		Taints:            nodeTaintsOf(0),
+	}
+	if driver.IsKIC(cc.Driver) {
+		cp.StaticIP = nodeStaticIP(0)
+	}
+	if driver.IsKIC(cc.Driver) || driver.IsKVM(cc.Driver) {
+		nodeResourcesOf(0).Apply(&cp)
 	}
 	cc.Nodes = []config.Node{cp}
 	return cc, cp, nil
@@ -1419,6 +1545,61 @@ func autoSetDriverOptions(cmd *cobra.Command, drvName string) (err error) {
 	return err
 }
 
+// validateCAFlags validates the CA of the organization, making its paths absolute as they are saved to the cluster config
+func validateCAFlags() {
+	cert, key := viper.GetString(caCert), viper.GetString(caKey)
+	if cert == "" && key == "" {
+		return
+	}
+	if cert == "" || key == "" {
+		exit.Message(reason.Usage, "The --ca-cert and --ca-key flags must be set together")
+	}
+	for flag, p := range map[string]string{caCert: cert, caKey: key} {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			exit.Message(reason.Usage, "Invalid --{{.flag}} path {{.path}}: {{.error}}", out.V{"flag": flag, "path": p, "error": err})
+		}
+		viper.Set(flag, abs)
+	}
+	if err := util.CheckCACert(viper.GetString(caCert), viper.GetString(caKey)); err != nil {
+		exit.Message(reason.Usage, "Invalid CA: {{.error}}", out.V{"error": err})
+	}
+}
+
+// validateSharedCA refuses to replace the CA shared among profiles with the CA of the organization while other profiles
+// exist, as their clusters would no longer trust the certificates signed by it
+func validateSharedCA(profile string) {
+	cert := viper.GetString(caCert)
+	if cert == "" {
+		return
+	}
+	replaced, err := bootstrapper.CAReplaced(cert)
+	if err != nil {
+		exit.Message(reason.Usage, "Invalid CA: {{.error}}", out.V{"error": err})
+	}
+	if !replaced {
+		return
+	}
+	if others := otherProfiles(profile); len(others) > 0 {
+		exit.Message(reason.Usage, `The CA of minikube is shared by all the profiles, the clusters of {{.profiles}} would no longer work with the CA of {{.cert}}. Delete them first, or set MINIKUBE_HOME to a separate directory for the clusters signed with this CA`, out.V{"profiles": strings.Join(others, ", "), "cert": cert})
+	}
+}
+
+// otherProfiles returns the names of the valid profiles other than profile, which share its CA
+func otherProfiles(profile string) []string {
+	profiles, err := config.ListValidProfiles()
+	if err != nil {
+		klog.Warningf("unable to list profiles: %v", err)
+	}
+	var others []string
+	for _, p := range profiles {
+		if p.Name != profile {
+			others = append(others, p.Name)
+		}
+	}
+	return others
+}
+
 // validateKubernetesVersion ensures that the requested version is reasonable
 func validateKubernetesVersion(old *config.ClusterConfig) {
 	nvs, _ := semver.Make(strings.TrimPrefix(getKubernetesVersion(old), version.VersionPrefix))
@@ -1485,6 +1666,11 @@ func getKubernetesVersion(old *config.ClusterConfig) string {
 		paramVersion = old.KubernetesConfig.KubernetesVersion
 	}
 
+	return normalizeKubernetesVersion(paramVersion)
+}
+
+// normalizeKubernetesVersion returns the version of Kubernetes of a flag value, resolving "stable" and "latest", with the v prefix
+func normalizeKubernetesVersion(paramVersion string) string {
 	if paramVersion == "" || strings.EqualFold(paramVersion, "stable") {
 		paramVersion = constants.DefaultKubernetesVersion
 	} else if strings.EqualFold(paramVersion, "latest") {
@@ -18,6 +18,8 @@ package cmd
 
 import (
 	"fmt"
+	"net"
+	"strconv"
 	"strings"
 	"time"
 
@@ -38,10 +40,13 @@ import (
 	"k8s.io/minikube/pkg/minikube/download"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/node"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/proxy"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/registrycache"
 	"k8s.io/minikube/pkg/minikube/style"
+	pkgnetwork "k8s.io/minikube/pkg/network"
 	pkgutil "k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/version"
 )
@@ -105,12 +110,17 @@ const (
 	hostOnlyNicType         = "host-only-nic-type"
 	natNicType              = "nat-nic-type"
 	nodes                   = "nodes"
+	ha                      = "ha"
+	haControlPlanes         = 3 // the smallest etcd cluster tolerating the failure of a member
 	preload                 = "preload"
 	deleteOnFailure         = "delete-on-failure"
 	forceSystemd            = "force-systemd"
 	kicBaseImage            = "base-image"
 	ports                   = "ports"
 	network                 = "network"
+	subnet                  = "subnet"
+	staticIP                = "static-ip"
+	extraNetwork            = "extra-network"
 	startNamespace          = "namespace"
 	trace                   = "trace"
 	sshIPAddress            = "ssh-ip-address"
@@ -120,6 +130,15 @@ const (
 	defaultSSHUser          = "root"
 	defaultSSHPort          = 22
 	listenAddress           = "listen-address"
+	clusterConfigFile       = "config"
+	cniOpt                  = "cni-opt"
+	registryCache           = "registry-cache"
+	certExpiration          = "cert-expiration"
+	caCert                  = "ca-cert"
+	caKey                   = "ca-key"
+	importHostCerts         = "import-host-certs"
+	nodeLabels              = "node-labels"
+	nodeTaints              = "node-taints"
 )
 
 var (
@@ -146,6 +165,10 @@ func initMinikubeFlags() {
 	startCmd.Flags().String(kicBaseImage, kic.BaseImage, "The base image to use for docker/podman drivers. Intended for local development.")
 	startCmd.Flags().Bool(keepContext, false, "This will keep the existing kubectl context and will create a minikube context.")
 	startCmd.Flags().Bool(embedCerts, false, "if true, will embed the certs in kubeconfig.")
+	startCmd.Flags().Duration(certExpiration, constants.DefaultCertExpiration, "Duration until the certs signed by the minikube CAs expire. Applies to the certs issued afterwards, see \"minikube certs rotate\".")
+	startCmd.Flags().String(caCert, "", "File of the PEM encoded CA certificate of the organization, typically an intermediate CA, to sign the cluster with instead of the minikube CA shared by all profiles, so only allowed when no other profile exists. Requires --ca-key.")
+	startCmd.Flags().String(caKey, "", "File of the PEM encoded private key of the CA certificate set with --ca-cert.")
+	startCmd.Flags().Bool(importHostCerts, false, "Import the CA certificates of the system trust store of the host into the nodes and their container runtime, e.g. to pull through a TLS-intercepting proxy.")
 	startCmd.Flags().String(containerRuntime, constants.DefaultContainerRuntime, fmt.Sprintf("The container runtime to be used (%s).", strings.Join(cruntime.ValidRuntimes(), ", ")))
 	startCmd.Flags().Bool(createMount, false, "This will start the mount daemon and automatically mount files into minikube.")
 	startCmd.Flags().String(mountString, constants.DefaultMountDir+":/minikube-host", "The argument to pass the minikube mount command on start.")
@@ -154,18 +177,26 @@ func initMinikubeFlags() {
 	startCmd.Flags().String(networkPlugin, "", "Kubelet network plug-in to use (default: auto)")
 	startCmd.Flags().Bool(enableDefaultCNI, false, "DEPRECATED: Replaced by --cni=bridge")
 	startCmd.Flags().String(cniFlag, "", "CNI plug-in to use. Valid options: auto, bridge, calico, cilium, flannel, kindnet, or path to a CNI manifest (default: auto)")
+	startCmd.Flags().StringSlice(cniOpt, nil, "Option of the CNI plug-in, formatted as key=value, may be repeated. Valid keys depend on the CNI: pod-cidr, mtu (calico, cilium), encapsulation (calico, cilium, flannel) and hubble (cilium)")
 	startCmd.Flags().StringSlice(waitComponents, kverify.DefaultWaitList, fmt.Sprintf("comma separated list of Kubernetes components to verify and wait for after starting a cluster. defaults to %q, available options: %q . other acceptable values are 'all' or 'none', 'true' and 'false'", strings.Join(kverify.DefaultWaitList, ","), strings.Join(kverify.AllComponentsList, ",")))
 	startCmd.Flags().Duration(waitTimeout, 6*time.Minute, "max time to wait per Kubernetes or host to be healthy.")
 	startCmd.Flags().Bool(nativeSSH, true, "Use native Golang SSH client (default true). Set to 'false' to use the command line 'ssh' command when accessing the docker machine. Useful for the machine drivers when they will not start with 'Waiting for SSH'.")
 	startCmd.Flags().Bool(autoUpdate, true, "If set, automatically updates drivers to the latest version. Defaults to true.")
 	startCmd.Flags().Bool(installAddons, true, "If set, install addons. Defaults to true.")
 	startCmd.Flags().IntP(nodes, "n", 1, "The number of nodes to spin up. Defaults to 1.")
+	startCmd.Flags().StringSlice(nodeLabels, nil, "Kubernetes labels of the nodes, formatted as key=value, may be repeated. Applied at every start, node-role.kubernetes.io/<role>= labels give the nodes a role.")
+	startCmd.Flags().StringSlice(nodeTaints, nil, "Kubernetes taints of the nodes, formatted as key[=value]:effect where effect is NoSchedule, PreferNoSchedule or NoExecute, may be repeated.")
+	startCmd.Flags().Bool(ha, false, "Create a highly available cluster: 3 control-plane nodes with stacked etcd, behind a virtual IP. Additional --nodes are workers.")
 	startCmd.Flags().Bool(preload, true, "If set, download tarball of preloaded images if available to improve start time. Defaults to true.")
 	startCmd.Flags().Bool(deleteOnFailure, false, "If set, delete the current cluster if start fails and try again. Defaults to false.")
 	startCmd.Flags().Bool(forceSystemd, false, "If set, force the container runtime to use systemd as cgroup manager. Defaults to false.")
 	startCmd.Flags().StringP(network, "", "", "network to run minikube with. Now it is used by docker/podman and KVM drivers. If left empty, minikube will create a new network.")
+	startCmd.Flags().String(subnet, "", "Subnet of the network minikube creates, in CIDR notation, e.g. 192.168.60.0/24 (docker and podman drivers only). If left empty, minikube picks a free private subnet.")
+	startCmd.Flags().String(staticIP, "", "Static IP of the primary control-plane node, the other nodes get the following addresses (docker and podman drivers only). The network minikube creates defaults to its /24 subnet.")
+	startCmd.Flags().StringSlice(extraNetwork, nil, "Existing docker or podman network to attach the nodes to, in addition to --network, may be repeated (docker and podman drivers only). Clusters sharing a network can reach each other's nodes.")
 	startCmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Format to print stdout in. Options include: [text,json]")
-	startCmd.Flags().StringP(trace, "", "", "Send trace events. Options include: [gcp]")
+	startCmd.Flags().StringP(trace, "", "", "Send trace events. Options include: [gcp, otlp, file]")
+	startCmd.Flags().String(clusterConfigFile, "", "Path to a cluster definition file (YAML or JSON), as written by 'minikube profile export'. Flags set on the command line take precedence over the file.")
 }
 
 // initKubernetesFlags inits the commandline flags for Kubernetes related options
@@ -227,7 +258,8 @@ func initDriverFlags() {
 // initNetworkingFlags inits the commandline flags for connectivity related flags for start
 func initNetworkingFlags() {
 	startCmd.Flags().StringSliceVar(&insecureRegistry, "insecure-registry", nil, "Insecure Docker registries to pass to the Docker daemon.  The default service CIDR range will automatically be added.")
-	startCmd.Flags().StringSliceVar(&registryMirror, "registry-mirror", nil, "Registry mirrors to pass to the Docker daemon")
+	startCmd.Flags().StringSliceVar(&registryMirror, "registry-mirror", nil, "Registry mirrors of Docker Hub to pass to the container runtime")
+	startCmd.Flags().Bool(registryCache, false, fmt.Sprintf("Pull images from Docker Hub through a cache running on the host, on port %d, shared by all profiles and kept when they are deleted. Requires docker or podman on the host.", registrycache.HostPort))
 	startCmd.Flags().String(imageRepository, "", "Alternative image repository to pull docker images from. This can be used when you have limited access to gcr.io. Set it to \"auto\" to let minikube decide one for you. For Chinese mainland users, you may use local gcr.io mirrors such as registry.cn-hangzhou.aliyuncs.com/google_containers")
 	startCmd.Flags().String(imageMirrorCountry, "", "Country code of the image mirror to be used. Leave empty to use the global one. For Chinese mainland users, set it to cn.")
 	startCmd.Flags().String(serviceCIDR, constants.DefaultServiceCIDR, "The CIDR to be used for service cluster IPs.")
@@ -246,12 +278,193 @@ func ClusterFlagValue() string {
 	return viper.GetString(config.ProfileName)
 }
 
+// clusterFile is the cluster definition read from --config, if any
+var clusterFile *config.ClusterFile
+
+// applyClusterFile reads the cluster definition passed with --config, and sets the flags which were not set
+// on the command line to the values it defines. It returns nil if no cluster definition was passed.
+func applyClusterFile(cmd *cobra.Command) (*config.ClusterFile, error) {
+	file := viper.GetString(clusterConfigFile)
+	if file == "" {
+		return nil, nil
+	}
+	cf, err := config.ReadClusterFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	setDefault := func(name string, value interface{}) {
+		if cmd.Flags().Changed(name) {
+			klog.Infof("flag --%s takes precedence over the cluster file", name)
+			return
+		}
+		viper.Set(name, value)
+	}
+	s := cf.Spec
+	values := map[string]string{
+		config.ProfileName:    cf.Metadata.Name,
+		"driver":              s.Driver,
+		kubernetesVersion:     s.KubernetesVersion,
+		containerRuntime:      s.ContainerRuntime,
+		cniFlag:               s.CNI,
+		featureGates:          s.FeatureGates,
+		imageRepository:       s.ImageRepository,
+		memory:                s.Resources.Memory,
+		humanReadableDiskSize: s.Resources.Disk,
+	}
+	for name, value := range values {
+		if value != "" {
+			setDefault(name, value)
+		}
+	}
+	if s.Resources.CPUs > 0 {
+		setDefault(cpus, strconv.Itoa(s.Resources.CPUs))
+	}
+	if len(s.Nodes) > 0 {
+		setDefault(nodes, len(s.Nodes))
+	}
+	if s.Network != "" {
+		setDefault(network, s.Network)
+	}
+	if s.Subnet != "" {
+		setDefault(subnet, s.Subnet)
+	}
+	if len(s.ExtraNetworks) > 0 {
+		setDefault(extraNetwork, s.ExtraNetworks)
+	}
+	if s.RegistryCache {
+		setDefault(registryCache, true)
+	}
+	if s.ControlPlanes() > 1 {
+		setDefault(ha, true)
+	}
+	if len(s.CNIOptions) > 0 {
+		setDefault(cniOpt, cni.FormatOptions(s.CNIOptions))
+	}
+	if len(s.Mounts) > 0 {
+		setDefault(createMount, true)
+		setDefault(mountString, s.Mounts[0].String())
+	}
+
+	var addons []string
+	for _, a := range s.Addons {
+		if a.IsEnabled() {
+			addons = append(addons, a.Name)
+		}
+	}
+	if len(addons) > 0 {
+		setDefault(config.AddonListFlag, addons)
+	}
+
+	if !cmd.Flags().Changed("registry-mirror") && len(s.RegistryMirrors) > 0 {
+		registryMirror = s.RegistryMirrors
+	}
+	if !cmd.Flags().Changed("insecure-registry") && len(s.InsecureRegistries) > 0 {
+		insecureRegistry = s.InsecureRegistries
+	}
+	for _, eo := range s.ExtraOptions {
+		if config.ExtraOptions.Exists(eo) {
+			continue
+		}
+		if err := config.ExtraOptions.Set(eo); err != nil {
+			return nil, errors.Wrapf(err, "extra option %q", eo)
+		}
+	}
+	return cf, nil
+}
+
+// clusterFileNodeName returns the name of the i-th node defined in the cluster file, or the generated name if none is defined
+func clusterFileNodeName(i int) string {
+	if clusterFile != nil && i < len(clusterFile.Spec.Nodes) && clusterFile.Spec.Nodes[i].Name != "" {
+		return clusterFile.Spec.Nodes[i].Name
+	}
+	return node.Name(i + 1)
+}
+
+// isControlPlaneNode returns whether the i-th node is a control plane, as defined in the cluster file, or as requested by --ha
+func isControlPlaneNode(i int) bool {
+	if clusterFile != nil && i < len(clusterFile.Spec.Nodes) {
+		return clusterFile.Spec.Nodes[i].Role == config.RoleControlPlane
+	}
+	return viper.GetBool(ha) && i < haControlPlanes
+}
+
+// nodeStaticIP returns the static IP of the i-th node, as defined in the cluster file, or the i-th address
+// from --static-ip. It is empty if the address of the node is calculated by the driver.
+func nodeStaticIP(i int) string {
+	if clusterFile != nil && i < len(clusterFile.Spec.Nodes) {
+		return clusterFile.Spec.Nodes[i].StaticIP
+	}
+	ip := net.ParseIP(viper.GetString(staticIP))
+	if ip == nil {
+		return ""
+	}
+	return pkgnetwork.IPOffset(ip, i).String()
+}
+
+// nodeLabelsOf returns the labels of the i-th node: the ones defined in the cluster file, and the ones of --node-labels
+func nodeLabelsOf(i int) map[string]string {
+	labels := map[string]string{}
+	if clusterFile != nil && i < len(clusterFile.Spec.Nodes) {
+		for k, v := range clusterFile.Spec.Nodes[i].Labels {
+			labels[k] = v
+		}
+	}
+	// validated by validateFlags
+	flagLabels, _ := config.ParseNodeLabels(viper.GetStringSlice(nodeLabels))
+	for k, v := range flagLabels {
+		labels[k] = v
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	return labels
+}
+
+// nodeTaintsOf returns the taints of the i-th node: the ones defined in the cluster file, and the ones of --node-taints
+func nodeTaintsOf(i int) []string {
+	var taints []string
+	if clusterFile != nil && i < len(clusterFile.Spec.Nodes) {
+		taints = append(taints, clusterFile.Spec.Nodes[i].Taints...)
+	}
+	return append(taints, viper.GetStringSlice(nodeTaints)...)
+}
+
+// nodeResourcesOf returns the resources of the i-th node defined in the cluster file, nil if it has none
+func nodeResourcesOf(i int) *config.ResourcesSpec {
+	if clusterFile != nil && i < len(clusterFile.Spec.Nodes) {
+		return clusterFile.Spec.Nodes[i].Resources
+	}
+	return nil
+}
+
+// clusterSubnet returns the subnet of the network created for the cluster: --subnet, or the /24 subnet of
+// the static IP of the primary control plane
+func clusterSubnet() string {
+	if s := viper.GetString(subnet); s != "" {
+		return s
+	}
+	if ip := nodeStaticIP(0); ip != "" {
+		return oci.StaticIPSubnet(ip)
+	}
+	return ""
+}
+
+// validateCNIOptions exits if the CNI of the cluster does not support the options set with --cni-opt
+func validateCNIOptions(cc *config.ClusterConfig) {
+	if err := cni.ValidateOptions(cc); err != nil {
+		exit.Message(reason.Usage, "Invalid CNI options: {{.error}}", out.V{"error": err})
+	}
+}
+
 // generateClusterConfig generate a config.ClusterConfig based on flags or existing cluster config
 func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k8sVersion string, drvName string) (config.ClusterConfig, config.Node, error) {
 	var cc config.ClusterConfig
 	if existing != nil {
 		cc = updateExistingConfigFromFlags(cmd, existing)
 
+		validateCNIOptions(&cc)
+
 		// identify appropriate cni then configure cruntime accordingly
 		_, err := cni.New(&cc)
 		if err != nil {
@@ -260,6 +473,7 @@ func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k
 	} else {
 		klog.Info("no existing cluster config was found, will generate one from the flags ")
 		cc = generateNewConfigFromFlags(cmd, k8sVersion, drvName)
+		validateCNIOptions(&cc)
 
 		cnm, err := cni.New(&cc)
 		if err != nil {
@@ -391,6 +605,15 @@ func getCNIConfig(cmd *cobra.Command) string {
 	return chosenCNI
 }
 
+// getCNIOptions returns the options set with --cni-opt
+func getCNIOptions() map[string]string {
+	opts, err := cni.ParseOptions(viper.GetStringSlice(cniOpt))
+	if err != nil {
+		exit.Message(reason.Usage, "{{.error}}", out.V{"error": err})
+	}
+	return opts
+}
+
 // generateNewConfigFromFlags generate a config.ClusterConfig based on flags
 func generateNewConfigFromFlags(cmd *cobra.Command, k8sVersion string, drvName string) config.ClusterConfig {
 	var cc config.ClusterConfig
@@ -405,6 +628,13 @@ func generateNewConfigFromFlags(cmd *cobra.Command, k8sVersion string, drvName s
 		out.WarningT("--network flag is only valid with the docker/podman and KVM drivers, it will be ignored")
 	}
 
+	var sn string
+	var extraNetworks []string
+	if driver.IsKIC(drvName) {
+		sn = clusterSubnet()
+		extraNetworks = viper.GetStringSlice(extraNetwork)
+	}
+
 	checkNumaCount(k8sVersion)
 
 	cc = config.ClusterConfig{
@@ -414,6 +644,8 @@ func generateNewConfigFromFlags(cmd *cobra.Command, k8sVersion string, drvName s
 		MinikubeISO:             viper.GetString(isoURL),
 		KicBaseImage:            viper.GetString(kicBaseImage),
 		Network:                 viper.GetString(network),
+		Subnet:                  sn,
+		ExtraNetworks:           extraNetworks,
 		Memory:                  getMemorySize(cmd, drvName),
 		CPUs:                    getCPUCount(drvName),
 		DiskSize:                getDiskSize(),
@@ -427,6 +659,7 @@ func generateNewConfigFromFlags(cmd *cobra.Command, k8sVersion string, drvName s
 		DockerOpt:               config.DockerOpt,
 		InsecureRegistry:        insecureRegistry,
 		RegistryMirror:          registryMirror,
+		RegistryCache:           viper.GetBool(registryCache),
 		HostOnlyCIDR:            viper.GetString(hostOnlyCIDR),
 		HypervVirtualSwitch:     viper.GetString(hypervVirtualSwitch),
 		HypervUseExternalSwitch: viper.GetBool(hypervUseExternalSwitch),
@@ -444,6 +677,10 @@ func generateNewConfigFromFlags(cmd *cobra.Command, k8sVersion string, drvName s
 		HostOnlyNicType:         viper.GetString(hostOnlyNicType),
 		NatNicType:              viper.GetString(natNicType),
 		StartHostTimeout:        viper.GetDuration(waitTimeout),
+		CertExpiration:          viper.GetDuration(certExpiration),
+		CACert:                  viper.GetString(caCert),
+		CAKey:                   viper.GetString(caKey),
+		ImportHostCerts:         viper.GetBool(importHostCerts),
 		ExposedPorts:            viper.GetStringSlice(ports),
 		SSHIPAddress:            viper.GetString(sshIPAddress),
 		SSHUser:                 viper.GetString(sshSSHUser),
@@ -466,13 +703,21 @@ func generateNewConfigFromFlags(cmd *cobra.Command, k8sVersion string, drvName s
 			ExtraOptions:           config.ExtraOptions,
 			ShouldLoadCachedImages: viper.GetBool(cacheImages),
 			CNI:                    getCNIConfig(cmd),
+			CNIOptions:             getCNIOptions(),
 			NodePort:               viper.GetInt(apiServerPort),
 		},
 		MultiNodeRequested: viper.GetInt(nodes) > 1,
 	}
 	cc.VerifyComponents = interpretWaitFlag(*cmd)
-	if viper.GetBool(createMount) && driver.IsKIC(drvName) {
-		cc.ContainerVolumeMounts = []string{viper.GetString(mountString)}
+	if viper.GetBool(createMount) {
+		cc.Mount = true
+		cc.MountString = viper.GetString(mountString)
+		if driver.IsKIC(drvName) {
+			cc.ContainerVolumeMounts = []string{cc.MountString}
+		}
+	}
+	if clusterFile != nil {
+		cc.CustomAddonImages, cc.CustomAddonRegistries = clusterFile.AddonImages()
 	}
 
 	return cc
@@ -546,18 +791,32 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 	cc := *existing
 
 	if cmd.Flags().Changed(memory) && getMemorySize(cmd, cc.Driver) != cc.Memory {
-		out.WarningT("You cannot change the memory size for an existing minikube cluster. Please first delete the cluster.")
+		out.WarningT("You cannot change the memory size for an existing minikube cluster. Please run \"minikube config resize --memory\" instead.")
+	}
+
+	if cmd.Flags().Changed(nodeLabels) || cmd.Flags().Changed(nodeTaints) {
+		out.WarningT("You cannot change the labels and taints of the nodes of an existing minikube cluster. Use \"minikube node add\" to add nodes with other labels and taints.")
 	}
 
 	if cmd.Flags().Changed(cpus) && viper.GetInt(cpus) != cc.CPUs {
-		out.WarningT("You cannot change the CPUs for an existing minikube cluster. Please first delete the cluster.")
+		out.WarningT("You cannot change the CPUs for an existing minikube cluster. Please run \"minikube config resize --cpus\" instead.")
 	}
 
 	// validate the memory size in case user changed their system memory limits (example change docker desktop or upgraded memory.)
 	validateRequestedMemorySize(cc.Memory, cc.Driver)
 
 	if cmd.Flags().Changed(humanReadableDiskSize) && getDiskSize() != existing.DiskSize {
-		out.WarningT("You cannot change the disk size for an existing minikube cluster. Please first delete the cluster.")
+		out.WarningT("You cannot change the disk size for an existing minikube cluster. Please run \"minikube config resize --disk-size\" instead.")
+	}
+
+	if (cmd.Flags().Changed(caCert) && viper.GetString(caCert) != existing.CACert) || (cmd.Flags().Changed(caKey) && viper.GetString(caKey) != existing.CAKey) {
+		out.WarningT(`You cannot change the CA of an existing minikube cluster at start, it will be ignored. Please run "minikube certs rotate --ca-cert --ca-key" instead.`)
+	}
+
+	for _, name := range []string{subnet, staticIP, extraNetwork} {
+		if cmd.Flags().Changed(name) {
+			out.WarningT("You cannot change the --{{.flag}} of an existing minikube cluster, it will be ignored. Please first delete the cluster.", out.V{"flag": name})
+		}
 	}
 
 	updateStringFromFlag(cmd, &cc.MinikubeISO, isoURL)
@@ -583,9 +842,12 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 	updateBoolFromFlag(cmd, &cc.NoVTXCheck, noVTXCheck)
 	updateBoolFromFlag(cmd, &cc.DNSProxy, dnsProxy)
 	updateBoolFromFlag(cmd, &cc.HostDNSResolver, hostDNSResolver)
+	updateBoolFromFlag(cmd, &cc.RegistryCache, registryCache)
 	updateStringFromFlag(cmd, &cc.HostOnlyNicType, hostOnlyNicType)
 	updateStringFromFlag(cmd, &cc.NatNicType, natNicType)
 	updateDurationFromFlag(cmd, &cc.StartHostTimeout, waitTimeout)
+	updateDurationFromFlag(cmd, &cc.CertExpiration, certExpiration)
+	updateBoolFromFlag(cmd, &cc.ImportHostCerts, importHostCerts)
 	updateStringSliceFromFlag(cmd, &cc.ExposedPorts, ports)
 	updateStringFromFlag(cmd, &cc.SSHIPAddress, sshIPAddress)
 	updateStringFromFlag(cmd, &cc.SSHUser, sshSSHUser)
@@ -615,6 +877,10 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 		cc.KubernetesConfig.CNI = getCNIConfig(cmd)
 	}
 
+	if cmd.Flags().Changed(cniOpt) {
+		cc.KubernetesConfig.CNIOptions = getCNIOptions()
+	}
+
 	if cmd.Flags().Changed(waitComponents) {
 		cc.VerifyComponents = interpretWaitFlag(*cmd)
 	}
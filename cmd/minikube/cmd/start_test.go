@@ -17,7 +17,10 @@ limitations under the License.
 package cmd
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -363,3 +366,184 @@ func TestValidateImageRepository(t *testing.T) {
 	}
 
 }
+
+func TestApplyClusterFile(t *testing.T) {
+	defer viper.Reset()
+	oldExtraOptions, oldMirrors := cfg.ExtraOptions, registryMirror
+	defer func() { cfg.ExtraOptions, registryMirror = oldExtraOptions, oldMirrors }()
+	cfg.ExtraOptions = cfg.ExtraOptionSlice{{Component: "kubelet", Key: "max-pods", Value: "20"}}
+	registryMirror = nil
+
+	file := filepath.Join(t.TempDir(), "cluster.yaml")
+	doc := `apiVersion: minikube.sigs.k8s.io/v1alpha1
+kind: Cluster
+spec:
+  containerRuntime: containerd
+  registryMirrors:
+  - https://mirror.example.com
+  resources:
+    cpus: 4
+    memory: 4g
+  nodes:
+  - role: control-plane
+  - role: worker
+    name: worker-a
+  addons:
+  - name: ingress
+  - name: dashboard
+    enabled: false
+  extraOptions:
+  - kubelet.max-pods=50
+  - kubelet.cgroup-driver=systemd
+`
+	if err := ioutil.WriteFile(file, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String(cpus, "2", "")
+	if err := cmd.Flags().Set(cpus, "6"); err != nil {
+		t.Fatal(err)
+	}
+	viper.Set(clusterConfigFile, file)
+	cf, err := applyClusterFile(cmd)
+	if err != nil {
+		t.Fatalf("applyClusterFile() failed: %v", err)
+	}
+	clusterFile = cf
+	defer func() { clusterFile = nil }()
+
+	if got := viper.GetString(containerRuntime); got != "containerd" {
+		t.Errorf("container runtime = %q; want %q", got, "containerd")
+	}
+	if got := viper.GetString(memory); got != "4g" {
+		t.Errorf("memory = %q; want %q", got, "4g")
+	}
+	if viper.IsSet(cpus) {
+		t.Errorf("cpus = %q; want the flag set on the command line to take precedence", viper.GetString(cpus))
+	}
+	if got := viper.GetInt(nodes); got != 2 {
+		t.Errorf("nodes = %d; want 2", got)
+	}
+	if got := viper.GetStringSlice(cfg.AddonListFlag); len(got) != 1 || got[0] != "ingress" {
+		t.Errorf("addons = %v; want [ingress]", got)
+	}
+	if len(registryMirror) != 1 || registryMirror[0] != "https://mirror.example.com" {
+		t.Errorf("registry mirrors = %v; want the mirrors of the cluster file", registryMirror)
+	}
+	if got := cfg.ExtraOptions.Get("max-pods", "kubelet"); got != "20" {
+		t.Errorf("kubelet.max-pods = %q; want the value set on the command line", got)
+	}
+	if got := cfg.ExtraOptions.Get("cgroup-driver", "kubelet"); got != "systemd" {
+		t.Errorf("kubelet.cgroup-driver = %q; want the value of the cluster file", got)
+	}
+	if got := clusterFileNodeName(1); got != "worker-a" {
+		t.Errorf("clusterFileNodeName(1) = %q; want %q", got, "worker-a")
+	}
+}
+
+func TestIsControlPlaneNode(t *testing.T) {
+	defer viper.Reset()
+	viper.Set(ha, true)
+	for i, want := range []bool{true, true, true, false} {
+		if got := isControlPlaneNode(i); got != want {
+			t.Errorf("isControlPlaneNode(%d) = %v with --ha; want %v", i, got, want)
+		}
+	}
+
+	clusterFile = &cfg.ClusterFile{Spec: cfg.ClusterSpec{Nodes: []cfg.NodeSpec{
+		{Role: cfg.RoleControlPlane}, {Role: cfg.RoleWorker}, {Role: cfg.RoleControlPlane},
+	}}}
+	defer func() { clusterFile = nil }()
+	for i, want := range []bool{true, false, true} {
+		if got := isControlPlaneNode(i); got != want {
+			t.Errorf("isControlPlaneNode(%d) = %v with the cluster file; want %v", i, got, want)
+		}
+	}
+}
+
+func TestNodeStaticIP(t *testing.T) {
+	defer viper.Reset()
+	if got := nodeStaticIP(1); got != "" {
+		t.Errorf("nodeStaticIP(1) = %q without --static-ip; want empty", got)
+	}
+	if got := clusterSubnet(); got != "" {
+		t.Errorf("clusterSubnet() = %q without --static-ip; want empty", got)
+	}
+
+	viper.Set(staticIP, "192.168.60.10")
+	for i, want := range []string{"192.168.60.10", "192.168.60.11", "192.168.60.12"} {
+		if got := nodeStaticIP(i); got != want {
+			t.Errorf("nodeStaticIP(%d) = %q; want %q", i, got, want)
+		}
+	}
+	if got := clusterSubnet(); got != "192.168.60.0/24" {
+		t.Errorf("clusterSubnet() = %q; want the /24 subnet of the static IP", got)
+	}
+	viper.Set(subnet, "192.168.60.0/23")
+	if got := clusterSubnet(); got != "192.168.60.0/23" {
+		t.Errorf("clusterSubnet() = %q; want --subnet", got)
+	}
+
+	clusterFile = &cfg.ClusterFile{Spec: cfg.ClusterSpec{Nodes: []cfg.NodeSpec{
+		{Role: cfg.RoleControlPlane, StaticIP: "192.168.70.2"}, {Role: cfg.RoleWorker},
+	}}}
+	defer func() { clusterFile = nil }()
+	for i, want := range []string{"192.168.70.2", ""} {
+		if got := nodeStaticIP(i); got != want {
+			t.Errorf("nodeStaticIP(%d) = %q with the cluster file; want %q", i, got, want)
+		}
+	}
+}
+
+func TestNodeLabelsAndTaints(t *testing.T) {
+	defer viper.Reset()
+	if got := nodeLabelsOf(0); got != nil {
+		t.Errorf("nodeLabelsOf(0) = %v without --node-labels; want none", got)
+	}
+
+	viper.Set(nodeLabels, []string{"pool=gpu"})
+	viper.Set(nodeTaints, []string{"dedicated=gpu:NoSchedule"})
+	clusterFile = &cfg.ClusterFile{Spec: cfg.ClusterSpec{Nodes: []cfg.NodeSpec{
+		{Role: cfg.RoleControlPlane}, {Role: cfg.RoleWorker, Labels: map[string]string{"pool": "cpu", "zone": "a"}, Taints: []string{"spot:PreferNoSchedule"}},
+	}}}
+	defer func() { clusterFile = nil }()
+
+	if got, want := nodeLabelsOf(1), map[string]string{"pool": "gpu", "zone": "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("nodeLabelsOf(1) = %v; want the labels of the cluster file overridden by --node-labels %v", got, want)
+	}
+	if got, want := nodeTaintsOf(1), []string{"spot:PreferNoSchedule", "dedicated=gpu:NoSchedule"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("nodeTaintsOf(1) = %v; want %v", got, want)
+	}
+	if got, want := nodeLabelsOf(2), map[string]string{"pool": "gpu"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("nodeLabelsOf(2) = %v; want the labels of --node-labels %v", got, want)
+	}
+}
+
+func TestParseNodeResources(t *testing.T) {
+	tests := []struct {
+		cpus     int
+		memory   string
+		diskSize string
+		want     [3]int
+		wantErr  bool
+	}{
+		{0, "", "", [3]int{0, 0, 0}, false},
+		{8, "16g", "40000mb", [3]int{8, 16384, 40000}, false},
+		{0, "4096", "", [3]int{0, 4096, 0}, false},
+		{1, "", "", [3]int{}, true},
+		{0, "1g", "", [3]int{}, true},
+		{0, "lots", "", [3]int{}, true},
+		{0, "", "1000mb", [3]int{}, true},
+	}
+	for _, tc := range tests {
+		cpus, mem, disk, err := parseNodeResources(tc.cpus, tc.memory, tc.diskSize)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parseNodeResources(%d, %q, %q) error = %v, wantErr %v", tc.cpus, tc.memory, tc.diskSize, err, tc.wantErr)
+			continue
+		}
+		if got := [3]int{cpus, mem, disk}; !tc.wantErr && got != tc.want {
+			t.Errorf("parseNodeResources(%d, %q, %q) = %v; want %v", tc.cpus, tc.memory, tc.diskSize, got, tc.want)
+		}
+	}
+}
@@ -36,6 +36,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
 	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/driver"
@@ -68,6 +69,8 @@ const (
 	Nonexistent = "Nonexistent" // ~state.None
 	// Irrelevant is used for statuses that aren't meaningful for worker nodes
 	Irrelevant = "Irrelevant"
+	// AutoPaused means the cluster was paused by the auto-pause addon
+	AutoPaused = "Paused (auto)" // ~state.Paused
 )
 
 // New status modes, based roughly on HTTP/SMTP standards
@@ -140,6 +143,10 @@ type Status struct {
 	TimeToStop string `json:",omitempty"`
 	DockerEnv  string `json:",omitempty"`
 	PodManEnv  string `json:",omitempty"`
+	// CPUs, Memory and DiskSize (in MB) are only set for the clusters whose nodes have their own resources
+	CPUs     int `json:",omitempty"`
+	Memory   int `json:",omitempty"`
+	DiskSize int `json:",omitempty"`
 }
 
 // ClusterState holds a cluster state representation
@@ -195,12 +202,18 @@ docker-env: {{.DockerEnv}}
 {{- if .PodManEnv }}
 podman-env: {{.PodManEnv}}
 {{- end }}
+{{- if .CPUs }}
+resources: {{.CPUs}} CPUs, {{.Memory}}MB memory{{if .DiskSize}}, {{.DiskSize}}MB disk{{end}}
+{{- end }}
 
 `
 	workerStatusFormat = `{{.Name}}
 type: Worker
 host: {{.Host}}
 kubelet: {{.Kubelet}}
+{{- if .CPUs }}
+resources: {{.CPUs}} CPUs, {{.Memory}}MB memory{{if .DiskSize}}, {{.DiskSize}}MB disk{{end}}
+{{- end }}
 
 `
 )
@@ -223,6 +236,10 @@ var statusCmd = &cobra.Command{
 		cname := ClusterFlagValue()
 		api, cc := mustload.Partial(cname)
 
+		if output == "text" {
+			warnExpiringCerts(api, *cc)
+		}
+
 		duration := watch
 		if !cmd.Flags().Changed("watch") || watch < 0 {
 			duration = 0
@@ -231,6 +248,28 @@ var statusCmd = &cobra.Command{
 	},
 }
 
+// warnExpiringCerts warns about the certs of the cluster close to expiry, including the ones of the primary control plane if it is running
+func warnExpiringCerts(api libmachine.API, cc config.ClusterConfig) {
+	cp, err := config.PrimaryControlPlane(&cc)
+	if err != nil {
+		klog.Warningf("unable to get the primary control plane: %v", err)
+		return
+	}
+
+	var runner command.Runner
+	st, err := machine.Status(api, config.MachineName(cc, cp))
+	if err == nil && st == state.Running.String() {
+		h, err := machine.GetHost(api, cc, cp)
+		if err == nil {
+			runner, err = machine.CommandRunner(h)
+		}
+		if err != nil {
+			klog.Warningf("unable to get a command runner for %s: %v", config.MachineName(cc, cp), err)
+		}
+	}
+	node.WarnExpiringCerts(runner, cc, cp)
+}
+
 // writeStatusesAtInterval writes statuses in a given output format - at intervals defined by duration
 func writeStatusesAtInterval(duration time.Duration, api libmachine.API, cc *config.ClusterConfig) {
 	for {
@@ -323,6 +362,13 @@ func nodeStatus(api libmachine.API, cc config.ClusterConfig, n config.Node) (*St
 		Kubeconfig: Nonexistent,
 		Worker:     !controlPlane,
 	}
+	if config.HasNodeResources(cc) {
+		st.CPUs = config.NodeCPUs(cc, n)
+		st.Memory = config.NodeMemory(cc, n)
+		if !driver.IsKIC(cc.Driver) {
+			st.DiskSize = config.NodeDiskSize(cc, n)
+		}
+	}
 
 	hs, err := machine.Status(api, name)
 	klog.Infof("%s host status = %q (err=%v)", name, hs, err)
@@ -398,7 +444,12 @@ func nodeStatus(api libmachine.API, cc config.ClusterConfig, n config.Node) (*St
 
 	var hostname string
 	var port int
-	if cc.Addons["auto-pause"] {
+	primary := config.IsPrimaryControlPlane(cc, n)
+	if !primary {
+		// the kubeconfig points to the virtual IP, check the API server of this node
+		st.Kubeconfig = Irrelevant
+		hostname, _, port, err = driver.NodeEndpoint(&cc, &n, host.DriverName)
+	} else if cc.Addons["auto-pause"] {
 		hostname, _, port, err = driver.AutoPauseProxyEndpoint(&cc, &n, host.DriverName)
 	} else {
 		hostname, _, port, err = driver.ControlPlaneEndpoint(&cc, &n, host.DriverName)
@@ -407,7 +458,7 @@ func nodeStatus(api libmachine.API, cc config.ClusterConfig, n config.Node) (*St
 	if err != nil {
 		klog.Errorf("forwarded endpoint: %v", err)
 		st.Kubeconfig = Misconfigured
-	} else {
+	} else if primary {
 		err := kubeconfig.VerifyEndpoint(cc.Name, hostname, port)
 		if err != nil && st.Host != state.Starting.String() {
 			klog.Errorf("kubeconfig endpoint: %v", err)
@@ -415,6 +466,17 @@ func nodeStatus(api libmachine.API, cc config.ClusterConfig, n config.Node) (*St
 		}
 	}
 
+	// Checking the apiserver through the auto-pause proxy would unpause it, so ask the daemon first
+	if primary && cc.Addons["auto-pause"] {
+		aps, err := cluster.CheckAutoPause(cr, cc.KubernetesConfig)
+		if err != nil {
+			klog.Warningf("unable to get auto-pause status: %v", err)
+		} else if aps.Paused {
+			st.APIServer = AutoPaused
+			return st, nil
+		}
+	}
+
 	sta, err := kverify.APIServerStatus(cr, hostname, port)
 	klog.Infof("%s apiserver status = %s (err=%v)", name, stk, err)
 
@@ -640,6 +702,8 @@ func statusCode(st string) int {
 		return OK
 	case "Misconfigured":
 		return Error
+	case AutoPaused:
+		return Paused
 	}
 
 	// new names
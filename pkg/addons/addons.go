@@ -33,6 +33,7 @@ import (
 	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/kapi"
 	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/cni"
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
@@ -44,6 +45,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/reason"
 	"k8s.io/minikube/pkg/minikube/style"
 	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/trace"
 	"k8s.io/minikube/pkg/util/retry"
 )
 
@@ -233,6 +235,12 @@ https://github.com/kubernetes/minikube/issues/7332`, out.V{"driver_name": cc.Dri
 	if len(cc.Nodes) >= 1 {
 		networkInfo.ControlPlaneNodeIP = cc.Nodes[0].IP
 		networkInfo.ControlPlaneNodePort = cc.Nodes[0].Port
+		if cnm, err := cni.New(cc); err == nil {
+			networkInfo.PodCIDR = cnm.CIDR()
+		} else {
+			klog.Warningf("unable to get the pod CIDR: %v", err)
+			networkInfo.PodCIDR = cni.DefaultPodCIDR
+		}
 	} else {
 		out.WarningT("At least needs control plane nodes to enable addon")
 	}
@@ -386,7 +394,10 @@ func Start(wg *sync.WaitGroup, cc *config.ClusterConfig, toEnable map[string]boo
 	for _, a := range toEnableList {
 		awg.Add(1)
 		go func(name string) {
+			span := trace.SpanName(trace.AddonEnable, name)
+			trace.StartSpan(span)
 			err := RunCallbacks(cc, name, "true")
+			trace.EndSpan(span)
 			if err != nil {
 				out.WarningT("Enabling '{{.name}}' returned an error: {{.error}}", out.V{"name": name, "error": err})
 			} else {
@@ -17,11 +17,13 @@ limitations under the License.
 package addons
 
 import (
+	"os/exec"
 	"strconv"
 
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/kapi"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/driver"
@@ -71,3 +73,18 @@ func enableOrDisableAutoPause(cc *config.ClusterConfig, name string, val string)
 
 	return nil
 }
+
+// RestartAutoPause restarts the auto-pause daemon and proxy, so that they pick up a new configuration
+func RestartAutoPause(cc *config.ClusterConfig) error {
+	co := mustload.Running(cc.Name)
+	if err := sysinit.New(co.CP.Runner).Restart("auto-pause"); err != nil {
+		return errors.Wrap(err, "restarting auto-pause")
+	}
+
+	kubectl := kapi.KubectlBinaryPath(cc.KubernetesConfig.KubernetesVersion)
+	cmd := exec.Command("sudo", "KUBECONFIG=/var/lib/minikube/kubeconfig", kubectl, "rollout", "restart", "deployment/auto-pause-proxy", "--namespace=auto-pause")
+	if _, err := co.CP.Runner.RunCmd(cmd); err != nil {
+		return errors.Wrap(err, "restarting auto-pause proxy")
+	}
+	return nil
+}
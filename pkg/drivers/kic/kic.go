@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -30,6 +31,7 @@ import (
 	"github.com/docker/machine/libmachine/drivers"
 	"github.com/docker/machine/libmachine/ssh"
 	"github.com/docker/machine/libmachine/state"
+	"github.com/juju/mutex"
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 
@@ -41,9 +43,11 @@ import (
 	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/download"
 	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/style"
 	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/util/lock"
 	"k8s.io/minikube/pkg/util/retry"
 )
 
@@ -71,6 +75,14 @@ func NewDriver(c Config) *Driver {
 	return d
 }
 
+// acquireNetworkLock serializes the creation of the containers on a network, by goroutines as well as processes
+func acquireNetworkLock(networkName string) (mutex.Releaser, error) {
+	spec := lock.PathMutexSpec(filepath.Join(localpath.MiniPath(), "networks", networkName))
+	spec.Timeout = 10 * time.Minute
+	klog.Infof("acquiring lock for network %s: %+v", networkName, spec)
+	return mutex.Acquire(spec)
+}
+
 // Create a host using the driver's config
 func (d *Driver) Create() error {
 	ctx := context.Background()
@@ -88,24 +100,6 @@ func (d *Driver) Create() error {
 		APIServerPort: d.NodeConfig.APIServerPort,
 	}
 
-	networkName := d.NodeConfig.Network
-	if networkName == "" {
-		networkName = d.NodeConfig.ClusterName
-	}
-	if gateway, err := oci.CreateNetwork(d.OCIBinary, networkName); err != nil {
-		out.WarningT("Unable to create dedicated network, this might result in cluster IP change after restart: {{.error}}", out.V{"error": err})
-	} else if gateway != nil {
-		params.Network = networkName
-		ip := gateway.To4()
-		// calculate the container IP based on guessing the machine index
-		index := driver.IndexFromMachineName(d.NodeConfig.MachineName)
-		if int(ip[3])+index > 255 {
-			return fmt.Errorf("too many machines to calculate an IP")
-		}
-		ip[3] += byte(index)
-		klog.Infof("calculated static IP %q for the %q container", ip.String(), d.NodeConfig.MachineName)
-		params.IP = ip.String()
-	}
 	drv := d.DriverName()
 
 	listAddr := oci.DefaultBindIPV4
@@ -162,6 +156,42 @@ func (d *Driver) Create() error {
 		}
 	}
 
+	var releaser mutex.Releaser
+	networkName := d.NodeConfig.Network
+	if networkName == "" {
+		networkName = d.NodeConfig.ClusterName
+	}
+	if gateway, err := oci.CreateNetwork(d.OCIBinary, networkName, d.NodeConfig.Subnet); err != nil {
+		if d.NodeConfig.StaticIP != "" {
+			return errors.Wrapf(err, "create network %s for static IP %s", networkName, d.NodeConfig.StaticIP)
+		}
+		out.WarningT("Unable to create dedicated network, this might result in cluster IP change after restart: {{.error}}", out.V{"error": err})
+	} else if gateway != nil {
+		params.Network = networkName
+		// the nodes created concurrently on the network would pick the same IP: hold the lock until the container has taken it
+		releaser, err = acquireNetworkLock(networkName)
+		if err != nil {
+			return errors.Wrapf(err, "acquire lock for network %s", networkName)
+		}
+		defer releaser.Release()
+		// calculate the container IP based on guessing the machine index, other containers may share the network
+		index := driver.IndexFromMachineName(d.NodeConfig.MachineName)
+		ip, err := oci.NodeIP(d.OCIBinary, networkName, index, d.NodeConfig.StaticIP)
+		if err != nil {
+			return errors.Wrap(err, "calculate IP")
+		}
+		klog.Infof("calculated static IP %q for the %q container", ip, d.NodeConfig.MachineName)
+		params.IP = ip
+	} else if d.NodeConfig.StaticIP != "" {
+		return fmt.Errorf("static IP %s is not supported on the default %s network", d.NodeConfig.StaticIP, networkName)
+	}
+
+	for _, n := range d.NodeConfig.ExtraNetworks {
+		if !oci.NetworkExists(d.OCIBinary, n) {
+			return fmt.Errorf("network %s does not exist, extra networks must be created beforehand", n)
+		}
+	}
+
 	if err := oci.PrepareContainerNode(params); err != nil {
 		return errors.Wrap(err, "setting up container node")
 	}
@@ -192,10 +222,20 @@ func (d *Driver) Create() error {
 		return pErr
 	}
 
-	if err := oci.CreateContainerNode(params); err != nil {
+	err = oci.CreateContainerNode(params)
+	if releaser != nil {
+		releaser.Release()
+	}
+	if err != nil {
 		return errors.Wrap(err, "create kic node")
 	}
 
+	for _, n := range d.NodeConfig.ExtraNetworks {
+		if err := oci.ConnectNetwork(d.OCIBinary, n, params.Name); err != nil {
+			return errors.Wrap(err, "attach kic node to extra network")
+		}
+	}
+
 	if err := d.prepareSSH(); err != nil {
 		return errors.Wrap(err, "prepare kic ssh")
 	}
@@ -205,7 +205,7 @@ func podmanContainerIP(ociBin string, name string) (string, string, error) {
 // dockerContainerIP returns ipv4, ipv6 of container or error
 func dockerContainerIP(ociBin string, name string) (string, string, error) {
 	// retrieve the IP address of the node using docker inspect
-	lines, err := inspect(ociBin, name, "{{range .NetworkSettings.Networks}}{{.IPAddress}},{{.GlobalIPv6Address}}{{end}}")
+	lines, err := inspect(ociBin, name, "{{.HostConfig.NetworkMode}}{{range $k, $v := .NetworkSettings.Networks}};{{$k}},{{$v.IPAddress}},{{$v.GlobalIPv6Address}}{{end}}")
 	if err != nil {
 		return "", "", errors.Wrap(err, "inspecting NetworkSettings.Networks")
 	}
@@ -213,10 +213,30 @@ func dockerContainerIP(ociBin string, name string) (string, string, error) {
 	if len(lines) != 1 {
 		return "", "", errors.Errorf("IPs output should only be one line, got %d lines", len(lines))
 	}
+	return networkModeIPs(lines[0])
+}
+
+// networkModeIPs returns the addresses of a container on the network it was created with, as opposed to the
+// networks it was attached to afterwards. The inspect output looks like: shared;extra,192.168.58.2,;shared,192.168.49.2,
+func networkModeIPs(line string) (string, string, error) {
+	fields := strings.Split(line, ";")
+	mode, networks := fields[0], fields[1:]
+	if len(networks) == 0 {
+		return "", "", errors.Errorf("container is not attached to any network: %q", line)
+	}
 
-	ips := strings.Split(lines[0], ",")
-	if len(ips) != 2 {
-		return "", "", errors.Errorf("container addresses should have 2 values, got %d values: %+v", len(ips), ips)
+	ips := strings.Split(networks[0], ",")
+	for _, n := range networks {
+		vals := strings.Split(n, ",")
+		if len(vals) != 3 {
+			return "", "", errors.Errorf("container addresses should have 3 values, got %d values: %+v", len(vals), vals)
+		}
+		if vals[0] == mode {
+			ips = vals
+		}
+	}
+	if len(ips) != 3 {
+		return "", "", errors.Errorf("container addresses should have 3 values, got %d values: %+v", len(ips), ips)
 	}
-	return ips[0], ips[1], nil
+	return ips[1], ips[2], nil
 }
@@ -19,6 +19,7 @@ package oci
 import (
 	"bufio"
 	"bytes"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"net"
@@ -53,8 +54,9 @@ func defaultBridgeName(ociBin string) string {
 	}
 }
 
-// CreateNetwork creates a network returns gateway and error, minikube creates one network per cluster
-func CreateNetwork(ociBin string, networkName string) (net.IP, error) {
+// CreateNetwork creates a network returns gateway and error, minikube creates one network per cluster.
+// If subnet is empty, a free private subnet is picked.
+func CreateNetwork(ociBin string, networkName string, subnet string) (net.IP, error) {
 	defaultBridgeName := defaultBridgeName(ociBin)
 	if networkName == defaultBridgeName {
 		klog.Infof("skipping creating network since default network %s was specified", networkName)
@@ -65,6 +67,9 @@ func CreateNetwork(ociBin string, networkName string) (net.IP, error) {
 	info, err := containerNetworkInspect(ociBin, networkName)
 	if err == nil {
 		klog.Infof("Found existing network %+v", info)
+		if subnet != "" && info.subnet != nil && info.subnet.String() != subnet {
+			klog.Warningf("existing network %s has subnet %s, not the requested %s", networkName, info.subnet, subnet)
+		}
 		return info.gateway, nil
 	}
 
@@ -75,6 +80,19 @@ func CreateNetwork(ociBin string, networkName string) (net.IP, error) {
 		klog.Warningf("failed to get mtu information from the %s's default network %q: %v", ociBin, defaultBridgeName, err)
 	}
 
+	if subnet != "" {
+		params, err := network.Inspect(subnet)
+		if err != nil {
+			return nil, fmt.Errorf("un-retryable: %w", err)
+		}
+		gateway, err := tryCreateDockerNetwork(ociBin, params, info.mtu, networkName)
+		if err != nil {
+			return nil, fmt.Errorf("un-retryable: %w", err)
+		}
+		klog.Infof("%s network %s %s created", ociBin, networkName, params.CIDR)
+		return gateway, nil
+	}
+
 	// retry up to 5 times to create container network
 	for attempts, subnetAddr := 0, firstSubnetAddr; attempts < 5; attempts++ {
 		// Rather than iterate through all of the valid subnets, give up at 20 to avoid a lengthy user delay for something that is unlikely to work.
@@ -147,10 +165,11 @@ func tryCreateDockerNetwork(ociBin string, subnet *network.Parameters, mtu int,
 
 // netInfo holds part of a docker or podman network information relevant to kic drivers
 type netInfo struct {
-	name    string
-	subnet  *net.IPNet
-	gateway net.IP
-	mtu     int
+	name         string
+	subnet       *net.IPNet
+	gateway      net.IP
+	mtu          int
+	containerIPs []net.IP // addresses of the running containers attached to the network
 }
 
 func containerNetworkInspect(ociBin string, name string) (netInfo, error) {
@@ -211,6 +230,15 @@ func dockerNetworkInspect(name string) (netInfo, error) {
 		return info, errors.Wrapf(err, "parse subnet for %s", name)
 	}
 
+	for _, c := range vals.ContainerIPs {
+		ip, _, err := net.ParseCIDR(c)
+		if err != nil {
+			klog.Warningf("invalid container address %q in network %s: %v", c, name, err)
+			continue
+		}
+		info.containerIPs = append(info.containerIPs, ip)
+	}
+
 	return info, nil
 }
 
@@ -247,9 +275,47 @@ func podmanNetworkInspect(name string) (netInfo, error) {
 		return info, errors.Wrapf(err, "parse subnet for %s", name)
 	}
 
+	// the network inspect output of podman does not list the containers attached to it
+	info.containerIPs, err = podmanContainerIPs(name)
+	if err != nil {
+		return info, errors.Wrapf(err, "container addresses in %s", name)
+	}
+
 	return info, nil
 }
 
+var podmanContainerIPsGetter = func(name string) (*RunResult, error) {
+	rr, err := runCmd(exec.Command(Podman, "ps", "-q", "--filter", "network="+name))
+	if err != nil {
+		return rr, err
+	}
+	ids := strings.Fields(rr.Stdout.String())
+	if len(ids) == 0 {
+		return &RunResult{}, nil
+	}
+	format := fmt.Sprintf(`{{range $k,$v := .NetworkSettings.Networks}}{{if eq $k %q}}{{$v.IPAddress}}{{end}}{{end}}`, name)
+	return runCmd(exec.Command(Podman, append([]string{"container", "inspect", "--format", format}, ids...)...))
+}
+
+// podmanContainerIPs returns the addresses of the running podman containers attached to a network
+func podmanContainerIPs(name string) ([]net.IP, error) {
+	rr, err := podmanContainerIPsGetter(name)
+	if err != nil {
+		return nil, err
+	}
+	var ips []net.IP
+	// results looks like one address per container: 192.168.49.2
+	for _, s := range strings.Fields(rr.Stdout.String()) {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			klog.Warningf("invalid container address %q in network %s", s, name)
+			continue
+		}
+		ips = append(ips, ip)
+	}
+	return ips, nil
+}
+
 func logDockerNetworkInspect(ociBin string, name string) {
 	cmd := exec.Command(ociBin, "network", "inspect", name)
 	klog.Infof("running %v to gather additional debugging logs...", cmd.Args)
@@ -260,9 +326,149 @@ func logDockerNetworkInspect(ociBin string, name string) {
 	klog.Infof("output of %v: %v", rr.Args, rr.Output())
 }
 
+// ValidateSubnet checks that subnet is a private IPv4 network in CIDR form, with room for a few nodes
+func ValidateSubnet(subnet string) error {
+	ip, ipnet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return err
+	}
+	if ip.To4() == nil {
+		return fmt.Errorf("%s is not an IPv4 subnet", subnet)
+	}
+	if !network.IsPrivate(ip) {
+		return fmt.Errorf("%s is not a private subnet", subnet)
+	}
+	if ones, bits := ipnet.Mask.Size(); bits-ones < 3 {
+		return fmt.Errorf("%s is too small, the prefix length must be at most 29", subnet)
+	}
+	return nil
+}
+
+// StaticIPSubnet returns the /24 subnet containing ip, used for the network of the cluster if no subnet is set
+func StaticIPSubnet(ip string) string {
+	v4 := net.ParseIP(ip).To4()
+	if v4 == nil {
+		return ""
+	}
+	return (&net.IPNet{IP: v4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+}
+
+// ValidateStaticIP checks that ip is a private IPv4 address which can be given to a node of the subnet,
+// if it is set: neither its network, gateway nor broadcast address.
+func ValidateStaticIP(ip string, subnet string) error {
+	v4 := net.ParseIP(ip).To4()
+	if v4 == nil {
+		return fmt.Errorf("%s is not an IPv4 address", ip)
+	}
+	if !network.IsPrivate(v4) {
+		return fmt.Errorf("%s is not a private address", ip)
+	}
+	if subnet == "" {
+		return nil
+	}
+	_, ipnet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return err
+	}
+	return checkNodeAddress(v4, ipnet)
+}
+
+// checkNodeAddress checks that ip is in ipnet, and is neither its network, gateway nor broadcast address,
+// nor the last usable one, which is reserved for the virtual IP of the HA clusters on the network
+func checkNodeAddress(ip net.IP, ipnet *net.IPNet) error {
+	if !ipnet.Contains(ip) {
+		return fmt.Errorf("%s is not in the subnet %s", ip, ipnet)
+	}
+	first := binary.BigEndian.Uint32(ipnet.IP.To4())
+	last := first | ^binary.BigEndian.Uint32(ipnet.Mask)
+	switch binary.BigEndian.Uint32(ip.To4()) {
+	case first, first + 1:
+		return fmt.Errorf("%s is the network or gateway address of %s", ip, ipnet)
+	case last:
+		return fmt.Errorf("%s is the broadcast address of %s", ip, ipnet)
+	case last - 1:
+		return fmt.Errorf("%s is reserved for the virtual IP of the HA clusters on %s", ip, ipnet)
+	}
+	return nil
+}
+
+// NodeIP returns the address of the container of the node at index on a network: staticIP if it is set,
+// otherwise the address at index after the gateway, or the next one which is not used by another container
+func NodeIP(ociBin string, networkName string, index int, staticIP string) (string, error) {
+	info, err := containerNetworkInspect(ociBin, networkName)
+	if err != nil {
+		return "", errors.Wrapf(err, "inspect network %s", networkName)
+	}
+	ip, err := nodeIP(info, index, staticIP)
+	if err != nil {
+		return "", err
+	}
+	return ip.String(), nil
+}
+
+func nodeIP(info netInfo, index int, staticIP string) (net.IP, error) {
+	used := func(ip net.IP) bool {
+		for _, c := range info.containerIPs {
+			if c.Equal(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if staticIP != "" {
+		ip := net.ParseIP(staticIP).To4()
+		if ip == nil {
+			return nil, fmt.Errorf("invalid static IP %q", staticIP)
+		}
+		if info.subnet != nil {
+			if err := checkNodeAddress(ip, info.subnet); err != nil {
+				return nil, errors.Wrapf(err, "network %s", info.name)
+			}
+		}
+		if used(ip) {
+			return nil, fmt.Errorf("static IP %s is already used by another container on network %s", staticIP, info.name)
+		}
+		return ip, nil
+	}
+
+	gateway := info.gateway.To4()
+	if gateway == nil {
+		return nil, fmt.Errorf("network %s has no IPv4 gateway", info.name)
+	}
+	if info.subnet == nil {
+		if int(gateway[3])+index > 255 {
+			return nil, fmt.Errorf("too many machines to calculate an IP")
+		}
+		return network.IPOffset(gateway, index), nil
+	}
+	for ip := network.IPOffset(gateway, index); ; ip = network.IPOffset(ip, 1) {
+		if checkNodeAddress(ip, info.subnet) != nil {
+			return nil, fmt.Errorf("no free address left on network %s", info.name)
+		}
+		if !used(ip) {
+			return ip, nil
+		}
+		klog.Infof("skipping %s, which is used by another container on network %s", ip, info.name)
+	}
+}
+
+// ConnectNetwork attaches a container to an existing network, in addition to the ones it is attached to
+func ConnectNetwork(ociBin string, networkName string, container string) error {
+	rr, err := runCmd(exec.Command(ociBin, "network", "connect", networkName, container))
+	if err != nil {
+		// Error response from daemon: endpoint with name minikube already exists in network shared
+		if strings.Contains(rr.Output(), "already exists") {
+			return nil
+		}
+		return errors.Wrapf(err, "connect %s to network %s", container, networkName)
+	}
+	return nil
+}
+
 // RemoveNetwork removes a network
 func RemoveNetwork(ociBin string, name string) error {
-	if !networkExists(ociBin, name) {
+	if !NetworkExists(ociBin, name) {
 		return nil
 	}
 	rr, err := runCmd(exec.Command(ociBin, "network", "rm", name))
@@ -279,7 +485,8 @@ func RemoveNetwork(ociBin string, name string) error {
 	return err
 }
 
-func networkExists(ociBin string, name string) bool {
+// NetworkExists returns whether a network exists
+func NetworkExists(ociBin string, name string) bool {
 	_, err := containerNetworkInspect(ociBin, name)
 	if err != nil && !errors.Is(err, ErrNetworkNotFound) { // log unexpected error
 		klog.Warningf("Error inspecting docker network %s: %v", name, err)
@@ -82,3 +82,122 @@ func TestDockerInspect(t *testing.T) {
 		})
 	}
 }
+
+func TestPodmanContainerIPs(t *testing.T) {
+	old := podmanContainerIPsGetter
+	t.Cleanup(func() { podmanContainerIPsGetter = old })
+	podmanContainerIPsGetter = func(name string) (*RunResult, error) {
+		return &RunResult{Stdout: *bytes.NewBufferString("192.168.49.2\n\n192.168.49.3\nnot-an-ip\n")}, nil
+	}
+
+	ips, err := podmanContainerIPs("minikube")
+	if err != nil {
+		t.Fatalf("podmanContainerIPs() failed: %v", err)
+	}
+	if len(ips) != 2 || !ips[0].Equal(net.ParseIP("192.168.49.2")) || !ips[1].Equal(net.ParseIP("192.168.49.3")) {
+		t.Errorf("podmanContainerIPs() = %v; want [192.168.49.2 192.168.49.3]", ips)
+	}
+	info := netInfo{name: "minikube", gateway: net.ParseIP("192.168.49.1"), containerIPs: ips}
+	_, info.subnet, _ = net.ParseCIDR("192.168.49.0/24")
+	ip, err := nodeIP(info, 1, "")
+	if err != nil {
+		t.Fatalf("nodeIP() failed: %v", err)
+	}
+	if !ip.Equal(net.ParseIP("192.168.49.4")) {
+		t.Errorf("nodeIP() = %s; want 192.168.49.4, the first address no podman container uses", ip)
+	}
+}
+
+func TestValidateStaticIP(t *testing.T) {
+	tests := []struct {
+		ip      string
+		subnet  string
+		wantErr bool
+	}{
+		{"192.168.49.2", "", false},
+		{"192.168.49.2", "192.168.49.0/24", false},
+		{"10.0.0.253", "10.0.0.0/24", false},
+		{"10.0.0.254", "10.0.0.0/24", true},
+		{"192.168.49.0", "192.168.49.0/24", true},
+		{"192.168.49.1", "192.168.49.0/24", true},
+		{"192.168.49.255", "192.168.49.0/24", true},
+		{"192.168.50.2", "192.168.49.0/24", true},
+		{"8.8.8.8", "", true},
+		{"fd00::2", "", true},
+		{"minikube", "", true},
+	}
+	for _, tc := range tests {
+		err := ValidateStaticIP(tc.ip, tc.subnet)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ValidateStaticIP(%q, %q) = %v; want error: %v", tc.ip, tc.subnet, err, tc.wantErr)
+		}
+	}
+}
+
+func TestValidateSubnet(t *testing.T) {
+	tests := []struct {
+		subnet  string
+		wantErr bool
+	}{
+		{"192.168.49.0/24", false},
+		{"172.20.0.0/16", false},
+		{"10.1.2.0/29", false},
+		{"10.1.2.0/30", true},
+		{"8.8.8.0/24", true},
+		{"192.168.49.0", true},
+		{"fd00::/64", true},
+	}
+	for _, tc := range tests {
+		err := ValidateSubnet(tc.subnet)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ValidateSubnet(%q) = %v; want error: %v", tc.subnet, err, tc.wantErr)
+		}
+	}
+}
+
+func TestStaticIPSubnet(t *testing.T) {
+	if got := StaticIPSubnet("192.168.58.10"); got != "192.168.58.0/24" {
+		t.Errorf("StaticIPSubnet() = %q; want 192.168.58.0/24", got)
+	}
+	if got := StaticIPSubnet("invalid"); got != "" {
+		t.Errorf("StaticIPSubnet() = %q; want empty", got)
+	}
+}
+
+func TestNodeIP(t *testing.T) {
+	_, subnet, _ := net.ParseCIDR("192.168.49.0/29")
+	info := netInfo{
+		name:         "shared",
+		subnet:       subnet,
+		gateway:      net.ParseIP("192.168.49.1"),
+		containerIPs: []net.IP{net.ParseIP("192.168.49.2"), net.ParseIP("192.168.49.4")},
+	}
+	tests := []struct {
+		description string
+		index       int
+		staticIP    string
+		want        string
+		wantErr     bool
+	}{
+		{"free address", 2, "", "192.168.49.3", false},
+		{"skips used addresses", 1, "", "192.168.49.3", false},
+		{"skips to the next free address", 3, "", "192.168.49.5", false},
+		{"subnet is full", 6, "", "", true},
+		{"virtual IP is reserved", 5, "", "", true},
+		{"static IP", 1, "192.168.49.5", "192.168.49.5", false},
+		{"static IP reserved for the virtual IP", 1, "192.168.49.6", "", true},
+		{"static IP used by another container", 1, "192.168.49.4", "", true},
+		{"static IP out of the subnet", 1, "192.168.49.9", "", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			ip, err := nodeIP(info, tc.index, tc.staticIP)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("nodeIP() error = %v; want error: %v", err, tc.wantErr)
+			}
+			if err == nil && ip.String() != tc.want {
+				t.Errorf("nodeIP() = %s; want %s", ip, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import "testing"
+
+func TestNetworkModeIPs(t *testing.T) {
+	tests := []struct {
+		line     string
+		ipv4     string
+		ipv6     string
+		hasError bool
+	}{
+		{"minikube;minikube,192.168.49.2,", "192.168.49.2", "", false},
+		{"shared;extra,192.168.58.2,;shared,192.168.49.3,fd00::3", "192.168.49.3", "fd00::3", false},
+		{"default;bridge,172.17.0.2,", "172.17.0.2", "", false},
+		{"minikube", "", "", true},
+		{"minikube;minikube,192.168.49.2", "", "", true},
+	}
+	for _, tc := range tests {
+		ipv4, ipv6, err := networkModeIPs(tc.line)
+		if (err != nil) != tc.hasError {
+			t.Errorf("networkModeIPs(%q) error = %v; want error: %v", tc.line, err, tc.hasError)
+		}
+		if ipv4 != tc.ipv4 || ipv6 != tc.ipv6 {
+			t.Errorf("networkModeIPs(%q) = %q, %q; want %q, %q", tc.line, ipv4, ipv6, tc.ipv4, tc.ipv6)
+		}
+	}
+}
@@ -139,6 +139,49 @@ func hasMemorySwapCgroup() bool {
 	return memcgSwap
 }
 
+// hasCPUCfs returns whether the kernel supports CPU cfs period and quota, which limit the CPUs of a container
+func hasCPUCfs() bool {
+	if runtime.GOOS != "linux" {
+		return true
+	}
+	cpuCfsPeriod := true
+	cpuCfsQuota := true
+	if _, err := os.Stat("/sys/fs/cgroup/cpu/cpu.cfs_period_us"); os.IsNotExist(err) {
+		cpuCfsPeriod = false
+	}
+	if _, err := os.Stat("/sys/fs/cgroup/cpu/cpu.cfs_quota_us"); os.IsNotExist(err) {
+		cpuCfsQuota = false
+	}
+	if !cpuCfsPeriod || !cpuCfsQuota {
+		// requires CONFIG_CFS_BANDWIDTH
+		klog.Warning("Your kernel does not support CPU cfs period/quota or the cgroup is not mounted.")
+		return false
+	}
+	return true
+}
+
+// UpdateContainerResources changes the CPUs and memory limits of a container in place, memory is formatted as for CreateParams
+func UpdateContainerResources(ociBin string, name string, cpus string, memory string) error {
+	args := []string{"update"}
+	if hasCPUCfs() {
+		args = append(args, fmt.Sprintf("--cpus=%s", cpus))
+	}
+	if HasMemoryCgroup() {
+		args = append(args, fmt.Sprintf("--memory=%s", memory))
+	}
+	if hasMemorySwapCgroup() {
+		// swap stays disabled by matching the memory limit
+		args = append(args, fmt.Sprintf("--memory-swap=%s", memory))
+	}
+	if len(args) == 1 {
+		return fmt.Errorf("the cgroups of %s do not support limiting the resources of containers", ociBin)
+	}
+	if rr, err := runCmd(exec.Command(ociBin, append(args, name)...)); err != nil {
+		return errors.Wrapf(err, "update resources of %s: %s", name, rr.Output())
+	}
+	return nil
+}
+
 // CreateContainerNode creates a new container node
 func CreateContainerNode(p CreateParams) error {
 	// on windows os, if docker desktop is using Windows Containers. Exit early with error
@@ -219,22 +262,7 @@ func CreateContainerNode(p CreateParams) error {
 		virtualization = "docker" // VIRTUALIZATION_DOCKER
 	}
 
-	cpuCfsPeriod := true
-	cpuCfsQuota := true
-	if runtime.GOOS == "linux" {
-		if _, err := os.Stat("/sys/fs/cgroup/cpu/cpu.cfs_period_us"); os.IsNotExist(err) {
-			cpuCfsPeriod = false
-		}
-		if _, err := os.Stat("/sys/fs/cgroup/cpu/cpu.cfs_quota_us"); os.IsNotExist(err) {
-			cpuCfsQuota = false
-		}
-		if !cpuCfsPeriod || !cpuCfsQuota {
-			// requires CONFIG_CFS_BANDWIDTH
-			klog.Warning("Your kernel does not support CPU cfs period/quota or the cgroup is not mounted.")
-		}
-	}
-
-	if cpuCfsPeriod && cpuCfsQuota {
+	if hasCPUCfs() {
 		runArgs = append(runArgs, fmt.Sprintf("--cpus=%s", p.CPUs))
 	}
 
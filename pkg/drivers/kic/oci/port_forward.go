@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PortForwardLabelKey is applied to the proxy containers forwarding ports of the host to the nodes of a profile port.minikube.sigs.k8s.io=PROFILE_NAME
+const PortForwardLabelKey = "port.minikube.sigs.k8s.io"
+
+// PortForwardParams are the parameters of a proxy container forwarding a port of the host to a port of a node
+type PortForwardParams struct {
+	Name          string // name of the proxy container
+	Profile       string // name of the profile of the node
+	Image         string // image to run socat from, the kic base image
+	Network       string // network to reach the node on, the default one if empty
+	ListenAddress string // IP address of the host to listen on
+	HostPort      int
+	Protocol      string // tcp or udp
+	NodeIP        string
+	NodePort      int
+	OCIBinary     string // docker or podman
+}
+
+// CreatePortForward runs a proxy container which forwards a port of the host to a port of a node.
+// The container is restarted with the container runtime, until it is stopped.
+func CreatePortForward(p PortForwardParams) error {
+	proto := strings.ToUpper(p.Protocol)
+	args := []string{
+		"run", "-d",
+		"--name", p.Name,
+		"--restart=unless-stopped",
+		"--label", fmt.Sprintf("%s=%s", CreatedByLabelKey, "true"),
+		"--label", fmt.Sprintf("%s=%s", PortForwardLabelKey, p.Profile),
+		fmt.Sprintf("--publish=%s:%d:%d/%s", p.ListenAddress, p.HostPort, p.HostPort, p.Protocol),
+		"--entrypoint", "socat",
+	}
+	if p.Network != "" {
+		args = append(args, "--network", p.Network)
+	}
+	args = append(args, p.Image,
+		fmt.Sprintf("%s-LISTEN:%d,fork,reuseaddr", proto, p.HostPort),
+		fmt.Sprintf("%s:%s:%d", proto, p.NodeIP, p.NodePort))
+
+	if rr, err := runCmd(exec.Command(p.OCIBinary, args...)); err != nil {
+		return errors.Wrapf(err, "create port forward %s: %s", p.Name, rr.Output())
+	}
+	return nil
+}
+
+// StopContainer stops a container with "docker/podman stop"
+func StopContainer(ociBin string, name string) error {
+	if rr, err := runCmd(exec.Command(ociBin, "stop", name)); err != nil {
+		return errors.Wrapf(err, "stop %s: %s", name, rr.Output())
+	}
+	return nil
+}
+
+// RemoveContainer removes a container which does not need to be shut down first, unlike the nodes
+func RemoveContainer(ociBin string, name string) error {
+	if rr, err := runCmd(exec.Command(ociBin, "rm", "-f", name)); err != nil {
+		return errors.Wrapf(err, "remove %s: %s", name, rr.Output())
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RegistryCacheParams are the parameters of a registry container proxying and caching a remote registry
+type RegistryCacheParams struct {
+	Name            string   // name of the registry container
+	Image           string   // image of the registry
+	Volume          string   // volume the cached images are stored in, kept when the container is deleted
+	HostPort        int      // port of the host the registry is published on
+	ListenAddresses []string // addresses of the host the registry is published on, the ones the nodes reach it on
+	RemoteURL       string   // URL of the registry to proxy
+	OCIBinary       string   // docker or podman
+}
+
+// CreateRegistryCache runs a pull-through cache registry container.
+// The container is restarted with the container runtime, until it is stopped.
+func CreateRegistryCache(p RegistryCacheParams) error {
+	args := []string{
+		"run", "-d",
+		"--name", p.Name,
+		"--restart=unless-stopped",
+		"--label", fmt.Sprintf("%s=%s", CreatedByLabelKey, "true"),
+	}
+	for _, addr := range p.ListenAddresses {
+		args = append(args, fmt.Sprintf("--publish=%s:%d:5000", addr, p.HostPort))
+	}
+	args = append(args,
+		"--volume", fmt.Sprintf("%s:/var/lib/registry", p.Volume),
+		"--env", fmt.Sprintf("REGISTRY_PROXY_REMOTEURL=%s", p.RemoteURL),
+		p.Image,
+	)
+	if rr, err := runCmd(exec.Command(p.OCIBinary, args...)); err != nil {
+		return errors.Wrapf(err, "create registry cache %s: %s", p.Name, rr.Output())
+	}
+	return nil
+}
+
+// RegistryCacheAddresses returns the addresses of the host a registry container is published on
+func RegistryCacheAddresses(ociBin string, name string) ([]string, error) {
+	rr, err := runCmd(exec.Command(ociBin, "container", "inspect", "-f", `{{range (index .HostConfig.PortBindings "5000/tcp")}}{{.HostIp}} {{end}}`, name))
+	if err != nil {
+		return nil, errors.Wrapf(err, "inspect %s", name)
+	}
+	return strings.Fields(rr.Stdout.String()), nil
+}
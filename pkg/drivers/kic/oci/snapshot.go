@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// SnapshotLabelKey is applied to the volumes holding snapshots, which are not volumes of a node but are removed with the snapshots of the profile
+const SnapshotLabelKey = "snapshot.minikube.sigs.k8s.io"
+
+// snapshotConfigDir is the directory of the node restored from the committed container, the rest of the state lives in the /var volume
+const snapshotConfigDir = "/etc/kubernetes"
+
+// SnapshotImage returns the image the container of a node is committed to for a snapshot
+func SnapshotImage(nodeName string, snapshot string) string {
+	return fmt.Sprintf("minikube-snapshot/%s:%s", nodeName, snapshot)
+}
+
+// SnapshotVolume returns the name of the volume holding a copy of the /var volume of a node for a snapshot
+func SnapshotVolume(nodeName string, snapshot string) string {
+	return fmt.Sprintf("%s-snapshot-%s", nodeName, snapshot)
+}
+
+// SaveSnapshot commits the container of a node to an image and copies its /var volume, pausing the container if it is running
+func SaveSnapshot(ociBin string, nodeName string, snapshot string) error {
+	running, err := ContainerRunning(ociBin, nodeName)
+	if err != nil {
+		return errors.Wrapf(err, "checking %s is running", nodeName)
+	}
+	if running {
+		if _, err := runCmd(exec.Command(ociBin, "pause", nodeName)); err != nil {
+			return errors.Wrapf(err, "pausing %s", nodeName)
+		}
+		defer func() {
+			if _, err := runCmd(exec.Command(ociBin, "unpause", nodeName)); err != nil {
+				klog.Errorf("unable to unpause %s: %v", nodeName, err)
+			}
+		}()
+	}
+
+	img := SnapshotImage(nodeName, snapshot)
+	if _, err := runCmd(exec.Command(ociBin, "commit", "--pause=false", nodeName, img)); err != nil {
+		return errors.Wrapf(err, "committing %s", nodeName)
+	}
+
+	vol := SnapshotVolume(nodeName, snapshot)
+	if _, err := runCmd(exec.Command(ociBin, "volume", "create", vol, "--label", fmt.Sprintf("%s=%s", SnapshotLabelKey, snapshot), "--label", fmt.Sprintf("%s=%s", CreatedByLabelKey, "true"))); err != nil {
+		return errors.Wrapf(err, "creating volume %s", vol)
+	}
+	if err := copyVolume(ociBin, img, nodeName, vol); err != nil {
+		return errors.Wrapf(err, "copying volume %s", nodeName)
+	}
+	return nil
+}
+
+// RestoreSnapshot restores the /var volume and the kubernetes configuration of a stopped node container from a snapshot
+func RestoreSnapshot(ociBin string, nodeName string, snapshot string) error {
+	img := SnapshotImage(nodeName, snapshot)
+	if err := copyVolume(ociBin, img, SnapshotVolume(nodeName, snapshot), nodeName); err != nil {
+		return errors.Wrapf(err, "restoring volume %s", nodeName)
+	}
+
+	// the committed image is only used to copy files from, as the node container is kept
+	tmp := fmt.Sprintf("%s-snapshot-restore", nodeName)
+	if _, err := runCmd(exec.Command(ociBin, "create", "--name", tmp, img)); err != nil {
+		return errors.Wrapf(err, "creating container from %s", img)
+	}
+	defer func() {
+		if _, err := runCmd(exec.Command(ociBin, "rm", "-f", tmp)); err != nil {
+			klog.Warningf("unable to remove %s: %v", tmp, err)
+		}
+	}()
+
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		return errors.Wrap(err, "creating temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := runCmd(exec.Command(ociBin, "cp", fmt.Sprintf("%s:%s", tmp, snapshotConfigDir), dir)); err != nil {
+		return errors.Wrapf(err, "copying %s from %s", snapshotConfigDir, img)
+	}
+	if _, err := runCmd(exec.Command(ociBin, "cp", filepath.Join(dir, path.Base(snapshotConfigDir)), fmt.Sprintf("%s:%s", nodeName, path.Dir(snapshotConfigDir)))); err != nil {
+		return errors.Wrapf(err, "copying %s to %s", snapshotConfigDir, nodeName)
+	}
+	return nil
+}
+
+// DeleteSnapshot removes the image and the volume holding the snapshot of a node
+func DeleteSnapshot(ociBin string, nodeName string, snapshot string) error {
+	img := SnapshotImage(nodeName, snapshot)
+	if _, err := runCmd(exec.Command(ociBin, "rmi", "-f", img)); err != nil {
+		klog.Warningf("unable to remove image %s: %v", img, err)
+	}
+	if err := RemoveVolume(ociBin, SnapshotVolume(nodeName, snapshot)); err != nil && !errors.Is(err, ErrVolumeNotFound) {
+		return errors.Wrapf(err, "removing volume for %s", nodeName)
+	}
+	return nil
+}
+
+// copyVolume replaces the contents of the volume dst with those of the volume src, using a temporary container of image
+func copyVolume(ociBin string, image string, src string, dst string) error {
+	args := []string{"run", "--rm", "--entrypoint", "/bin/bash"}
+	// see ExtractTarballToVolume for why selinux labels are disabled
+	if ociBin == Podman && runtime.GOOS == "linux" {
+		args = append(args, "--security-opt", "label=disable")
+	}
+	args = append(args, "-v", fmt.Sprintf("%s:/from:ro", src), "-v", fmt.Sprintf("%s:/to", dst), image, "-c", "find /to -mindepth 1 -delete && cp -a /from/. /to/")
+	_, err := runCmd(exec.Command(ociBin, args...))
+	return err
+}
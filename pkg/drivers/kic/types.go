@@ -64,6 +64,9 @@ type Config struct {
 	KubernetesVersion string            // Kubernetes version to install
 	ContainerRuntime  string            // container runtime kic is running
 	Network           string            //  network to run with kic
+	Subnet            string            // subnet of the network created for the cluster, picked automatically if empty
+	StaticIP          string            // address of the container on the network, calculated if empty
+	ExtraNetworks     []string          // existing networks the container is attached to, in addition to Network
 	ExtraArgs         []string          // a list of any extra option to pass to oci binary during creation time, for example --expose 8080...
 	ListenAddress     string            // IP Address to listen to
 }
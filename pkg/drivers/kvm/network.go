@@ -41,7 +41,7 @@ const networkTmpl = `
   {{with .Parameters}}
   <ip address='{{.Gateway}}' netmask='{{.Netmask}}'>
     <dhcp>
-      <range start='{{.ClientMin}}' end='{{.ClientMax}}'/>
+      <range start='{{.ClientMin}}' end='{{.DHCPMax}}'/>
     </dhcp>
   </ip>
   {{end}}
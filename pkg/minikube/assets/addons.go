@@ -18,6 +18,7 @@ package assets
 
 import (
 	"fmt"
+	"net"
 	"runtime"
 	"strings"
 
@@ -46,6 +47,7 @@ type Addon struct {
 type NetworkInfo struct {
 	ControlPlaneNodeIP   string
 	ControlPlaneNodePort int
+	PodCIDR              string
 }
 
 // NewAddon creates a new Addon
@@ -796,6 +798,11 @@ func GenerateTemplateData(addon *Addon, cfg config.KubernetesConfig, netInfo Net
 		LoadBalancerEndIP   string
 		CustomIngressCert   string
 		ContainerRuntime    string
+		AutoPauseInterval   string
+		AutoPauseAddress    string
+		AutoPauseHost       string
+		AutoPausePort       string
+		AutoPauseNamespaces string
 		Images              map[string]string
 		Registries          map[string]string
 		CustomRegistries    map[string]string
@@ -808,6 +815,9 @@ func GenerateTemplateData(addon *Addon, cfg config.KubernetesConfig, netInfo Net
 		LoadBalancerEndIP:   cfg.LoadBalancerEndIP,
 		CustomIngressCert:   cfg.CustomIngressCert,
 		ContainerRuntime:    cfg.ContainerRuntime,
+		AutoPauseInterval:   constants.AutoPauseInterval.String(),
+		AutoPauseAddress:    constants.AutoPauseListenAddress,
+		AutoPauseNamespaces: "kube-system",
 		Images:              images,
 		Registries:          addon.Registries,
 		CustomRegistries:    customRegistries,
@@ -820,9 +830,29 @@ func GenerateTemplateData(addon *Addon, cfg config.KubernetesConfig, netInfo Net
 		opts.Registries = make(map[string]string)
 	}
 
+	// auto-pause settings, the defaults are used unless configured with "minikube addons configure auto-pause"
+	if cfg.AutoPauseInterval != 0 {
+		opts.AutoPauseInterval = cfg.AutoPauseInterval.String()
+	}
+	if cfg.AutoPauseAddress != "" {
+		opts.AutoPauseAddress = cfg.AutoPauseAddress
+	}
+	// the proxy and the hook reach the daemon on the configured address, or on the node if it listens on all addresses
+	opts.AutoPauseHost = netInfo.ControlPlaneNodeIP
+	if host, port, err := net.SplitHostPort(opts.AutoPauseAddress); err == nil {
+		if ip := net.ParseIP(host); host != "" && (ip == nil || !ip.IsUnspecified()) {
+			opts.AutoPauseHost = host
+		}
+		opts.AutoPausePort = port
+	}
+	if len(cfg.AutoPauseNamespaces) != 0 {
+		opts.AutoPauseNamespaces = strings.Join(cfg.AutoPauseNamespaces, ",")
+	}
+
 	// Network info for generating template
 	opts.NetworkInfo["ControlPlaneNodeIP"] = netInfo.ControlPlaneNodeIP
 	opts.NetworkInfo["ControlPlaneNodePort"] = fmt.Sprint(netInfo.ControlPlaneNodePort)
+	opts.NetworkInfo["PodCIDR"] = netInfo.PodCIDR
 
 	// Append postfix "/" to registries
 	for k, v := range opts.Registries {
@@ -24,6 +24,7 @@ import (
 	"io"
 	"os"
 	"path"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
@@ -48,6 +49,18 @@ type CopyableFile interface {
 	Close() error
 }
 
+// WriteableFile is something that can be written to, when copying a file back from a node.
+// The target is the file on the node, and the source is the file on the host.
+type WriteableFile interface {
+	io.Writer
+	GetSourcePath() string
+
+	GetTargetDir() string
+	GetTargetName() string
+	GetPermissions() string
+	Close() error
+}
+
 // BaseAsset is the base asset class
 type BaseAsset struct {
 	SourcePath  string
@@ -165,6 +178,50 @@ func (f *FileAsset) Close() error {
 	return f.file.Close()
 }
 
+// WriteableAsset is an asset using a host file that can be written to
+type WriteableAsset struct {
+	BaseAsset
+	file *os.File
+}
+
+// NewWriteableAsset creates a new WriteableAsset, creating or truncating the file at src
+func NewWriteableAsset(src, targetDir, targetName, permissions string) (*WriteableAsset, error) {
+	klog.V(4).Infof("NewWriteableAsset: %s <- %s", src, path.Join(targetDir, targetName))
+
+	perms, err := strconv.ParseUint(permissions, 8, 32)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error converting permissions %s to integer", permissions)
+	}
+	if perms > 07777 {
+		return nil, errors.Errorf("invalid permissions %s", permissions)
+	}
+
+	f, err := os.OpenFile(src, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.FileMode(perms))
+	if err != nil {
+		return nil, errors.Wrap(err, "open")
+	}
+
+	return &WriteableAsset{
+		BaseAsset: BaseAsset{
+			SourcePath:  src,
+			TargetDir:   targetDir,
+			TargetName:  targetName,
+			Permissions: permissions,
+		},
+		file: f,
+	}, nil
+}
+
+// Write writes to the asset
+func (f *WriteableAsset) Write(p []byte) (int, error) {
+	return f.file.Write(p)
+}
+
+// Close closes the opened file.
+func (f *WriteableAsset) Close() error {
+	return f.file.Close()
+}
+
 // MemoryAsset is a memory-based asset
 type MemoryAsset struct {
 	BaseAsset
@@ -19,7 +19,9 @@ package audit
 import (
 	"os"
 	"os/user"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/viper"
@@ -41,21 +43,71 @@ func userName() string {
 	return osUser.Username
 }
 
-// args concats the args into space delimited string.
+// secretFlags are the flags whose values are masked in the audit log
+var secretFlags = []string{"password", "token"}
+
+// maskedValue replaces the values of the secret flags
+const maskedValue = "***"
+
+// args concats the args into space delimited string, with the values of secret flags masked.
 func args() string {
 	// first arg is binary and second is command, anything beyond is a minikube arg
 	if len(os.Args) < 3 {
 		return ""
 	}
-	return strings.Join(os.Args[2:], " ")
+	return strings.Join(maskSecrets(os.Args[2:]), " ")
+}
+
+// maskSecrets returns the args with the values of the secret flags masked, passed as --flag=value or --flag value
+func maskSecrets(args []string) []string {
+	masked := make([]string, len(args))
+	copy(masked, args)
+	for i, a := range masked {
+		for _, f := range secretFlags {
+			switch {
+			case a == "--"+f && i+1 < len(masked):
+				masked[i+1] = maskedValue
+			case strings.HasPrefix(a, "--"+f+"="):
+				masked[i] = "--" + f + "=" + maskedValue
+			}
+		}
+	}
+	return masked
+}
+
+var (
+	// commandStart is the start time of the command, used when logging a failed command on exit
+	commandStart time.Time
+	// logOnce makes sure the command is only logged once, as exiting skips the deferred Log
+	logOnce sync.Once
+)
+
+// SetStartTime records the start time of the command, so that it can be logged by LogExit.
+func SetStartTime(t time.Time) {
+	commandStart = t
 }
 
-// Log details about the executed command.
+// Log details about the executed command, which completed successfully.
 func Log(startTime time.Time) {
+	logOnce.Do(func() { logCommand(startTime, 0, "") })
+}
+
+// LogExit details about the executed command, which is exiting with the given exit code and reason ID.
+func LogExit(exitCode int, reasonID string) {
+	if commandStart.IsZero() {
+		return
+	}
+	logOnce.Do(func() { logCommand(commandStart, exitCode, reasonID) })
+}
+
+// logCommand appends the executed command and its outcome to the audit log.
+func logCommand(startTime time.Time, exitCode int, reasonID string) {
 	if len(os.Args) < 2 || !shouldLog() {
 		return
 	}
 	r := newRow(os.Args[1], args(), userName(), version.GetVersion(), startTime, time.Now())
+	r.exitCode = strconv.Itoa(exitCode)
+	r.reason = reasonID
 	if err := appendToLog(r); err != nil {
 		klog.Warning(err)
 	}
@@ -73,7 +125,7 @@ func shouldLog() bool {
 	}
 
 	// commands that should not be logged.
-	no := []string{"status", "version"}
+	no := []string{"audit", "status", "version"}
 	a := os.Args[1]
 	for _, c := range no {
 		if a == c {
@@ -74,6 +74,14 @@ func TestAudit(t *testing.T) {
 				[]string{"minikube", "start", "--user", "testUser"},
 				"--user testUser",
 			},
+			{
+				[]string{"minikube", "registries", "set", "registry.example.com", "--username", "user", "--password", "secret"},
+				"set registry.example.com --username user --password ***",
+			},
+			{
+				[]string{"minikube", "registries", "set", "quay.io", "--token=secret", "--password-stdin"},
+				"set quay.io --token=*** --password-stdin",
+			},
 		}
 
 		for _, test := range tests {
@@ -111,6 +119,10 @@ func TestAudit(t *testing.T) {
 				[]string{"minikube", "version"},
 				false,
 			},
+			{
+				[]string{"minikube", "audit"},
+				false,
+			},
 			{
 				[]string{"minikube"},
 				false,
@@ -17,6 +17,7 @@ limitations under the License.
 package audit
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 
@@ -27,6 +28,14 @@ import (
 // currentLogFile the file that's used to store audit logs
 var currentLogFile *os.File
 
+// maxLogSize is the size in bytes above which the log file is rotated
+var maxLogSize int64 = 1024 * 1024
+
+// rotatedLogPath returns the path the log file at path is rotated to, only a single rotated file is kept.
+func rotatedLogPath(path string) string {
+	return path + ".1"
+}
+
 // setLogFile sets the logPath and creates the log file if it doesn't exist.
 func setLogFile() error {
 	lp := localpath.AuditLog()
@@ -45,6 +54,9 @@ func appendToLog(row *row) error {
 			return err
 		}
 	}
+	if err := rotateLog(); err != nil {
+		return err
+	}
 	ce := register.CloudEvent(row, row.toMap())
 	bs, err := ce.MarshalJSON()
 	if err != nil {
@@ -55,3 +67,57 @@ func appendToLog(row *row) error {
 	}
 	return nil
 }
+
+// rotateLog moves the log file aside once it grows beyond maxLogSize, and starts a new one.
+func rotateLog() error {
+	fi, err := currentLogFile.Stat()
+	if err != nil {
+		return fmt.Errorf("unable to stat audit log: %v", err)
+	}
+	if fi.Size() < maxLogSize {
+		return nil
+	}
+	lp := currentLogFile.Name()
+	if err := currentLogFile.Close(); err != nil {
+		return fmt.Errorf("unable to close %s: %v", lp, err)
+	}
+	if err := os.Rename(lp, rotatedLogPath(lp)); err != nil {
+		return fmt.Errorf("unable to rotate %s: %v", lp, err)
+	}
+	f, err := os.OpenFile(lp, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to open %s: %v", lp, err)
+	}
+	currentLogFile = f
+	return nil
+}
+
+// readLogs returns the lines of the rotated and the current log file, oldest first.
+func readLogs() ([]string, error) {
+	lp := localpath.AuditLog()
+	if currentLogFile != nil {
+		lp = currentLogFile.Name()
+	}
+	var logs []string
+	for _, p := range []string{rotatedLogPath(lp), lp} {
+		f, err := os.Open(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to open %s: %v", p, err)
+		}
+		s := bufio.NewScanner(f)
+		for s.Scan() {
+			if s.Text() != "" {
+				logs = append(logs, s.Text())
+			}
+		}
+		err = s.Err()
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read from audit file %s: %v", p, err)
+		}
+	}
+	return logs, nil
+}
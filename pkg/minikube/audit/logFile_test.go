@@ -59,4 +59,39 @@ func TestLogFile(t *testing.T) {
 			t.Errorf("Log was not appended to file: %v", err)
 		}
 	})
+	t.Run("RotateLog", func(t *testing.T) {
+		lp := filepath.Join(t.TempDir(), "audit.json")
+		f, err := os.OpenFile(lp, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
+		if err != nil {
+			t.Fatalf("Error creating log file: %v", err)
+		}
+
+		oldLogFile, oldMaxLogSize := currentLogFile, maxLogSize
+		defer func() { currentLogFile, maxLogSize = oldLogFile, oldMaxLogSize }()
+		currentLogFile = f
+		maxLogSize = 1
+
+		for _, c := range []string{"start", "stop", "delete"} {
+			if err := appendToLog(newRow(c, "", "user1", "v1.18.0", time.Now(), time.Now())); err != nil {
+				t.Fatalf("Error appendingToLog: %v", err)
+			}
+		}
+		defer currentLogFile.Close()
+
+		logs, err := readLogs()
+		if err != nil {
+			t.Fatalf("Error reading logs: %v", err)
+		}
+		// only the rotated and the current file are kept
+		if len(logs) != 2 {
+			t.Fatalf("got %d log lines after rotation, want 2", len(logs))
+		}
+		rows, err := logsToRows(logs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rows[0].command != "stop" || rows[1].command != "delete" {
+			t.Errorf("got commands %q and %q after rotation, want stop and delete", rows[0].command, rows[1].command)
+		}
+	})
 }
@@ -0,0 +1,168 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// Filter selects the audit log entries returned by Query, empty fields match everything.
+type Filter struct {
+	Profile string
+	User    string
+	Command string
+	Version string
+	// Since and Until bound the start time of the command
+	Since time.Time
+	Until time.Time
+}
+
+// Entry is a single command recorded in the audit log.
+type Entry struct {
+	Command   string    `json:"command"`
+	Args      string    `json:"args"`
+	Profile   string    `json:"profile"`
+	User      string    `json:"user"`
+	Version   string    `json:"version"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Duration  string    `json:"duration"`
+	ExitCode  int       `json:"exitCode"`
+	Reason    string    `json:"reason,omitempty"`
+
+	// event is the cloud event the entry was read from
+	event string
+}
+
+// matches returns whether the entry is selected by the filter.
+func (f Filter) matches(e Entry) bool {
+	if f.Profile != "" && e.Profile != f.Profile {
+		return false
+	}
+	if f.User != "" && e.User != f.User {
+		return false
+	}
+	if f.Command != "" && e.Command != f.Command {
+		return false
+	}
+	if f.Version != "" && e.Version != f.Version {
+		return false
+	}
+	if !f.Since.IsZero() && e.StartTime.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && e.StartTime.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// newEntry converts a row read from the log into an entry.
+func newEntry(r row, event string) Entry {
+	e := Entry{
+		Command: r.command,
+		Args:    r.args,
+		Profile: r.profile,
+		User:    r.user,
+		Version: r.version,
+		Reason:  r.reason,
+		event:   event,
+	}
+	if t, err := time.ParseInLocation(constants.TimeFormat, r.startTime, time.Local); err == nil {
+		e.StartTime = t
+	}
+	if t, err := time.ParseInLocation(constants.TimeFormat, r.endTime, time.Local); err == nil {
+		e.EndTime = t
+	}
+	if !e.StartTime.IsZero() && !e.EndTime.IsZero() {
+		e.Duration = e.EndTime.Sub(e.StartTime).String()
+	}
+	// older versions only logged commands which completed successfully, without an exit code
+	if r.exitCode != "" {
+		code, err := strconv.Atoi(r.exitCode)
+		if err != nil {
+			klog.Warningf("invalid exit code %q in audit log: %v", r.exitCode, err)
+		}
+		e.ExitCode = code
+	}
+	return e
+}
+
+// ExitStatus returns the exit code of the command, along with the reason it failed if known.
+func (e Entry) ExitStatus() string {
+	if e.Reason == "" {
+		return strconv.Itoa(e.ExitCode)
+	}
+	return fmt.Sprintf("%d (%s)", e.ExitCode, e.Reason)
+}
+
+// Query returns the entries of the audit log matching the filter, oldest first.
+func Query(f Filter) ([]Entry, error) {
+	logs, err := readLogs()
+	if err != nil {
+		return nil, err
+	}
+	entries := []Entry{}
+	for _, l := range logs {
+		r := row{}
+		if err := json.Unmarshal([]byte(l), &r); err != nil {
+			klog.Warningf("skipping invalid audit log line %q: %v", l, err)
+			continue
+		}
+		r.assignFields()
+		e := newEntry(r, l)
+		if f.matches(e) {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// EntriesToASCIITable converts entries into a formatted ASCII table.
+func EntriesToASCIITable(entries []Entry) string {
+	c := [][]string{}
+	for _, e := range entries {
+		c = append(c, []string{e.Command, e.Args, e.Profile, e.User, e.Version, e.StartTime.Format(constants.TimeFormat), e.Duration, e.ExitStatus()})
+	}
+	b := new(bytes.Buffer)
+	t := tablewriter.NewWriter(b)
+	t.SetHeader([]string{"Command", "Args", "Profile", "User", "Version", "Start Time", "Duration", "Exit Status"})
+	t.SetAutoFormatHeaders(false)
+	t.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	t.SetCenterSeparator("|")
+	t.AppendBulk(c)
+	t.Render()
+	return b.String()
+}
+
+// EntriesToCloudEvents returns the cloud events the entries were read from, one per line.
+func EntriesToCloudEvents(entries []Entry) string {
+	var events []string
+	for _, e := range entries {
+		events = append(events, e.event)
+	}
+	return strings.Join(events, "\n")
+}
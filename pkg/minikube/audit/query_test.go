@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuery(t *testing.T) {
+	dir := t.TempDir()
+	lp := filepath.Join(dir, "audit.json")
+
+	rotated := `{"data":{"args":"-p mini1","command":"start","endTime":"Wed, 03 Feb 2021 15:33:05 UTC","profile":"mini1","startTime":"Wed, 03 Feb 2021 15:30:33 UTC","user":"user1","version":"v1.17.1"},"datacontenttype":"application/json","id":"9b7593cb-fbec-49e5-a3ce-bdc2d0bfb208","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.audit"}
+`
+	current := `{"data":{"args":"-p mini2","command":"start","endTime":"Thu, 04 Feb 2021 10:00:30 UTC","exitCode":"80","profile":"mini2","reason":"GUEST_PROVISION","startTime":"Thu, 04 Feb 2021 10:00:00 UTC","user":"user2","version":"v1.18.0"},"datacontenttype":"application/json","id":"fec03227-2484-48b6-880a-88fd010b5efd","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.audit"}
+{"data":{"args":"-p mini1","command":"stop","endTime":"Fri, 05 Feb 2021 09:00:10 UTC","exitCode":"0","profile":"mini1","reason":"","startTime":"Fri, 05 Feb 2021 09:00:00 UTC","user":"user1","version":"v1.18.0"},"datacontenttype":"application/json","id":"a1b2c3d4-0d08-4b57-ac3b-db8d67774768","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.audit"}
+`
+	if err := ioutil.WriteFile(rotatedLogPath(lp), []byte(rotated), 0644); err != nil {
+		t.Fatalf("failed writing rotated log: %v", err)
+	}
+	if err := ioutil.WriteFile(lp, []byte(current), 0644); err != nil {
+		t.Fatalf("failed writing log: %v", err)
+	}
+	f, err := os.OpenFile(lp, os.O_APPEND|os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatalf("failed opening log: %v", err)
+	}
+	defer f.Close()
+
+	oldLogFile := currentLogFile
+	defer func() { currentLogFile = oldLogFile }()
+	currentLogFile = f
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"all", Filter{}, []string{"start mini1", "start mini2", "stop mini1"}},
+		{"profile", Filter{Profile: "mini1"}, []string{"start mini1", "stop mini1"}},
+		{"user", Filter{User: "user2"}, []string{"start mini2"}},
+		{"command", Filter{Command: "stop"}, []string{"stop mini1"}},
+		{"version", Filter{Version: "v1.18.0"}, []string{"start mini2", "stop mini1"}},
+		{"since", Filter{Since: time.Date(2021, 2, 4, 0, 0, 0, 0, time.UTC)}, []string{"start mini2", "stop mini1"}},
+		{"until", Filter{Until: time.Date(2021, 2, 4, 12, 0, 0, 0, time.UTC)}, []string{"start mini1", "start mini2"}},
+		{"no match", Filter{Profile: "mini1", User: "user2"}, []string{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, err := Query(tc.filter)
+			if err != nil {
+				t.Fatalf("Query() failed: %v", err)
+			}
+			got := []string{}
+			for _, e := range entries {
+				got = append(got, e.Command+" "+e.Profile)
+			}
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("Query(%+v) = %v; want %v", tc.filter, got, tc.want)
+			}
+		})
+	}
+
+	t.Run("exit status", func(t *testing.T) {
+		entries, err := Query(Filter{})
+		if err != nil {
+			t.Fatalf("Query() failed: %v", err)
+		}
+		want := []struct {
+			status   string
+			duration string
+		}{
+			{"0", "2m32s"},
+			{"80 (GUEST_PROVISION)", "30s"},
+			{"0", "10s"},
+		}
+		for i, e := range entries {
+			if e.ExitStatus() != want[i].status {
+				t.Errorf("entry %d ExitStatus() = %q; want %q", i, e.ExitStatus(), want[i].status)
+			}
+			if e.Duration != want[i].duration {
+				t.Errorf("entry %d Duration = %q; want %q", i, e.Duration, want[i].duration)
+			}
+		}
+	})
+
+	t.Run("cloud events", func(t *testing.T) {
+		entries, err := Query(Filter{User: "user2"})
+		if err != nil {
+			t.Fatalf("Query() failed: %v", err)
+		}
+		got := EntriesToCloudEvents(entries)
+		if want := strings.TrimSpace(strings.Split(current, "\n")[0]); got != want {
+			t.Errorf("EntriesToCloudEvents() = %s; want %s", got, want)
+		}
+	})
+}
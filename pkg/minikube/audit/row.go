@@ -33,7 +33,9 @@ type row struct {
 	args      string
 	command   string
 	endTime   string
+	exitCode  string
 	profile   string
+	reason    string
 	startTime string
 	user      string
 	version   string
@@ -51,7 +53,9 @@ func (e *row) assignFields() {
 	e.args = e.Data["args"]
 	e.command = e.Data["command"]
 	e.endTime = e.Data["endTime"]
+	e.exitCode = e.Data["exitCode"]
 	e.profile = e.Data["profile"]
+	e.reason = e.Data["reason"]
 	e.startTime = e.Data["startTime"]
 	e.user = e.Data["user"]
 	e.version = e.Data["version"]
@@ -64,7 +68,9 @@ func (e *row) toMap() map[string]string {
 		"args":      e.args,
 		"command":   e.command,
 		"endTime":   e.endTime,
+		"exitCode":  e.exitCode,
 		"profile":   e.profile,
+		"reason":    e.reason,
 		"startTime": e.startTime,
 		"user":      e.user,
 		"version":   e.version,
@@ -40,11 +40,17 @@ type Bootstrapper interface {
 	DeleteCluster(config.KubernetesConfig) error
 	WaitForNode(config.ClusterConfig, config.Node, time.Duration) error
 	JoinCluster(config.ClusterConfig, config.Node, string) error
+	// RestoreControlPlane restarts a secondary control-plane node which joined the cluster before, keeping its etcd member.
+	RestoreControlPlane(config.ClusterConfig, config.Node) error
 	UpdateNode(config.ClusterConfig, config.Node, cruntime.Manager) error
-	GenerateToken(config.ClusterConfig) (string, error)
+	GenerateToken(config.ClusterConfig, config.Node) (string, error)
 	// LogCommands returns a map of log type to a command which will display that log.
 	LogCommands(config.ClusterConfig, LogOptions) map[string]string
-	SetupCerts(config.KubernetesConfig, config.Node) error
+	SetupCerts(config.ClusterConfig, config.Node) error
+	// RotateCerts issues the certs of a control-plane node again and restarts the components using them.
+	RotateCerts(config.ClusterConfig, config.Node) error
+	// LabelAndTaintNode applies the labels and taints of a node once it registered, including the ones the kubelet may not set.
+	LabelAndTaintNode(config.ClusterConfig, config.Node) error
 	GetAPIServerStatus(string, int) (string, error)
 }
 
@@ -21,6 +21,7 @@ import (
 	"bytes"
 	"os"
 	"path"
+	"strings"
 
 	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/drivers/kic/oci"
@@ -34,6 +35,49 @@ import (
 	"k8s.io/minikube/pkg/util"
 )
 
+// kubeletLabels are the labels of the kubernetes.io and k8s.io namespaces which the kubelet may set on its node
+var kubeletLabels = map[string]bool{
+	"kubernetes.io/hostname":                   true,
+	"kubernetes.io/arch":                       true,
+	"kubernetes.io/os":                         true,
+	"beta.kubernetes.io/arch":                  true,
+	"beta.kubernetes.io/os":                    true,
+	"beta.kubernetes.io/instance-type":         true,
+	"node.kubernetes.io/instance-type":         true,
+	"failure-domain.beta.kubernetes.io/region": true,
+	"failure-domain.beta.kubernetes.io/zone":   true,
+	"topology.kubernetes.io/region":            true,
+	"topology.kubernetes.io/zone":              true,
+}
+
+// isKubeletLabel returns whether the kubelet may register its node with a label, the others such as
+// the node-role.kubernetes.io ones are applied once the node registered
+func isKubeletLabel(key string) bool {
+	i := strings.Index(key, "/")
+	if i < 0 || kubeletLabels[key] {
+		return true
+	}
+	namespace := key[:i]
+	inNamespace := func(ns string) bool {
+		return namespace == ns || strings.HasSuffix(namespace, "."+ns)
+	}
+	if !inNamespace("kubernetes.io") && !inNamespace("k8s.io") {
+		return true
+	}
+	return inNamespace("kubelet.kubernetes.io") || inNamespace("node.kubernetes.io")
+}
+
+// appendFlagValue appends to the comma separated value of a flag
+func appendFlagValue(opts map[string]string, flag string, values ...string) {
+	if len(values) == 0 {
+		return
+	}
+	if opts[flag] != "" {
+		values = append([]string{opts[flag]}, values...)
+	}
+	opts[flag] = strings.Join(values, ",")
+}
+
 func extraKubeletOpts(mc config.ClusterConfig, nc config.Node, r cruntime.Manager) (map[string]string, error) {
 	k8s := mc.KubernetesConfig
 	version, err := util.ParseKubernetesVersion(k8s.KubernetesVersion)
@@ -73,6 +117,16 @@ func extraKubeletOpts(mc config.ClusterConfig, nc config.Node, r cruntime.Manage
 		extraOpts["hostname-override"] = nodeName
 	}
 
+	// register the node with its labels and taints, the labels the kubelet may not set are applied afterwards
+	var labels []string
+	for _, l := range config.FormatNodeLabels(nc.Labels) {
+		if isKubeletLabel(strings.SplitN(l, "=", 2)[0]) {
+			labels = append(labels, l)
+		}
+	}
+	appendFlagValue(extraOpts, "node-labels", labels...)
+	appendFlagValue(extraOpts, "register-with-taints", nc.Taints...)
+
 	pauseImage := images.Pause(version, k8s.ImageRepository)
 	if _, ok := extraOpts["pod-infra-container-image"]; !ok && k8s.ImageRepository != "" && pauseImage != "" && k8s.ContainerRuntime != remoteContainerRuntime {
 		extraOpts["pod-infra-container-image"] = pauseImage
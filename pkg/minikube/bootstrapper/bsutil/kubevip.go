@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	kconst "k8s.io/kubernetes/cmd/kubeadm/app/constants"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/ktmpl"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// KubeVIPImage is the image of kube-vip, which serves the virtual IP of HA clusters
+const KubeVIPImage = "ghcr.io/kube-vip/kube-vip:v0.4.0"
+
+// KubeVIPManifestPath is the path to the kube-vip static pod of the control-plane nodes of HA clusters
+var KubeVIPManifestPath = path.Join(vmpath.GuestManifestsDir, "kube-vip.yaml")
+
+// NewKubeVIPManifest returns the kube-vip static pod of a control-plane node of a HA cluster, announcing the
+// virtual IP on iface. If lb is set, kube-vip also balances the API server traffic among the control planes.
+func NewKubeVIPManifest(cc config.ClusterConfig, n config.Node, iface string, lb bool) ([]byte, error) {
+	if !config.IsHA(cc) {
+		return nil, fmt.Errorf("cluster %s has no virtual IP", cc.Name)
+	}
+	opts := struct {
+		Image          string
+		VIP            string
+		Port           int
+		Interface      string
+		LoadBalance    bool
+		KubeconfigPath string
+	}{
+		Image:          KubeVIPImage,
+		VIP:            cc.KubernetesConfig.APIServerHAVIP,
+		Port:           n.Port,
+		Interface:      iface,
+		LoadBalance:    lb,
+		KubeconfigPath: path.Join(kconst.KubernetesDir, kconst.AdminKubeConfigFileName),
+	}
+	var b bytes.Buffer
+	if err := ktmpl.KubeVIPTemplate.Execute(&b, opts); err != nil {
+		return nil, errors.Wrap(err, "template execute")
+	}
+	return b.Bytes(), nil
+}
+
+// NodeInterface returns the name of the network interface of the node with the address ip
+func NodeInterface(cr command.Runner, ip string) (string, error) {
+	rr, err := cr.RunCmd(exec.Command("ip", "-o", "-4", "addr", "show"))
+	if err != nil {
+		return "", errors.Wrap(err, "listing addresses")
+	}
+	iface := interfaceOf(rr.Stdout.String(), ip)
+	if iface == "" {
+		return "", fmt.Errorf("no interface has the address %s", ip)
+	}
+	return iface, nil
+}
+
+// interfaceOf returns the interface with the address ip in the output of "ip -o -4 addr show", such as:
+// 2: eth0    inet 192.168.49.2/24 brd 192.168.49.255 scope global eth0\       valid_lft forever preferred_lft forever
+func interfaceOf(out string, ip string) string {
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		for i := 0; i+1 < len(fields); i++ {
+			if fields[i] == "inet" && strings.HasPrefix(fields[i+1], ip+"/") {
+				// veth peers are named like eth0@if5
+				return strings.Split(fields[1], "@")[0]
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestNewKubeVIPManifest(t *testing.T) {
+	n := config.Node{IP: "192.168.49.2", Port: 8443, ControlPlane: true}
+	cc := config.ClusterConfig{Name: "ha", Nodes: []config.Node{n}}
+	if _, err := NewKubeVIPManifest(cc, n, "eth0", true); err == nil {
+		t.Errorf("NewKubeVIPManifest() succeeded for a cluster without virtual IP")
+	}
+
+	cc.KubernetesConfig.APIServerHAVIP = "192.168.49.254"
+	for _, lb := range []bool{false, true} {
+		b, err := NewKubeVIPManifest(cc, n, "eth0", lb)
+		if err != nil {
+			t.Fatalf("NewKubeVIPManifest() failed: %v", err)
+		}
+		m := string(b)
+		for _, want := range []string{"value: 192.168.49.254", "value: eth0", `value: "8443"`, "image: " + KubeVIPImage, "path: /etc/kubernetes/admin.conf"} {
+			if !strings.Contains(m, want) {
+				t.Errorf("manifest does not contain %q:\n%s", want, m)
+			}
+		}
+		if got := strings.Contains(m, "lb_enable"); got != lb {
+			t.Errorf("manifest enables load balancing: %v; want %v", got, lb)
+		}
+	}
+}
+
+func TestInterfaceOf(t *testing.T) {
+	out := `1: lo    inet 127.0.0.1/8 scope host lo\       valid_lft forever preferred_lft forever
+2: docker0    inet 172.17.0.1/16 brd 172.17.255.255 scope global docker0\       valid_lft forever preferred_lft forever
+25: eth0@if26    inet 192.168.49.2/24 brd 192.168.49.255 scope global eth0\       valid_lft forever preferred_lft forever
+3: eth1    inet 192.168.39.22/24 brd 192.168.39.255 scope global dynamic eth1\       valid_lft 3397sec preferred_lft 3397sec
+`
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{"192.168.49.2", "eth0"},
+		{"192.168.39.22", "eth1"},
+		{"192.168.39.2", ""},
+		{"10.0.0.1", ""},
+	}
+	for _, tc := range tests {
+		if got := interfaceOf(out, tc.ip); got != tc.want {
+			t.Errorf("interfaceOf(%q) = %q; want %q", tc.ip, got, tc.want)
+		}
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package bootstrapper
 
 import (
+	"bytes"
 	"crypto/sha1"
 	"encoding/pem"
 	"fmt"
@@ -28,6 +29,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/otiai10/copy"
 	"github.com/pkg/errors"
@@ -44,19 +46,21 @@ import (
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/vmpath"
 	"k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/util/lock"
 )
 
 // SetupCerts gets the generated credentials required to talk to the APIServer.
-func SetupCerts(cmd command.Runner, k8s config.KubernetesConfig, n config.Node) error {
+func SetupCerts(cmd command.Runner, cc config.ClusterConfig, n config.Node) error {
+	k8s := cc.KubernetesConfig
 	localPath := localpath.Profile(k8s.ClusterName)
 	klog.Infof("Setting up %s for IP: %s\n", localPath, n.IP)
 
-	ccs, err := generateSharedCACerts()
+	ccs, err := generateSharedCACerts(cc)
 	if err != nil {
 		return errors.Wrap(err, "shared CA certs")
 	}
 
-	xfer, err := generateProfileCerts(k8s, n, ccs)
+	xfer, err := generateProfileCerts(k8s, n, ccs, CertExpiration(cc))
 	if err != nil {
 		return errors.Wrap(err, "profile certs")
 	}
@@ -147,8 +151,9 @@ type CACerts struct {
 	proxyKey  string
 }
 
-// generateSharedCACerts generates CA certs shared among profiles, but only if missing
-func generateSharedCACerts() (CACerts, error) {
+// generateSharedCACerts generates CA certs shared among profiles, but only if missing.
+// The missing client / apiserver CA is installed from the CA of the organization instead, if the cluster has one.
+func generateSharedCACerts(cfg config.ClusterConfig) (CACerts, error) {
 	globalPath := localpath.MiniPath()
 	cc := CACerts{
 		caCert:    localpath.CACert(),
@@ -180,6 +185,14 @@ func generateSharedCACerts() (CACerts, error) {
 			continue
 		}
 
+		if ca.certPath == cc.caCert && cfg.CACert != "" {
+			klog.Infof("installing %s CA from %s", ca.subject, cfg.CACert)
+			if err := InstallCA(cfg.CACert, cfg.CAKey); err != nil {
+				return cc, errors.Wrap(err, "install ca cert")
+			}
+			continue
+		}
+
 		klog.Infof("generating %s CA: %s", ca.subject, ca.keyPath)
 		if err := util.GenerateCACert(ca.certPath, ca.keyPath, ca.subject); err != nil {
 			return cc, errors.Wrap(err, "generate ca cert")
@@ -189,8 +202,68 @@ func generateSharedCACerts() (CACerts, error) {
 	return cc, nil
 }
 
+// InstallCA installs a CA of the organization as the client / apiserver CA shared among profiles.
+// Only the first certificate is installed, so that the rest of a chain is not trusted by the apiserver.
+func InstallCA(certPath, keyPath string) error {
+	if err := util.CheckCACert(certPath, keyPath); err != nil {
+		return err
+	}
+	cert, err := util.FirstCertificate(certPath)
+	if err != nil {
+		return err
+	}
+	key, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return err
+	}
+
+	files := []struct {
+		data []byte
+		dst  string
+		perm os.FileMode
+	}{
+		{cert, localpath.CACert(), 0644},
+		{key, filepath.Join(localpath.MiniPath(), "ca.key"), 0600},
+	}
+	for _, f := range files {
+		installed, err := ioutil.ReadFile(f.dst)
+		if err == nil && bytes.Equal(f.data, installed) {
+			continue
+		}
+		klog.Infof("installing %s", f.dst)
+		if err := lock.WriteFile(f.dst, f.data, f.perm); err != nil {
+			return errors.Wrapf(err, "write %s", f.dst)
+		}
+	}
+	return nil
+}
+
+// CAReplaced returns whether installing the CA of the organization would replace the client / apiserver CA shared among profiles
+func CAReplaced(certPath string) (bool, error) {
+	cert, err := util.FirstCertificate(certPath)
+	if err != nil {
+		return false, err
+	}
+	installed, err := ioutil.ReadFile(localpath.CACert())
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !bytes.Equal(cert, installed), nil
+}
+
+// CertExpiration returns the validity period of the certs of a cluster
+func CertExpiration(cc config.ClusterConfig) time.Duration {
+	if cc.CertExpiration <= 0 {
+		return constants.DefaultCertExpiration
+	}
+	return cc.CertExpiration
+}
+
 // generateProfileCerts generates profile certs for a profile
-func generateProfileCerts(k8s config.KubernetesConfig, n config.Node, ccs CACerts) ([]string, error) {
+func generateProfileCerts(k8s config.KubernetesConfig, n config.Node, ccs CACerts, expiration time.Duration) ([]string, error) {
 
 	// Only generate these certs for the api server
 	if !n.ControlPlane {
@@ -206,6 +279,9 @@ func generateProfileCerts(k8s config.KubernetesConfig, n config.Node, ccs CACert
 
 	apiServerIPs := append(k8s.APIServerIPs,
 		net.ParseIP(n.IP), serviceIP, net.ParseIP(oci.DefaultBindIPV4), net.ParseIP("10.0.0.1"))
+	if k8s.APIServerHAVIP != "" {
+		apiServerIPs = append(apiServerIPs, net.ParseIP(k8s.APIServerHAVIP))
+	}
 
 	apiServerNames := append(k8s.APIServerNames, k8s.APIServerName, constants.ControlPlaneAlias)
 	apiServerAlternateNames := append(
@@ -295,7 +371,7 @@ func generateProfileCerts(k8s config.KubernetesConfig, n config.Node, ccs CACert
 		err := util.GenerateSignedCert(
 			cp, kp, spec.subject,
 			spec.ips, spec.alternateNames,
-			spec.caCertPath, spec.caKeyPath,
+			spec.caCertPath, spec.caKeyPath, expiration,
 		)
 		if err != nil {
 			return xfer, errors.Wrapf(err, "generate signed cert for %q", spec.subject)
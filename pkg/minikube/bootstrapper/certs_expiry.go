@@ -0,0 +1,231 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+const (
+	// IssuerMinikube is the issuer of the certs minikube signs with its CAs
+	IssuerMinikube = "minikube"
+	// IssuerKubeadm is the issuer of the certs kubeadm manages on the control-plane nodes
+	IssuerKubeadm = "kubeadm"
+)
+
+// minikubeNodeCerts are the certs of the certs dir of the nodes which minikube copies, the others are managed by kubeadm
+var minikubeNodeCerts = map[string]bool{
+	"ca.crt":              true,
+	"proxy-client-ca.crt": true,
+	"apiserver.crt":       true,
+	"proxy-client.crt":    true,
+}
+
+// kubeadmKubeconfigs are the kubeconfigs kubeadm generates on the control-plane nodes, with embedded client certs
+var kubeadmKubeconfigs = []string{
+	"/etc/kubernetes/admin.conf",
+	"/etc/kubernetes/controller-manager.conf",
+	"/etc/kubernetes/scheduler.conf",
+}
+
+// CertInfo describes a cert of a cluster
+type CertInfo struct {
+	Name     string // file name of the cert, or of the kubeconfig embedding it
+	Node     string // node the cert was read from, empty for the minikube home
+	Issuer   string // IssuerMinikube or IssuerKubeadm
+	Subject  string
+	SANs     []string
+	IsCA     bool
+	NotAfter time.Time
+}
+
+// ExpiresWithin returns whether the cert expires within d, or has expired
+func (c CertInfo) ExpiresWithin(d time.Duration) bool {
+	return time.Until(c.NotAfter) < d
+}
+
+// parseCert parses the first cert of PEM encoded data
+func parseCert(data []byte) (CertInfo, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return CertInfo{}, fmt.Errorf("no PEM encoded certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return CertInfo{}, errors.Wrap(err, "parse certificate")
+	}
+	sans := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return CertInfo{Subject: cert.Subject.CommonName, SANs: sans, IsCA: cert.IsCA, NotAfter: cert.NotAfter}, nil
+}
+
+// LocalCerts returns the certs of a cluster in the minikube home: the CAs shared by all profiles and the certs of the profile
+func LocalCerts(clusterName string) ([]CertInfo, error) {
+	profilePath := localpath.Profile(clusterName)
+	paths := []string{
+		localpath.CACert(),
+		filepath.Join(localpath.MiniPath(), "proxy-client-ca.crt"),
+		localpath.ClientCert(clusterName),
+		filepath.Join(profilePath, "apiserver.crt"),
+		filepath.Join(profilePath, "proxy-client.crt"),
+	}
+
+	certs := []CertInfo{}
+	for _, p := range paths {
+		data, err := ioutil.ReadFile(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		c, err := parseCert(data)
+		if err != nil {
+			return nil, errors.Wrap(err, p)
+		}
+		c.Name = filepath.Base(p)
+		c.Issuer = IssuerMinikube
+		certs = append(certs, c)
+	}
+	return certs, nil
+}
+
+// nodeCertsMarker precedes the path of each file in the output of the command reading the certs of a node
+const nodeCertsMarker = "==> minikube cert file: "
+
+// NodeCerts returns the certs kubeadm manages on a control-plane node: the ones of its certs dir and of its kubeconfigs.
+// They are read with a single command, as the status of the cluster reads them as well.
+func NodeCerts(cr command.Runner, nodeName string) ([]CertInfo, error) {
+	script := fmt.Sprintf(`for f in $(find %s -name '*.crt' | sort) %s; do if [ -f "$f" ]; then printf '\n%s%%s\n' "$f"; cat "$f"; fi; done`,
+		vmpath.GuestKubernetesCertsDir, strings.Join(kubeadmKubeconfigs, " "), nodeCertsMarker)
+	rr, err := cr.RunCmd(exec.Command("sudo", "/bin/bash", "-c", script))
+	if err != nil {
+		return nil, errors.Wrap(err, "read certs")
+	}
+	return parseNodeCerts(rr.Stdout.String(), nodeName)
+}
+
+// parseNodeCerts parses the certs and the kubeconfigs of a node, each preceded by nodeCertsMarker and its path
+func parseNodeCerts(output string, nodeName string) ([]CertInfo, error) {
+	certs := []CertInfo{}
+	for _, part := range strings.Split(output, "\n"+nodeCertsMarker)[1:] {
+		nl := strings.Index(part, "\n")
+		if nl < 0 {
+			continue
+		}
+		f, data := part[:nl], []byte(part[nl+1:])
+
+		if strings.HasSuffix(f, ".crt") {
+			name := strings.TrimPrefix(f, vmpath.GuestKubernetesCertsDir+"/")
+			if minikubeNodeCerts[name] {
+				continue
+			}
+			c, err := parseCert(data)
+			if err != nil {
+				return nil, errors.Wrap(err, f)
+			}
+			c.Name = name
+			c.Node = nodeName
+			c.Issuer = IssuerKubeadm
+			certs = append(certs, c)
+			continue
+		}
+
+		kc, err := clientcmd.Load(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "load %s", f)
+		}
+		for _, ai := range kc.AuthInfos {
+			if len(ai.ClientCertificateData) == 0 {
+				continue
+			}
+			c, err := parseCert(ai.ClientCertificateData)
+			if err != nil {
+				return nil, errors.Wrap(err, f)
+			}
+			c.Name = path.Base(f)
+			c.Node = nodeName
+			c.Issuer = IssuerKubeadm
+			certs = append(certs, c)
+		}
+	}
+	return certs, nil
+}
+
+// ExpiringCerts returns the certs expiring within d
+func ExpiringCerts(certs []CertInfo, d time.Duration) []CertInfo {
+	expiring := []CertInfo{}
+	for _, c := range certs {
+		if c.ExpiresWithin(d) {
+			expiring = append(expiring, c)
+		}
+	}
+	return expiring
+}
+
+// RemoveProfileCerts removes the certs minikube signed for a profile, and with ca the CAs shared by all profiles,
+// for SetupCerts to issue them again
+func RemoveProfileCerts(clusterName string, ca bool) error {
+	profilePath := localpath.Profile(clusterName)
+	patterns := []string{
+		localpath.ClientCert(clusterName),
+		localpath.ClientKey(clusterName),
+		filepath.Join(profilePath, "apiserver.crt*"),
+		filepath.Join(profilePath, "apiserver.key*"),
+		filepath.Join(profilePath, "proxy-client.crt"),
+		filepath.Join(profilePath, "proxy-client.key"),
+	}
+	if ca {
+		patterns = append(patterns,
+			localpath.CACert(),
+			filepath.Join(localpath.MiniPath(), "ca.key"),
+			filepath.Join(localpath.MiniPath(), "proxy-client-ca.crt"),
+			filepath.Join(localpath.MiniPath(), "proxy-client-ca.key"))
+	}
+
+	for _, pattern := range patterns {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			klog.Infof("removing %s", f)
+			if err := os.Remove(f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/tests"
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestLocalCerts(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer tests.RemoveTempDir(tempDir)
+
+	if err := os.MkdirAll(localpath.Profile("p1"), 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := util.GenerateCACert(localpath.CACert(), filepath.Join(tempDir, "ca.key"), "minikubeCA"); err != nil {
+		t.Fatal(err)
+	}
+	if err := util.GenerateSignedCert(localpath.ClientCert("p1"), localpath.ClientKey("p1"), "minikube-user", nil, nil, localpath.CACert(), filepath.Join(tempDir, "ca.key"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	apiserverCert := filepath.Join(localpath.Profile("p1"), "apiserver.crt")
+	if err := util.GenerateSignedCert(apiserverCert, filepath.Join(localpath.Profile("p1"), "apiserver.key"), "minikube", []net.IP{net.ParseIP("192.168.49.2")}, []string{"control-plane.minikube.internal"}, localpath.CACert(), filepath.Join(tempDir, "ca.key"), 24*time.Hour*365); err != nil {
+		t.Fatal(err)
+	}
+
+	certs, err := LocalCerts("p1")
+	if err != nil {
+		t.Fatalf("LocalCerts() error = %v", err)
+	}
+	if len(certs) != 3 {
+		t.Fatalf("LocalCerts() = %d certs, want 3 as the proxy client ones do not exist: %+v", len(certs), certs)
+	}
+	if certs[0].Name != "ca.crt" || !certs[0].IsCA || certs[0].Issuer != IssuerMinikube {
+		t.Errorf("LocalCerts()[0] = %+v, want the CA", certs[0])
+	}
+	if certs[2].Name != "apiserver.crt" || len(certs[2].SANs) != 2 || certs[2].SANs[0] != "control-plane.minikube.internal" || certs[2].SANs[1] != "192.168.49.2" {
+		t.Errorf("LocalCerts()[2] = %+v, want the apiserver cert with its SANs", certs[2])
+	}
+
+	expiring := ExpiringCerts(certs, 30*24*time.Hour)
+	if len(expiring) != 1 || expiring[0].Name != "client.crt" {
+		t.Errorf("ExpiringCerts() = %+v, want the client cert", expiring)
+	}
+
+	if err := RemoveProfileCerts("p1", false); err != nil {
+		t.Fatalf("RemoveProfileCerts() error = %v", err)
+	}
+	for _, f := range []string{localpath.ClientCert("p1"), localpath.ClientKey("p1"), apiserverCert} {
+		if _, err := os.Stat(f); !os.IsNotExist(err) {
+			t.Errorf("RemoveProfileCerts() did not remove %s", f)
+		}
+	}
+	if _, err := os.Stat(localpath.CACert()); err != nil {
+		t.Errorf("RemoveProfileCerts() without ca removed the CA: %v", err)
+	}
+}
+
+func TestParseNodeCerts(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer tests.RemoveTempDir(tempDir)
+
+	ca, caKey := filepath.Join(tempDir, "ca.crt"), filepath.Join(tempDir, "ca.key")
+	if err := util.GenerateCACert(ca, caKey, "etcd-ca"); err != nil {
+		t.Fatal(err)
+	}
+	peer := filepath.Join(tempDir, "peer.crt")
+	if err := util.GenerateSignedCert(peer, filepath.Join(tempDir, "peer.key"), "minikube", nil, nil, ca, caKey, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	read := func(f string) string {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	admin := `apiVersion: v1
+kind: Config
+users:
+- name: kubernetes-admin
+  user:
+    client-certificate-data: ` + base64.StdEncoding.EncodeToString([]byte(read(peer))) + "\n"
+
+	// the output of the script of NodeCerts: each file is preceded by the marker and its path
+	var output strings.Builder
+	for _, f := range []struct{ path, content string }{
+		{"/var/lib/minikube/certs/ca.crt", read(ca)},
+		{"/var/lib/minikube/certs/etcd/ca.crt", read(ca)},
+		{"/var/lib/minikube/certs/etcd/peer.crt", read(peer)},
+		{"/etc/kubernetes/admin.conf", admin},
+	} {
+		output.WriteString("\n" + nodeCertsMarker + f.path + "\n" + f.content)
+	}
+
+	certs, err := parseNodeCerts(output.String(), "p1")
+	if err != nil {
+		t.Fatalf("parseNodeCerts() error = %v", err)
+	}
+	if len(certs) != 3 {
+		t.Fatalf("parseNodeCerts() = %d certs, want 3 as minikube copies ca.crt: %+v", len(certs), certs)
+	}
+	if certs[0].Name != "etcd/ca.crt" || !certs[0].IsCA || certs[0].Node != "p1" || certs[0].Issuer != IssuerKubeadm {
+		t.Errorf("parseNodeCerts()[0] = %+v, want the etcd CA", certs[0])
+	}
+	if certs[1].Name != "etcd/peer.crt" || certs[1].Subject != "minikube" {
+		t.Errorf("parseNodeCerts()[1] = %+v, want the etcd peer cert", certs[1])
+	}
+	if certs[2].Name != "admin.conf" || !certs[2].ExpiresWithin(2*time.Hour) {
+		t.Errorf("parseNodeCerts()[2] = %+v, want the client cert of admin.conf", certs[2])
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package bootstrapper
 
 import (
+	"bytes"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
@@ -24,6 +26,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/tests"
 	"k8s.io/minikube/pkg/util"
 )
@@ -57,7 +60,57 @@ func TestSetupCerts(t *testing.T) {
 	f := command.NewFakeCommandRunner()
 	f.SetCommandToOutput(expected)
 
-	if err := SetupCerts(f, k8s, config.Node{}); err != nil {
+	if err := SetupCerts(f, config.ClusterConfig{KubernetesConfig: k8s}, config.Node{}); err != nil {
 		t.Fatalf("Error starting cluster: %v", err)
 	}
 }
+
+func TestInstallCA(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer tests.RemoveTempDir(tempDir)
+
+	orgCert, orgKey := filepath.Join(tempDir, "org", "ca.pem"), filepath.Join(tempDir, "org", "ca-key.pem")
+	if err := util.GenerateCACert(orgCert, orgKey, "Example Intermediate CA"); err != nil {
+		t.Fatal(err)
+	}
+	otherCert, otherKey := filepath.Join(tempDir, "other", "ca.pem"), filepath.Join(tempDir, "other", "ca-key.pem")
+	if err := util.GenerateCACert(otherCert, otherKey, "Example Root CA"); err != nil {
+		t.Fatal(err)
+	}
+	org, err := ioutil.ReadFile(orgCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := ioutil.ReadFile(otherCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the intermediate CA followed by its root, only the first one must be trusted by the apiserver
+	chain := filepath.Join(tempDir, "org", "chain.pem")
+	if err := ioutil.WriteFile(chain, append(append([]byte{}, org...), root...), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if replaced, err := CAReplaced(chain); err != nil || replaced {
+		t.Fatalf("CAReplaced() without a CA = %v, %v; want false, nil", replaced, err)
+	}
+	if err := InstallCA(chain, orgKey); err != nil {
+		t.Fatalf("InstallCA() failed: %v", err)
+	}
+	installed, err := ioutil.ReadFile(localpath.CACert())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(installed, org) {
+		t.Errorf("InstallCA() installed %q; want only the first certificate of %s", installed, chain)
+	}
+	if replaced, err := CAReplaced(chain); err != nil || replaced {
+		t.Errorf("CAReplaced() of the installed CA = %v, %v; want false, nil", replaced, err)
+	}
+	if replaced, err := CAReplaced(otherCert); err != nil || !replaced {
+		t.Errorf("CAReplaced() of another CA = %v, %v; want true, nil", replaced, err)
+	}
+	if err := InstallCA(orgCert, otherKey); err == nil {
+		t.Errorf("InstallCA() with the key of another CA succeeded")
+	}
+}
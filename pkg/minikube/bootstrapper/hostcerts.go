@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"bytes"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path"
+	"runtime"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+const (
+	// hostCACertsFile is the name of the CA certificates of the host in the CA dir of the nodes
+	hostCACertsFile = "minikube-host-ca-certificates.crt"
+	// guestCABundle is the CA bundle of the nodes, read by the container runtimes and most tools
+	guestCABundle = "/etc/ssl/certs/ca-certificates.crt"
+)
+
+// hostCABundles are the CA bundles of the system trust store of Linux hosts, as looked up by crypto/x509
+var hostCABundles = []string{
+	"/etc/ssl/certs/ca-certificates.crt",                // Debian/Ubuntu/Gentoo etc.
+	"/etc/pki/tls/certs/ca-bundle.crt",                  // Fedora/RHEL 6
+	"/etc/ssl/ca-bundle.pem",                            // OpenSUSE
+	"/etc/pki/tls/cacert.pem",                           // OpenELEC
+	"/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem", // CentOS/RHEL 7
+	"/etc/ssl/cert.pem",                                 // Alpine Linux
+}
+
+// windowsExportRoots exports the trusted root certificates of the machine and of the user as PEM
+const windowsExportRoots = `Get-ChildItem Cert:\LocalMachine\Root, Cert:\CurrentUser\Root | ForEach-Object {
+"-----BEGIN CERTIFICATE-----"; [Convert]::ToBase64String($_.RawData, 'InsertLineBreaks'); "-----END CERTIFICATE-----" }`
+
+// HostCACerts returns the PEM encoded CA certificates of the system trust store of the host
+func HostCACerts() ([]byte, error) {
+	var data []byte
+	var err error
+	switch runtime.GOOS {
+	case "linux":
+		for _, f := range hostCABundles {
+			data, err = ioutil.ReadFile(f)
+			if err == nil {
+				break
+			}
+		}
+	case "darwin":
+		data, err = exec.Command("security", "find-certificate", "-a", "-p", "/System/Library/Keychains/SystemRootCertificates.keychain", "/Library/Keychains/System.keychain").Output()
+	case "windows":
+		data, err = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsExportRoots).Output()
+	default:
+		return nil, fmt.Errorf("importing the trust store of the host is not supported on %s", runtime.GOOS)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "read the trust store of the host")
+	}
+
+	certs := pemCerts(data)
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no CA certificates found in the trust store of the host")
+	}
+	return certs, nil
+}
+
+// pemCerts returns the distinct certificates of PEM encoded data, without the other blocks and text
+func pemCerts(data []byte) []byte {
+	var certs bytes.Buffer
+	seen := map[string]bool{}
+	for {
+		block, rest := pem.Decode(data)
+		if block == nil {
+			break
+		}
+		data = rest
+		if block.Type != "CERTIFICATE" || seen[string(block.Bytes)] {
+			continue
+		}
+		seen[string(block.Bytes)] = true
+		if err := pem.Encode(&certs, &pem.Block{Type: block.Type, Bytes: block.Bytes}); err != nil {
+			klog.Warningf("unable to encode certificate: %v", err)
+		}
+	}
+	return certs.Bytes()
+}
+
+// InstallHostCACerts adds the CA certificates of the host to the CA bundle of a node, which the container runtime loads when it starts.
+// Without certificates, the original CA bundle of the node is restored.
+func InstallHostCACerts(cr command.Runner, certs []byte) error {
+	orig := guestCABundle + ".minikube-orig"
+	hostCerts := path.Join(vmpath.GuestCertAuthDir, hostCACertsFile)
+	if len(certs) == 0 {
+		script := fmt.Sprintf("if [ -f %s ]; then sudo mv %s %s; fi && sudo rm -f %s", orig, orig, guestCABundle, hostCerts)
+		if _, err := cr.RunCmd(exec.Command("/bin/bash", "-c", script)); err != nil {
+			return errors.Wrap(err, "restore CA bundle")
+		}
+		return nil
+	}
+
+	f := assets.NewMemoryAsset(certs, vmpath.GuestCertAuthDir, hostCACertsFile, "0644")
+	if err := cr.Copy(f); err != nil {
+		return errors.Wrap(err, "copy CA certificates of the host")
+	}
+	script := fmt.Sprintf("sudo mkdir -p %s && if [ ! -f %s ]; then sudo touch %s && sudo cp %s %s; fi && cat %s %s | sudo tee %s >/dev/null",
+		path.Dir(guestCABundle), orig, guestCABundle, guestCABundle, orig, orig, hostCerts, guestCABundle)
+	if _, err := cr.RunCmd(exec.Command("/bin/bash", "-c", script)); err != nil {
+		return errors.Wrap(err, "add CA certificates of the host to the CA bundle")
+	}
+	return nil
+}
@@ -18,12 +18,18 @@ package kubeadm
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net"
+	"os"
 	"os/exec"
 	"path"
+	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
@@ -56,12 +62,14 @@ import (
 	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/kubeconfig"
+	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/out/register"
 	"k8s.io/minikube/pkg/minikube/style"
 	"k8s.io/minikube/pkg/minikube/sysinit"
 	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/trace"
 	"k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/util/retry"
 	"k8s.io/minikube/pkg/version"
@@ -174,6 +182,10 @@ func (k *Bootstrapper) clearStaleConfigs(cfg config.ClusterConfig) error {
 }
 
 func (k *Bootstrapper) init(cfg config.ClusterConfig) error {
+	span := trace.SpanName(trace.KubeadmInit, primaryMachineName(cfg))
+	trace.StartSpan(span)
+	defer trace.EndSpan(span)
+
 	version, err := util.ParseKubernetesVersion(cfg.KubernetesConfig.KubernetesVersion)
 	if err != nil {
 		return errors.Wrap(err, "parsing Kubernetes version")
@@ -774,8 +786,34 @@ func (k *Bootstrapper) JoinCluster(cc config.ClusterConfig, n config.Node, joinC
 	return nil
 }
 
-// GenerateToken creates a token and returns the appropriate kubeadm join command to run, or the already existing token
-func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig) (string, error) {
+// RestoreControlPlane restores the kubeconfigs and static pods of a secondary control-plane node which joined the cluster
+// before, as restartControlPlane does for the primary one. The etcd data of the node still holds its membership.
+func (k *Bootstrapper) RestoreControlPlane(cfg config.ClusterConfig, n config.Node) error {
+	conf := bsutil.KubeadmYamlPath
+	if _, err := k.c.RunCmd(exec.Command("sudo", "cp", conf+".new", conf)); err != nil {
+		return errors.Wrap(err, "cp")
+	}
+
+	baseCmd := fmt.Sprintf("%s init", bsutil.InvokeKubeadm(cfg.KubernetesConfig.KubernetesVersion))
+	cmds := []string{
+		fmt.Sprintf("%s phase certs all --config %s", baseCmd, conf),
+		fmt.Sprintf("%s phase kubeconfig all --config %s", baseCmd, conf),
+		fmt.Sprintf("%s phase kubelet-start --config %s", baseCmd, conf),
+		fmt.Sprintf("%s phase control-plane all --config %s", baseCmd, conf),
+		fmt.Sprintf("%s phase etcd local --config %s", baseCmd, conf),
+	}
+
+	klog.Infof("restoring control plane %s from %s", config.MachineName(cfg, n), conf)
+	for _, c := range cmds {
+		if _, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", c)); err != nil {
+			return errors.Wrap(err, "run")
+		}
+	}
+	return nil
+}
+
+// GenerateToken creates a token and returns the appropriate kubeadm join command for n to run, or the already existing token
+func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig, n config.Node) (string, error) {
 	// Take that generated token and use it to get a kubeadm join command
 	tokenCmd := exec.Command("/bin/bash", "-c", fmt.Sprintf("%s token create --print-join-command --ttl=0", bsutil.InvokeKubeadm(cc.KubernetesConfig.KubernetesVersion)))
 	r, err := k.c.RunCmd(tokenCmd)
@@ -798,9 +836,42 @@ func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig) (string, error) {
 	}
 	joinCmd = fmt.Sprintf("%s --cri-socket %s", joinCmd, sp)
 
+	if n.ControlPlane {
+		certKey, err := k.uploadCerts(cc)
+		if err != nil {
+			return "", errors.Wrap(err, "uploading certs")
+		}
+		joinCmd = fmt.Sprintf("%s --control-plane --certificate-key=%s --apiserver-advertise-address=%s --apiserver-bind-port=%d", joinCmd, certKey, n.IP, n.Port)
+	}
+
 	return joinCmd, nil
 }
 
+// uploadCerts stores the certificate authorities of the control plane in the cluster, encrypted with the returned key,
+// from which joining control-plane nodes download them. kubeadm deletes them after two hours.
+func (k *Bootstrapper) uploadCerts(cc config.ClusterConfig) (string, error) {
+	c := exec.Command("/bin/bash", "-c", fmt.Sprintf("%s init phase upload-certs --upload-certs --config %s",
+		bsutil.InvokeKubeadm(cc.KubernetesConfig.KubernetesVersion), bsutil.KubeadmYamlPath))
+	rr, err := k.c.RunCmd(c)
+	if err != nil {
+		return "", err
+	}
+	return certificateKey(rr.Stdout.String())
+}
+
+// certificateKey returns the certificate key printed by "kubeadm init phase upload-certs", such as:
+// [upload-certs] Storing the certificates in Secret "kubeadm-certs" in the "kube-system" Namespace
+// [upload-certs] Using certificate key:
+// 2c2d6f4f1b7b8a...
+func certificateKey(out string) (string, error) {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	key := strings.TrimSpace(lines[len(lines)-1])
+	if _, err := hex.DecodeString(key); err != nil || len(key) != 64 {
+		return "", fmt.Errorf("unexpected output of upload-certs: %q", out)
+	}
+	return key, nil
+}
+
 // DeleteCluster removes the components that were started earlier
 func (k *Bootstrapper) DeleteCluster(k8s config.KubernetesConfig) error {
 	cr, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: k.c, Socket: k8s.CRISocket})
@@ -847,8 +918,191 @@ func (k *Bootstrapper) DeleteCluster(k8s config.KubernetesConfig) error {
 }
 
 // SetupCerts sets up certificates within the cluster.
-func (k *Bootstrapper) SetupCerts(k8s config.KubernetesConfig, n config.Node) error {
-	return bootstrapper.SetupCerts(k.c, k8s, n)
+func (k *Bootstrapper) SetupCerts(cc config.ClusterConfig, n config.Node) error {
+	return bootstrapper.SetupCerts(k.c, cc, n)
+}
+
+// kubeadmCert is a cert, or a kubeconfig embedding one, which kubeadm manages on a control-plane node
+type kubeadmCert struct {
+	name string // name of the cert for "kubeadm certs renew"
+	path string
+}
+
+// kubeadmRenewedCerts are the certs and kubeconfigs RotateCerts renews with kubeadm, the apiserver cert being issued by minikube
+var kubeadmRenewedCerts = []kubeadmCert{
+	{"apiserver-kubelet-client", path.Join(vmpath.GuestKubernetesCertsDir, "apiserver-kubelet-client.crt")},
+	{"apiserver-etcd-client", path.Join(vmpath.GuestKubernetesCertsDir, "apiserver-etcd-client.crt")},
+	{"front-proxy-client", path.Join(vmpath.GuestKubernetesCertsDir, "front-proxy-client.crt")},
+	{"etcd-server", path.Join(vmpath.GuestKubernetesCertsDir, "etcd", "server.crt")},
+	{"etcd-peer", path.Join(vmpath.GuestKubernetesCertsDir, "etcd", "peer.crt")},
+	{"etcd-healthcheck-client", path.Join(vmpath.GuestKubernetesCertsDir, "etcd", "healthcheck-client.crt")},
+	{"admin.conf", "/etc/kubernetes/admin.conf"},
+	{"controller-manager.conf", "/etc/kubernetes/controller-manager.conf"},
+	{"scheduler.conf", "/etc/kubernetes/scheduler.conf"},
+}
+
+// kubeadmCAs are the CAs of a control-plane node which sign the certs kubeadm manages
+var kubeadmCAs = []string{"ca", "etcd/ca", "front-proxy-ca", "proxy-client-ca"}
+
+// clientCertDataRe matches the client cert embedded in a kubeconfig generated by kubeadm
+var clientCertDataRe = regexp.MustCompile(`client-certificate-data: (\S+)`)
+
+// RotateCerts issues the certs of a control-plane node again: the ones minikube signs, which SetupCerts copies,
+// then the ones kubeadm manages. The kubeconfigs of the node are generated again if the minikube CA changed.
+// The control-plane components are restarted to load them.
+func (k *Bootstrapper) RotateCerts(cfg config.ClusterConfig, n config.Node) error {
+	version, err := util.ParseKubernetesVersion(cfg.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "parsing Kubernetes version")
+	}
+	if version.LT(semver.MustParse("1.15.0")) {
+		return fmt.Errorf("renewing the certs of kubeadm requires Kubernetes v1.15 or later")
+	}
+	certsCmd := "certs"
+	if version.LT(semver.MustParse("1.20.0")) {
+		certsCmd = "alpha certs"
+	}
+
+	caChanged, err := k.caChanged()
+	if err != nil {
+		return errors.Wrap(err, "compare CA")
+	}
+	if caChanged && len(cfg.Nodes) > 1 {
+		return fmt.Errorf("the kubelets of the other nodes do not trust the new CA, delete the cluster and start it again")
+	}
+
+	if err := k.SetupCerts(cfg, n); err != nil {
+		return errors.Wrap(err, "setting up certs")
+	}
+
+	kubeadm := bsutil.InvokeKubeadm(cfg.KubernetesConfig.KubernetesVersion)
+	cmds := []string{}
+	for _, c := range kubeadmRenewedCerts {
+		cmds = append(cmds, fmt.Sprintf("%s %s renew %s --config %s", kubeadm, certsCmd, c.name, bsutil.KubeadmYamlPath))
+	}
+	if caChanged {
+		cmds = append(cmds,
+			"sudo rm -f /etc/kubernetes/admin.conf /etc/kubernetes/kubelet.conf /etc/kubernetes/controller-manager.conf /etc/kubernetes/scheduler.conf",
+			fmt.Sprintf("%s init phase kubeconfig all --config %s", kubeadm, bsutil.KubeadmYamlPath))
+	}
+	for _, c := range cmds {
+		if _, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", c)); err != nil {
+			return errors.Wrap(err, "renew certs")
+		}
+	}
+	if err := k.resignKubeadmCerts(bootstrapper.CertExpiration(cfg)); err != nil {
+		return errors.Wrap(err, "extend certs")
+	}
+
+	cr, err := cruntime.New(cruntime.Config{Type: cfg.KubernetesConfig.ContainerRuntime, Runner: k.c})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+	if caChanged {
+		if err := sysinit.New(k.c).Restart("kubelet"); err != nil {
+			return errors.Wrap(err, "restart kubelet")
+		}
+	}
+	// the kubelet starts the static pods of the components again
+	for _, name := range []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler", "etcd"} {
+		ids, err := cr.ListContainers(cruntime.ListContainersOptions{State: cruntime.Running, Name: name, Namespaces: []string{"kube-system"}})
+		if err != nil {
+			return errors.Wrapf(err, "list %s containers", name)
+		}
+		if len(ids) > 0 {
+			if err := cr.StopContainers(ids); err != nil {
+				return errors.Wrapf(err, "stop %s", name)
+			}
+		}
+	}
+	return kverify.WaitForAPIServerProcess(cr, k, cfg, k.c, time.Now(), kconst.DefaultControlPlaneTimeout)
+}
+
+// resignKubeadmCerts signs the certs kubeadm renewed again, as kubeadm issues them for a year, to be valid for expiration
+func (k *Bootstrapper) resignKubeadmCerts(expiration time.Duration) error {
+	type ca struct{ cert, key []byte }
+	cas := []ca{}
+	for _, name := range kubeadmCAs {
+		p := path.Join(vmpath.GuestKubernetesCertsDir, name)
+		cert, err := k.readFile(p + ".crt")
+		if err != nil {
+			klog.Infof("skipping CA %s: %v", name, err)
+			continue
+		}
+		key, err := k.readFile(p + ".key")
+		if err != nil {
+			klog.Infof("skipping CA %s: %v", name, err)
+			continue
+		}
+		cas = append(cas, ca{cert, key})
+	}
+
+	for _, c := range kubeadmRenewedCerts {
+		data, err := k.readFile(c.path)
+		if err != nil {
+			return err
+		}
+		cert, perms := data, "0644"
+		isKubeconfig := strings.HasSuffix(c.path, ".conf")
+		var encoded []byte
+		if isKubeconfig {
+			m := clientCertDataRe.FindSubmatch(data)
+			if m == nil {
+				klog.Infof("%s has no embedded client cert", c.path)
+				continue
+			}
+			encoded = m[1]
+			if cert, err = base64.StdEncoding.DecodeString(string(encoded)); err != nil {
+				return errors.Wrapf(err, "decode client cert of %s", c.path)
+			}
+			perms = "0600"
+		}
+
+		var resigned []byte
+		for _, ca := range cas {
+			if util.IssuedBy(cert, ca.cert) {
+				if resigned, err = util.ResignCert(cert, ca.cert, ca.key, expiration); err != nil {
+					return errors.Wrapf(err, "sign %s", c.path)
+				}
+				break
+			}
+		}
+		if resigned == nil {
+			return fmt.Errorf("no CA of the node issued %s", c.path)
+		}
+		if isKubeconfig {
+			resigned = bytes.Replace(data, encoded, []byte(base64.StdEncoding.EncodeToString(resigned)), 1)
+		}
+		if err := k.c.Copy(assets.NewMemoryAssetTarget(resigned, c.path, perms)); err != nil {
+			return errors.Wrapf(err, "copy %s", c.path)
+		}
+	}
+	return nil
+}
+
+// readFile returns the content of a file of the node
+func (k *Bootstrapper) readFile(p string) ([]byte, error) {
+	rr, err := k.c.RunCmd(exec.Command("sudo", "cat", p))
+	if err != nil {
+		return nil, errors.Wrapf(err, "read %s", p)
+	}
+	return rr.Stdout.Bytes(), nil
+}
+
+// caChanged returns whether the CA of the node is not the one of the minikube home, or the latter is to be generated
+func (k *Bootstrapper) caChanged() (bool, error) {
+	local, err := ioutil.ReadFile(localpath.CACert())
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	rr, err := k.c.RunCmd(exec.Command("sudo", "cat", path.Join(vmpath.GuestKubernetesCertsDir, "ca.crt")))
+	if err != nil {
+		return false, err
+	}
+	return !bytes.Equal(bytes.TrimSpace(local), bytes.TrimSpace(rr.Stdout.Bytes())), nil
 }
 
 // UpdateCluster updates the control plane with cluster-level info.
@@ -866,9 +1120,12 @@ func (k *Bootstrapper) UpdateCluster(cfg config.ClusterConfig) error {
 		return errors.Wrap(err, "runtime")
 	}
 
+	span := trace.SpanName(trace.Preload, primaryMachineName(cfg))
+	trace.StartSpan(span)
 	if err := r.Preload(cfg); err != nil {
 		klog.Infof("preload failed, will try to load cached images: %v", err)
 	}
+	trace.EndSpan(span)
 
 	if cfg.KubernetesConfig.ShouldLoadCachedImages {
 		if err := machine.LoadCachedImages(&cfg, k.c, images, constants.ImageCacheDir, false); err != nil {
@@ -923,6 +1180,14 @@ func (k *Bootstrapper) UpdateNode(cfg config.ClusterConfig, n config.Node, r cru
 		files = append(files, assets.NewMemoryAssetTarget(kubeadmCfg, bsutil.KubeadmYamlPath+".new", "0640"))
 	}
 
+	if n.ControlPlane && config.IsHA(cfg) {
+		kubeVIP, err := k.kubeVIPManifest(cfg, n)
+		if err != nil {
+			return errors.Wrap(err, "generating kube-vip manifest")
+		}
+		files = append(files, assets.NewMemoryAssetTarget(kubeVIP, bsutil.KubeVIPManifestPath, "0600"))
+	}
+
 	// Installs compatibility shims for non-systemd environments
 	kubeletPath := path.Join(vmpath.GuestPersistentDir, "binaries", cfg.KubernetesConfig.KubernetesVersion, "kubelet")
 	shims, err := sm.GenerateInitShim("kubelet", kubeletPath, bsutil.KubeletSystemdConfFile)
@@ -940,13 +1205,34 @@ func (k *Bootstrapper) UpdateNode(cfg config.ClusterConfig, n config.Node, r cru
 		return errors.Wrap(err, "control plane")
 	}
 
-	if err := machine.AddHostAlias(k.c, constants.ControlPlaneAlias, net.ParseIP(cp.IP)); err != nil {
+	// the nodes of HA clusters reach the control planes through their virtual IP
+	cpIP := cp.IP
+	if config.IsHA(cfg) {
+		cpIP = cfg.KubernetesConfig.APIServerHAVIP
+	}
+	if err := machine.AddHostAlias(k.c, constants.ControlPlaneAlias, net.ParseIP(cpIP)); err != nil {
 		return errors.Wrap(err, "host alias")
 	}
 
 	return nil
 }
 
+// kubeVIPManifest returns the kube-vip static pod of the control-plane node n of a HA cluster. kube-vip balances
+// the API server traffic only if the IPVS kernel modules can be loaded, else it just moves the virtual IP.
+func (k *Bootstrapper) kubeVIPManifest(cfg config.ClusterConfig, n config.Node) ([]byte, error) {
+	iface, err := bsutil.NodeInterface(k.c, n.IP)
+	if err != nil {
+		return nil, err
+	}
+
+	lb := true
+	if _, err := k.c.RunCmd(exec.Command("sudo", "modprobe", "-a", "ip_vs", "ip_vs_rr", "nf_conntrack")); err != nil {
+		klog.Infof("IPVS is not available, control-plane load balancing disabled: %v", err)
+		lb = false
+	}
+	return bsutil.NewKubeVIPManifest(cfg, n, iface, lb)
+}
+
 // kubectlPath returns the path to the kubelet
 func kubectlPath(cfg config.ClusterConfig) string {
 	return path.Join(vmpath.GuestPersistentDir, "binaries", cfg.KubernetesConfig.KubernetesVersion, "kubectl")
@@ -978,6 +1264,33 @@ func (k *Bootstrapper) applyNodeLabels(cfg config.ClusterConfig) error {
 	return nil
 }
 
+// LabelAndTaintNode applies the labels and taints of a node, with kubectl on the control-plane node of the bootstrapper
+func (k *Bootstrapper) LabelAndTaintNode(cfg config.ClusterConfig, n config.Node) error {
+	if len(n.Labels) == 0 && len(n.Taints) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), applyTimeoutSeconds*time.Second)
+	defer cancel()
+
+	nodeName := bsutil.KubeNodeName(cfg, n)
+	kubeconfig := fmt.Sprintf("--kubeconfig=%s", path.Join(vmpath.GuestPersistentDir, "kubeconfig"))
+	// example:
+	// sudo /var/lib/minikube/binaries/<version>/kubectl label nodes p1-m02 pool=gpu --overwrite --kubeconfig=/var/lib/minikube/kubeconfig
+	if len(n.Labels) > 0 {
+		args := append([]string{kubectlPath(cfg), "label", "nodes", nodeName}, config.FormatNodeLabels(n.Labels)...)
+		if _, err := k.c.RunCmd(exec.CommandContext(ctx, "sudo", append(args, "--overwrite", kubeconfig)...)); err != nil {
+			return errors.Wrapf(err, "labeling node %s", nodeName)
+		}
+	}
+	if len(n.Taints) > 0 {
+		args := append([]string{kubectlPath(cfg), "taint", "nodes", nodeName}, n.Taints...)
+		if _, err := k.c.RunCmd(exec.CommandContext(ctx, "sudo", append(args, "--overwrite", kubeconfig)...)); err != nil {
+			return errors.Wrapf(err, "tainting node %s", nodeName)
+		}
+	}
+	return nil
+}
+
 // elevateKubeSystemPrivileges gives the kube-system service account cluster admin privileges to work with RBAC.
 func (k *Bootstrapper) elevateKubeSystemPrivileges(cfg config.ClusterConfig) error {
 	start := time.Now()
@@ -1107,3 +1420,12 @@ func adviseNodePressure(err error, name string, drv string) {
 		return
 	}
 }
+
+// primaryMachineName returns the machine name of the primary control plane, which names its trace spans
+func primaryMachineName(cfg config.ClusterConfig) string {
+	cp, err := config.PrimaryControlPlane(&cfg)
+	if err != nil {
+		return cfg.Name
+	}
+	return config.MachineName(cfg, cp)
+}
@@ -0,0 +1,388 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bundle creates and imports archives of the files "minikube start" downloads,
+// so that clusters can be started on machines with no network access.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/detect"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/image"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/version"
+)
+
+// manifestFile is the first file of a bundle, describing its content
+const manifestFile = "manifest.json"
+
+// Manifest describes what a bundle can start
+type Manifest struct {
+	MinikubeVersion   string
+	KubernetesVersion string
+	ContainerRuntime  string
+	Driver            string
+	// Arch is the architecture of the host and of the binaries of Kubernetes
+	Arch   string
+	Addons []string `json:",omitempty"`
+	// Images are the images of the addons, loaded from the cache by "minikube start" once they are in the "cache" config
+	Images []string `json:",omitempty"`
+	// Files are the paths of the files of the bundle, relative to the minikube home directory
+	Files []string
+}
+
+// Options are what a bundle is created for
+type Options struct {
+	KubernetesVersion string
+	ContainerRuntime  string
+	Driver            string
+	Addons            []string
+}
+
+// Supported returns whether "minikube start" can use the files of a bundle with a driver:
+// the ISO for VM drivers and the base image for docker, which is loaded into the daemon from the cache
+func Supported(name string) bool {
+	return driver.IsVM(name) || driver.IsDocker(name)
+}
+
+// Cache downloads to the cache of the minikube home directory all the files needed to start a cluster
+// with o, as "minikube start --download-only" would, along with the images of the addons.
+// It returns the manifest of a bundle of these files.
+func Cache(o Options) (*Manifest, error) {
+	m := &Manifest{
+		MinikubeVersion:   version.GetVersion(),
+		KubernetesVersion: o.KubernetesVersion,
+		ContainerRuntime:  o.ContainerRuntime,
+		Driver:            o.Driver,
+		Arch:              detect.EffectiveArch(),
+		Addons:            o.Addons,
+	}
+	var files []string
+
+	if driver.IsKIC(o.Driver) {
+		out.Step(style.Pulling, "Pulling base image ...")
+		if err := download.ImageToCache(kic.BaseImage); err != nil {
+			return nil, errors.Wrap(err, "caching base image")
+		}
+		files = append(files, download.ImagePathInCache(kic.BaseImage))
+	} else {
+		u, err := download.ISO(download.DefaultISOURLs(), false)
+		if err != nil {
+			return nil, errors.Wrap(err, "caching ISO")
+		}
+		iso, err := download.LocalISOPath(u)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, iso)
+	}
+
+	if download.PreloadExists(o.KubernetesVersion, o.ContainerRuntime, o.Driver) {
+		if err := download.Preload(o.KubernetesVersion, o.ContainerRuntime, o.Driver); err != nil {
+			return nil, errors.Wrap(err, "caching preload")
+		}
+		files = append(files, download.TarballPath(o.KubernetesVersion, o.ContainerRuntime))
+		if _, err := os.Stat(download.PreloadChecksumPath(o.KubernetesVersion, o.ContainerRuntime)); err == nil {
+			files = append(files, download.PreloadChecksumPath(o.KubernetesVersion, o.ContainerRuntime))
+		}
+	} else {
+		// without a preload, the images and binaries of Kubernetes are loaded from the cache
+		klog.Infof("no preload for %s on %s, caching images and binaries", o.KubernetesVersion, o.ContainerRuntime)
+		if err := machine.CacheImagesForBootstrapper("", o.KubernetesVersion, bootstrapper.Kubeadm); err != nil {
+			return nil, err
+		}
+		imgs, err := images.Kubeadm("", o.KubernetesVersion)
+		if err != nil {
+			return nil, errors.Wrap(err, "kubeadm images")
+		}
+		for _, img := range imgs {
+			files = append(files, imagePath(img))
+		}
+
+		if err := machine.CacheBinariesForBootstrapper(o.KubernetesVersion, bootstrapper.Kubeadm, nil); err != nil {
+			return nil, errors.Wrap(err, "caching binaries")
+		}
+		for _, bin := range bootstrapper.GetCachedBinaryList(bootstrapper.Kubeadm) {
+			files = append(files, localpath.MakeMiniPath("cache", "linux", o.KubernetesVersion, bin))
+		}
+	}
+
+	kubectl, err := node.CacheKubectlBinary(o.KubernetesVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "caching kubectl")
+	}
+	files = append(files, kubectl)
+
+	for _, name := range o.Addons {
+		imgs, err := addonImages(name)
+		if err != nil {
+			return nil, err
+		}
+		if len(imgs) == 0 {
+			continue
+		}
+		out.Step(style.FileDownload, "Caching the images of addon {{.name}} ...", out.V{"name": name})
+		if err := image.SaveToDir(imgs, constants.ImageCacheDir, false); err != nil {
+			return nil, errors.Wrapf(err, "caching images of addon %s", name)
+		}
+		for _, img := range imgs {
+			f := imagePath(img)
+			// SaveToDir warns about the images which could not be found, without failing
+			if _, err := os.Stat(f); err != nil {
+				klog.Warningf("leaving %s out of the bundle: %v", img, err)
+				continue
+			}
+			m.Images = append(m.Images, img)
+			files = append(files, f)
+		}
+	}
+
+	for _, f := range files {
+		rel, err := relPath(f)
+		if err != nil {
+			return nil, err
+		}
+		m.Files = append(m.Files, rel)
+	}
+	m.Files = dedupe(m.Files)
+	return m, nil
+}
+
+// addonImages returns the default images of an addon, with their registry
+func addonImages(name string) ([]string, error) {
+	a, ok := assets.Addons[name]
+	if !ok {
+		return nil, fmt.Errorf("addon %q does not exist", name)
+	}
+	var imgs []string
+	for k, img := range a.Images {
+		if reg := a.Registries[k]; reg != "" {
+			img = strings.TrimSuffix(reg, "/") + "/" + img
+		}
+		imgs = append(imgs, img)
+	}
+	sort.Strings(imgs)
+	return imgs, nil
+}
+
+// imagePath returns the path image.SaveToDir saves an image to
+func imagePath(img string) string {
+	return localpath.SanitizeCacheDir(filepath.Join(constants.ImageCacheDir, img))
+}
+
+// relPath returns the slash separated path of a file of the minikube home directory, relative to it
+func relPath(f string) (string, error) {
+	rel, err := filepath.Rel(localpath.MiniPath(), f)
+	if err != nil {
+		return "", errors.Wrapf(err, "relative path of %s", f)
+	}
+	rel = filepath.ToSlash(rel)
+	if err := validPath(rel); err != nil {
+		return "", err
+	}
+	return rel, nil
+}
+
+// validPath returns an error if a path of a bundle is not in the cache of the minikube home directory,
+// so that importing a bundle can not write anywhere else
+func validPath(name string) error {
+	if path.IsAbs(name) || strings.Contains(name, `\`) || path.Clean(name) != name || !strings.HasPrefix(name, "cache/") {
+		return fmt.Errorf("%q is not a path in the cache directory", name)
+	}
+	return nil
+}
+
+func dedupe(s []string) []string {
+	seen := map[string]bool{}
+	var d []string
+	for _, e := range s {
+		if !seen[e] {
+			seen[e] = true
+			d = append(d, e)
+		}
+	}
+	return d
+}
+
+// Write writes a bundle of the files of m to w, as a gzipped tarball starting with the manifest
+func Write(m *Manifest, w io.Writer) error {
+	// most of the files are already compressed, favor speed
+	gz, err := gzip.NewWriterLevel(w, gzip.BestSpeed)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(gz)
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshal manifest")
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: manifestFile, Mode: 0644, Size: int64(len(b))}); err != nil {
+		return errors.Wrap(err, "writing header of manifest")
+	}
+	if _, err := tw.Write(b); err != nil {
+		return errors.Wrap(err, "writing manifest")
+	}
+
+	for _, name := range m.Files {
+		if err := addFile(tw, name); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return errors.Wrap(err, "closing tarball")
+	}
+	return gz.Close()
+}
+
+// addFile writes a file of the minikube home directory to the bundle
+func addFile(tw *tar.Writer, name string) error {
+	f, err := os.Open(localpath.MakeMiniPath(filepath.FromSlash(name)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	h, err := tar.FileInfoHeader(st, "")
+	if err != nil {
+		return errors.Wrapf(err, "header of %s", name)
+	}
+	h.Name = name
+	if err := tw.WriteHeader(h); err != nil {
+		return errors.Wrapf(err, "writing header of %s", name)
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return errors.Wrapf(err, "writing %s", name)
+	}
+	return nil
+}
+
+// Create writes a bundle of the files of m to a file
+func Create(m *Manifest, file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return errors.Wrap(err, "creating bundle")
+	}
+	if err := Write(m, f); err != nil {
+		f.Close()
+		os.Remove(file)
+		return err
+	}
+	return f.Close()
+}
+
+// Read extracts the files of a bundle read from r to the minikube home directory, and returns its manifest.
+// Files already in the cache are replaced.
+func Read(r io.Reader) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "not a bundle")
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	h, err := tr.Next()
+	if err != nil || h.Name != manifestFile {
+		return nil, fmt.Errorf("not a bundle, %s is missing", manifestFile)
+	}
+	m := &Manifest{}
+	if err := json.NewDecoder(tr).Decode(m); err != nil {
+		return nil, errors.Wrap(err, "reading manifest")
+	}
+
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "reading bundle")
+		}
+		if err := validPath(h.Name); err != nil {
+			return nil, err
+		}
+		if err := extractFile(tr, h); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// extractFile writes a file of the bundle to the minikube home directory, through a temporary file
+// so that an interrupted import does not leave a truncated file in the cache
+func extractFile(r io.Reader, h *tar.Header) error {
+	dst := localpath.MakeMiniPath(filepath.FromSlash(h.Name))
+	klog.Infof("extracting %s (%d bytes)", dst, h.Size)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return errors.Wrapf(err, "making directory of %s", dst)
+	}
+	tmp := dst + ".import"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(h.Mode).Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return errors.Wrapf(err, "extracting %s", h.Name)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+// Import extracts the files of a bundle file to the minikube home directory, and returns its manifest
+func Import(file string) (*Manifest, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening bundle")
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// DefaultFile returns the default name of a bundle for o
+func DefaultFile(o Options) string {
+	return fmt.Sprintf("minikube-%s-%s-%s-%s.tar.gz", version.GetVersion(), o.KubernetesVersion, o.ContainerRuntime, o.Driver)
+}
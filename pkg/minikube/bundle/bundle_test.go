@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// setMinikubeHome points the minikube home to a temporary directory for the duration of the test
+func setMinikubeHome(t *testing.T) {
+	old := os.Getenv(localpath.MinikubeHome)
+	if err := os.Setenv(localpath.MinikubeHome, t.TempDir()); err != nil {
+		t.Fatalf("unable to set %s: %v", localpath.MinikubeHome, err)
+	}
+	t.Cleanup(func() { os.Setenv(localpath.MinikubeHome, old) })
+}
+
+func TestValidPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"cache/iso/amd64/minikube-v1.20.0.iso", true},
+		{"cache/images/k8s.gcr.io/pause_3.2", true},
+		{"config/config.json", false},
+		{"cache/../config/config.json", false},
+		{"../cache/kic/kicbase.tar", false},
+		{"/cache/kic/kicbase.tar", false},
+		{`cache\..\..\evil`, false},
+		{"cache/", false},
+	}
+	for _, tc := range tests {
+		if err := validPath(tc.name); (err == nil) != tc.valid {
+			t.Errorf("validPath(%q) = %v; want valid: %v", tc.name, err, tc.valid)
+		}
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	setMinikubeHome(t)
+	files := map[string]string{
+		"cache/linux/v1.20.7/kubelet":   "kubelet",
+		"cache/images/k8s.gcr.io/pause": "pause",
+	}
+	m := &Manifest{KubernetesVersion: "v1.20.7", ContainerRuntime: "docker", Driver: "kvm2", Images: []string{"k8s.gcr.io/pause"}}
+	for name, content := range files {
+		p := localpath.MakeMiniPath(filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		m.Files = append(m.Files, name)
+	}
+
+	var b bytes.Buffer
+	if err := Write(m, &b); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	// import to another minikube home
+	setMinikubeHome(t)
+	got, err := Read(&b)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if got.KubernetesVersion != m.KubernetesVersion || len(got.Files) != len(m.Files) || len(got.Images) != 1 {
+		t.Errorf("Read returned manifest %+v; want %+v", got, m)
+	}
+	for name, content := range files {
+		b, err := ioutil.ReadFile(localpath.MakeMiniPath(filepath.FromSlash(name)))
+		if err != nil {
+			t.Errorf("%s was not imported: %v", name, err)
+			continue
+		}
+		if string(b) != content {
+			t.Errorf("%s = %q; want %q", name, b, content)
+		}
+	}
+}
+
+func TestReadOutsideCache(t *testing.T) {
+	setMinikubeHome(t)
+
+	var b bytes.Buffer
+	gz := gzip.NewWriter(&b)
+	tw := tar.NewWriter(gz)
+	add := func(name, content string) {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add(manifestFile, "{}")
+	add("cache/../config/config.json", "{}")
+	tw.Close()
+	gz.Close()
+
+	if _, err := Read(&b); err == nil {
+		t.Errorf("Read extracted a file outside of the cache")
+	}
+	if _, err := os.Stat(localpath.ConfigFile()); err == nil {
+		t.Errorf("Read wrote %s", localpath.ConfigFile())
+	}
+}
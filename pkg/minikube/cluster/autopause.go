@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os/exec"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// AutoPauseStatus is the status served by the auto-pause daemon on its /status endpoint
+type AutoPauseStatus struct {
+	Paused       bool      `json:"paused"`
+	Interval     string    `json:"interval"`
+	Namespaces   []string  `json:"namespaces"`
+	LastActivity time.Time `json:"lastActivity"`
+	Requests     int64     `json:"requests"`
+	Failures     int       `json:"failures"`
+	LastError    string    `json:"lastError,omitempty"`
+}
+
+// AutoPauseAddress returns the address the auto-pause daemon listens on
+func AutoPauseAddress(k config.KubernetesConfig) string {
+	if k.AutoPauseAddress != "" {
+		return k.AutoPauseAddress
+	}
+	return constants.AutoPauseListenAddress
+}
+
+// CheckAutoPause queries the status endpoint of the auto-pause daemon running on the node
+func CheckAutoPause(r command.Runner, k config.KubernetesConfig) (*AutoPauseStatus, error) {
+	url, err := autoPauseStatusURL(AutoPauseAddress(k))
+	if err != nil {
+		return nil, errors.Wrap(err, "auto-pause address")
+	}
+
+	rr, err := r.RunCmd(exec.Command("curl", "-sS", "--max-time", "5", url))
+	if err != nil {
+		return nil, errors.Wrap(err, "auto-pause status")
+	}
+
+	st := &AutoPauseStatus{}
+	if err := json.Unmarshal(rr.Stdout.Bytes(), st); err != nil {
+		return nil, errors.Wrap(err, "parsing auto-pause status")
+	}
+	return st, nil
+}
+
+// autoPauseStatusURL returns the URL of the status endpoint of the daemon listening on addr, through the loopback when it listens on all the addresses
+func autoPauseStatusURL(addr string) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+	return fmt.Sprintf("http://%s/status", net.JoinHostPort(host, port)), nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import "testing"
+
+func TestAutoPauseStatusURL(t *testing.T) {
+	var tests = []struct {
+		addr string
+		want string
+	}{
+		{addr: ":8080", want: "http://127.0.0.1:8080/status"},
+		{addr: "0.0.0.0:8080", want: "http://127.0.0.1:8080/status"},
+		{addr: "[::]:8080", want: "http://127.0.0.1:8080/status"},
+		{addr: "192.168.49.2:8080", want: "http://192.168.49.2:8080/status"},
+		{addr: "[fd00::2]:8080", want: "http://[fd00::2]:8080/status"},
+	}
+	for _, tc := range tests {
+		got, err := autoPauseStatusURL(tc.addr)
+		if err != nil {
+			t.Fatalf("autoPauseStatusURL(%q) error: %v", tc.addr, err)
+		}
+		if got != tc.want {
+			t.Errorf("autoPauseStatusURL(%q) = %q, want %q", tc.addr, got, tc.want)
+		}
+	}
+
+	if _, err := autoPauseStatusURL("8080"); err == nil {
+		t.Errorf("autoPauseStatusURL(%q) expected an error", "8080")
+	}
+}
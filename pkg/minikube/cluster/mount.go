@@ -137,6 +137,15 @@ func mntCmd(source string, target string, c *MountConfig) string {
 	return fmt.Sprintf("sudo mount -t %s -o %s %s %s", c.Type, strings.Join(opts, ","), source, target)
 }
 
+// Mounted returns whether target is a mount point in the VM
+func Mounted(r mountRunner, target string) (bool, error) {
+	rr, err := r.RunCmd(exec.Command("/bin/bash", "-c", fmt.Sprintf("findmnt -n -M %s || true", target)))
+	if err != nil {
+		return false, errors.Wrap(err, "findmnt")
+	}
+	return strings.TrimSpace(rr.Stdout.String()) != "", nil
+}
+
 // Unmount unmounts a path
 func Unmount(r mountRunner, target string) error {
 	// grep because findmnt will also display the parent!
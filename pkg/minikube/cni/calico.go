@@ -39,11 +39,11 @@ data:
   # Typha is disabled.
   typha_service_name: "none"
   # Configure the backend to use.
-  calico_backend: "bird"
+  calico_backend: "{{ if eq .Encapsulation "vxlan" }}vxlan{{ else }}bird{{ end }}"
   # Configure the MTU to use for workload interfaces and the
   # tunnels.  For IPIP, set to your network MTU - 20; for VXLAN
   # set to your network MTU - 50.
-  veth_mtu: "1440"
+  veth_mtu: "{{ .MTU }}"
 
   # The CNI network configuration to install on each node.  The special
   # values in this config will be automatically populated.
@@ -682,10 +682,10 @@ spec:
               value: "autodetect"
             # Enable IPIP
             - name: CALICO_IPV4POOL_IPIP
-              value: "Always"
+              value: "{{ if eq .Encapsulation "ipip" }}Always{{ else }}Never{{ end }}"
             # Enable or Disable VXLAN on the default IP pool.
             - name: CALICO_IPV4POOL_VXLAN
-              value: "Never"
+              value: "{{ if eq .Encapsulation "vxlan" }}Always{{ else }}Never{{ end }}"
             # Set MTU for tunnel device used if ipip is enabled
             - name: FELIX_IPINIPMTU
               valueFrom:
@@ -701,8 +701,13 @@ spec:
             # The default IPv4 pool to create on startup if none exists. Pod IPs will be
             # chosen from this range. Changing this value after installation will have
             # no effect. This should fall within --cluster-cidr
+            {{- if .PodCIDR }}
+            - name: CALICO_IPV4POOL_CIDR
+              value: "{{ .PodCIDR }}"
+            {{- else }}
             # - name: CALICO_IPV4POOL_CIDR
             #   value: "192.168.0.0/16"
+            {{- end }}
             # Disable file logging so kubectl logs works.
             - name: CALICO_DISABLE_FILE_LOGGING
               value: "true"
@@ -729,7 +734,9 @@ spec:
               command:
               - /bin/calico-node
               - -felix-live
+              {{- if ne .Encapsulation "vxlan" }}
               - -bird-live
+              {{- end }}
             periodSeconds: 10
             initialDelaySeconds: 10
             failureThreshold: 6
@@ -738,7 +745,9 @@ spec:
               command:
               - /bin/calico-node
               - -felix-ready
+              {{- if ne .Encapsulation "vxlan" }}
               - -bird-ready
+              {{- end }}
             periodSeconds: 10
           volumeMounts:
             - mountPath: /lib/modules
@@ -878,6 +887,7 @@ type Calico struct {
 }
 
 type calicoTmplStruct struct {
+	tmplInput
 	DeploymentImageName string
 	DaemonSetImageName  string
 }
@@ -890,6 +900,12 @@ func (c Calico) String() string {
 // manifest returns a Kubernetes manifest for a CNI
 func (c Calico) manifest() (assets.CopyableFile, error) {
 	input := &calicoTmplStruct{
+		tmplInput: tmplInput{
+			// unless set, calico detects the pod CIDR passed to kubeadm
+			PodCIDR:       c.cc.KubernetesConfig.CNIOptions[OptPodCIDR],
+			MTU:           optionOrDefault(c.cc, OptMTU, "1440"),
+			Encapsulation: optionOrDefault(c.cc, OptEncapsulation, "ipip"),
+		},
 		DeploymentImageName: images.CalicoDeployment(c.cc.KubernetesConfig.ImageRepository),
 		DaemonSetImageName:  images.CalicoDaemonSet(c.cc.KubernetesConfig.ImageRepository),
 	}
@@ -913,5 +929,14 @@ func (c Calico) Apply(r Runner) error {
 // CIDR returns the default CIDR used by this CNI
 func (c Calico) CIDR() string {
 	// Calico docs specify 192.168.0.0/16 - but we do this for compatibility with other CNI's.
-	return DefaultPodCIDR
+	return podCIDR(c.cc)
+}
+
+// ValidateOptions checks the options set with --cni-opt, the MTU should be the network MTU - 20 for ipip and - 50 for vxlan
+func (c Calico) ValidateOptions(opts map[string]string) error {
+	return validateOptions(c, map[string]validator{
+		OptPodCIDR:       validateCIDR,
+		OptMTU:           validateMTU,
+		OptEncapsulation: oneOf("ipip", "vxlan", "none"),
+	}, opts)
 }
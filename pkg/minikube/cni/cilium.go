@@ -17,14 +17,17 @@ limitations under the License.
 package cni
 
 import (
+	"bytes"
 	"os/exec"
+	"text/template"
 
 	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/config"
 )
 
 // From https://raw.githubusercontent.com/cilium/cilium/v1.8/install/kubernetes/quick-install.yaml
-var ciliumTmpl = `---
+var ciliumTmpl = template.Must(template.New("cilium").Parse(`---
 # Source: cilium/charts/agent/templates/serviceaccount.yaml
 apiVersion: v1
 kind: ServiceAccount
@@ -121,7 +124,7 @@ data:
   #   - disabled
   #   - vxlan (default)
   #   - geneve
-  tunnel: vxlan
+  tunnel: {{ .Encapsulation }}
 
   # Name of the cluster. Only relevant when building a mesh of clusters.
   cluster-name: default
@@ -166,9 +169,17 @@ data:
   enable-remote-node-identity: "true"
   operator-api-serve-addr: "127.0.0.1:9234"
   ipam: "cluster-pool"
-  cluster-pool-ipv4-cidr: "10.0.0.0/8"
+  cluster-pool-ipv4-cidr: "{{ .PodCIDR }}"
   cluster-pool-ipv4-mask-size: "24"
   disable-cnp-status-updates: "true"
+{{- if .MTU }}
+  mtu: "{{ .MTU }}"
+{{- end }}
+{{- if .Hubble }}
+  enable-hubble: "true"
+  hubble-socket-path: "/var/run/cilium/hubble.sock"
+  hubble-listen-address: ":4244"
+{{- end }}
 ---
 # Source: cilium/charts/agent/templates/clusterrole.yaml
 apiVersion: rbac.authorization.k8s.io/v1
@@ -667,7 +678,7 @@ spec:
       - configMap:
           name: cilium-config
         name: cilium-config-path
-`
+`))
 
 // Cilium is the Cilium CNI manager
 type Cilium struct {
@@ -686,10 +697,40 @@ func (c Cilium) Apply(r Runner) error {
 		return errors.Wrap(err, "bpf mount")
 	}
 
-	return applyManifest(c.cc, r, manifestAsset([]byte(ciliumTmpl)))
+	m, err := c.manifest()
+	if err != nil {
+		return errors.Wrap(err, "manifest")
+	}
+	return applyManifest(c.cc, r, m)
+}
+
+// manifest returns a Kubernetes manifest for a CNI
+func (c Cilium) manifest() (assets.CopyableFile, error) {
+	input := &tmplInput{
+		PodCIDR:       optionOrDefault(c.cc, OptPodCIDR, "10.0.0.0/8"),
+		MTU:           c.cc.KubernetesConfig.CNIOptions[OptMTU],
+		Encapsulation: optionOrDefault(c.cc, OptEncapsulation, "vxlan"),
+		Hubble:        c.cc.KubernetesConfig.CNIOptions[OptHubble] == "true",
+	}
+
+	b := bytes.Buffer{}
+	if err := ciliumTmpl.Execute(&b, input); err != nil {
+		return nil, err
+	}
+	return manifestAsset(b.Bytes()), nil
 }
 
 // CIDR returns the default CIDR used by this CNI
 func (c Cilium) CIDR() string {
-	return DefaultPodCIDR
+	return podCIDR(c.cc)
+}
+
+// ValidateOptions checks the options set with --cni-opt
+func (c Cilium) ValidateOptions(opts map[string]string) error {
+	return validateOptions(c, map[string]validator{
+		OptPodCIDR:       validateCIDR,
+		OptMTU:           validateMTU,
+		OptEncapsulation: oneOf("vxlan", "geneve"),
+		OptHubble:        oneOf("true", "false"),
+	}, opts)
 }
@@ -73,6 +73,9 @@ type Manager interface {
 
 	// String representation
 	String() string
+
+	// ValidateOptions checks the options set with --cni-opt, rejecting the ones this CNI does not support
+	ValidateOptions(map[string]string) error
 }
 
 // tmplInputs are inputs to CNI templates
@@ -81,6 +84,12 @@ type tmplInput struct {
 	PodCIDR      string
 	DefaultRoute string
 	CNIConfDir   string
+	// MTU of the pod interfaces and tunnel devices
+	MTU string
+	// Encapsulation is the mode used to carry the traffic between nodes
+	Encapsulation string
+	// Hubble enables the Cilium observability layer
+	Hubble bool
 }
 
 // New returns a new CNI manager
@@ -92,6 +101,36 @@ func New(cc *config.ClusterConfig) (Manager, error) {
 
 	klog.Infof("Creating CNI manager for %q", cc.KubernetesConfig.CNI)
 
+	cnm, err := newManager(cc)
+	if err != nil {
+		return cnm, err
+	}
+
+	if err := cnm.ValidateOptions(cc.KubernetesConfig.CNIOptions); err != nil {
+		return cnm, err
+	}
+
+	if err := configureCNI(cc, cnm); err != nil {
+		klog.Errorf("unable to set CNI Config Directory: %v", err)
+	}
+
+	return cnm, nil
+}
+
+// ValidateOptions checks that the CNI chosen for the cluster supports the options set with --cni-opt
+func ValidateOptions(cc *config.ClusterConfig) error {
+	if len(cc.KubernetesConfig.CNIOptions) == 0 {
+		return nil
+	}
+	cnm, err := newManager(cc)
+	if err != nil {
+		return err
+	}
+	return cnm.ValidateOptions(cc.KubernetesConfig.CNIOptions)
+}
+
+// newManager returns the CNI manager chosen for the cluster
+func newManager(cc *config.ClusterConfig) (Manager, error) {
 	var cnm Manager
 	var err error
 	switch cc.KubernetesConfig.CNI {
@@ -112,11 +151,6 @@ func New(cc *config.ClusterConfig) (Manager, error) {
 	default:
 		cnm, err = NewCustom(*cc, cc.KubernetesConfig.CNI)
 	}
-
-	if err := configureCNI(cc, cnm); err != nil {
-		klog.Errorf("unable to set CNI Config Directory: %v", err)
-	}
-
 	return cnm, err
 }
 
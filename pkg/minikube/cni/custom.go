@@ -67,5 +67,11 @@ func (c Custom) Apply(r Runner) error {
 
 // CIDR returns the default CIDR used by this CNI
 func (c Custom) CIDR() string {
-	return DefaultPodCIDR
+	return podCIDR(c.cc)
+}
+
+// ValidateOptions checks the options set with --cni-opt. The manifest is applied as is,
+// so only the pod CIDR passed to Kubernetes can be set, which has to match the manifest.
+func (c Custom) ValidateOptions(opts map[string]string) error {
+	return validateOptions(c, map[string]validator{OptPodCIDR: validateCIDR}, opts)
 }
@@ -50,3 +50,8 @@ func (c Disabled) CIDR() string {
 	// Even without any CNI we want our nodes to have spec.PodCIDR set.
 	return DefaultPodCIDR
 }
+
+// ValidateOptions rejects all options, as there is no CNI to configure
+func (c Disabled) ValidateOptions(opts map[string]string) error {
+	return validateOptions(c, nil, opts)
+}
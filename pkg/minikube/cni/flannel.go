@@ -17,17 +17,20 @@ limitations under the License.
 package cni
 
 import (
+	"bytes"
 	"os/exec"
 	"path/filepath"
+	"text/template"
 
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 )
 
 // From https://raw.githubusercontent.com/coreos/flannel/master/Documentation/kube-flannel.yml
-var flannelTmpl = `---
+var flannelTmpl = template.Must(template.New("flannel").Parse(`---
 apiVersion: policy/v1beta1
 kind: PodSecurityPolicy
 metadata:
@@ -154,9 +157,9 @@ data:
     }
   net-conf.json: |
     {
-      "Network": "10.244.0.0/16",
+      "Network": "{{ .PodCIDR }}",
       "Backend": {
-        "Type": "vxlan"
+        "Type": "{{ .Encapsulation }}"
       }
     }
 ---
@@ -629,7 +632,7 @@ spec:
         - name: flannel-cfg
           configMap:
             name: kube-flannel-cfg
-`
+`))
 
 // Flannel is the Flannel CNI manager
 type Flannel struct {
@@ -664,10 +667,36 @@ func (c Flannel) Apply(r Runner) error {
 		}
 	}
 
-	return applyManifest(c.cc, r, manifestAsset([]byte(flannelTmpl)))
+	m, err := c.manifest()
+	if err != nil {
+		return errors.Wrap(err, "manifest")
+	}
+	return applyManifest(c.cc, r, m)
+}
+
+// manifest returns a Kubernetes manifest for a CNI
+func (c Flannel) manifest() (assets.CopyableFile, error) {
+	input := &tmplInput{
+		PodCIDR:       podCIDR(c.cc),
+		Encapsulation: optionOrDefault(c.cc, OptEncapsulation, "vxlan"),
+	}
+
+	b := bytes.Buffer{}
+	if err := flannelTmpl.Execute(&b, input); err != nil {
+		return nil, err
+	}
+	return manifestAsset(b.Bytes()), nil
 }
 
 // CIDR returns the default CIDR used by this CNI
 func (c Flannel) CIDR() string {
-	return DefaultPodCIDR
+	return podCIDR(c.cc)
+}
+
+// ValidateOptions checks the options set with --cni-opt, the encapsulation is the flannel backend type
+func (c Flannel) ValidateOptions(opts map[string]string) error {
+	return validateOptions(c, map[string]validator{
+		OptPodCIDR:       validateCIDR,
+		OptEncapsulation: oneOf("vxlan", "host-gw"),
+	}, opts)
 }
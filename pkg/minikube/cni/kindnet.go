@@ -164,7 +164,7 @@ func (c KindNet) String() string {
 func (c KindNet) manifest() (assets.CopyableFile, error) {
 	input := &tmplInput{
 		DefaultRoute: "0.0.0.0/0", // assumes IPv4
-		PodCIDR:      DefaultPodCIDR,
+		PodCIDR:      podCIDR(c.cc),
 		ImageName:    images.KindNet(c.cc.KubernetesConfig.ImageRepository),
 		CNIConfDir:   ConfDir,
 	}
@@ -193,5 +193,10 @@ func (c KindNet) Apply(r Runner) error {
 
 // CIDR returns the default CIDR used by this CNI
 func (c KindNet) CIDR() string {
-	return DefaultPodCIDR
+	return podCIDR(c.cc)
+}
+
+// ValidateOptions checks the options set with --cni-opt
+func (c KindNet) ValidateOptions(opts map[string]string) error {
+	return validateOptions(c, map[string]validator{OptPodCIDR: validateCIDR}, opts)
 }
@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cni
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// Names of the options which can be set with --cni-opt
+const (
+	// OptPodCIDR is the CIDR pod IPs are allocated from
+	OptPodCIDR = "pod-cidr"
+	// OptMTU is the MTU of the pod interfaces
+	OptMTU = "mtu"
+	// OptEncapsulation is the encapsulation mode of the traffic between nodes
+	OptEncapsulation = "encapsulation"
+	// OptHubble enables Hubble, the observability layer of Cilium
+	OptHubble = "hubble"
+)
+
+// validator checks the value of an option supported by a CNI manager
+type validator func(string) error
+
+// oneOf returns a validator only accepting the given values
+func oneOf(values ...string) validator {
+	return func(v string) error {
+		for _, value := range values {
+			if v == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of: %s", strings.Join(values, ", "))
+	}
+}
+
+// ParseOptions parses the options set with --cni-opt, formatted as key=value
+func ParseOptions(opts []string) (map[string]string, error) {
+	if len(opts) == 0 {
+		return nil, nil
+	}
+	parsed := map[string]string{}
+	for _, o := range opts {
+		kv := strings.SplitN(o, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid CNI option %q: must be formatted as key=value", o)
+		}
+		parsed[kv[0]] = kv[1]
+	}
+	return parsed, nil
+}
+
+// FormatOptions formats the options as accepted by --cni-opt, sorted by key
+func FormatOptions(opts map[string]string) []string {
+	var formatted []string
+	for k, v := range opts {
+		formatted = append(formatted, k+"="+v)
+	}
+	sort.Strings(formatted)
+	return formatted
+}
+
+// validateOptions checks the options against the options supported by a CNI manager
+func validateOptions(cnm Manager, supported map[string]validator, opts map[string]string) error {
+	var keys []string
+	for k := range opts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		validate, ok := supported[k]
+		if !ok {
+			if len(supported) == 0 {
+				return fmt.Errorf("unknown CNI option %q: %s does not support any option", k, cnm)
+			}
+			var valid []string
+			for name := range supported {
+				valid = append(valid, name)
+			}
+			sort.Strings(valid)
+			return fmt.Errorf("unknown CNI option %q for %s, valid options: %s", k, cnm, strings.Join(valid, ", "))
+		}
+		if err := validate(opts[k]); err != nil {
+			return errors.Wrapf(err, "invalid value %q for CNI option %q", opts[k], k)
+		}
+	}
+	return nil
+}
+
+// optionOrDefault returns the value of a CNI option, or def if it is not set
+func optionOrDefault(cc config.ClusterConfig, key string, def string) string {
+	if v, ok := cc.KubernetesConfig.CNIOptions[key]; ok && v != "" {
+		return v
+	}
+	return def
+}
+
+// podCIDR returns the CIDR pod IPs are allocated from
+func podCIDR(cc config.ClusterConfig) string {
+	return optionOrDefault(cc, OptPodCIDR, DefaultPodCIDR)
+}
+
+func validateCIDR(v string) error {
+	ip, _, err := net.ParseCIDR(v)
+	if err != nil {
+		return err
+	}
+	if ip.To4() == nil {
+		return fmt.Errorf("only IPv4 CIDRs are supported")
+	}
+	return nil
+}
+
+func validateMTU(v string) error {
+	mtu, err := strconv.Atoi(v)
+	if err != nil {
+		return err
+	}
+	// 1280 is the minimum MTU of IPv6, 9000 is the usual jumbo frame size
+	if mtu < 1280 || mtu > 9000 {
+		return fmt.Errorf("must be between 1280 and 9000")
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cni
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestParseOptions(t *testing.T) {
+	opts, err := ParseOptions([]string{"mtu=1400", "pod-cidr=10.100.0.0/16", "empty="})
+	if err != nil {
+		t.Fatalf("ParseOptions() failed: %v", err)
+	}
+	if opts["mtu"] != "1400" || opts["pod-cidr"] != "10.100.0.0/16" || len(opts) != 3 {
+		t.Errorf("ParseOptions() = %v; unexpected options", opts)
+	}
+	if got := strings.Join(FormatOptions(opts), ","); got != "empty=,mtu=1400,pod-cidr=10.100.0.0/16" {
+		t.Errorf("FormatOptions() = %q; want the options sorted by key", got)
+	}
+
+	for _, o := range []string{"mtu", "=1400"} {
+		if _, err := ParseOptions([]string{o}); err == nil {
+			t.Errorf("ParseOptions(%q) succeeded; want error", o)
+		}
+	}
+}
+
+func TestValidateOptions(t *testing.T) {
+	tests := []struct {
+		description string
+		cni         string
+		opts        map[string]string
+		wantErr     string
+	}{
+		{"no options", "calico", nil, ""},
+		{"calico", "calico", map[string]string{"pod-cidr": "10.100.0.0/16", "mtu": "1410", "encapsulation": "vxlan"}, ""},
+		{"cilium hubble", "cilium", map[string]string{"hubble": "true", "encapsulation": "geneve"}, ""},
+		{"flannel host-gw", "flannel", map[string]string{"encapsulation": "host-gw"}, ""},
+		{"unknown key", "calico", map[string]string{"hubble": "true"}, `unknown CNI option "hubble" for Calico, valid options: encapsulation, mtu, pod-cidr`},
+		{"disabled", "false", map[string]string{"mtu": "1400"}, "does not support any option"},
+		{"invalid cidr", "bridge", map[string]string{"pod-cidr": "10.100.0.0"}, `invalid value "10.100.0.0" for CNI option "pod-cidr"`},
+		{"ipv6 cidr", "kindnet", map[string]string{"pod-cidr": "fd00::/64"}, "only IPv4"},
+		{"invalid mtu", "cilium", map[string]string{"mtu": "100"}, "between 1280 and 9000"},
+		{"invalid encapsulation", "calico", map[string]string{"encapsulation": "geneve"}, "must be one of: ipip, vxlan, none"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			cc := &config.ClusterConfig{KubernetesConfig: config.KubernetesConfig{CNI: tc.cni, CNIOptions: tc.opts}}
+			_, err := New(cc)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("New() failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("New() error = %v; want error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// render returns the content of a manifest, after checking that it is valid YAML
+func render(t *testing.T, manifest func() (assets.CopyableFile, error)) string {
+	t.Helper()
+	f, err := manifest()
+	if err != nil {
+		t.Fatalf("unable to render manifest: %v", err)
+	}
+	b := &bytes.Buffer{}
+	if _, err := io.Copy(b, f); err != nil {
+		t.Fatal(err)
+	}
+	d := yaml.NewDecoder(bytes.NewReader(b.Bytes()))
+	for {
+		var doc interface{}
+		err := d.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("rendered manifest is not valid YAML: %v", err)
+		}
+	}
+	return b.String()
+}
+
+func TestManifestOptions(t *testing.T) {
+	opts := map[string]string{"pod-cidr": "10.100.0.0/16", "mtu": "1400", "encapsulation": "vxlan"}
+	cc := config.ClusterConfig{KubernetesConfig: config.KubernetesConfig{CNIOptions: opts}}
+
+	m := render(t, Calico{cc: cc}.manifest)
+	for _, want := range []string{`veth_mtu: "1400"`, `calico_backend: "vxlan"`, `value: "10.100.0.0/16"`} {
+		if !strings.Contains(m, want) {
+			t.Errorf("calico manifest does not contain %q", want)
+		}
+	}
+	if strings.Contains(m, "-bird-live") {
+		t.Errorf("calico manifest checks bird liveness with the vxlan backend")
+	}
+	if m := render(t, Calico{}.manifest); !strings.Contains(m, "-bird-live") || !strings.Contains(m, `veth_mtu: "1440"`) {
+		t.Errorf("calico manifest does not use the defaults without options")
+	}
+
+	cc.KubernetesConfig.CNIOptions = map[string]string{"pod-cidr": "10.100.0.0/16", "hubble": "true", "encapsulation": "geneve"}
+	m = render(t, Cilium{cc: cc}.manifest)
+	for _, want := range []string{`cluster-pool-ipv4-cidr: "10.100.0.0/16"`, `enable-hubble: "true"`, "tunnel: geneve"} {
+		if !strings.Contains(m, want) {
+			t.Errorf("cilium manifest does not contain %q", want)
+		}
+	}
+
+	cc.KubernetesConfig.CNIOptions = map[string]string{"pod-cidr": "10.100.0.0/16", "encapsulation": "host-gw"}
+	m = render(t, Flannel{cc: cc}.manifest)
+	for _, want := range []string{`"Network": "10.100.0.0/16"`, `"Type": "host-gw"`} {
+		if !strings.Contains(m, want) {
+			t.Errorf("flannel manifest does not contain %q", want)
+		}
+	}
+	if got := (Flannel{cc: cc}).CIDR(); got != "10.100.0.0/16" {
+		t.Errorf("CIDR() = %q; want the pod-cidr option", got)
+	}
+}
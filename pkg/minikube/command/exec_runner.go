@@ -184,6 +184,36 @@ func (e *execRunner) Copy(f assets.CopyableFile) error {
 	return writeFile(dst, f, os.FileMode(perms))
 }
 
+// CopyFrom copies a file
+func (e *execRunner) CopyFrom(f assets.WriteableFile) error {
+	src := path.Join(f.GetTargetDir(), f.GetTargetName())
+	dst := f.GetSourcePath()
+	klog.Infof("cp: %s --> %s", src, dst)
+
+	if e.sudo {
+		// the file may not be readable by the current user
+		cmd := exec.Command("sudo", "cat", src)
+		cmd.Stdout = f
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return errors.Wrapf(err, "error copying %s to %s: %s", src, dst, stderr.String())
+		}
+		return nil
+	}
+
+	r, err := os.Open(src)
+	if err != nil {
+		return errors.Wrapf(err, "error opening file %s", src)
+	}
+	defer r.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return errors.Wrapf(err, "error copying %s to %s", src, dst)
+	}
+	return nil
+}
+
 // Remove removes a file
 func (e *execRunner) Remove(f assets.CopyableFile) error {
 	dst := filepath.Join(f.GetTargetDir(), f.GetTargetName())
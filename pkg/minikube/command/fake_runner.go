@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"os/exec"
+	"path"
 	"strings"
 	"time"
 
@@ -142,6 +143,19 @@ func (f *FakeCommandRunner) Copy(file assets.CopyableFile) error {
 	return nil
 }
 
+// CopyFrom writes the stored contents of the target file to the file.
+func (f *FakeCommandRunner) CopyFrom(file assets.WriteableFile) error {
+	src := path.Join(file.GetTargetDir(), file.GetTargetName())
+	v, ok := f.fileMap.Load(src)
+	if !ok {
+		return fmt.Errorf("unknown file %s", src)
+	}
+	if _, err := io.WriteString(file, v.(string)); err != nil {
+		return errors.Wrapf(err, "error writing file: %+v", file)
+	}
+	return nil
+}
+
 // Remove removes the filename, file contents key value pair from the stored map
 func (f *FakeCommandRunner) Remove(file assets.CopyableFile) error {
 	f.fileMap.Delete(file.GetSourcePath())
@@ -17,7 +17,9 @@ limitations under the License.
 package command
 
 import (
+	"io/ioutil"
 	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"k8s.io/minikube/pkg/minikube/assets"
@@ -75,6 +77,33 @@ func TestFakeRunnerFile(t *testing.T) {
 		}
 	})
 
+	t.Run("CopyFromFile", func(t *testing.T) {
+		expectedFileContents := "remote contents"
+		fakeCommandRunner.SetFileToContents(map[string]string{"/remote/file": expectedFileContents})
+
+		dst := filepath.Join(t.TempDir(), "file")
+		file, err := assets.NewWriteableAsset(dst, "/remote", "file", "0644")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := fakeCommandRunner.CopyFrom(file); err != nil {
+			t.Fatal(err)
+		}
+		if err := file.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		retrievedFileContents, err := ioutil.ReadFile(dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if expectedFileContents != string(retrievedFileContents) {
+			t.Errorf("expected %q, retrieved %q", expectedFileContents, retrievedFileContents)
+		}
+	})
+
 	t.Run("RunCmd", func(t *testing.T) {
 		expectedOutput := "123"
 		command := &exec.Cmd{Args: []string{cmdArg}}
@@ -204,6 +204,23 @@ func (k *kicRunner) Copy(f assets.CopyableFile) error {
 	return k.copy(tf.Name(), dst)
 }
 
+// CopyFrom copies a file back from the container
+func (k *kicRunner) CopyFrom(f assets.WriteableFile) error {
+	src := path.Join(f.GetTargetDir(), f.GetTargetName())
+	dst := f.GetSourcePath()
+	klog.Infof("%s (cat): %s --> %s", k.ociBin, src, dst)
+
+	// stream the file through exec, as podman cp does not support copying to stdout
+	cmd := oci.PrefixCmd(exec.Command(k.ociBin, "exec", k.nameOrID, "sudo", "cat", src))
+	cmd.Stdout = f
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, "%s copy %s from %s, output: %s", k.ociBin, src, k.nameOrID, stderr.String())
+	}
+	return nil
+}
+
 // tempDirectory returns the directory to use as the temp directory
 // or an empty string if it should use the os default temp directory.
 func tempDirectory(isMinikubeSnap bool, isDockerSnap bool) (string, error) {
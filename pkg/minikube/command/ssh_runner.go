@@ -141,10 +141,6 @@ func teeSSH(s *ssh.Session, cmd string, outB io.Writer, errB io.Writer) error {
 
 // RunCmd implements the Command Runner interface to run a exec.Cmd object
 func (s *SSHRunner) RunCmd(cmd *exec.Cmd) (*RunResult, error) {
-	if cmd.Stdin != nil {
-		return nil, fmt.Errorf("SSHRunner does not support stdin - you could be the first to add it")
-	}
-
 	rr := &RunResult{Args: cmd.Args}
 	klog.Infof("Run: %v", rr.Command())
 
@@ -178,12 +174,31 @@ func (s *SSHRunner) RunCmd(cmd *exec.Cmd) (*RunResult, error) {
 		}
 	}()
 
+	if cmd.Stdin != nil {
+		w, err := sess.StdinPipe()
+		if err != nil {
+			return rr, errors.Wrap(err, "StdinPipe")
+		}
+		// not waited for, as the session would wait for the end of stdin even after the command exited
+		go func() {
+			if _, err := io.Copy(w, cmd.Stdin); err != nil && err != io.EOF {
+				klog.Warningf("copy stdin: %v", err)
+			}
+			if err := w.Close(); err != nil && err != io.EOF {
+				klog.Warningf("close stdin: %v", err)
+			}
+		}()
+	}
+
 	err = teeSSH(sess, shellquote.Join(cmd.Args...), outb, errb)
 	elapsed := time.Since(start)
 
 	if exitError, ok := err.(*exec.ExitError); ok {
 		rr.ExitCode = exitError.ExitCode()
 	}
+	if exitError, ok := err.(*ssh.ExitError); ok {
+		rr.ExitCode = exitError.ExitStatus()
+	}
 	// Decrease log spam
 	if elapsed > (1 * time.Second) {
 		klog.Infof("Completed: %s: (%s)", rr.Command(), elapsed)
@@ -279,6 +294,9 @@ func (s *SSHRunner) WaitCmd(sc *StartedCmd) (*RunResult, error) {
 	if exitError, ok := err.(*exec.ExitError); ok {
 		rr.ExitCode = exitError.ExitCode()
 	}
+	if exitError, ok := err.(*ssh.ExitError); ok {
+		rr.ExitCode = exitError.ExitStatus()
+	}
 
 	sc.wg.Wait()
 
@@ -373,3 +391,30 @@ func (s *SSHRunner) Copy(f assets.CopyableFile) error {
 	}
 	return g.Wait()
 }
+
+// CopyFrom copies a file from the remote over SSH.
+func (s *SSHRunner) CopyFrom(f assets.WriteableFile) error {
+	src := path.Join(f.GetTargetDir(), f.GetTargetName())
+	klog.Infof("scp %s --> %s", src, f.GetSourcePath())
+
+	sess, err := s.session()
+	if err != nil {
+		return errors.Wrap(err, "NewSession")
+	}
+	defer func() {
+		if err := sess.Close(); err != nil {
+			if err != io.EOF {
+				klog.Errorf("session close: %v", err)
+			}
+		}
+	}()
+
+	var stderr bytes.Buffer
+	sess.Stdout = f
+	sess.Stderr = &stderr
+	cmd := shellquote.Join("sudo", "cat", src)
+	if err := sess.Run(cmd); err != nil {
+		return fmt.Errorf("%s: %s\noutput: %s", cmd, err, stderr.String())
+	}
+	return nil
+}
@@ -0,0 +1,425 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// ClusterFileAPIVersion is the version of the cluster definition file format
+	ClusterFileAPIVersion = "minikube.sigs.k8s.io/v1alpha1"
+	// ClusterFileKind is the kind of the cluster definition document
+	ClusterFileKind = "Cluster"
+
+	// RoleControlPlane is the role of nodes running the Kubernetes control plane, which also run workloads
+	RoleControlPlane = "control-plane"
+	// RoleWorker is the role of nodes only running workloads
+	RoleWorker = "worker"
+)
+
+// validNodeName matches the node names allowed in a cluster definition file
+var validNodeName = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// ClusterFile is a versioned, declarative definition of a cluster, used by "minikube start --config" and "minikube profile export"
+type ClusterFile struct {
+	APIVersion string              `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string              `json:"kind" yaml:"kind"`
+	Metadata   ClusterFileMetadata `json:"metadata" yaml:"metadata"`
+	Spec       ClusterSpec         `json:"spec" yaml:"spec"`
+}
+
+// ClusterFileMetadata identifies the cluster
+type ClusterFileMetadata struct {
+	// Name is the name of the profile
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+// ClusterSpec describes the cluster, empty fields keep the default of the matching "minikube start" flag
+type ClusterSpec struct {
+	Driver            string `json:"driver,omitempty" yaml:"driver,omitempty"`
+	KubernetesVersion string `json:"kubernetesVersion,omitempty" yaml:"kubernetesVersion,omitempty"`
+	ContainerRuntime  string `json:"containerRuntime,omitempty" yaml:"containerRuntime,omitempty"`
+	CNI               string `json:"cni,omitempty" yaml:"cni,omitempty"`
+	// CNIOptions are the options of the CNI, as the --cni-opt flag
+	CNIOptions         map[string]string `json:"cniOptions,omitempty" yaml:"cniOptions,omitempty"`
+	FeatureGates       string            `json:"featureGates,omitempty" yaml:"featureGates,omitempty"`
+	ImageRepository    string            `json:"imageRepository,omitempty" yaml:"imageRepository,omitempty"`
+	RegistryMirrors    []string          `json:"registryMirrors,omitempty" yaml:"registryMirrors,omitempty"`
+	InsecureRegistries []string          `json:"insecureRegistries,omitempty" yaml:"insecureRegistries,omitempty"`
+	RegistryCache      bool              `json:"registryCache,omitempty" yaml:"registryCache,omitempty"`
+	Resources          ResourcesSpec     `json:"resources,omitempty" yaml:"resources,omitempty"`
+	Nodes              []NodeSpec        `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+	Addons             []AddonSpec       `json:"addons,omitempty" yaml:"addons,omitempty"`
+	// ExtraOptions are formatted as component.key=value, as the --extra-config flag
+	ExtraOptions []string    `json:"extraOptions,omitempty" yaml:"extraOptions,omitempty"`
+	Mounts       []MountSpec `json:"mounts,omitempty" yaml:"mounts,omitempty"`
+	// Network is the docker or podman network of the nodes, as the --network flag
+	Network string `json:"network,omitempty" yaml:"network,omitempty"`
+	// Subnet is the subnet of the network created for the cluster, as the --subnet flag
+	Subnet string `json:"subnet,omitempty" yaml:"subnet,omitempty"`
+	// ExtraNetworks are existing networks the nodes are attached to, as the --extra-network flag
+	ExtraNetworks []string `json:"extraNetworks,omitempty" yaml:"extraNetworks,omitempty"`
+}
+
+// ResourcesSpec are the resources of each node, sizes are formatted as <number>[<unit>], where unit = b, k, m or g
+type ResourcesSpec struct {
+	CPUs   int    `json:"cpus,omitempty" yaml:"cpus,omitempty"`
+	Memory string `json:"memory,omitempty" yaml:"memory,omitempty"`
+	Disk   string `json:"disk,omitempty" yaml:"disk,omitempty"`
+}
+
+// validate checks the resources, prefix is the path of the resources in the cluster file
+func (r ResourcesSpec) validate(prefix string) error {
+	if r.CPUs < 0 {
+		return fmt.Errorf("invalid %s.cpus %d", prefix, r.CPUs)
+	}
+	for field, size := range map[string]string{"memory": r.Memory, "disk": r.Disk} {
+		if size == "" {
+			continue
+		}
+		if _, err := util.CalculateSizeInMB(size); err != nil {
+			return fmt.Errorf("invalid %s.%s %q: %v", prefix, field, size, err)
+		}
+	}
+	return nil
+}
+
+// Apply sets the resources of node n to the ones defined, which have been validated. Unset ones are kept.
+func (r *ResourcesSpec) Apply(n *Node) {
+	if r == nil {
+		return
+	}
+	if r.CPUs > 0 {
+		n.CPUs = r.CPUs
+	}
+	if r.Memory != "" {
+		n.Memory, _ = util.CalculateSizeInMB(r.Memory)
+	}
+	if r.Disk != "" {
+		n.DiskSize, _ = util.CalculateSizeInMB(r.Disk)
+	}
+}
+
+// nodeResourcesSpec returns the resources of node n which override the ones of the cluster, nil if there are none
+func nodeResourcesSpec(n Node) *ResourcesSpec {
+	if n.CPUs == 0 && n.Memory == 0 && n.DiskSize == 0 {
+		return nil
+	}
+	r := &ResourcesSpec{CPUs: n.CPUs}
+	if n.Memory > 0 {
+		r.Memory = strconv.Itoa(n.Memory) + "mb"
+	}
+	if n.DiskSize > 0 {
+		r.Disk = strconv.Itoa(n.DiskSize) + "mb"
+	}
+	return r
+}
+
+// NodeSpec describes a node, the first node is the primary control plane. Additional control-plane nodes
+// make a HA cluster, as "minikube start --ha".
+type NodeSpec struct {
+	// Name is the name of the node, generated if empty. The primary control plane is named after the profile.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Role is either control-plane or worker
+	Role string `json:"role" yaml:"role"`
+	// StaticIP is the address of the node on the network of the cluster, docker and podman drivers only
+	StaticIP string `json:"staticIP,omitempty" yaml:"staticIP,omitempty"`
+	// Labels are the Kubernetes labels of the node, as the --node-labels flag
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// Taints are the Kubernetes taints of the node formatted as key[=value]:effect, as the --node-taints flag
+	Taints []string `json:"taints,omitempty" yaml:"taints,omitempty"`
+	// Resources override the ones of the cluster for this node, docker, podman and kvm2 drivers only
+	Resources *ResourcesSpec `json:"resources,omitempty" yaml:"resources,omitempty"`
+}
+
+// AddonSpec describes an addon, along with its custom images and registries
+type AddonSpec struct {
+	Name string `json:"name" yaml:"name"`
+	// Enabled defaults to true
+	Enabled *bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// Images maps the image names of the addon to the images to use, as "minikube addons enable --images"
+	Images map[string]string `json:"images,omitempty" yaml:"images,omitempty"`
+	// Registries maps the image names of the addon to the registries to use, as "minikube addons enable --registries"
+	Registries map[string]string `json:"registries,omitempty" yaml:"registries,omitempty"`
+}
+
+// IsEnabled returns whether the addon is enabled
+func (a AddonSpec) IsEnabled() bool {
+	return a.Enabled == nil || *a.Enabled
+}
+
+// AddonImages returns the custom images and registries of the enabled addons, as stored in the cluster config
+func (cf *ClusterFile) AddonImages() (images map[string]string, registries map[string]string) {
+	for _, a := range cf.Spec.Addons {
+		if !a.IsEnabled() {
+			continue
+		}
+		for name, image := range a.Images {
+			if images == nil {
+				images = map[string]string{}
+			}
+			images[name] = image
+		}
+		for name, registry := range a.Registries {
+			if registries == nil {
+				registries = map[string]string{}
+			}
+			registries[name] = registry
+		}
+	}
+	return images, registries
+}
+
+// MountSpec describes a host directory mounted into the nodes
+type MountSpec struct {
+	Source string `json:"source" yaml:"source"`
+	Target string `json:"target" yaml:"target"`
+}
+
+// String returns the mount formatted as the --mount-string flag
+func (m MountSpec) String() string {
+	return fmt.Sprintf("%s:%s", m.Source, m.Target)
+}
+
+// ReadClusterFile reads and validates a cluster definition file, in YAML or JSON format
+func ReadClusterFile(file string) (*ClusterFile, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading cluster file")
+	}
+	cf, err := ParseClusterFile(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid cluster file %s", file)
+	}
+	return cf, nil
+}
+
+// ParseClusterFile parses and validates a cluster definition document, in YAML or JSON format
+func ParseClusterFile(b []byte) (*ClusterFile, error) {
+	cf := &ClusterFile{}
+	// JSON documents are valid YAML, unknown fields are rejected so that typos are not silently ignored
+	if err := yaml.UnmarshalStrict(bytes.TrimSpace(b), cf); err != nil {
+		return nil, err
+	}
+	if err := cf.Validate(); err != nil {
+		return nil, err
+	}
+	return cf, nil
+}
+
+// Validate checks the cluster definition against its schema
+func (cf *ClusterFile) Validate() error {
+	if cf.APIVersion != ClusterFileAPIVersion {
+		return fmt.Errorf("unsupported apiVersion %q, expected %q", cf.APIVersion, ClusterFileAPIVersion)
+	}
+	if cf.Kind != ClusterFileKind {
+		return fmt.Errorf("unsupported kind %q, expected %q", cf.Kind, ClusterFileKind)
+	}
+	if cf.Metadata.Name != "" && !ProfileNameValid(cf.Metadata.Name) {
+		return fmt.Errorf("invalid metadata.name %q: only alphanumeric characters and dashes are permitted", cf.Metadata.Name)
+	}
+
+	s := cf.Spec
+	if err := s.Resources.validate("resources"); err != nil {
+		return err
+	}
+
+	for k := range s.CNIOptions {
+		if k == "" {
+			return fmt.Errorf("cniOptions keys must not be empty")
+		}
+	}
+
+	var subnet *net.IPNet
+	if s.Subnet != "" {
+		_, ipnet, err := net.ParseCIDR(s.Subnet)
+		if err != nil || ipnet.IP.To4() == nil {
+			return fmt.Errorf("invalid subnet %q, expected an IPv4 CIDR", s.Subnet)
+		}
+		subnet = ipnet
+	}
+	if err := validateNodeSpecs(s.Nodes, subnet); err != nil {
+		return err
+	}
+
+	addons := map[string]bool{}
+	for i, a := range s.Addons {
+		if a.Name == "" {
+			return fmt.Errorf("addons[%d].name is required", i)
+		}
+		if addons[a.Name] {
+			return fmt.Errorf("addon %q is defined more than once", a.Name)
+		}
+		addons[a.Name] = true
+	}
+
+	var eo ExtraOptionSlice
+	for _, o := range s.ExtraOptions {
+		if err := eo.Set(o); err != nil {
+			return fmt.Errorf("invalid extraOptions entry %q: %v", o, err)
+		}
+	}
+
+	if len(s.Mounts) > 1 {
+		return fmt.Errorf("only a single mount is supported, got %d", len(s.Mounts))
+	}
+	for i, m := range s.Mounts {
+		if m.Source == "" || m.Target == "" {
+			return fmt.Errorf("mounts[%d] requires a source and a target", i)
+		}
+		if !path.IsAbs(m.Target) {
+			return fmt.Errorf("mounts[%d].target %q must be an absolute path", i, m.Target)
+		}
+	}
+	return nil
+}
+
+// validateNodeSpecs checks the roles, names, static IPs and resources of the nodes
+func validateNodeSpecs(nodes []NodeSpec, subnet *net.IPNet) error {
+	names := map[string]bool{}
+	ips := map[string]bool{}
+	for i, n := range nodes {
+		if n.StaticIP != "" {
+			ip := net.ParseIP(n.StaticIP).To4()
+			if ip == nil {
+				return fmt.Errorf("invalid nodes[%d].staticIP %q, expected an IPv4 address", i, n.StaticIP)
+			}
+			if subnet != nil && !subnet.Contains(ip) {
+				return fmt.Errorf("nodes[%d].staticIP %s is not in the subnet %s", i, n.StaticIP, subnet)
+			}
+			if ips[ip.String()] {
+				return fmt.Errorf("static IP %s is used by more than one node", n.StaticIP)
+			}
+			ips[ip.String()] = true
+		}
+		switch n.Role {
+		case RoleControlPlane, RoleWorker:
+		default:
+			return fmt.Errorf("invalid nodes[%d].role %q, expected %q or %q", i, n.Role, RoleControlPlane, RoleWorker)
+		}
+		if err := ValidateNodeLabels(n.Labels); err != nil {
+			return fmt.Errorf("nodes[%d].labels: %v", i, err)
+		}
+		if err := ValidateNodeTaints(n.Taints); err != nil {
+			return fmt.Errorf("nodes[%d].taints: %v", i, err)
+		}
+		if n.Resources != nil {
+			if err := n.Resources.validate(fmt.Sprintf("nodes[%d].resources", i)); err != nil {
+				return err
+			}
+		}
+		if n.Name == "" {
+			continue
+		}
+		if !validNodeName.MatchString(n.Name) {
+			return fmt.Errorf("invalid nodes[%d].name %q", i, n.Name)
+		}
+		if names[n.Name] {
+			return fmt.Errorf("node %q is defined more than once", n.Name)
+		}
+		names[n.Name] = true
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	if nodes[0].Role != RoleControlPlane {
+		return fmt.Errorf("the first node must have the %s role", RoleControlPlane)
+	}
+	return nil
+}
+
+// ControlPlanes returns the number of control-plane nodes defined, more than one makes a HA cluster
+func (s ClusterSpec) ControlPlanes() int {
+	n := 0
+	for _, ns := range s.Nodes {
+		if ns.Role == RoleControlPlane {
+			n++
+		}
+	}
+	return n
+}
+
+// MarshalClusterFile encodes a cluster definition as YAML
+func MarshalClusterFile(cf *ClusterFile) ([]byte, error) {
+	return yaml.Marshal(cf)
+}
+
+// NewClusterFile returns the definition of an existing cluster. The addon images are left to the caller,
+// as they are grouped by addon.
+func NewClusterFile(cc *ClusterConfig) *ClusterFile {
+	k := cc.KubernetesConfig
+	cf := &ClusterFile{
+		APIVersion: ClusterFileAPIVersion,
+		Kind:       ClusterFileKind,
+		Metadata:   ClusterFileMetadata{Name: cc.Name},
+		Spec: ClusterSpec{
+			Driver:             cc.Driver,
+			KubernetesVersion:  k.KubernetesVersion,
+			ContainerRuntime:   k.ContainerRuntime,
+			CNI:                k.CNI,
+			CNIOptions:         k.CNIOptions,
+			FeatureGates:       k.FeatureGates,
+			ImageRepository:    k.ImageRepository,
+			RegistryMirrors:    cc.RegistryMirror,
+			InsecureRegistries: cc.InsecureRegistry,
+			RegistryCache:      cc.RegistryCache,
+			Resources: ResourcesSpec{
+				CPUs: cc.CPUs,
+			},
+			Network:       cc.Network,
+			Subnet:        cc.Subnet,
+			ExtraNetworks: cc.ExtraNetworks,
+		},
+	}
+	if cc.Memory > 0 {
+		cf.Spec.Resources.Memory = strconv.Itoa(cc.Memory) + "mb"
+	}
+	if cc.DiskSize > 0 {
+		cf.Spec.Resources.Disk = strconv.Itoa(cc.DiskSize) + "mb"
+	}
+
+	for _, n := range cc.Nodes {
+		role := RoleWorker
+		if n.ControlPlane {
+			role = RoleControlPlane
+		}
+		cf.Spec.Nodes = append(cf.Spec.Nodes, NodeSpec{Name: n.Name, Role: role, StaticIP: n.StaticIP, Labels: n.Labels, Taints: n.Taints, Resources: nodeResourcesSpec(n)})
+	}
+
+	for _, eo := range k.ExtraOptions {
+		cf.Spec.ExtraOptions = append(cf.Spec.ExtraOptions, eo.String())
+	}
+
+	if cc.Mount {
+		if i := strings.LastIndex(cc.MountString, ":"); i > 0 {
+			cf.Spec.Mounts = []MountSpec{{Source: cc.MountString[:i], Target: cc.MountString[i+1:]}}
+		}
+	}
+	return cf
+}
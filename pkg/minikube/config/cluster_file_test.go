@@ -0,0 +1,215 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const clusterFileYAML = `
+apiVersion: minikube.sigs.k8s.io/v1alpha1
+kind: Cluster
+metadata:
+  name: team
+spec:
+  driver: docker
+  kubernetesVersion: v1.20.2
+  containerRuntime: containerd
+  cni: calico
+  cniOptions:
+    mtu: "1400"
+  registryMirrors:
+  - https://mirror.example.com
+  resources:
+    cpus: 4
+    memory: 4g
+    disk: 30000mb
+  nodes:
+  - role: control-plane
+    staticIP: 192.168.60.10
+  - name: worker-a
+    role: worker
+    resources:
+      cpus: 8
+      memory: 16g
+  subnet: 192.168.60.0/24
+  extraNetworks:
+  - mesh
+  addons:
+  - name: ingress
+    images:
+      IngressController: example.com/ingress:v1
+  - name: dashboard
+    enabled: false
+  extraOptions:
+  - kubelet.max-pods=50
+  mounts:
+  - source: /home/team/src
+    target: /src
+`
+
+const clusterFileJSON = `{
+  "apiVersion": "minikube.sigs.k8s.io/v1alpha1",
+  "kind": "Cluster",
+  "metadata": {"name": "team"},
+  "spec": {"driver": "kvm2", "nodes": [{"role": "control-plane"}, {"role": "worker"}]}
+}`
+
+func TestParseClusterFile(t *testing.T) {
+	cf, err := ParseClusterFile([]byte(clusterFileYAML))
+	if err != nil {
+		t.Fatalf("ParseClusterFile() of YAML failed: %v", err)
+	}
+	if cf.Metadata.Name != "team" || cf.Spec.Resources.CPUs != 4 || len(cf.Spec.Nodes) != 2 || cf.Spec.Nodes[1].Name != "worker-a" {
+		t.Errorf("ParseClusterFile() = %+v; unexpected content", cf)
+	}
+	if cf.Spec.CNIOptions["mtu"] != "1400" {
+		t.Errorf("cniOptions = %v; want mtu=1400", cf.Spec.CNIOptions)
+	}
+	if cf.Spec.Nodes[0].StaticIP != "192.168.60.10" || cf.Spec.Subnet != "192.168.60.0/24" || !reflect.DeepEqual(cf.Spec.ExtraNetworks, []string{"mesh"}) {
+		t.Errorf("ParseClusterFile() = %+v; unexpected networks", cf.Spec)
+	}
+	n := Node{}
+	cf.Spec.Nodes[1].Resources.Apply(&n)
+	if n.CPUs != 8 || n.Memory != 16384 || n.DiskSize != 0 {
+		t.Errorf("resources of worker-a = %d CPUs, %dMB memory, %dMB disk; want 8 CPUs, 16384MB memory", n.CPUs, n.Memory, n.DiskSize)
+	}
+	if got := cf.Spec.Mounts[0].String(); got != "/home/team/src:/src" {
+		t.Errorf("mount = %q; want %q", got, "/home/team/src:/src")
+	}
+
+	images, registries := cf.AddonImages()
+	if !reflect.DeepEqual(images, map[string]string{"IngressController": "example.com/ingress:v1"}) || registries != nil {
+		t.Errorf("AddonImages() = %v, %v; want only the ingress images", images, registries)
+	}
+
+	cf, err = ParseClusterFile([]byte(clusterFileJSON))
+	if err != nil {
+		t.Fatalf("ParseClusterFile() of JSON failed: %v", err)
+	}
+	if cf.Spec.Driver != "kvm2" || len(cf.Spec.Nodes) != 2 {
+		t.Errorf("ParseClusterFile() = %+v; unexpected content", cf)
+	}
+}
+
+func TestParseClusterFileInvalid(t *testing.T) {
+	header := "apiVersion: minikube.sigs.k8s.io/v1alpha1\nkind: Cluster\n"
+	tests := []struct {
+		description string
+		doc         string
+		want        string
+	}{
+		{"wrong apiVersion", "apiVersion: v1\nkind: Cluster\n", "unsupported apiVersion"},
+		{"wrong kind", "apiVersion: minikube.sigs.k8s.io/v1alpha1\nkind: Pod\n", "unsupported kind"},
+		{"unknown field", header + "spec:\n  drivr: docker\n", "drivr"},
+		{"invalid name", header + "metadata:\n  name: -bad\n", "metadata.name"},
+		{"invalid memory", header + "spec:\n  resources:\n    memory: lots\n", "resources.memory"},
+		{"invalid role", header + "spec:\n  nodes:\n  - role: master\n", "role"},
+		{"worker first", header + "spec:\n  nodes:\n  - role: worker\n", "first node"},
+		{"duplicate node", header + "spec:\n  nodes:\n  - role: control-plane\n    name: a\n  - role: worker\n    name: a\n", "more than once"},
+		{"duplicate addon", header + "spec:\n  addons:\n  - name: ingress\n  - name: ingress\n", "more than once"},
+		{"invalid extra option", header + "spec:\n  extraOptions:\n  - kubelet\n", "extraOptions"},
+		{"invalid subnet", header + "spec:\n  subnet: 192.168.60.0\n", "subnet"},
+		{"invalid static IP", header + "spec:\n  nodes:\n  - role: control-plane\n    staticIP: fd00::2\n", "staticIP"},
+		{"static IP out of subnet", header + "spec:\n  subnet: 192.168.60.0/24\n  nodes:\n  - role: control-plane\n    staticIP: 192.168.61.2\n", "not in the subnet"},
+		{"duplicate static IP", header + "spec:\n  nodes:\n  - role: control-plane\n    staticIP: 192.168.60.2\n  - role: worker\n    staticIP: 192.168.60.2\n", "more than one node"},
+		{"invalid label", header + "spec:\n  nodes:\n  - role: control-plane\n    labels:\n      pool: not valid\n", "labels"},
+		{"invalid taint", header + "spec:\n  nodes:\n  - role: control-plane\n    taints:\n    - dedicated=gpu\n", "taints"},
+		{"invalid node disk", header + "spec:\n  nodes:\n  - role: control-plane\n    resources:\n      disk: big\n", "nodes[0].resources.disk"},
+		{"relative mount", header + "spec:\n  mounts:\n  - source: /src\n    target: src\n", "absolute"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := ParseClusterFile([]byte(tc.doc))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("ParseClusterFile() error = %v; want error containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseClusterFileHA(t *testing.T) {
+	doc := `apiVersion: minikube.sigs.k8s.io/v1alpha1
+kind: Cluster
+spec:
+  nodes:
+  - role: control-plane
+  - role: control-plane
+  - role: control-plane
+  - role: worker
+`
+	cf, err := ParseClusterFile([]byte(doc))
+	if err != nil {
+		t.Fatalf("ParseClusterFile() failed: %v", err)
+	}
+	if got := cf.Spec.ControlPlanes(); got != 3 {
+		t.Errorf("ControlPlanes() = %d; want 3", got)
+	}
+}
+
+func TestNewClusterFile(t *testing.T) {
+	cc := &ClusterConfig{
+		Name:        "team",
+		Driver:      "docker",
+		CPUs:        2,
+		Memory:      2200,
+		DiskSize:    20000,
+		Mount:       true,
+		MountString: "/home/team/src:/src",
+		KubernetesConfig: KubernetesConfig{
+			KubernetesVersion: "v1.20.2",
+			ContainerRuntime:  "docker",
+			CNI:               "cilium",
+			CNIOptions:        map[string]string{"hubble": "true"},
+			ExtraOptions:      ExtraOptionSlice{{Component: "kubelet", Key: "max-pods", Value: "50"}},
+		},
+		Nodes: []Node{
+			{Name: "", ControlPlane: true, Worker: true},
+			{Name: "m02", Worker: true, Labels: map[string]string{"pool": "gpu"}, Taints: []string{"dedicated=gpu:NoSchedule"}, CPUs: 8, Memory: 16384},
+		},
+	}
+
+	cf := NewClusterFile(cc)
+	b, err := MarshalClusterFile(cf)
+	if err != nil {
+		t.Fatalf("MarshalClusterFile() failed: %v", err)
+	}
+	got, err := ParseClusterFile(b)
+	if err != nil {
+		t.Fatalf("ParseClusterFile() of exported file failed: %v\n%s", err, b)
+	}
+	if !reflect.DeepEqual(got, cf) {
+		t.Errorf("exported cluster file changed after parsing:\n%+v\nwant:\n%+v", got, cf)
+	}
+
+	want := ClusterSpec{
+		Driver:            "docker",
+		KubernetesVersion: "v1.20.2",
+		ContainerRuntime:  "docker",
+		CNI:               "cilium",
+		CNIOptions:        map[string]string{"hubble": "true"},
+		Resources:         ResourcesSpec{CPUs: 2, Memory: "2200mb", Disk: "20000mb"},
+		Nodes:             []NodeSpec{{Role: RoleControlPlane}, {Name: "m02", Role: RoleWorker, Labels: map[string]string{"pool": "gpu"}, Taints: []string{"dedicated=gpu:NoSchedule"}, Resources: &ResourcesSpec{CPUs: 8, Memory: "16384mb"}}},
+		ExtraOptions:      []string{"kubelet.max-pods=50"},
+		Mounts:            []MountSpec{{Source: "/home/team/src", Target: "/src"}},
+	}
+	if !reflect.DeepEqual(cf.Spec, want) {
+		t.Errorf("NewClusterFile().Spec = %+v; want %+v", cf.Spec, want)
+	}
+}
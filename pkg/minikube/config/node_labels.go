@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// taintEffects are the valid effects of the taints of a node
+var taintEffects = []string{"NoSchedule", "PreferNoSchedule", "NoExecute"}
+
+// ParseNodeLabels parses the labels set with --node-labels, formatted as key=value
+func ParseNodeLabels(labels []string) (map[string]string, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	parsed := map[string]string{}
+	for _, l := range labels {
+		kv := strings.SplitN(l, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid node label %q: must be formatted as key=value", l)
+		}
+		parsed[kv[0]] = kv[1]
+	}
+	if err := ValidateNodeLabels(parsed); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// ValidateNodeLabels checks the keys and values of the labels of a node
+func ValidateNodeLabels(labels map[string]string) error {
+	for k, v := range labels {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("invalid node label key %q: %s", k, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return fmt.Errorf("invalid value %q of node label %q: %s", v, k, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// ValidateNodeTaints checks the taints of a node, formatted as key[=value]:effect
+func ValidateNodeTaints(taints []string) error {
+	for _, t := range taints {
+		i := strings.LastIndex(t, ":")
+		if i < 0 {
+			return fmt.Errorf("invalid node taint %q: must be formatted as key[=value]:effect", t)
+		}
+		kv := strings.SplitN(t[:i], "=", 2)
+		if errs := validation.IsQualifiedName(kv[0]); len(errs) > 0 {
+			return fmt.Errorf("invalid key of node taint %q: %s", t, strings.Join(errs, "; "))
+		}
+		if len(kv) == 2 {
+			if errs := validation.IsValidLabelValue(kv[1]); len(errs) > 0 {
+				return fmt.Errorf("invalid value of node taint %q: %s", t, strings.Join(errs, "; "))
+			}
+		}
+		valid := false
+		for _, e := range taintEffects {
+			if t[i+1:] == e {
+				valid = true
+			}
+		}
+		if !valid {
+			return fmt.Errorf("invalid effect of node taint %q, expected one of %s", t, strings.Join(taintEffects, ", "))
+		}
+	}
+	return nil
+}
+
+// FormatNodeLabels formats the labels of a node as accepted by --node-labels, sorted by key
+func FormatNodeLabels(labels map[string]string) []string {
+	var formatted []string
+	for k, v := range labels {
+		formatted = append(formatted, k+"="+v)
+	}
+	sort.Strings(formatted)
+	return formatted
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseNodeLabels(t *testing.T) {
+	tests := []struct {
+		labels  []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{nil, nil, false},
+		{[]string{"pool=gpu", "node-role.kubernetes.io/gpu="}, map[string]string{"pool": "gpu", "node-role.kubernetes.io/gpu": ""}, false},
+		{[]string{"pool"}, nil, true},
+		{[]string{"=gpu"}, nil, true},
+		{[]string{"pool=not valid"}, nil, true},
+	}
+	for _, tc := range tests {
+		got, err := ParseNodeLabels(tc.labels)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ParseNodeLabels(%v) error = %v, wantErr %v", tc.labels, err, tc.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ParseNodeLabels(%v) = %v, want %v", tc.labels, got, tc.want)
+		}
+		if !tc.wantErr && !reflect.DeepEqual(FormatNodeLabels(got), FormatNodeLabels(tc.want)) {
+			t.Errorf("FormatNodeLabels(%v) = %v", got, FormatNodeLabels(got))
+		}
+	}
+}
+
+func TestValidateNodeTaints(t *testing.T) {
+	tests := []struct {
+		taints  []string
+		wantErr bool
+	}{
+		{[]string{"dedicated=gpu:NoSchedule", "spot:PreferNoSchedule", "example.com/maintenance=true:NoExecute"}, false},
+		{[]string{"dedicated=gpu"}, true},
+		{[]string{"dedicated=gpu:Never"}, true},
+		{[]string{":NoSchedule"}, true},
+		{[]string{"dedicated=not valid:NoSchedule"}, true},
+	}
+	for _, tc := range tests {
+		if err := ValidateNodeTaints(tc.taints); (err != nil) != tc.wantErr {
+			t.Errorf("ValidateNodeTaints(%v) error = %v, wantErr %v", tc.taints, err, tc.wantErr)
+		}
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/spf13/viper"
 	"k8s.io/klog/v2"
@@ -82,6 +83,31 @@ func PrimaryControlPlane(cc *ClusterConfig) (Node, error) {
 	return cp, nil
 }
 
+// ControlPlanes returns the control-plane nodes of the cluster, the primary one first
+func ControlPlanes(cc ClusterConfig) []Node {
+	var cps []Node
+	for _, n := range cc.Nodes {
+		if n.ControlPlane {
+			cps = append(cps, n)
+		}
+	}
+	return cps
+}
+
+// IsPrimaryControlPlane returns whether n is the primary control plane, which initializes the cluster
+func IsPrimaryControlPlane(cc ClusterConfig, n Node) bool {
+	if !n.ControlPlane {
+		return false
+	}
+	cps := ControlPlanes(cc)
+	return len(cps) == 0 || cps[0].Name == n.Name
+}
+
+// IsHA returns whether the cluster has multiple control planes behind a virtual IP
+func IsHA(cc ClusterConfig) bool {
+	return cc.KubernetesConfig.APIServerHAVIP != ""
+}
+
 // ProfileNameValid checks if the profile name is container name and DNS hostname/label friendly.
 func ProfileNameValid(name string) bool {
 	// RestrictedNamePattern describes the characters allowed to represent a profile's name
@@ -120,8 +146,19 @@ func CreateEmptyProfile(name string, miniHome ...string) error {
 	return SaveProfile(name, cfg, miniHome...)
 }
 
-// SaveNode saves a node to a cluster
+// nodesMutex serializes the saves of the nodes started concurrently, each with its own copy of the cluster config
+var nodesMutex sync.Mutex
+
+// SaveNode saves a node to a cluster. The other nodes are saved as they were last saved,
+// as the nodes started concurrently save their own copies of the cluster config.
 func SaveNode(cfg *ClusterConfig, node *Node) error {
+	nodesMutex.Lock()
+	defer nodesMutex.Unlock()
+
+	if saved, err := Load(viper.GetString(ProfileName)); err == nil {
+		cfg.Nodes = savedNodes(cfg.Nodes, saved.Nodes)
+	}
+
 	update := false
 	for i, n := range cfg.Nodes {
 		if n.Name == node.Name {
@@ -138,6 +175,21 @@ func SaveNode(cfg *ClusterConfig, node *Node) error {
 	return SaveProfile(viper.GetString(ProfileName), cfg)
 }
 
+// savedNodes returns the nodes, replaced by their saved version if any
+func savedNodes(nodes []Node, saved []Node) []Node {
+	merged := []Node{}
+	for _, n := range nodes {
+		for _, sn := range saved {
+			if sn.Name == n.Name {
+				n = sn
+				break
+			}
+		}
+		merged = append(merged, n)
+	}
+	return merged
+}
+
 // SaveProfile creates an profile out of the cfg and stores in $MINIKUBE_HOME/profiles/<profilename>/config.json
 func SaveProfile(name string, cfg *ClusterConfig, miniHome ...string) error {
 	data, err := json.MarshalIndent(cfg, "", "    ")
@@ -333,8 +385,42 @@ func ProfileFolderPath(profile string, miniHome ...string) string {
 // MachineName returns the name of the machine, as seen by the hypervisor given the cluster and node names
 func MachineName(cc ClusterConfig, n Node) string {
 	// For single node cluster, default to back to old naming
-	if (len(cc.Nodes) == 1 && cc.Nodes[0].Name == n.Name) || n.ControlPlane {
+	if (len(cc.Nodes) == 1 && cc.Nodes[0].Name == n.Name) || IsPrimaryControlPlane(cc, n) {
 		return cc.Name
 	}
 	return fmt.Sprintf("%s-%s", cc.Name, n.Name)
 }
+
+// NodeCPUs returns the number of CPUs of the node, which defaults to the one of the cluster
+func NodeCPUs(cc ClusterConfig, n Node) int {
+	if n.CPUs > 0 {
+		return n.CPUs
+	}
+	return cc.CPUs
+}
+
+// NodeMemory returns the memory of the node in MB, which defaults to the one of the cluster
+func NodeMemory(cc ClusterConfig, n Node) int {
+	if n.Memory > 0 {
+		return n.Memory
+	}
+	return cc.Memory
+}
+
+// NodeDiskSize returns the disk size of the node in MB, which defaults to the one of the cluster
+func NodeDiskSize(cc ClusterConfig, n Node) int {
+	if n.DiskSize > 0 {
+		return n.DiskSize
+	}
+	return cc.DiskSize
+}
+
+// HasNodeResources returns whether any node of the cluster has its own CPUs, memory or disk size
+func HasNodeResources(cc ClusterConfig) bool {
+	for _, n := range cc.Nodes {
+		if n.CPUs > 0 || n.Memory > 0 || n.DiskSize > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -20,6 +20,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/spf13/viper"
@@ -347,3 +348,111 @@ func TestGetPrimaryControlPlane(t *testing.T) {
 		}()
 	}
 }
+
+func TestMachineNameHA(t *testing.T) {
+	cc := ClusterConfig{Name: "ha", Nodes: []Node{
+		{Name: "", ControlPlane: true, Worker: true},
+		{Name: "m02", ControlPlane: true, Worker: true},
+		{Name: "m03", Worker: true},
+	}}
+	tests := []struct {
+		node    Node
+		primary bool
+		machine string
+	}{
+		{cc.Nodes[0], true, "ha"},
+		{cc.Nodes[1], false, "ha-m02"},
+		{cc.Nodes[2], false, "ha-m03"},
+	}
+	for _, tc := range tests {
+		if got := IsPrimaryControlPlane(cc, tc.node); got != tc.primary {
+			t.Errorf("IsPrimaryControlPlane(%q) = %v; want %v", tc.node.Name, got, tc.primary)
+		}
+		if got := MachineName(cc, tc.node); got != tc.machine {
+			t.Errorf("MachineName(%q) = %q; want %q", tc.node.Name, got, tc.machine)
+		}
+	}
+	if got := len(ControlPlanes(cc)); got != 2 {
+		t.Errorf("ControlPlanes() returned %d nodes; want 2", got)
+	}
+}
+
+func TestNodeResources(t *testing.T) {
+	cc := ClusterConfig{Name: "pools", CPUs: 2, Memory: 2200, DiskSize: 20000, Nodes: []Node{
+		{Name: "", ControlPlane: true, Worker: true},
+		{Name: "m02", Worker: true, CPUs: 8, Memory: 16384},
+		{Name: "m03", Worker: true, DiskSize: 40000},
+	}}
+	tests := []struct {
+		node     Node
+		cpus     int
+		memory   int
+		diskSize int
+	}{
+		{cc.Nodes[0], 2, 2200, 20000},
+		{cc.Nodes[1], 8, 16384, 20000},
+		{cc.Nodes[2], 2, 2200, 40000},
+	}
+	for _, tc := range tests {
+		if got := NodeCPUs(cc, tc.node); got != tc.cpus {
+			t.Errorf("NodeCPUs(%q) = %d; want %d", tc.node.Name, got, tc.cpus)
+		}
+		if got := NodeMemory(cc, tc.node); got != tc.memory {
+			t.Errorf("NodeMemory(%q) = %d; want %d", tc.node.Name, got, tc.memory)
+		}
+		if got := NodeDiskSize(cc, tc.node); got != tc.diskSize {
+			t.Errorf("NodeDiskSize(%q) = %d; want %d", tc.node.Name, got, tc.diskSize)
+		}
+	}
+	if !HasNodeResources(cc) {
+		t.Errorf("HasNodeResources() = false; want true")
+	}
+	cc.Nodes = cc.Nodes[:1]
+	if HasNodeResources(cc) {
+		t.Errorf("HasNodeResources() of a cluster without per-node resources = true; want false")
+	}
+}
+
+func TestSaveNodeConcurrent(t *testing.T) {
+	originalMinikubeHomeEnv := os.Getenv("MINIKUBE_HOME")
+	defer os.Setenv("MINIKUBE_HOME", originalMinikubeHomeEnv)
+	os.Setenv("MINIKUBE_HOME", t.TempDir())
+	viper.Set(ProfileName, "multinode")
+	defer viper.Set(ProfileName, "")
+
+	cc := &ClusterConfig{Name: "multinode", Nodes: []Node{{Name: "", ControlPlane: true, Worker: true}}}
+	names := []string{"m02", "m03", "m04", "m05"}
+	for _, name := range names {
+		if err := SaveNode(cc, &Node{Name: name, Worker: true}); err != nil {
+			t.Fatalf("SaveNode(%q) error = %v", name, err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			// each node saves its own copy of the cluster config, as node.AddNodes does
+			c := *cc
+			c.Nodes = append([]Node{}, cc.Nodes...)
+			if err := SaveNode(&c, &Node{Name: name, IP: "192.168.49." + name[2:], Worker: true}); err != nil {
+				t.Errorf("SaveNode(%q) error = %v", name, err)
+			}
+		}(name)
+	}
+	wg.Wait()
+
+	saved, err := Load("multinode")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(saved.Nodes) != 5 {
+		t.Fatalf("saved %d nodes; want 5: %+v", len(saved.Nodes), saved.Nodes)
+	}
+	for _, n := range saved.Nodes[1:] {
+		if n.IP != "192.168.49."+n.Name[2:] {
+			t.Errorf("node %q saved with IP %q; want the one it saved", n.Name, n.IP)
+		}
+	}
+}
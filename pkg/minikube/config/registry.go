@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import "fmt"
+
+// redacted replaces the secrets of the registries in the output of minikube and in its logs
+const redacted = "<redacted>"
+
+// Redacted returns a copy of the registry with its password and token masked
+func (r Registry) Redacted() Registry {
+	if r.Password != "" {
+		r.Password = redacted
+	}
+	if r.Token != "" {
+		r.Token = redacted
+	}
+	return r
+}
+
+// String returns the registry with its secrets masked, which keeps them out of the logged cluster configs
+func (r Registry) String() string {
+	// the conversion drops the methods of Registry, which would recurse
+	type registry Registry
+	return fmt.Sprintf("%+v", registry(r.Redacted()))
+}
+
+// Redacted returns a copy of the cluster config with the secrets of its registries masked
+func (cc ClusterConfig) Redacted() ClusterConfig {
+	if len(cc.Registries) == 0 {
+		return cc
+	}
+	regs := make([]Registry, len(cc.Registries))
+	for i, r := range cc.Registries {
+		regs[i] = r.Redacted()
+	}
+	cc.Registries = regs
+	return cc
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRedactedRegistries(t *testing.T) {
+	cc := &ClusterConfig{Name: "p1", Registries: []Registry{
+		{Host: "docker.io", Username: "team", Password: "s3cr3t-password"},
+		{Host: "quay.io", Token: "s3cr3t-token"},
+		{Host: "registry.local:5000"},
+	}}
+
+	logged := fmt.Sprintf("%+v", cc)
+	for _, secret := range []string{"s3cr3t-password", "s3cr3t-token"} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("the logged config contains the secret %q: %s", secret, logged)
+		}
+	}
+	if !strings.Contains(logged, "Username:team") {
+		t.Errorf("the logged config lacks the username of the registry: %s", logged)
+	}
+
+	b, err := json.Marshal(cc.Redacted())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "s3cr3t") {
+		t.Errorf("the redacted config contains secrets: %s", b)
+	}
+	if cc.Registries[0].Password != "s3cr3t-password" || cc.Registries[1].Token != "s3cr3t-token" {
+		t.Errorf("Redacted() changed the config: %+v", cc.Registries)
+	}
+	if r := cc.Redacted().Registries[2]; r.Password != "" || r.Token != "" {
+		t.Errorf("Redacted() = %+v; want no secrets set for a registry without any", r)
+	}
+}
@@ -48,6 +48,8 @@ type ClusterConfig struct {
 	ContainerVolumeMounts   []string // Only used by container drivers: Docker, Podman
 	InsecureRegistry        []string
 	RegistryMirror          []string
+	Registries              []Registry
+	RegistryCache           bool   // Pull from Docker Hub through a cache on the host, shared by all profiles
 	HostOnlyCIDR            string // Only used by the virtualbox driver
 	HypervVirtualSwitch     string
 	HypervUseExternalSwitch bool
@@ -78,11 +80,21 @@ type ClusterConfig struct {
 	CustomAddonRegistries   map[string]string // Maps image names to the registry to use for addons. See CustomAddonImages for example.
 	VerifyComponents        map[string]bool   // map of components to verify and wait for after start.
 	StartHostTimeout        time.Duration
+	CertExpiration          time.Duration
+	CACert                  string // CA of the organization signing the certs instead of the minikube one, with CAKey
+	CAKey                   string
+	ImportHostCerts         bool // import the system trust store of the host into the nodes
 	ScheduledStop           *ScheduledStopConfig
 	ExposedPorts            []string // Only used by the docker and podman driver
 	ListenAddress           string   // Only used by the docker and podman driver
 	Network                 string   // only used by docker driver
+	Subnet                  string   // Only used by the docker and podman driver
+	ExtraNetworks           []string // Only used by the docker and podman driver
 	MultiNodeRequested      bool
+	Mount                   bool          // used by start to run the mount daemon
+	MountString             string        // used by start to run the mount daemon, formatted as <source directory>:<target directory>
+	Mounts                  []MountConfig // served in the background by "minikube mount --background", re-established by start
+	PortForwards            []PortForward // added by "minikube port add", only used by the docker and podman driver
 }
 
 // KubernetesConfig contains the parameters used to configure the VM Kubernetes.
@@ -93,6 +105,7 @@ type KubernetesConfig struct {
 	APIServerName       string
 	APIServerNames      []string
 	APIServerIPs        []net.IP
+	APIServerHAVIP      string // virtual IP of the control planes of HA clusters, served by kube-vip
 	DNSDomain           string
 	ContainerRuntime    string
 	CRISocket           string
@@ -100,15 +113,19 @@ type KubernetesConfig struct {
 	FeatureGates        string // https://kubernetes.io/docs/reference/command-line-tools-reference/feature-gates/
 	ServiceCIDR         string // the subnet which Kubernetes services will be deployed to
 	ImageRepository     string
-	LoadBalancerStartIP string // currently only used by MetalLB addon
-	LoadBalancerEndIP   string // currently only used by MetalLB addon
-	CustomIngressCert   string // used by Ingress addon
+	LoadBalancerStartIP string        // currently only used by MetalLB addon
+	LoadBalancerEndIP   string        // currently only used by MetalLB addon
+	CustomIngressCert   string        // used by Ingress addon
+	AutoPauseInterval   time.Duration // used by auto-pause addon
+	AutoPauseAddress    string        // used by auto-pause addon
+	AutoPauseNamespaces []string      // used by auto-pause addon
 	ExtraOptions        ExtraOptionSlice
 
 	ShouldLoadCachedImages bool
 
-	EnableDefaultCNI bool   // deprecated in preference to CNI
-	CNI              string // CNI to use
+	EnableDefaultCNI bool              // deprecated in preference to CNI
+	CNI              string            // CNI to use
+	CNIOptions       map[string]string // options of the CNI, set with --cni-opt
 
 	// We need to keep these in the short term for backwards compatibility
 	NodeIP   string
@@ -124,6 +141,12 @@ type Node struct {
 	KubernetesVersion string
 	ControlPlane      bool
 	Worker            bool
+	StaticIP          string            // Only used by the docker and podman driver
+	Labels            map[string]string // Kubernetes labels of the node, applied at registration and at every start
+	Taints            []string          // Kubernetes taints of the node, formatted as key[=value]:effect
+	CPUs              int               // Number of CPUs of the node, 0 means the one of the cluster
+	Memory            int               // Memory of the node in MB, 0 means the one of the cluster
+	DiskSize          int               // Disk size of the node in MB, 0 means the one of the cluster
 }
 
 // VersionedExtraOption holds information on flags to apply to a specific range
@@ -157,3 +180,37 @@ type ScheduledStopConfig struct {
 	InitiationTime int64
 	Duration       time.Duration
 }
+
+// MountConfig contains a host directory served in the background by a mount server
+type MountConfig struct {
+	Source  string // directory on the host
+	Target  string // absolute path of the directory in the control plane
+	IP      string // host IP the control plane connects to, detected when empty
+	Type    string
+	UID     string
+	GID     string
+	Version string
+	MSize   int
+	Mode    uint32
+	Options []string
+	Watch   bool // propagate the changes on the host to file watchers in the guest
+}
+
+// Registry contains the mirrors, credentials and CA certificates of a container registry, applied to the runtime of all the nodes
+type Registry struct {
+	Host     string   // host and optional port of the registry, docker.io for Docker Hub
+	Mirrors  []string // URLs of mirrors tried in order before the registry
+	Username string   // username of basic auth
+	Password string   // password of basic auth
+	Token    string   // identity token, instead of a username and password
+	CACert   string   // PEM encoded CA certificates of the registry and its mirrors
+}
+
+// PortForward contains a port of the host forwarded to a port of a node by a proxy container
+type PortForward struct {
+	ListenAddress string // IP address of the host to listen on, the one of the cluster when empty
+	HostPort      int
+	NodePort      int
+	Protocol      string // tcp or udp
+	Node          string // name of the node, the primary control plane when empty
+}
@@ -48,6 +48,10 @@ const (
 	APIServerPort = 8443
 	// AutoPauseProxyPort is the port to be used as a reverse proxy for apiserver port
 	AutoPauseProxyPort = 32443
+	// AutoPauseListenAddress is the default address the auto-pause daemon listens on for unpause and status requests
+	AutoPauseListenAddress = "0.0.0.0:8080"
+	// AutoPauseInterval is the default duration of inactivity before the auto-pause daemon pauses the cluster
+	AutoPauseInterval = time.Minute
 
 	// SSHPort is the SSH serviceport on the node vm and container
 	SSHPort = 22
@@ -68,6 +72,10 @@ const (
 	HostAlias = "host.minikube.internal"
 	// ControlPlaneAlias is a DNS alias pointing to the apiserver frontend
 	ControlPlaneAlias = "control-plane.minikube.internal"
+	// DefaultCertExpiration is the default validity period of the certs signed by the minikube CAs, the one of kubeadm
+	DefaultCertExpiration = time.Hour * 24 * 365
+	// CertExpiryWarning is how long before the expiry of a cert start and status warn about it
+	CertExpiryWarning = time.Hour * 24 * 30
 
 	// DockerHostEnv is used for docker daemon settings
 	DockerHostEnv = "DOCKER_HOST"
@@ -18,13 +18,13 @@ package cruntime
 
 import (
 	"bytes"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
 	"os/exec"
 	"path"
+	"sort"
 	"strings"
 	"text/template"
 	"time"
@@ -104,11 +104,30 @@ oom_score = 0
     [plugins.cri.registry]
       [plugins.cri.registry.mirrors]
         [plugins.cri.registry.mirrors."docker.io"]
-          endpoint = ["https://registry-1.docker.io"]
+          endpoint = [{{ range .RegistryMirror }}"{{.}}", {{ end }}"https://registry-1.docker.io"]
         {{ range .InsecureRegistry -}}
         [plugins.cri.registry.mirrors."{{. -}}"]
           endpoint = ["http://{{. -}}"]
         {{ end -}}
+        {{ range .Registries -}}
+        {{ if and .Mirrors (ne .Host "docker.io") -}}
+        [plugins.cri.registry.mirrors."{{.Host}}"]
+          endpoint = [{{ range .Mirrors }}"{{.}}", {{ end }}"https://{{.Host}}"]
+        {{ end -}}
+        {{ end -}}
+      [plugins.cri.registry.configs]
+        {{ range .RegistryConfigs -}}
+        {{ if .CAFile -}}
+        [plugins.cri.registry.configs."{{.Host}}".tls]
+          ca_file = "{{.CAFile}}"
+        {{ end -}}
+        {{ if or .Username .IdentityToken -}}
+        [plugins.cri.registry.configs."{{.Host}}".auth]
+          username = {{ printf "%q" .Username }}
+          password = {{ printf "%q" .Password }}
+          identitytoken = {{ printf "%q" .IdentityToken }}
+        {{ end -}}
+        {{ end -}}
   [plugins.diff-service]
     default = ["walking"]
   [plugins.scheduler]
@@ -128,6 +147,57 @@ type Containerd struct {
 	KubernetesVersion semver.Version
 	Init              sysinit.Manager
 	InsecureRegistry  []string
+	RegistryMirror    []string
+	Registries        []config.Registry
+}
+
+// containerdRegistryConfig contains the CA certificates and credentials of a registry endpoint
+type containerdRegistryConfig struct {
+	Host          string
+	CAFile        string
+	Username      string
+	Password      string
+	IdentityToken string
+}
+
+// containerdRegistryConfigs returns the configs of the endpoints of the registries and their mirrors
+func containerdRegistryConfigs(regs []config.Registry) []containerdRegistryConfig {
+	// Docker Hub is pulled from its actual host
+	endpoint := func(host string) string {
+		if host == DockerHub {
+			return "registry-1.docker.io"
+		}
+		return host
+	}
+	configs := map[string]*containerdRegistryConfig{}
+	get := func(host string) *containerdRegistryConfig {
+		if configs[endpoint(host)] == nil {
+			configs[endpoint(host)] = &containerdRegistryConfig{Host: endpoint(host)}
+		}
+		return configs[endpoint(host)]
+	}
+	for host := range registryCAs(regs) {
+		get(host).CAFile = path.Join(containerdCertsDir, host, registryCAFile)
+	}
+	for _, r := range regs {
+		if r.Username != "" || r.Token != "" {
+			c := get(r.Host)
+			c.Username = r.Username
+			c.Password = r.Password
+			c.IdentityToken = r.Token
+		}
+	}
+
+	hosts := []string{}
+	for host := range configs {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	rc := []containerdRegistryConfig{}
+	for _, host := range hosts {
+		rc = append(rc, *configs[host])
+	}
+	return rc
 }
 
 // Name is a human readable name for containerd
@@ -178,7 +248,7 @@ func (r *Containerd) Available() error {
 }
 
 // generateContainerdConfig sets up /etc/containerd/config.toml
-func generateContainerdConfig(cr CommandRunner, imageRepository string, kv semver.Version, forceSystemd bool, insecureRegistry []string) error {
+func generateContainerdConfig(cr CommandRunner, imageRepository string, kv semver.Version, forceSystemd bool, insecureRegistry []string, registryMirror []string, registries []config.Registry) error {
 	cPath := containerdConfigFile
 	t, err := template.New("containerd.config.toml").Parse(containerdConfigTemplate)
 	if err != nil {
@@ -189,19 +259,25 @@ func generateContainerdConfig(cr CommandRunner, imageRepository string, kv semve
 		PodInfraContainerImage string
 		SystemdCgroup          bool
 		InsecureRegistry       []string
+		RegistryMirror         []string
+		Registries             []config.Registry
+		RegistryConfigs        []containerdRegistryConfig
 		CNIConfDir             string
 	}{
 		PodInfraContainerImage: pauseImage,
 		SystemdCgroup:          forceSystemd,
 		InsecureRegistry:       insecureRegistry,
+		RegistryMirror:         registryMirror,
+		Registries:             registries,
+		RegistryConfigs:        containerdRegistryConfigs(registries),
 		CNIConfDir:             cni.ConfDir,
 	}
 	var b bytes.Buffer
 	if err := t.Execute(&b, opts); err != nil {
 		return err
 	}
-	c := exec.Command("/bin/bash", "-c", fmt.Sprintf("sudo mkdir -p %s && printf %%s \"%s\" | base64 -d | sudo tee %s", path.Dir(cPath), base64.StdEncoding.EncodeToString(b.Bytes()), cPath))
-	if _, err := cr.RunCmd(c); err != nil {
+	// the config contains the credentials of the registries
+	if err := copySecret(cr, b.Bytes(), cPath); err != nil {
 		return errors.Wrap(err, "generate containerd cfg.")
 	}
 	return nil
@@ -217,7 +293,10 @@ func (r *Containerd) Enable(disOthers, forceSystemd bool) error {
 	if err := populateCRIConfig(r.Runner, r.SocketPath()); err != nil {
 		return err
 	}
-	if err := generateContainerdConfig(r.Runner, r.ImageRepository, r.KubernetesVersion, forceSystemd, r.InsecureRegistry); err != nil {
+	if err := generateContainerdConfig(r.Runner, r.ImageRepository, r.KubernetesVersion, forceSystemd, r.InsecureRegistry, r.RegistryMirror, r.Registries); err != nil {
+		return err
+	}
+	if err := writeRegistryCAs(r.Runner, containerdCertsDir, r.Registries); err != nil {
 		return err
 	}
 	if err := enableIPForwarding(r.Runner); err != nil {
@@ -228,6 +307,21 @@ func (r *Containerd) Enable(disOthers, forceSystemd bool) error {
 	return r.Init.Restart("containerd")
 }
 
+// ConfigureRegistries regenerates the config of containerd with the registries, keeping its cgroup driver, and restarts it
+func (r *Containerd) ConfigureRegistries() error {
+	cgroupDriver, err := r.CGroupDriver()
+	if err != nil {
+		return errors.Wrap(err, "cgroup driver")
+	}
+	if err := generateContainerdConfig(r.Runner, r.ImageRepository, r.KubernetesVersion, cgroupDriver == "systemd", r.InsecureRegistry, r.RegistryMirror, r.Registries); err != nil {
+		return err
+	}
+	if err := writeRegistryCAs(r.Runner, containerdCertsDir, r.Registries); err != nil {
+		return err
+	}
+	return r.Init.Restart("containerd")
+}
+
 // Disable idempotently disables containerd on a host
 func (r *Containerd) Disable() error {
 	return r.Init.ForceStop("containerd")
@@ -243,21 +337,12 @@ func (r *Containerd) ImageExists(name string, sha string) bool {
 }
 
 // ListImages lists images managed by this container runtime
-func (r *Containerd) ListImages(ListImagesOptions) ([]string, error) {
-	c := exec.Command("sudo", "ctr", "-n=k8s.io", "images", "list", "--quiet")
-	rr, err := r.Runner.RunCmd(c)
+func (r *Containerd) ListImages(o ListImagesOptions) ([]ListImage, error) {
+	imgs, err := listCRIImages(r.Runner)
 	if err != nil {
-		return nil, errors.Wrapf(err, "ctr images list")
-	}
-	all := strings.Split(rr.Stdout.String(), "\n")
-	imgs := []string{}
-	for _, img := range all {
-		if img == "" || strings.Contains(img, "sha256:") {
-			continue
-		}
-		imgs = append(imgs, img)
+		return nil, err
 	}
-	return imgs, nil
+	return filterImages(imgs, o)
 }
 
 // LoadImage loads an image into this runtime
@@ -285,6 +370,39 @@ func (r *Containerd) SaveImage(name string, path string) error {
 	return nil
 }
 
+// TagImage tags an image in this runtime
+func (r *Containerd) TagImage(source string, target string) error {
+	klog.Infof("Tagging image %s: %s", source, target)
+	c := exec.Command("sudo", "ctr", "-n=k8s.io", "images", "tag", "--force", ctrImageName(source), ctrImageName(target))
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrapf(err, "ctr images tag")
+	}
+	return nil
+}
+
+// PushImage pushes an image
+func (r *Containerd) PushImage(name string) error {
+	klog.Infof("Pushing image: %s", name)
+	c := exec.Command("sudo", "ctr", "-n=k8s.io", "images", "push", ctrImageName(name))
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrapf(err, "ctr images push")
+	}
+	return nil
+}
+
+// ctrImageName returns the fully qualified image name, as stored by containerd
+// for example "busybox" becomes "docker.io/library/busybox:latest"
+func ctrImageName(name string) string {
+	name = addDockerIO(name)
+	if strings.Contains(name, "@") {
+		return name
+	}
+	if !strings.Contains(path.Base(name), ":") {
+		name += ":latest"
+	}
+	return name
+}
+
 // RemoveImage removes a image
 func (r *Containerd) RemoveImage(name string) error {
 	return removeCRIImage(r.Runner, name)
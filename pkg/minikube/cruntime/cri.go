@@ -23,6 +23,7 @@ import (
 	"html/template"
 	"os/exec"
 	"path"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -200,6 +201,48 @@ func pullCRIImage(cr CommandRunner, name string) error {
 	return nil
 }
 
+// criImages maps to 'crictl images --output json'
+type criImages struct {
+	Images []struct {
+		ID          string   `json:"id"`
+		RepoTags    []string `json:"repoTags"`
+		RepoDigests []string `json:"repoDigests"`
+		Size        string   `json:"size"`
+	} `json:"images"`
+}
+
+// listCRIImages lists images using crictl
+func listCRIImages(cr CommandRunner) ([]ListImage, error) {
+	crictl := getCrictlPath(cr)
+	c := exec.Command("sudo", crictl, "images", "--output", "json")
+	rr, err := cr.RunCmd(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "crictl images")
+	}
+
+	var ci criImages
+	if err := json.Unmarshal(rr.Stdout.Bytes(), &ci); err != nil {
+		return nil, errors.Wrap(err, "parsing crictl images")
+	}
+
+	imgs := []ListImage{}
+	for _, img := range ci.Images {
+		size, err := strconv.ParseUint(img.Size, 10, 64)
+		if err != nil {
+			klog.Warningf("failed to parse size %q of image %s: %v", img.Size, img.ID, err)
+		}
+		li := ListImage{ID: img.ID, RepoTags: img.RepoTags, RepoDigests: img.RepoDigests, Size: size}
+		if li.RepoTags == nil {
+			li.RepoTags = []string{}
+		}
+		if li.RepoDigests == nil {
+			li.RepoDigests = []string{}
+		}
+		imgs = append(imgs, li)
+	}
+	return imgs, nil
+}
+
 // removeCRIImage remove image using crictl
 func removeCRIImage(cr CommandRunner, name string) error {
 	klog.Infof("Removing image: %s", name)
@@ -17,6 +17,7 @@ limitations under the License.
 package cruntime
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -41,6 +42,8 @@ import (
 const (
 	// CRIOConfFile is the path to the CRI-O configuration
 	crioConfigFile = "/etc/crio/crio.conf"
+	// crioMirrorsFile is the drop-in of the registries configuration with the mirrors of registries
+	crioMirrorsFile = "/etc/containers/registries.conf.d/02-minikube-mirrors.conf"
 )
 
 // CRIO contains CRIO runtime state
@@ -50,6 +53,8 @@ type CRIO struct {
 	ImageRepository   string
 	KubernetesVersion semver.Version
 	Init              sysinit.Manager
+	RegistryMirror    []string
+	Registries        []config.Registry
 }
 
 // generateCRIOConfig sets up /etc/crio/crio.conf
@@ -72,6 +77,66 @@ func generateCRIOConfig(cr CommandRunner, imageRepository string, kv semver.Vers
 	return nil
 }
 
+// crioMirrorsConfig returns the registries configuration making pulls try the mirrors first:
+// the mirrors of Docker Hub, then the ones of the other registries
+func crioMirrorsConfig(mirrors []string, regs []config.Registry) string {
+	var b strings.Builder
+	add := func(host string, mirrors []string) {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "[[registry]]\nprefix = %q\nlocation = %q\n", host, host)
+		for _, m := range mirrors {
+			location := m
+			insecure := false
+			if i := strings.Index(m, "://"); i >= 0 {
+				location = m[i+3:]
+				insecure = strings.EqualFold(m[:i], "http")
+			}
+			fmt.Fprintf(&b, "\n[[registry.mirror]]\nlocation = %q\ninsecure = %t\n", strings.TrimSuffix(location, "/"), insecure)
+		}
+	}
+	if len(mirrors) > 0 {
+		add(DockerHub, mirrors)
+	}
+	for _, r := range regs {
+		if r.Host != DockerHub && len(r.Mirrors) > 0 {
+			add(r.Host, r.Mirrors)
+		}
+	}
+	return b.String()
+}
+
+// generateCRIOMirrorsConfig sets up the mirrors of the registries, or removes them if there are none
+func generateCRIOMirrorsConfig(cr CommandRunner, mirrors []string, regs []config.Registry) error {
+	c := exec.Command("sudo", "rm", "-f", crioMirrorsFile)
+	if conf := crioMirrorsConfig(mirrors, regs); conf != "" {
+		c = exec.Command("/bin/bash", "-c", fmt.Sprintf("sudo mkdir -p %s && printf %%s \"%s\" | base64 -d | sudo tee %s", path.Dir(crioMirrorsFile), base64.StdEncoding.EncodeToString([]byte(conf)), crioMirrorsFile))
+	}
+	if _, err := cr.RunCmd(c); err != nil {
+		return errors.Wrap(err, "generate crio mirrors cfg")
+	}
+	return nil
+}
+
+// configureCRIORegistries sets up the mirrors, CA certificates and credentials of the registries
+func configureCRIORegistries(cr CommandRunner, mirrors []string, regs []config.Registry) error {
+	if err := generateCRIOMirrorsConfig(cr, mirrors, regs); err != nil {
+		return err
+	}
+	if err := writeRegistryCAs(cr, crioCertsDir, regs); err != nil {
+		return err
+	}
+	if err := writeAuthConfig(cr, crioAuthFile, regs, DockerHub); err != nil {
+		return err
+	}
+	c := exec.Command("/bin/bash", "-c", fmt.Sprintf("sudo sed -e 's|^.*global_auth_file = .*$|global_auth_file = \"%s\"|' -i %s", crioAuthFile, crioConfigFile))
+	if _, err := cr.RunCmd(c); err != nil {
+		return errors.Wrap(err, "set crio global_auth_file")
+	}
+	return nil
+}
+
 // Name is a human readable name for CRIO
 func (r *CRIO) Name() string {
 	return "CRI-O"
@@ -151,12 +216,23 @@ func (r *CRIO) Enable(disOthers, _ bool) error {
 	if err := generateCRIOConfig(r.Runner, r.ImageRepository, r.KubernetesVersion); err != nil {
 		return err
 	}
+	if err := configureCRIORegistries(r.Runner, r.RegistryMirror, r.Registries); err != nil {
+		return err
+	}
 	if err := enableIPForwarding(r.Runner); err != nil {
 		return err
 	}
 	return r.Init.Start("crio")
 }
 
+// ConfigureRegistries sets up the registries and restarts CRIO
+func (r *CRIO) ConfigureRegistries() error {
+	if err := configureCRIORegistries(r.Runner, r.RegistryMirror, r.Registries); err != nil {
+		return err
+	}
+	return r.Init.Restart("crio")
+}
+
 // Disable idempotently disables CRIO on a host
 func (r *CRIO) Disable() error {
 	return r.Init.ForceStop("crio")
@@ -177,13 +253,12 @@ func (r *CRIO) ImageExists(name string, sha string) bool {
 }
 
 // ListImages returns a list of images managed by this container runtime
-func (r *CRIO) ListImages(ListImagesOptions) ([]string, error) {
-	c := exec.Command("sudo", "podman", "images", "--format", "{{.Repository}}:{{.Tag}}")
-	rr, err := r.Runner.RunCmd(c)
+func (r *CRIO) ListImages(o ListImagesOptions) ([]ListImage, error) {
+	imgs, err := listCRIImages(r.Runner)
 	if err != nil {
-		return nil, errors.Wrapf(err, "podman images")
+		return nil, err
 	}
-	return strings.Split(strings.TrimSpace(rr.Stdout.String()), "\n"), nil
+	return filterImages(imgs, o)
 }
 
 // LoadImage loads an image into this runtime
@@ -211,6 +286,26 @@ func (r *CRIO) SaveImage(name string, path string) error {
 	return nil
 }
 
+// TagImage tags an image in this runtime
+func (r *CRIO) TagImage(source string, target string) error {
+	klog.Infof("Tagging image %s: %s", source, target)
+	c := exec.Command("sudo", "podman", "tag", source, target)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "crio tag image")
+	}
+	return nil
+}
+
+// PushImage pushes an image
+func (r *CRIO) PushImage(name string) error {
+	klog.Infof("Pushing image: %s", name)
+	c := exec.Command("sudo", "podman", "push", name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "crio push image")
+	}
+	return nil
+}
+
 // RemoveImage removes a image
 func (r *CRIO) RemoveImage(name string) error {
 	return removeCRIImage(r.Runner, name)
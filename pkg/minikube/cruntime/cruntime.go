@@ -20,6 +20,8 @@ package cruntime
 import (
 	"fmt"
 	"os/exec"
+	"path"
+	"strings"
 
 	"github.com/blang/semver/v4"
 	"github.com/pkg/errors"
@@ -65,6 +67,8 @@ type CommandRunner interface {
 	WaitCmd(sc *command.StartedCmd) (*command.RunResult, error)
 	// Copy is a convenience method that runs a command to copy a file
 	Copy(assets.CopyableFile) error
+	// CopyFrom is a convenience method that runs a command to copy a file back
+	CopyFrom(assets.WriteableFile) error
 	// Remove is a convenience method that runs a command to remove a file
 	Remove(assets.CopyableFile) error
 }
@@ -88,6 +92,8 @@ type Manager interface {
 
 	// CGroupDriver returns cgroup driver ("cgroupfs" or "systemd")
 	CGroupDriver() (string, error)
+	// ConfigureRegistries applies the registries config to the runtime, restarting it if needed
+	ConfigureRegistries() error
 	// KubeletOptions returns kubelet options for a runtime.
 	KubeletOptions() map[string]string
 	// SocketPath returns the path to the socket file for a given runtime
@@ -101,11 +107,15 @@ type Manager interface {
 	BuildImage(string, string, string, bool, []string, []string) error
 	// Save an image from the runtime on a host
 	SaveImage(string, string) error
+	// Tag an image
+	TagImage(string, string) error
+	// Push an image from the runtime to the container registry
+	PushImage(string) error
 
 	// ImageExists takes image name and image sha checks if an it exists
 	ImageExists(string, string) bool
 	// ListImages returns a list of images managed by this container runtime
-	ListImages(ListImagesOptions) ([]string, error)
+	ListImages(ListImagesOptions) ([]ListImage, error)
 
 	// RemoveImage remove image based on name
 	RemoveImage(string) error
@@ -144,6 +154,10 @@ type Config struct {
 	KubernetesVersion semver.Version
 	// InsecureRegistry list of insecure registries
 	InsecureRegistry []string
+	// RegistryMirror list of mirrors of Docker Hub, tried in order before it
+	RegistryMirror []string
+	// Registries the mirrors, credentials and CA certificates of registries, the mirrors of Docker Hub are the ones of RegistryMirror
+	Registries []config.Registry
 }
 
 // ListContainersOptions are the options to use for listing containers
@@ -158,6 +172,66 @@ type ListContainersOptions struct {
 
 // ListImagesOptions are the options to use for listing images
 type ListImagesOptions struct {
+	// Reference is a pattern to filter the image references by, for example "k8s.gcr.io/*"
+	Reference string
+	// Dangling lists only the images without any tags
+	Dangling bool
+}
+
+// ListImage is an image managed by a container runtime
+type ListImage struct {
+	// ID is the image ID
+	ID string `json:"id" yaml:"id"`
+	// RepoTags are the references tagging the image
+	RepoTags []string `json:"repoTags" yaml:"repoTags"`
+	// RepoDigests are the references to the image by digest
+	RepoDigests []string `json:"repoDigests" yaml:"repoDigests"`
+	// Size is the size of the image in bytes
+	Size uint64 `json:"size" yaml:"size"`
+}
+
+// filterImages returns the images matching the list options
+func filterImages(images []ListImage, o ListImagesOptions) ([]ListImage, error) {
+	filtered := []ListImage{}
+	for _, img := range images {
+		if o.Dangling && len(img.RepoTags) > 0 {
+			continue
+		}
+		if o.Reference != "" {
+			match, err := matchReference(img, o.Reference)
+			if err != nil {
+				return nil, err
+			}
+			if !match {
+				continue
+			}
+		}
+		filtered = append(filtered, img)
+	}
+	return filtered, nil
+}
+
+// matchReference returns whether any reference to the image matches the pattern,
+// either with or without its tag, for example "docker.io/library/busybox:latest" or "docker.io/library/busybox"
+func matchReference(img ListImage, pattern string) (bool, error) {
+	for _, ref := range append(img.RepoTags, img.RepoDigests...) {
+		repo := ref
+		if i := strings.LastIndex(ref, "@"); i >= 0 {
+			repo = ref[:i]
+		} else if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+			repo = ref[:i]
+		}
+		for _, r := range []string{ref, repo} {
+			match, err := path.Match(pattern, r)
+			if err != nil {
+				return false, errors.Wrapf(err, "invalid reference pattern %q", pattern)
+			}
+			if match {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
 }
 
 // ErrContainerRuntimeNotRunning is thrown when container runtime is not running
@@ -200,6 +274,7 @@ func New(c Config) (Manager, error) {
 			KubernetesVersion: c.KubernetesVersion,
 			Init:              sm,
 			UseCRI:            (c.Socket != ""), // !dockershim
+			Registries:        c.Registries,
 		}, nil
 	case "crio", "cri-o":
 		return &CRIO{
@@ -208,6 +283,8 @@ func New(c Config) (Manager, error) {
 			ImageRepository:   c.ImageRepository,
 			KubernetesVersion: c.KubernetesVersion,
 			Init:              sm,
+			RegistryMirror:    c.RegistryMirror,
+			Registries:        c.Registries,
 		}, nil
 	case "containerd":
 		return &Containerd{
@@ -217,6 +294,8 @@ func New(c Config) (Manager, error) {
 			KubernetesVersion: c.KubernetesVersion,
 			Init:              sm,
 			InsecureRegistry:  c.InsecureRegistry,
+			RegistryMirror:    c.RegistryMirror,
+			Registries:        c.Registries,
 		}, nil
 	default:
 		return nil, fmt.Errorf("unknown runtime type: %q", c.Type)
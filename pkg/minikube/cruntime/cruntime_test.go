@@ -18,6 +18,7 @@ package cruntime
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os/exec"
 	"strings"
@@ -29,6 +30,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
 )
 
 func TestName(t *testing.T) {
@@ -236,6 +238,10 @@ func (f *FakeRunner) Copy(assets.CopyableFile) error {
 	return nil
 }
 
+func (f *FakeRunner) CopyFrom(assets.WriteableFile) error {
+	return nil
+}
+
 func (f *FakeRunner) Remove(assets.CopyableFile) error {
 	return nil
 }
@@ -290,6 +296,18 @@ func (f *FakeRunner) dockerInspect(args []string) (string, error) {
 		}
 		return "sha256:" + image, nil
 	}
+	if args[1] == "--format" && args[2] == "{{.Id}} {{.Size}}" {
+		sizes := map[string]string{
+			"sha256:69593048aa3acfee0f75f20b77acb549de2472063053f6730c4091b53f2dfb02": "1239748",
+			"sha256:80d28bedfe5dec59da9ebf8e6260224ac9008ab5c11dbbe16ee3ba3e4439ac2c": "682696",
+			"sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef": "9999871",
+		}
+		var lines []string
+		for _, id := range args[3:] {
+			lines = append(lines, id+" "+sizes[id])
+		}
+		return strings.Join(lines, "\n"), nil
+	}
 	return "", nil
 }
 
@@ -331,6 +349,9 @@ func (f *FakeRunner) docker(args []string, _ bool) (string, error) {
 	case "rmi":
 		return f.dockerRmi(args)
 
+	case "images":
+		return dockerImagesOutput, nil
+
 	case "inspect":
 		return f.dockerInspect(args)
 
@@ -448,6 +469,8 @@ func (f *FakeRunner) crictl(args []string, _ bool) (string, error) {
 			}
 			delete(f.images, id)
 		}
+	case "images":
+		return crictlImagesOutput, nil
 	}
 	return "", nil
 }
@@ -760,3 +783,172 @@ func TestContainerFunctions(t *testing.T) {
 		})
 	}
 }
+
+const dockerImagesOutput = `{"Containers":"N/A","CreatedAt":"2021-06-07 19:39:35 +0000 UTC","CreatedSince":"2 months ago","Digest":"sha256:930490f97e5b921535c153e0e7110d251134cc4b72bbb8133c6a5065cc68580d","ID":"sha256:69593048aa3acfee0f75f20b77acb549de2472063053f6730c4091b53f2dfb02","Repository":"busybox","SharedSize":"N/A","Size":"1.24MB","Tag":"latest","UniqueSize":"N/A","VirtualSize":"1.24MB"}
+{"Containers":"N/A","CreatedAt":"2021-06-07 19:39:35 +0000 UTC","CreatedSince":"2 months ago","Digest":"<none>","ID":"sha256:69593048aa3acfee0f75f20b77acb549de2472063053f6730c4091b53f2dfb02","Repository":"localhost:5000/busybox","SharedSize":"N/A","Size":"1.24MB","Tag":"test","UniqueSize":"N/A","VirtualSize":"1.24MB"}
+{"Containers":"N/A","CreatedAt":"2021-03-19 19:56:03 +0000 UTC","CreatedSince":"5 months ago","Digest":"sha256:6c3835cab3980f11b83277305d0d736051c32b17606f5ec59f1dda67c9ba3810","ID":"sha256:80d28bedfe5dec59da9ebf8e6260224ac9008ab5c11dbbe16ee3ba3e4439ac2c","Repository":"k8s.gcr.io/pause","SharedSize":"N/A","Size":"683kB","Tag":"3.4.1","UniqueSize":"N/A","VirtualSize":"683kB"}
+{"Containers":"N/A","CreatedAt":"2021-03-19 19:56:03 +0000 UTC","CreatedSince":"5 months ago","Digest":"<none>","ID":"sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef","Repository":"<none>","SharedSize":"N/A","Size":"10MB","Tag":"<none>","UniqueSize":"N/A","VirtualSize":"10MB"}
+`
+
+const crictlImagesOutput = `{
+  "images": [
+    {
+      "id": "sha256:69593048aa3acfee0f75f20b77acb549de2472063053f6730c4091b53f2dfb02",
+      "repoTags": [
+        "docker.io/library/busybox:latest",
+        "localhost:5000/busybox:test"
+      ],
+      "repoDigests": [
+        "docker.io/library/busybox@sha256:930490f97e5b921535c153e0e7110d251134cc4b72bbb8133c6a5065cc68580d"
+      ],
+      "size": "1239748",
+      "uid": null,
+      "username": ""
+    },
+    {
+      "id": "sha256:80d28bedfe5dec59da9ebf8e6260224ac9008ab5c11dbbe16ee3ba3e4439ac2c",
+      "repoTags": [
+        "k8s.gcr.io/pause:3.4.1"
+      ],
+      "repoDigests": [
+        "k8s.gcr.io/pause@sha256:6c3835cab3980f11b83277305d0d736051c32b17606f5ec59f1dda67c9ba3810"
+      ],
+      "size": "682696",
+      "uid": null,
+      "username": ""
+    },
+    {
+      "id": "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+      "repoTags": [],
+      "repoDigests": [],
+      "size": "9999871",
+      "uid": null,
+      "username": ""
+    }
+  ]
+}`
+
+func TestListImages(t *testing.T) {
+	busybox := ListImage{
+		ID:          "sha256:69593048aa3acfee0f75f20b77acb549de2472063053f6730c4091b53f2dfb02",
+		RepoTags:    []string{"docker.io/library/busybox:latest", "localhost:5000/busybox:test"},
+		RepoDigests: []string{"docker.io/library/busybox@sha256:930490f97e5b921535c153e0e7110d251134cc4b72bbb8133c6a5065cc68580d"},
+		Size:        1239748,
+	}
+	pause := ListImage{
+		ID:          "sha256:80d28bedfe5dec59da9ebf8e6260224ac9008ab5c11dbbe16ee3ba3e4439ac2c",
+		RepoTags:    []string{"k8s.gcr.io/pause:3.4.1"},
+		RepoDigests: []string{"k8s.gcr.io/pause@sha256:6c3835cab3980f11b83277305d0d736051c32b17606f5ec59f1dda67c9ba3810"},
+		Size:        682696,
+	}
+	dangling := ListImage{
+		ID:          "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		RepoTags:    []string{},
+		RepoDigests: []string{},
+		Size:        9999871,
+	}
+
+	var tests = []struct {
+		description string
+		opts        ListImagesOptions
+		want        []ListImage
+	}{
+		{"all", ListImagesOptions{}, []ListImage{busybox, pause, dangling}},
+		{"reference with tag", ListImagesOptions{Reference: "k8s.gcr.io/pause:*"}, []ListImage{pause}},
+		{"reference without tag", ListImagesOptions{Reference: "*/busybox"}, []ListImage{busybox}},
+		{"reference no match", ListImagesOptions{Reference: "k8s.gcr.io/coredns"}, []ListImage{}},
+		{"dangling", ListImagesOptions{Dangling: true}, []ListImage{dangling}},
+	}
+	for _, runtime := range []string{"docker", "crio", "containerd"} {
+		for _, tc := range tests {
+			t.Run(runtime+"/"+tc.description, func(t *testing.T) {
+				r, err := New(Config{Type: runtime, Runner: NewFakeRunner(t)})
+				if err != nil {
+					t.Fatalf("New(%s): %v", runtime, err)
+				}
+				got, err := r.ListImages(tc.opts)
+				if err != nil {
+					t.Fatalf("ListImages: %v", err)
+				}
+				if diff := cmp.Diff(tc.want, got); diff != "" {
+					t.Errorf("ListImages(%+v) unexpected results, diff (-want +got): %s", tc.opts, diff)
+				}
+			})
+		}
+	}
+}
+
+func TestCRIOMirrorsConfig(t *testing.T) {
+	regs := []config.Registry{
+		{Host: "docker.io", Mirrors: []string{"https://ignored.example.com"}},
+		{Host: "quay.io", Mirrors: []string{"https://quay-mirror.example.com/quay"}},
+		{Host: "registry.example.com:5000", Username: "user", Password: "pass"},
+	}
+	got := crioMirrorsConfig([]string{"http://host.minikube.internal:5050", "https://mirror.gcr.io/"}, regs)
+	want := `[[registry]]
+prefix = "docker.io"
+location = "docker.io"
+
+[[registry.mirror]]
+location = "host.minikube.internal:5050"
+insecure = true
+
+[[registry.mirror]]
+location = "mirror.gcr.io"
+insecure = false
+
+[[registry]]
+prefix = "quay.io"
+location = "quay.io"
+
+[[registry.mirror]]
+location = "quay-mirror.example.com/quay"
+insecure = false
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("crioMirrorsConfig diff (-want +got):\n%s", diff)
+	}
+	if got := crioMirrorsConfig(nil, regs[2:]); got != "" {
+		t.Errorf("crioMirrorsConfig without mirrors = %q; want empty", got)
+	}
+}
+
+func TestContainerdRegistryConfigs(t *testing.T) {
+	regs := []config.Registry{
+		{Host: "docker.io", Username: "user", Password: "pass"},
+		{Host: "registry.example.com:5000", Mirrors: []string{"https://mirror.example.com/v2"}, Token: "token", CACert: "CA"},
+	}
+	want := []containerdRegistryConfig{
+		{Host: "mirror.example.com", CAFile: "/etc/containerd/certs.d/mirror.example.com/minikube-ca.crt"},
+		{Host: "registry-1.docker.io", Username: "user", Password: "pass"},
+		{Host: "registry.example.com:5000", CAFile: "/etc/containerd/certs.d/registry.example.com:5000/minikube-ca.crt", IdentityToken: "token"},
+	}
+	if diff := cmp.Diff(want, containerdRegistryConfigs(regs)); diff != "" {
+		t.Errorf("containerdRegistryConfigs diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestAuthConfig(t *testing.T) {
+	regs := []config.Registry{
+		{Host: "docker.io", Username: "user", Password: "pass"},
+		{Host: "quay.io", Mirrors: []string{"https://quay-mirror.example.com"}},
+		{Host: "registry.example.com", Token: "token"},
+	}
+	b, err := authConfig(regs, "https://index.docker.io/v1/")
+	if err != nil {
+		t.Fatalf("authConfig: %v", err)
+	}
+	var got struct {
+		Auths map[string]authEntry
+	}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("unmarshal %s: %v", b, err)
+	}
+	want := map[string]authEntry{
+		"https://index.docker.io/v1/": {Auth: "dXNlcjpwYXNz"},
+		"registry.example.com":        {IdentityToken: "token"},
+	}
+	if diff := cmp.Diff(want, got.Auths); diff != "" {
+		t.Errorf("authConfig diff (-want +got):\n%s", diff)
+	}
+}
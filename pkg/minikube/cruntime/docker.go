@@ -17,10 +17,12 @@ limitations under the License.
 package cruntime
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -64,6 +66,7 @@ type Docker struct {
 	KubernetesVersion semver.Version
 	Init              sysinit.Manager
 	UseCRI            bool
+	Registries        []config.Registry
 }
 
 // Name is a human readable name for Docker
@@ -128,6 +131,10 @@ func (r *Docker) Enable(disOthers, forceSystemd bool) error {
 		klog.ErrorS(err, "Failed to enable", "service", "docker.socket")
 	}
 
+	if err := r.ConfigureRegistries(); err != nil {
+		return err
+	}
+
 	if forceSystemd {
 		if err := r.forceSystemd(); err != nil {
 			return err
@@ -143,6 +150,26 @@ func (r *Docker) Enable(disOthers, forceSystemd bool) error {
 	return r.Init.Start("docker")
 }
 
+// ConfigureRegistries installs the CA certificates of the registries, which docker reads on each pull,
+// and their credentials, which the kubelet passes to docker. The mirrors of Docker Hub are options of the daemon.
+func (r *Docker) ConfigureRegistries() error {
+	for _, reg := range r.Registries {
+		if len(reg.Mirrors) > 0 && reg.Host != "docker.io" {
+			klog.Warningf("the docker runtime ignores the mirrors of %s, it only supports the mirrors of docker.io", reg.Host)
+		}
+	}
+	if err := writeRegistryCAs(r.Runner, dockerCertsDir, r.Registries); err != nil {
+		return err
+	}
+	if !hasCredentials(r.Registries) {
+		if _, err := r.Runner.RunCmd(exec.Command("sudo", "rm", "-f", kubeletAuthFile)); err != nil {
+			return errors.Wrap(err, "remove registry credentials")
+		}
+		return nil
+	}
+	return writeAuthConfig(r.Runner, kubeletAuthFile, r.Registries, "https://index.docker.io/v1/")
+}
+
 // Restart restarts Docker on a host
 func (r *Docker) Restart() error {
 	return r.Init.Restart("docker")
@@ -179,25 +206,103 @@ func (r *Docker) ImageExists(name string, sha string) bool {
 	return true
 }
 
+// dockerImage maps to 'docker images --format {{json .}}'
+type dockerImage struct {
+	ID         string `json:"ID"`
+	Repository string `json:"Repository"`
+	Tag        string `json:"Tag"`
+	Digest     string `json:"Digest"`
+}
+
 // ListImages returns a list of images managed by this container runtime
-func (r *Docker) ListImages(ListImagesOptions) ([]string, error) {
-	c := exec.Command("docker", "images", "--format", "{{.Repository}}:{{.Tag}}")
+func (r *Docker) ListImages(o ListImagesOptions) ([]ListImage, error) {
+	c := exec.Command("docker", "images", "--no-trunc", "--digests", "--format", "{{json .}}")
 	rr, err := r.Runner.RunCmd(c)
 	if err != nil {
 		return nil, errors.Wrapf(err, "docker images")
 	}
-	short := strings.Split(rr.Stdout.String(), "\n")
-	imgs := []string{}
-	for _, img := range short {
-		if img == "" {
+	lines := strings.Split(rr.Stdout.String(), "\n")
+	imgs := []ListImage{}
+	index := map[string]int{}
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
 			continue
 		}
-		img = addDockerIO(img)
-		imgs = append(imgs, img)
+		var di dockerImage
+		if err := json.Unmarshal([]byte(line), &di); err != nil {
+			return nil, errors.Wrapf(err, "parsing docker image %q", line)
+		}
+		// an image with several tags is listed once per tag
+		i, ok := index[di.ID]
+		if !ok {
+			imgs = append(imgs, ListImage{ID: di.ID, RepoTags: []string{}, RepoDigests: []string{}})
+			i = len(imgs) - 1
+			index[di.ID] = i
+		}
+		if di.Repository == "<none>" {
+			continue
+		}
+		repo := addDockerIO(di.Repository)
+		if di.Tag != "<none>" {
+			imgs[i].RepoTags = appendUnique(imgs[i].RepoTags, repo+":"+di.Tag)
+		}
+		if di.Digest != "<none>" && di.Digest != "" {
+			imgs[i].RepoDigests = appendUnique(imgs[i].RepoDigests, repo+"@"+di.Digest)
+		}
 	}
+	imgs, err = filterImages(imgs, o)
+	if err != nil {
+		return nil, err
+	}
+	r.setImageSizes(imgs)
 	return imgs, nil
 }
 
+// setImageSizes sets the sizes of the images in bytes, which 'docker images' rounds
+func (r *Docker) setImageSizes(imgs []ListImage) {
+	if len(imgs) == 0 {
+		return
+	}
+	args := []string{"image", "inspect", "--format", "{{.Id}} {{.Size}}"}
+	for _, img := range imgs {
+		args = append(args, img.ID)
+	}
+	// the images removed since they were listed are missing from the output
+	rr, err := r.Runner.RunCmd(exec.Command("docker", args...))
+	if err != nil {
+		klog.Warningf("failed to inspect the sizes of the images: %v", err)
+	}
+	if rr == nil {
+		return
+	}
+	sizes := map[string]uint64{}
+	for _, line := range strings.Split(rr.Stdout.String(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		size, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			klog.Warningf("failed to parse size %q of image %s: %v", fields[1], fields[0], err)
+			continue
+		}
+		sizes[fields[0]] = size
+	}
+	for i := range imgs {
+		imgs[i].Size = sizes[imgs[i].ID]
+	}
+}
+
+// appendUnique appends s to the slice, unless it is already there
+func appendUnique(slice []string, s string) []string {
+	for _, v := range slice {
+		if v == s {
+			return slice
+		}
+	}
+	return append(slice, s)
+}
+
 // LoadImage loads an image into this runtime
 func (r *Docker) LoadImage(path string) error {
 	klog.Infof("Loading image: %s", path)
@@ -231,6 +336,26 @@ func (r *Docker) SaveImage(name string, path string) error {
 	return nil
 }
 
+// TagImage tags an image in this runtime
+func (r *Docker) TagImage(source string, target string) error {
+	klog.Infof("Tagging image %s: %s", source, target)
+	c := exec.Command("docker", "tag", source, target)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "tagimage docker.")
+	}
+	return nil
+}
+
+// PushImage pushes an image
+func (r *Docker) PushImage(name string) error {
+	klog.Infof("Pushing image: %s", name)
+	c := exec.Command("docker", "push", name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "push image docker.")
+	}
+	return nil
+}
+
 // RemoveImage removes a image
 func (r *Docker) RemoveImage(name string) error {
 	klog.Infof("Removing image: %s", name)
@@ -549,7 +674,7 @@ func dockerImagesPreloaded(runner command.Runner, images []string) bool {
 func addDockerIO(name string) string {
 	var reg, usr, img string
 	p := strings.SplitN(name, "/", 2)
-	if len(p) > 1 && strings.Contains(p[0], ".") {
+	if len(p) > 1 && (strings.ContainsAny(p[0], ".:") || p[0] == "localhost") {
 		reg = p[0]
 		img = p[1]
 	} else {
@@ -0,0 +1,151 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+const (
+	// DockerHub is the host of Docker Hub in the registries config
+	DockerHub = "docker.io"
+	// registryCAFile is the name of the CA certificates of a registry, in its directory of the certs.d directory of a runtime
+	registryCAFile = "minikube-ca.crt"
+	// the certs.d directories of the runtimes, with a directory of CA certificates per registry host
+	dockerCertsDir     = "/etc/docker/certs.d"
+	containerdCertsDir = "/etc/containerd/certs.d"
+	crioCertsDir       = "/etc/containers/certs.d"
+	// kubeletAuthFile is the docker config the kubelet passes the credentials of registries from, with the docker runtime
+	kubeletAuthFile = "/var/lib/kubelet/config.json"
+	// crioAuthFile is the global auth file of CRI-O
+	crioAuthFile = "/etc/crio/minikube-auth.json"
+)
+
+// MirrorHost returns the host, and port if any, of a mirror URL
+func MirrorHost(mirror string) string {
+	if i := strings.Index(mirror, "://"); i >= 0 {
+		mirror = mirror[i+3:]
+	}
+	if i := strings.Index(mirror, "/"); i >= 0 {
+		mirror = mirror[:i]
+	}
+	return mirror
+}
+
+// registryCAs returns the CA certificates of the registries by host: the ones of a registry are installed for it and its mirrors
+func registryCAs(regs []config.Registry) map[string]string {
+	cas := map[string]string{}
+	for _, r := range regs {
+		if r.CACert == "" {
+			continue
+		}
+		for _, host := range append([]string{r.Host}, r.Mirrors...) {
+			host = MirrorHost(host)
+			if cas[host] != "" && !strings.HasSuffix(cas[host], "\n") {
+				cas[host] += "\n"
+			}
+			cas[host] += r.CACert
+		}
+	}
+	return cas
+}
+
+// writeRegistryCAs installs the CA certificates of the registries in a certs.d directory, replacing the ones installed before
+func writeRegistryCAs(cr CommandRunner, dir string, regs []config.Registry) error {
+	cas := registryCAs(regs)
+	hosts := []string{}
+	for host := range cas {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	script := fmt.Sprintf("sudo rm -f %s/*/%s", dir, registryCAFile)
+	for _, host := range hosts {
+		d := path.Join(dir, host)
+		script += fmt.Sprintf(" && sudo mkdir -p %s && printf %%s \"%s\" | base64 -d | sudo tee %s >/dev/null", d, base64.StdEncoding.EncodeToString([]byte(cas[host])), path.Join(d, registryCAFile))
+	}
+	if _, err := cr.RunCmd(exec.Command("/bin/bash", "-c", script)); err != nil {
+		return errors.Wrapf(err, "install registry CA certificates in %s", dir)
+	}
+	return nil
+}
+
+// authEntry contains the credentials of a registry in a docker config
+type authEntry struct {
+	Auth          string `json:"auth,omitempty"`
+	IdentityToken string `json:"identitytoken,omitempty"`
+}
+
+// hasCredentials returns whether any of the registries has credentials
+func hasCredentials(regs []config.Registry) bool {
+	for _, r := range regs {
+		if r.Username != "" || r.Token != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// authConfig returns a docker config with the credentials of the registries, Docker Hub ones keyed by dockerHubKey
+func authConfig(regs []config.Registry, dockerHubKey string) ([]byte, error) {
+	auths := map[string]authEntry{}
+	for _, r := range regs {
+		if r.Username == "" && r.Token == "" {
+			continue
+		}
+		key := r.Host
+		if key == DockerHub {
+			key = dockerHubKey
+		}
+		e := authEntry{IdentityToken: r.Token}
+		if r.Username != "" {
+			e.Auth = base64.StdEncoding.EncodeToString([]byte(r.Username + ":" + r.Password))
+		}
+		auths[key] = e
+	}
+	return json.MarshalIndent(map[string]interface{}{"auths": auths}, "", "  ")
+}
+
+// writeAuthConfig writes the credentials of the registries to a docker config
+func writeAuthConfig(cr CommandRunner, file string, regs []config.Registry, dockerHubKey string) error {
+	b, err := authConfig(regs, dockerHubKey)
+	if err != nil {
+		return errors.Wrap(err, "marshal registry credentials")
+	}
+	return copySecret(cr, b, file)
+}
+
+// copySecret copies a file containing credentials to the node: unlike the commands of the runner, the copied content is not logged
+func copySecret(cr CommandRunner, b []byte, file string) error {
+	if _, err := cr.RunCmd(exec.Command("sudo", "mkdir", "-p", path.Dir(file))); err != nil {
+		return errors.Wrapf(err, "create %s", path.Dir(file))
+	}
+	if err := cr.Copy(assets.NewMemoryAssetTarget(b, file, "0600")); err != nil {
+		return errors.Wrapf(err, "copy %s", file)
+	}
+	return nil
+}
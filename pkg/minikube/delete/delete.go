@@ -59,6 +59,16 @@ func PossibleLeftOvers(ctx context.Context, cname string, driverName string) {
 		}
 	}
 
+	// the proxy containers forwarding ports to the nodes are not labeled with the profile, which would list them as one
+	pfs, err := oci.ListContainersByLabel(ctx, bin, fmt.Sprintf("%s=%s", oci.PortForwardLabelKey, cname))
+	if err == nil {
+		for _, c := range pfs {
+			if err := oci.RemoveContainer(bin, c); err != nil {
+				klog.Warningf("error removing port forward %q (might be okay): %v", c, err)
+			}
+		}
+	}
+
 	errs := oci.DeleteAllVolumesByLabel(ctx, bin, delLabel)
 	if errs != nil { // it will not error if there is nothing to delete
 		klog.Warningf("error deleting volumes (might be okay).\nTo see the list of volumes run: 'docker volume ls'\n:%v", errs)
@@ -0,0 +1,393 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diagnose collects the logs and state of a cluster into a bundle, to attach to bug reports.
+package diagnose
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/registry"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/version"
+)
+
+// errorsFile lists what could not be collected
+const errorsFile = "errors.txt"
+
+// Options are the options of the bundle
+type Options struct {
+	// Lines is the number of lines to collect from each journal and container log
+	Lines int
+}
+
+// bundle is a gzipped tarball, in which all files are redacted
+type bundle struct {
+	tw      *tar.Writer
+	created time.Time
+	// errs lists what could not be collected, the collection goes on after errors
+	errs []string
+}
+
+// add redacts and writes a file to the bundle
+func (b *bundle) add(name string, content []byte) error {
+	content = Redact(content)
+	h := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(content)),
+		ModTime: b.created,
+	}
+	if err := b.tw.WriteHeader(h); err != nil {
+		return errors.Wrapf(err, "writing header of %s", name)
+	}
+	if _, err := b.tw.Write(content); err != nil {
+		return errors.Wrapf(err, "writing %s", name)
+	}
+	return nil
+}
+
+// failed records that something could not be collected
+func (b *bundle) failed(what string, err error) {
+	klog.Warningf("unable to collect %s: %v", what, err)
+	b.errs = append(b.errs, fmt.Sprintf("%s: %v", what, err))
+}
+
+// Bundle writes a gzipped tarball of the diagnostics of a cluster to w: for every running node the kubelet and
+// runtime journals and the logs of all containers, the description of the failed pods, the node conditions,
+// along with the cluster config, the last start log, the audit log and information about the driver.
+// Secrets are redacted. Failures to collect an item are listed in errors.txt, instead of failing the bundle.
+func Bundle(api libmachine.API, cc *config.ClusterConfig, o Options, w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	b := &bundle{tw: tar.NewWriter(gz), created: time.Now()}
+
+	if err := collect(api, cc, o, b); err != nil {
+		return err
+	}
+
+	if len(b.errs) > 0 {
+		if err := b.add(errorsFile, []byte(strings.Join(b.errs, "\n")+"\n")); err != nil {
+			return err
+		}
+	}
+	if err := b.tw.Close(); err != nil {
+		return errors.Wrap(err, "closing tarball")
+	}
+	return gz.Close()
+}
+
+// collect adds all the diagnostics to the bundle, it only returns an error if the bundle can not be written
+func collect(api libmachine.API, cc *config.ClusterConfig, o Options, b *bundle) error {
+	c, err := json.MarshalIndent(cc, "", "    ")
+	if err != nil {
+		b.failed("cluster config", err)
+	} else if err := b.add("cluster/config.json", c); err != nil {
+		return err
+	}
+
+	if err := addHostFiles(b); err != nil {
+		return err
+	}
+	if err := b.add("host/driver.txt", driverInfo(cc)); err != nil {
+		return err
+	}
+
+	cp, err := config.PrimaryControlPlane(cc)
+	if err != nil {
+		b.failed("primary control plane", err)
+	}
+	var cpRunner command.Runner
+	for _, n := range cc.Nodes {
+		r, err := collectNode(api, cc, n, o, b)
+		if err != nil {
+			return err
+		}
+		if n.Name == cp.Name && n.ControlPlane {
+			cpRunner = r
+		}
+	}
+
+	if cpRunner == nil {
+		b.failed("kubernetes state", fmt.Errorf("the primary control plane is not running"))
+		return nil
+	}
+	return collectKubernetes(cc, cpRunner, b)
+}
+
+// addHostFiles adds the last start log and the audit logs of the host
+func addHostFiles(b *bundle) error {
+	files := map[string]string{"host/last_start.txt": localpath.LastStartLog()}
+	audits, err := filepath.Glob(localpath.AuditLog() + "*")
+	if err != nil {
+		b.failed("audit log", err)
+	}
+	for _, a := range audits {
+		files[path.Join("host", filepath.Base(a))] = a
+	}
+
+	names := []string{}
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		c, err := ioutil.ReadFile(files[name])
+		if err != nil {
+			b.failed(name, err)
+			continue
+		}
+		if err := b.add(name, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// driverInfo describes the host, the version of minikube and the state of the driver
+func driverInfo(cc *config.ClusterConfig) []byte {
+	var w bytes.Buffer
+	fmt.Fprintf(&w, "minikube version: %s\n", version.GetVersion())
+	fmt.Fprintf(&w, "commit: %s\n", version.GetGitCommitID())
+	fmt.Fprintf(&w, "host: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&w, "driver: %s\n", cc.Driver)
+
+	if def := registry.Driver(cc.Driver); !def.Empty() && def.Status != nil {
+		st := def.Status()
+		fmt.Fprintf(&w, "driver status: installed=%t healthy=%t running=%t\n", st.Installed, st.Healthy, st.Running)
+		if st.Error != nil {
+			fmt.Fprintf(&w, "driver error: %v\n", st.Error)
+		}
+	}
+	if driver.IsKIC(cc.Driver) {
+		info, err := oci.DaemonInfo(cc.Driver)
+		if err != nil {
+			fmt.Fprintf(&w, "%s info error: %v\n", cc.Driver, err)
+		}
+		fmt.Fprintf(&w, "%s info: %+v\n", cc.Driver, info)
+	}
+	return w.Bytes()
+}
+
+// collectNode adds the status, journals and container logs of a node, it returns the command runner of the node if it is running
+func collectNode(api libmachine.API, cc *config.ClusterConfig, n config.Node, o Options, b *bundle) (command.Runner, error) {
+	m := config.MachineName(*cc, n)
+	dir := path.Join("nodes", m)
+
+	st, err := machine.Status(api, m)
+	if err != nil {
+		b.failed(m+" status", err)
+		return nil, nil
+	}
+	if err := b.add(path.Join(dir, "status.txt"), []byte(st+"\n")); err != nil {
+		return nil, err
+	}
+	if driver.IsKIC(cc.Driver) {
+		if err := addCommand(b, exec.Command(cc.Driver, "inspect", m), path.Join(dir, "inspect.json")); err != nil {
+			return nil, err
+		}
+	}
+	if st != state.Running.String() {
+		return nil, nil
+	}
+
+	h, err := machine.LoadHost(api, m)
+	if err != nil {
+		b.failed(m+" host", err)
+		return nil, nil
+	}
+	runner, err := machine.CommandRunner(h)
+	if err != nil {
+		b.failed(m+" command runner", err)
+		return nil, nil
+	}
+	cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+	if err != nil {
+		b.failed(m+" container runtime", err)
+		return runner, nil
+	}
+
+	cmds := map[string]string{
+		"kubelet.log":          fmt.Sprintf("sudo journalctl -u kubelet -n %d", o.Lines),
+		cr.Name() + ".log":     cr.SystemLogCmd(o.Lines),
+		"dmesg.log":            fmt.Sprintf("sudo dmesg -PH -L=never | tail -n %d", o.Lines),
+		"container-status.txt": cruntime.ContainerStatusCommand(),
+	}
+	ids, err := cr.ListContainers(cruntime.ListContainersOptions{State: cruntime.All})
+	if err != nil {
+		b.failed(m+" containers", err)
+	}
+	for _, id := range ids {
+		cmds[path.Join("containers", id+".log")] = cr.ContainerLogCmd(id, o.Lines, false)
+	}
+
+	names := []string{}
+	for name := range cmds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := addRunnerCommand(b, runner, cmds[name], path.Join(dir, name)); err != nil {
+			return nil, err
+		}
+	}
+	return runner, nil
+}
+
+// collectKubernetes adds the node conditions and the description of the failed pods, using kubectl on the control plane
+func collectKubernetes(cc *config.ClusterConfig, r command.Runner, b *bundle) error {
+	kubectl := []string{"sudo", kapi.KubectlBinaryPath(cc.KubernetesConfig.KubernetesVersion), "--kubeconfig=" + path.Join(vmpath.GuestPersistentDir, "kubeconfig")}
+	get := func(args ...string) ([]byte, error) {
+		rr, err := r.RunCmd(exec.Command(kubectl[0], append(kubectl[1:], args...)...))
+		if err != nil {
+			return nil, err
+		}
+		return rr.Stdout.Bytes(), nil
+	}
+
+	nodes := &v1.NodeList{}
+	out, err := get("get", "nodes", "-o", "json")
+	if err == nil {
+		err = json.Unmarshal(out, nodes)
+	}
+	if err != nil {
+		b.failed("node conditions", err)
+	} else {
+		c, err := json.MarshalIndent(nodeConditions(nodes), "", "    ")
+		if err != nil {
+			b.failed("node conditions", err)
+		} else if err := b.add("kubernetes/node-conditions.json", c); err != nil {
+			return err
+		}
+	}
+
+	pods := &v1.PodList{}
+	out, err = get("get", "pods", "--all-namespaces", "-o", "json")
+	if err == nil {
+		err = json.Unmarshal(out, pods)
+	}
+	if err != nil {
+		b.failed("pods", err)
+		return nil
+	}
+	for _, p := range failedPods(pods) {
+		cmd := strings.Join(append(kubectl, "describe", "pod", "-n", p.Namespace, p.Name), " ")
+		if err := addRunnerCommand(b, r, cmd, path.Join("kubernetes", "failed-pods", p.Namespace+"_"+p.Name+".txt")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nodeConditions returns the conditions of each node, as verified by kverify
+func nodeConditions(nodes *v1.NodeList) map[string][]kverify.NodeCondition {
+	conditions := map[string][]kverify.NodeCondition{}
+	for _, n := range nodes.Items {
+		conditions[n.Name] = []kverify.NodeCondition{}
+		for _, c := range n.Status.Conditions {
+			conditions[n.Name] = append(conditions[n.Name], kverify.NodeCondition{Type: c.Type, Status: c.Status, Reason: c.Reason, Message: c.Message})
+		}
+	}
+	return conditions
+}
+
+// failedPods returns the pods which failed, are not scheduled or started yet, or have containers which are not ready
+func failedPods(pods *v1.PodList) []v1.Pod {
+	failed := []v1.Pod{}
+	for _, p := range pods.Items {
+		switch p.Status.Phase {
+		case v1.PodSucceeded:
+			continue
+		case v1.PodRunning:
+			ready := true
+			for _, cs := range p.Status.ContainerStatuses {
+				ready = ready && cs.Ready
+			}
+			if ready {
+				continue
+			}
+		}
+		failed = append(failed, p)
+	}
+	return failed
+}
+
+// addRunnerCommand adds the output of a command run on a node, the output is added even if the command fails
+func addRunnerCommand(b *bundle, r command.Runner, cmd string, name string) error {
+	var o bytes.Buffer
+	c := exec.Command("/bin/bash", "-c", cmd)
+	c.Stdout = &o
+	c.Stderr = &o
+	if _, err := r.RunCmd(c); err != nil {
+		b.failed(name, err)
+	}
+	return b.add(name, o.Bytes())
+}
+
+// addCommand adds the output of a command run on the host, the output is added even if the command fails
+func addCommand(b *bundle, c *exec.Cmd, name string) error {
+	o, err := c.CombinedOutput()
+	if err != nil {
+		b.failed(name, err)
+	}
+	return b.add(name, o)
+}
+
+// DefaultFile returns the default name of the bundle of a profile
+func DefaultFile(profile string) string {
+	return fmt.Sprintf("%s-diagnose-%s.tar.gz", profile, time.Now().Format("20060102150405"))
+}
+
+// Write writes the bundle of a cluster to a file
+func Write(api libmachine.API, cc *config.ClusterConfig, o Options, file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return errors.Wrap(err, "creating bundle")
+	}
+	if err := Bundle(api, cc, o, f); err != nil {
+		f.Close()
+		os.Remove(file)
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnose
+
+import (
+	"regexp"
+)
+
+// redacted replaces the secrets found in the bundle
+const redacted = "REDACTED"
+
+var (
+	// privateKeyRe matches PEM encoded private keys
+	privateKeyRe = regexp.MustCompile(`(?s)-----BEGIN ([A-Z ]*)PRIVATE KEY-----.*?-----END ([A-Z ]*)PRIVATE KEY-----`)
+	// userinfoRe matches the credentials of URLs, such as proxy URLs
+	userinfoRe = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://)[^/\s:@]+:[^/\s@]+@`)
+	// bearerRe matches bearer tokens of HTTP authorization headers
+	bearerRe = regexp.MustCompile(`(?i)(bearer\s+)[a-zA-Z0-9._~+/=-]+`)
+	// secretValueRe matches key=value and key: value pairs whose key names a secret, the value may be quoted
+	secretValueRe = regexp.MustCompile(`(?i)([a-z0-9_.-]*(?:password|passwd|secret|token|api[-_]?key|access[-_]?key|private[-_]?key|client-key-data|credentials)[a-z0-9_.-]*"?\s*[=:]\s*"?)[^\s",}]+`)
+	// secretFlagRe matches the values of flags which name a secret passed as --flag value, such as the token of logged
+	// "kubeadm join" commands. Values starting with a dash are the next flag.
+	secretFlagRe = regexp.MustCompile(`(?i)(--[a-z0-9-]*(?:password|passwd|secret|token|api-key|access-key|private-key|credentials)[a-z0-9-]*\s+"?)[^\s"',}\]-][^\s"',}\]]*`)
+	// base64FileRe matches the base64 encoded content of the files written by logged commands, which may contain credentials
+	base64FileRe = regexp.MustCompile(`(printf %s \\?")[A-Za-z0-9+/=]+(\\?" \| base64 -d)`)
+)
+
+// Redact replaces the secrets found in b: private keys, credentials of URLs, bearer tokens, the content of files
+// written by commands, and the values of keys and flags which name a secret, such as passwords and tokens.
+func Redact(b []byte) []byte {
+	b = privateKeyRe.ReplaceAll(b, []byte("-----BEGIN ${1}PRIVATE KEY-----"+redacted+"-----END ${2}PRIVATE KEY-----"))
+	b = userinfoRe.ReplaceAll(b, []byte("${1}"+redacted+"@"))
+	b = bearerRe.ReplaceAll(b, []byte("${1}"+redacted))
+	b = base64FileRe.ReplaceAll(b, []byte("${1}"+redacted+"${2}"))
+	b = secretFlagRe.ReplaceAll(b, []byte("${1}"+redacted))
+	return secretValueRe.ReplaceAll(b, []byte("${1}"+redacted))
+}
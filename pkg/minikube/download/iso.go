@@ -69,6 +69,15 @@ func fileURI(path string) string {
 	return "file://" + filepath.ToSlash(path)
 }
 
+// LocalISOPath returns where the ISO of a remote URL is stored locally
+func LocalISOPath(isoURL string) (string, error) {
+	u, err := url.Parse(isoURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "url.parse %q", isoURL)
+	}
+	return localISOPath(u), nil
+}
+
 // localISOPath returns where an ISO should be stored locally
 func localISOPath(u *url.URL) string {
 	if u.Scheme == fileScheme {
@@ -26,7 +26,8 @@ import (
 	"k8s.io/minikube/pkg/minikube/constants"
 )
 
-// ControlPlaneEndpoint returns the location where callers can reach this cluster
+// ControlPlaneEndpoint returns the location where callers can reach this cluster.
+// Drivers which need port forwarding only reach the primary control plane, "minikube start" refuses to create HA clusters with them.
 func ControlPlaneEndpoint(cc *config.ClusterConfig, cp *config.Node, driverName string) (string, net.IP, int, error) {
 	if NeedsPortForward(driverName) {
 		port, err := oci.ForwardedPort(cc.Driver, cc.Name, cp.Port)
@@ -47,20 +48,45 @@ func ControlPlaneEndpoint(cc *config.ClusterConfig, cp *config.Node, driverName
 		return hostname, ip, port, err
 	}
 
+	// the control planes of HA clusters are reached through their virtual IP
+	addr := cp.IP
+	if config.IsHA(*cc) {
+		addr = cc.KubernetesConfig.APIServerHAVIP
+	}
+
 	// https://github.com/kubernetes/minikube/issues/3878
-	hostname := cp.IP
+	hostname := addr
 	if cc.KubernetesConfig.APIServerName != constants.APIServerName {
 		hostname = cc.KubernetesConfig.APIServerName
 	}
-	ip := net.ParseIP(cp.IP)
+	ip := net.ParseIP(addr)
 	if ip == nil {
-		return hostname, ip, cp.Port, fmt.Errorf("failed to parse ip for %q", cp.IP)
+		return hostname, ip, cp.Port, fmt.Errorf("failed to parse ip for %q", addr)
 	}
 	return hostname, ip, cp.Port, nil
 }
 
+// NodeEndpoint returns the location where callers can reach the API server of a control-plane node,
+// rather than the virtual IP of a HA cluster
+func NodeEndpoint(cc *config.ClusterConfig, n *config.Node, driverName string) (string, net.IP, int, error) {
+	if NeedsPortForward(driverName) {
+		port, err := oci.ForwardedPort(cc.Driver, config.MachineName(*cc, *n), n.Port)
+		hostname := oci.DaemonHost(driverName)
+		return hostname, net.ParseIP(hostname), port, err
+	}
+
+	ip := net.ParseIP(n.IP)
+	if ip == nil {
+		return n.IP, ip, n.Port, fmt.Errorf("failed to parse ip for %q", n.IP)
+	}
+	return n.IP, ip, n.Port, nil
+}
+
 // AutoPauseProxyEndpoint returns the endpoint for the auto-pause (reverse proxy to api-sever)
 func AutoPauseProxyEndpoint(cc *config.ClusterConfig, cp *config.Node, driverName string) (string, net.IP, int, error) {
 	cp.Port = constants.AutoPauseProxyPort
-	return ControlPlaneEndpoint(cc, cp, driverName)
+	// the proxy only runs on the primary control plane, which may not hold the virtual IP
+	primary := *cc
+	primary.KubernetesConfig.APIServerHAVIP = ""
+	return ControlPlaneEndpoint(&primary, cp, driverName)
 }
@@ -23,6 +23,7 @@ import (
 	"runtime"
 
 	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/audit"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
 	"k8s.io/minikube/pkg/minikube/style"
@@ -64,11 +65,13 @@ func Message(r reason.Kind, format string, args ...out.V) {
 		out.Error(r, "Exiting due to {{.fatal_code}}: {{.fatal_msg}}", args...)
 	}
 
+	audit.LogExit(r.ExitCode, r.ID)
 	Code(r.ExitCode)
 }
 
 // Code will exit with a code
 func Code(code int) {
+	audit.LogExit(code, "")
 	if shell {
 		out.Output(os.Stdout, fmt.Sprintf("false exit code %d\n", code))
 	}
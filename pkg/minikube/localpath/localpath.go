@@ -62,6 +62,16 @@ func Profile(name string) string {
 	return filepath.Join(MiniPath(), "profiles", name)
 }
 
+// Snapshots returns the path to the snapshots of a profile
+func Snapshots(profile string) string {
+	return filepath.Join(MiniPath(), "snapshots", profile)
+}
+
+// Mounts returns the path to the pid and log files of the background mount servers of a profile
+func Mounts(profile string) string {
+	return filepath.Join(Profile(profile), "mounts")
+}
+
 // EventLog returns the path to a CloudEvents log
 // This log contains the transient state of minikube and the completed steps on start.
 func EventLog(name string) string {
@@ -74,6 +84,12 @@ func AuditLog() string {
 	return filepath.Join(MiniPath(), "logs", "audit.json")
 }
 
+// TraceLog returns the path to the trace log, written by the file tracer.
+// This log contains a span per line, for the steps of minikube start.
+func TraceLog() string {
+	return filepath.Join(MiniPath(), "logs", "trace.json")
+}
+
 // LastStartLog returns the path to the last start log.
 func LastStartLog() string {
 	return filepath.Join(MiniPath(), "logs", "lastStart.txt")
@@ -19,9 +19,9 @@ package machine
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"path"
 	"path/filepath"
-	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -387,6 +387,236 @@ func PullImages(images []string, profile *config.Profile) error {
 
 	klog.Infof("succeeded pulling to: %s", strings.Join(succeeded, " "))
 	klog.Infof("failed pulling to: %s", strings.Join(failed, " "))
+	if len(failed) > 0 {
+		return fmt.Errorf("failed pulling images to: %s", strings.Join(failed, " "))
+	}
+	if len(succeeded) == 0 {
+		return fmt.Errorf("no running node in profile %s to pull images to", pName)
+	}
+	return nil
+}
+
+// SaveImage saves an image from a node in the profile to a tarball on the host.
+// If nodeName is empty, the image is saved from the first running node that has it.
+func SaveImage(img string, dst string, profile *config.Profile, nodeName string) error {
+	api, err := NewAPIClient()
+	if err != nil {
+		return errors.Wrap(err, "error creating api client")
+	}
+	defer api.Close()
+
+	pName := profile.Name
+
+	c, err := config.Load(pName)
+	if err != nil {
+		klog.Errorf("Failed to load profile %q: %v", pName, err)
+		return errors.Wrapf(err, "error loading config for profile :%v", pName)
+	}
+
+	failed := []string{}
+	for _, n := range c.Nodes {
+		m := config.MachineName(*c, n)
+		// accept the full machine name as well as just the node name
+		if nodeName != "" && n.Name != nodeName && m != nodeName {
+			continue
+		}
+
+		status, err := Status(api, m)
+		if err != nil {
+			klog.Warningf("error getting status for %s: %v", m, err)
+			continue
+		}
+
+		if status == state.Running.String() {
+			h, err := api.Load(m)
+			if err != nil {
+				klog.Warningf("Failed to load machine %q: %v", m, err)
+				continue
+			}
+			runner, err := CommandRunner(h)
+			if err != nil {
+				return err
+			}
+			if err := transferAndSaveImage(runner, c.KubernetesConfig, img, dst); err != nil {
+				failed = append(failed, m)
+				klog.Warningf("Failed to save image %s from %s: %v", img, m, err)
+				continue
+			}
+			klog.Infof("succeeded saving %s from: %s", img, m)
+			return nil
+		}
+	}
+
+	if len(failed) == 0 {
+		return fmt.Errorf("no running node to save image %s from", img)
+	}
+	return fmt.Errorf("failed saving image %s from: %s", img, strings.Join(failed, " "))
+}
+
+// transferAndSaveImage saves a single image and transfers it to the host
+func transferAndSaveImage(cr command.Runner, k8s config.KubernetesConfig, imgName string, dst string) error {
+	r, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: cr})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+
+	filename := localpath.SanitizeCacheDir(path.Base(imgName)) + ".tar"
+	src := path.Join(loadRoot, filename)
+
+	args := append([]string{"mkdir", "-p"}, loadRoot)
+	if _, err := cr.RunCmd(exec.Command("sudo", args...)); err != nil {
+		return err
+	}
+
+	if err := r.SaveImage(imgName, src); err != nil {
+		return errors.Wrapf(err, "%s save %s", r.Name(), imgName)
+	}
+	defer func() {
+		if _, err := cr.RunCmd(exec.Command("sudo", "rm", "-f", src)); err != nil {
+			klog.Warningf("error removing %s: %v", src, err)
+		}
+	}()
+
+	f, err := assets.NewWriteableAsset(dst, loadRoot, filename, "0644")
+	if err != nil {
+		return errors.Wrapf(err, "creating writeable file asset: %s", dst)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			klog.Warningf("error closing the file %s: %v", f.GetSourcePath(), err)
+		}
+	}()
+
+	if err := cr.CopyFrom(f); err != nil {
+		return errors.Wrap(err, "transferring saved image")
+	}
+
+	klog.Infof("Saved %s to %s", imgName, dst)
+	return nil
+}
+
+// TagImage tags an image on all nodes in profile
+func TagImage(source string, target string, profile *config.Profile) error {
+	api, err := NewAPIClient()
+	if err != nil {
+		return errors.Wrap(err, "error creating api client")
+	}
+	defer api.Close()
+
+	succeeded := []string{}
+	failed := []string{}
+
+	pName := profile.Name
+
+	c, err := config.Load(pName)
+	if err != nil {
+		klog.Errorf("Failed to load profile %q: %v", pName, err)
+		return errors.Wrapf(err, "error loading config for profile :%v", pName)
+	}
+
+	for _, n := range c.Nodes {
+		m := config.MachineName(*c, n)
+
+		status, err := Status(api, m)
+		if err != nil {
+			klog.Warningf("error getting status for %s: %v", m, err)
+			continue
+		}
+
+		if status == state.Running.String() {
+			h, err := api.Load(m)
+			if err != nil {
+				klog.Warningf("Failed to load machine %q: %v", m, err)
+				continue
+			}
+			runner, err := CommandRunner(h)
+			if err != nil {
+				return err
+			}
+			cr, err := cruntime.New(cruntime.Config{Type: c.KubernetesConfig.ContainerRuntime, Runner: runner})
+			if err != nil {
+				return errors.Wrap(err, "error creating container runtime")
+			}
+			if err := cr.TagImage(source, target); err != nil {
+				failed = append(failed, m)
+				klog.Warningf("Failed to tag image for profile %s %v", pName, err.Error())
+				continue
+			}
+			succeeded = append(succeeded, m)
+		}
+	}
+
+	klog.Infof("succeeded tagging in: %s", strings.Join(succeeded, " "))
+	klog.Infof("failed tagging in: %s", strings.Join(failed, " "))
+	if len(failed) > 0 {
+		return fmt.Errorf("failed tagging %s in: %s", source, strings.Join(failed, " "))
+	}
+	if len(succeeded) == 0 {
+		return fmt.Errorf("no running node in profile %s to tag %s in", pName, source)
+	}
+	return nil
+}
+
+// PushImages pushes images from the profile to the container registry.
+// Each image is pushed once, from the first running node that is able to push it.
+func PushImages(images []string, profile *config.Profile) error {
+	api, err := NewAPIClient()
+	if err != nil {
+		return errors.Wrap(err, "error creating api client")
+	}
+	defer api.Close()
+
+	pName := profile.Name
+
+	c, err := config.Load(pName)
+	if err != nil {
+		klog.Errorf("Failed to load profile %q: %v", pName, err)
+		return errors.Wrapf(err, "error loading config for profile :%v", pName)
+	}
+
+	pending := images
+	for _, n := range c.Nodes {
+		if len(pending) == 0 {
+			break
+		}
+		m := config.MachineName(*c, n)
+
+		status, err := Status(api, m)
+		if err != nil {
+			klog.Warningf("error getting status for %s: %v", m, err)
+			continue
+		}
+
+		if status == state.Running.String() {
+			h, err := api.Load(m)
+			if err != nil {
+				klog.Warningf("Failed to load machine %q: %v", m, err)
+				continue
+			}
+			runner, err := CommandRunner(h)
+			if err != nil {
+				return err
+			}
+			cr, err := cruntime.New(cruntime.Config{Type: c.KubernetesConfig.ContainerRuntime, Runner: runner})
+			if err != nil {
+				return errors.Wrap(err, "error creating container runtime")
+			}
+			remaining := []string{}
+			for _, img := range pending {
+				if err := cr.PushImage(img); err != nil {
+					klog.Warningf("Failed to push image %s from %s: %v", img, m, err)
+					remaining = append(remaining, img)
+					continue
+				}
+				klog.Infof("succeeded pushing %s from: %s", img, m)
+			}
+			pending = remaining
+		}
+	}
+
+	if len(pending) > 0 {
+		return fmt.Errorf("failed pushing images: %s", strings.Join(pending, " "))
+	}
 	return nil
 }
 
@@ -471,11 +701,12 @@ func RemoveImages(images []string, profile *config.Profile) error {
 	return nil
 }
 
-// ListImages lists images on all nodes in profile
-func ListImages(profile *config.Profile) error {
+// ListImages lists images on the running nodes in profile, keyed by machine name.
+// If nodeName is not empty, only the images on that node are listed.
+func ListImages(profile *config.Profile, nodeName string, opts cruntime.ListImagesOptions) (map[string][]cruntime.ListImage, error) {
 	api, err := NewAPIClient()
 	if err != nil {
-		return errors.Wrap(err, "error creating api client")
+		return nil, errors.Wrap(err, "error creating api client")
 	}
 	defer api.Close()
 
@@ -484,11 +715,16 @@ func ListImages(profile *config.Profile) error {
 	c, err := config.Load(pName)
 	if err != nil {
 		klog.Errorf("Failed to load profile %q: %v", pName, err)
-		return errors.Wrapf(err, "error loading config for profile :%v", pName)
+		return nil, errors.Wrapf(err, "error loading config for profile :%v", pName)
 	}
 
+	images := map[string][]cruntime.ListImage{}
 	for _, n := range c.Nodes {
 		m := config.MachineName(*c, n)
+		// accept the full machine name as well as just the node name
+		if nodeName != "" && n.Name != nodeName && m != nodeName {
+			continue
+		}
 
 		status, err := Status(api, m)
 		if err != nil {
@@ -504,21 +740,20 @@ func ListImages(profile *config.Profile) error {
 			}
 			runner, err := CommandRunner(h)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			cr, err := cruntime.New(cruntime.Config{Type: c.KubernetesConfig.ContainerRuntime, Runner: runner})
 			if err != nil {
-				return errors.Wrap(err, "error creating container runtime")
+				return nil, errors.Wrap(err, "error creating container runtime")
 			}
-			list, err := cr.ListImages(cruntime.ListImagesOptions{})
+			list, err := cr.ListImages(opts)
 			if err != nil {
 				klog.Warningf("Failed to list images for profile %s %v", pName, err.Error())
 				continue
 			}
-			sort.Sort(sort.Reverse(sort.StringSlice(list)))
-			fmt.Printf(strings.Join(list, "\n") + "\n")
+			images[m] = list
 		}
 	}
 
-	return nil
+	return images, nil
 }
@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestTransferAndSaveImage(t *testing.T) {
+	runner := command.NewFakeCommandRunner()
+	runner.SetCommandToOutput(map[string]string{
+		"sudo mkdir -p /var/lib/minikube/images":                                    "",
+		"docker save busybox:latest -o /var/lib/minikube/images/busybox_latest.tar": "",
+		"sudo rm -f /var/lib/minikube/images/busybox_latest.tar":                    "",
+	})
+	runner.SetFileToContents(map[string]string{
+		"/var/lib/minikube/images/busybox_latest.tar": "image contents",
+	})
+
+	dst := filepath.Join(t.TempDir(), "busybox.tar")
+	k8s := config.KubernetesConfig{ContainerRuntime: "docker"}
+	if err := transferAndSaveImage(runner, k8s, "busybox:latest", dst); err != nil {
+		t.Fatalf("transferAndSaveImage: %v", err)
+	}
+
+	got, err := ioutil.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "image contents" {
+		t.Errorf("saved image = %q, want %q", got, "image contents")
+	}
+}
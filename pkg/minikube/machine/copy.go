@@ -0,0 +1,204 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+// CopyOptions are the options used when copying files to and from a node
+type CopyOptions struct {
+	// Mode overrides the permissions of the copied files (octal), the source permissions are kept if empty
+	Mode string
+	// Owner is the owner of the files copied to a node (as accepted by chown), root if empty
+	Owner string
+}
+
+// ValidateMode checks that the mode is a valid octal file mode
+func (o CopyOptions) ValidateMode() error {
+	if o.Mode == "" {
+		return nil
+	}
+	m, err := strconv.ParseUint(o.Mode, 8, 32)
+	if err != nil || m > 07777 {
+		return fmt.Errorf("invalid file mode %q, expected an octal value such as 0644", o.Mode)
+	}
+	return nil
+}
+
+// permissions returns the permissions to copy a file with
+func (o CopyOptions) permissions(mode os.FileMode) string {
+	if o.Mode != "" {
+		return o.Mode
+	}
+	return fmt.Sprintf("%04o", mode.Perm())
+}
+
+// CopyToNode copies the host file or directory at src to the path dst on the node, recursing into directories
+func CopyToNode(r command.Runner, src string, dst string, opts CopyOptions) error {
+	if err := opts.ValidateMode(); err != nil {
+		return err
+	}
+
+	type copyFile struct {
+		src  string
+		dst  string
+		mode os.FileMode
+	}
+	var files []copyFile
+	dirs := map[string]bool{}
+	err := filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := path.Join(dst, filepath.ToSlash(rel))
+		if info.IsDir() {
+			dirs[target] = true
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			klog.Warningf("skipping %s: not a regular file", p)
+			return nil
+		}
+		dirs[path.Dir(target)] = true
+		files = append(files, copyFile{src: p, dst: target, mode: info.Mode()})
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "walking %s", src)
+	}
+
+	var mkdirs []string
+	for d := range dirs {
+		mkdirs = append(mkdirs, d)
+	}
+	sort.Strings(mkdirs)
+	if len(mkdirs) > 0 {
+		if _, err := r.RunCmd(exec.Command("sudo", append([]string{"mkdir", "-p"}, mkdirs...)...)); err != nil {
+			return errors.Wrap(err, "creating target directories")
+		}
+	}
+
+	var owned []string
+	for _, f := range files {
+		klog.Infof("copying %s to %s", f.src, f.dst)
+		fa, err := assets.NewFileAsset(f.src, path.Dir(f.dst), path.Base(f.dst), opts.permissions(f.mode))
+		if err != nil {
+			return errors.Wrap(err, "getting file asset")
+		}
+		err = r.Copy(fa)
+		if cerr := fa.Close(); cerr != nil {
+			klog.Warningf("error closing the file %s: %v", fa.GetSourcePath(), cerr)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "copying %s", f.src)
+		}
+		owned = append(owned, f.dst)
+	}
+
+	if opts.Owner == "" {
+		return nil
+	}
+	// only change the owner of the directories we copied, not of the parents of dst
+	for _, d := range mkdirs {
+		if d == dst || strings.HasPrefix(d, dst+"/") {
+			owned = append(owned, d)
+		}
+	}
+	if len(owned) == 0 {
+		return nil
+	}
+	if _, err := r.RunCmd(exec.Command("sudo", append([]string{"chown", opts.Owner}, owned...)...)); err != nil {
+		return errors.Wrapf(err, "changing owner to %s", opts.Owner)
+	}
+	return nil
+}
+
+// CopyFromNode copies the file or directory at src on the node to the host path dst, recursing into directories
+func CopyFromNode(r command.Runner, src string, dst string, opts CopyOptions) error {
+	if err := opts.ValidateMode(); err != nil {
+		return err
+	}
+	src = path.Clean(src)
+
+	// lists the regular files under src (or src itself), along with their permissions
+	rr, err := r.RunCmd(exec.Command("sudo", "find", src, "-type", "f", "-exec", "stat", "-c", "%a %n", "{}", "+"))
+	if err != nil {
+		return errors.Wrapf(err, "listing %s", src)
+	}
+
+	found := false
+	for _, line := range strings.Split(rr.Stdout.String(), "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), " ", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		mode, err := strconv.ParseUint(fields[0], 8, 32)
+		if err != nil {
+			klog.Warningf("skipping %s: unable to parse mode %q: %v", fields[1], fields[0], err)
+			continue
+		}
+		name := fields[1]
+		rel := strings.TrimPrefix(strings.TrimPrefix(name, src), "/")
+		target := dst
+		if rel != "" {
+			target = filepath.Join(dst, filepath.FromSlash(rel))
+		}
+		found = true
+
+		klog.Infof("copying %s to %s", name, target)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return errors.Wrapf(err, "creating directory for %s", target)
+		}
+		wa, err := assets.NewWriteableAsset(target, path.Dir(name), path.Base(name), opts.permissions(os.FileMode(mode)))
+		if err != nil {
+			return errors.Wrap(err, "getting writeable asset")
+		}
+		err = r.CopyFrom(wa)
+		if cerr := wa.Close(); cerr != nil {
+			klog.Warningf("error closing the file %s: %v", wa.GetSourcePath(), cerr)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "copying %s", name)
+		}
+		// the permissions are only applied by OpenFile when creating the file
+		perms, _ := strconv.ParseUint(opts.permissions(os.FileMode(mode)), 8, 32)
+		if err := os.Chmod(target, os.FileMode(perms)); err != nil {
+			klog.Warningf("unable to set the mode of %s: %v", target, err)
+		}
+	}
+	if !found {
+		return fmt.Errorf("no files found at %s", src)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+func TestCopyToNode(t *testing.T) {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		filepath.Join(src, "a.txt"):        "a",
+		filepath.Join(src, "sub", "b.txt"): "b",
+	}
+	for f, c := range files {
+		if err := ioutil.WriteFile(f, []byte(c), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runner := command.NewFakeCommandRunner()
+	runner.SetCommandToOutput(map[string]string{
+		"sudo mkdir -p /home/docker/dir /home/docker/dir/sub":                                                       "",
+		"sudo chown docker /home/docker/dir/a.txt /home/docker/dir/sub/b.txt /home/docker/dir /home/docker/dir/sub": "",
+	})
+	if err := CopyToNode(runner, src, "/home/docker/dir", CopyOptions{Owner: "docker"}); err != nil {
+		t.Fatalf("CopyToNode: %v", err)
+	}
+	for f, c := range files {
+		got, err := runner.GetFileToContents(f)
+		if err != nil {
+			t.Fatalf("%s was not copied: %v", f, err)
+		}
+		if got != c {
+			t.Errorf("copied %s = %q, want %q", f, got, c)
+		}
+	}
+
+	if err := CopyToNode(runner, src, "/home/docker/dir", CopyOptions{Mode: "999"}); err == nil {
+		t.Errorf("CopyToNode with an invalid mode expected an error, got nil")
+	}
+}
+
+func TestCopyFromNode(t *testing.T) {
+	runner := command.NewFakeCommandRunner()
+	runner.SetCommandToOutput(map[string]string{
+		`sudo find /etc/dir -type f -exec stat -c "%a %n" {} +`:  "600 /etc/dir/a.txt\n755 /etc/dir/sub/b.sh\n",
+		`sudo find /etc/file -type f -exec stat -c "%a %n" {} +`: "644 /etc/file\n",
+		`sudo find /etc/none -type f -exec stat -c "%a %n" {} +`: "",
+	})
+	runner.SetFileToContents(map[string]string{
+		"/etc/dir/a.txt":    "a",
+		"/etc/dir/sub/b.sh": "b",
+		"/etc/file":         "file",
+	})
+
+	dst := t.TempDir()
+	if err := CopyFromNode(runner, "/etc/dir/", filepath.Join(dst, "dir"), CopyOptions{}); err != nil {
+		t.Fatalf("CopyFromNode: %v", err)
+	}
+	if err := CopyFromNode(runner, "/etc/file", filepath.Join(dst, "file"), CopyOptions{Mode: "0640"}); err != nil {
+		t.Fatalf("CopyFromNode: %v", err)
+	}
+	if err := CopyFromNode(runner, "/etc/none", filepath.Join(dst, "none"), CopyOptions{}); err == nil {
+		t.Errorf("CopyFromNode of a missing path expected an error, got nil")
+	}
+
+	tests := []struct {
+		path     string
+		contents string
+		mode     os.FileMode
+	}{
+		{filepath.Join(dst, "dir", "a.txt"), "a", 0600},
+		{filepath.Join(dst, "dir", "sub", "b.sh"), "b", 0755},
+		{filepath.Join(dst, "file"), "file", 0640},
+	}
+	for _, tc := range tests {
+		got, err := ioutil.ReadFile(tc.path)
+		if err != nil {
+			t.Errorf("%s was not copied: %v", tc.path, err)
+			continue
+		}
+		if string(got) != tc.contents {
+			t.Errorf("copied %s = %q, want %q", tc.path, got, tc.contents)
+		}
+		info, err := os.Stat(tc.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != tc.mode {
+			t.Errorf("mode of %s = %o, want %o", tc.path, info.Mode().Perm(), tc.mode)
+		}
+	}
+}
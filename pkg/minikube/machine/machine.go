@@ -27,6 +27,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/provision"
+	"k8s.io/minikube/pkg/trace"
 )
 
 // Machine contains information about a machine
@@ -87,7 +88,10 @@ func LoadMachine(name string) (*Machine, error) {
 func provisionDockerMachine(h *host.Host) error {
 	klog.Infof("provisioning docker machine ...")
 	start := time.Now()
+	span := trace.SpanName(trace.Provision, h.Name)
+	trace.StartSpan(span)
 	defer func() {
+		trace.EndSpan(span)
 		klog.Infof("provisioned docker machine in %s", time.Since(start))
 	}()
 
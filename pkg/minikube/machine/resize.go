@@ -0,0 +1,199 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// minGrowSectors is the unpartitioned space of a VM disk, in 512 bytes sectors, from which the data partition is grown
+const minGrowSectors = 64 * 2048
+
+// Resize changes the CPUs, memory and disk size of the machine of node n to the ones of the node, which default to the ones of cc, in place.
+// It returns whether the machine was stopped, and has to be started for the new resources to take effect.
+func Resize(api libmachine.API, cc config.ClusterConfig, n config.Node) (bool, error) {
+	name := config.MachineName(cc, n)
+	cpus, memory := config.NodeCPUs(cc, n), config.NodeMemory(cc, n)
+	switch {
+	case driver.IsKIC(cc.Driver):
+		klog.Infof("updating the resources of %s: cpus=%d memory=%dmb", name, cpus, memory)
+		return false, oci.UpdateContainerResources(cc.Driver, name, strconv.Itoa(cpus), fmt.Sprintf("%dmb", memory))
+	case driver.IsKVM(cc.Driver):
+		h, err := api.Load(name)
+		if err != nil {
+			return false, errors.Wrap(err, "load")
+		}
+		s, err := h.Driver.GetState()
+		if err != nil {
+			return false, errors.Wrap(err, "state")
+		}
+		stopped := false
+		if s != state.Stopped {
+			// the vcpus and memory of a running domain can not grow past their maximum
+			if err := StopHost(api, name); err != nil {
+				return false, errors.Wrap(err, "stop")
+			}
+			stopped = true
+		}
+		return stopped, resizeKVM(cc.KVMQemuURI, name, cpus, memory, config.NodeDiskSize(cc, n))
+	}
+	return false, fmt.Errorf("the %s driver does not support resizing", cc.Driver)
+}
+
+// resizeKVM applies the resources to the libvirt domain of a stopped kvm2 machine, and grows its disk.
+// virsh is used, as the kvm2 driver runs as a plugin which does not expose this.
+func resizeKVM(uri string, name string, cpus int, memory int, diskSize int) error {
+	if uri == "" {
+		uri = "qemu:///system"
+	}
+	vcpus := strconv.Itoa(cpus)
+	mem := fmt.Sprintf("%dM", memory)
+	// lowering the maximum lowers the current values as well
+	for _, args := range [][]string{
+		{"setvcpus", name, vcpus, "--config", "--maximum"},
+		{"setvcpus", name, vcpus, "--config"},
+		{"setmaxmem", name, mem, "--config"},
+		{"setmem", name, mem, "--config"},
+	} {
+		c := exec.Command("virsh", append([]string{"-c", uri}, args...)...)
+		klog.Infof("Run: %v", c.Args)
+		if out, err := c.CombinedOutput(); err != nil {
+			return errors.Wrapf(err, "virsh %s: %s", args[0], strings.TrimSpace(string(out)))
+		}
+	}
+
+	disk := filepath.Join(localpath.MiniPath(), "machines", name, name+".rawdisk")
+	fi, err := os.Stat(disk)
+	if err != nil {
+		return errors.Wrap(err, "disk")
+	}
+	size := int64(diskSize) * 1024 * 1024
+	qcow2, err := isQcow2(disk)
+	if err != nil {
+		return errors.Wrap(err, "disk")
+	}
+	if qcow2 {
+		// the disk is an overlay on a snapshot, qemu-img refuses to shrink it
+		klog.Infof("resizing %s to %dMB", disk, diskSize)
+		if out, err := exec.Command("qemu-img", "resize", disk, fmt.Sprintf("%dM", diskSize)).CombinedOutput(); err != nil {
+			return errors.Wrapf(err, "qemu-img resize: %s", strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+	if size < fi.Size() {
+		return fmt.Errorf("the disk of %s can not shrink from %dMB to %dMB", name, fi.Size()/1024/1024, diskSize)
+	}
+	if size > fi.Size() {
+		klog.Infof("growing %s to %dMB", disk, diskSize)
+		// the disk is sparse, the guest grows its data partition on the next start
+		if err := os.Truncate(disk, size); err != nil {
+			return errors.Wrap(err, "grow disk")
+		}
+	}
+	return nil
+}
+
+// isQcow2 returns whether a disk image is in the qcow2 format, which the disks of kvm2 machines are in once they have been snapshotted
+func isQcow2(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(f, magic); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return false, nil
+		}
+		return false, err
+	}
+	return bytes.Equal(magic, []byte{'Q', 'F', 'I', 0xfb}), nil
+}
+
+// growDataPartition grows the data partition of a VM and its filesystem to the end of the disk, which "minikube config resize" may have grown
+func growDataPartition(r command.Runner) error {
+	rr, err := r.RunCmd(exec.Command("sudo", "blkid", "-o", "device", "-l", "-t", "LABEL=boot2docker-data"))
+	if err != nil {
+		return errors.Wrap(err, "find data partition")
+	}
+	part := strings.TrimSpace(rr.Stdout.String())
+	disk := strings.TrimRight(part, "0123456789")
+	num := strings.TrimPrefix(part, disk)
+	if part == "" || num == "" {
+		return fmt.Errorf("unexpected data partition %q", part)
+	}
+
+	sys := "/sys/class/block/" + filepath.Base(disk)
+	rr, err = r.RunCmd(exec.Command("cat", sys+"/size", sys+"/"+filepath.Base(part)+"/start", sys+"/"+filepath.Base(part)+"/size"))
+	if err != nil {
+		return errors.Wrap(err, "partition sizes")
+	}
+	free, err := unpartitionedSectors(rr.Stdout.String())
+	if err != nil {
+		return err
+	}
+	if free < minGrowSectors {
+		return nil
+	}
+
+	klog.Infof("growing %s by %d sectors", part, free)
+	// parted moves the backup GPT header to the end of the grown disk, and asks before resizing a mounted partition
+	script := fmt.Sprintf("printf 'fix\\n' | parted ---pretend-input-tty %[1]s print && printf 'yes\\n' | parted ---pretend-input-tty %[1]s resizepart %[2]s 100%% && resize2fs %[3]s", disk, num, part)
+	if rr, err := r.RunCmd(exec.Command("sudo", "/bin/bash", "-c", script)); err != nil {
+		return errors.Wrapf(err, "grow data partition: %s", rr.Output())
+	}
+	return nil
+}
+
+// unpartitionedSectors returns the number of sectors after the last partition of a disk, given the
+// size of the disk, the start and the size of the partition, one per line. The backup GPT takes 34 sectors.
+func unpartitionedSectors(sizes string) (int64, error) {
+	fields := strings.Fields(sizes)
+	if len(fields) != 3 {
+		return 0, fmt.Errorf("unexpected partition sizes: %q", sizes)
+	}
+	var vals [3]int64
+	for i, f := range fields {
+		v, err := strconv.ParseInt(f, 10, 64)
+		if err != nil {
+			return 0, errors.Wrapf(err, "partition sizes %q", sizes)
+		}
+		vals[i] = v
+	}
+	free := vals[0] - vals[1] - vals[2] - 34
+	if free < 0 {
+		return 0, nil
+	}
+	return free, nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnpartitionedSectors(t *testing.T) {
+	tests := []struct {
+		sizes   string
+		want    int64
+		wantErr bool
+	}{
+		// a 20000MB disk grown to 40000MB
+		{"81920000\n2048\n40957919\n", 40959999, false},
+		{"40960000\n2048\n40957919\n", 0, false},
+		{"40960000\n2048\n", 0, true},
+		{"40960000\nstart\n40957919\n", 0, true},
+	}
+	for _, tc := range tests {
+		got, err := unpartitionedSectors(tc.sizes)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("unpartitionedSectors(%q) error = %v; want error: %v", tc.sizes, err, tc.wantErr)
+		}
+		if got != tc.want {
+			t.Errorf("unpartitionedSectors(%q) = %d; want %d", tc.sizes, got, tc.want)
+		}
+	}
+}
+
+func TestIsQcow2(t *testing.T) {
+	tests := []struct {
+		content []byte
+		want    bool
+	}{
+		{[]byte{'Q', 'F', 'I', 0xfb, 0, 0, 0, 3}, true},
+		{make([]byte, 512), false},
+		{[]byte{'Q'}, false},
+	}
+	for _, tc := range tests {
+		disk := filepath.Join(t.TempDir(), "disk")
+		if err := ioutil.WriteFile(disk, tc.content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := isQcow2(disk)
+		if err != nil {
+			t.Fatalf("isQcow2(%v) failed: %v", tc.content, err)
+		}
+		if got != tc.want {
+			t.Errorf("isQcow2(%v) = %t; want %t", tc.content, got, tc.want)
+		}
+	}
+}
@@ -47,8 +47,10 @@ import (
 	"k8s.io/minikube/pkg/minikube/proxy"
 	"k8s.io/minikube/pkg/minikube/reason"
 	"k8s.io/minikube/pkg/minikube/registry"
+	"k8s.io/minikube/pkg/minikube/registrycache"
 	"k8s.io/minikube/pkg/minikube/style"
 	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/trace"
 	"k8s.io/minikube/pkg/util/lock"
 )
 
@@ -115,7 +117,7 @@ func engineOptions(cfg config.ClusterConfig) *engine.Options {
 	o := engine.Options{
 		Env:              uniqueEnvs,
 		InsecureRegistry: append([]string{constants.DefaultServiceCIDR}, cfg.InsecureRegistry...),
-		RegistryMirror:   cfg.RegistryMirror,
+		RegistryMirror:   registrycache.Mirrors(cfg),
 		ArbitraryFlags:   cfg.DockerOpt,
 		InstallURL:       drivers.DefaultEngineInstallURL,
 	}
@@ -130,7 +132,7 @@ func createHost(api libmachine.API, cfg *config.ClusterConfig, n *config.Node) (
 	}()
 
 	if cfg.Driver != driver.SSH {
-		showHostInfo(nil, *cfg)
+		showHostInfo(nil, *cfg, *n)
 	}
 
 	def := registry.Driver(cfg.Driver)
@@ -167,7 +169,7 @@ func createHost(api libmachine.API, cfg *config.ClusterConfig, n *config.Node) (
 	}
 	klog.Infof("duration metric: libmachine.API.Create for %q took %s", cfg.Name, time.Since(cstart))
 	if cfg.Driver == driver.SSH {
-		showHostInfo(h, *cfg)
+		showHostInfo(h, *cfg, *n)
 	}
 
 	if err := postStartSetup(h, *cfg); err != nil {
@@ -190,7 +192,10 @@ func timedCreateHost(h *host.Host, api libmachine.API, t time.Duration) error {
 	createFinished := make(chan bool, 1)
 	var err error
 	go func() {
+		span := trace.SpanName(trace.DriverCreate, h.Name)
+		trace.StartSpan(span)
 		err = api.Create(h)
+		trace.EndSpan(span)
 		createFinished <- true
 	}()
 
@@ -292,10 +297,16 @@ func postStartSetup(h *host.Host, mc config.ClusterConfig) error {
 	if driver.IsVM(mc.Driver) || driver.IsKIC(mc.Driver) || driver.IsSSH(mc.Driver) {
 		logRemoteOsRelease(r)
 	}
+	if driver.IsVM(mc.Driver) {
+		if err := growDataPartition(r); err != nil {
+			klog.Warningf("unable to grow the data partition: %v", err)
+		}
+	}
 	return syncLocalAssets(r)
 }
 
-// acquireMachinesLock protects against code that is not parallel-safe (libmachine, cert setup)
+// acquireMachinesLock protects against code that is not parallel-safe (libmachine, cert setup).
+// The nodes of a multi-node cluster are started concurrently: the lock is held by goroutines as well as processes.
 func acquireMachinesLock(name string, drv string) (mutex.Releaser, error) {
 	lockPath := filepath.Join(localpath.MiniPath(), "machines", drv)
 	// With KIC, it's safe to provision multiple hosts simultaneously
@@ -320,8 +331,11 @@ func acquireMachinesLock(name string, drv string) (mutex.Releaser, error) {
 }
 
 // showHostInfo shows host information
-func showHostInfo(h *host.Host, cfg config.ClusterConfig) {
+func showHostInfo(h *host.Host, cfg config.ClusterConfig, n config.Node) {
 	machineType := driver.MachineType(cfg.Driver)
+	// the other nodes are started concurrently, each goes through the steps on its own
+	name := config.MachineName(cfg, n)
+	primary := config.IsPrimaryControlPlane(cfg, n)
 	if driver.BareMetal(cfg.Driver) {
 		info, cpuErr, memErr, DiskErr := LocalHostInfo()
 		if cpuErr == nil && memErr == nil && DiskErr == nil {
@@ -337,19 +351,32 @@ func showHostInfo(h *host.Host, cfg config.ClusterConfig) {
 			return
 		}
 		info, cpuErr, memErr, DiskErr := RemoteHostInfo(r)
-		if cpuErr == nil && memErr == nil && DiskErr == nil {
-			register.Reg.SetStep(register.RunningRemotely)
-			out.Step(style.StartingSSH, "Running remotely (CPUs={{.number_of_cpus}}, Memory={{.memory_size}}MB, Disk={{.disk_size}}MB) ...", out.V{"number_of_cpus": info.CPUs, "memory_size": info.Memory, "disk_size": info.DiskSize})
+		if cpuErr != nil || memErr != nil || DiskErr != nil {
+			return
+		}
+		if !primary {
+			out.NodeStep(name, register.RunningRemotely, style.StartingSSH, "Running node {{.name}} remotely (CPUs={{.number_of_cpus}}, Memory={{.memory_size}}MB, Disk={{.disk_size}}MB) ...", out.V{"name": name, "number_of_cpus": info.CPUs, "memory_size": info.Memory, "disk_size": info.DiskSize})
+			return
 		}
+		register.Reg.SetStep(register.RunningRemotely)
+		out.Step(style.StartingSSH, "Running remotely (CPUs={{.number_of_cpus}}, Memory={{.memory_size}}MB, Disk={{.disk_size}}MB) ...", out.V{"number_of_cpus": info.CPUs, "memory_size": info.Memory, "disk_size": info.DiskSize})
 		return
 	}
 	if driver.IsKIC(cfg.Driver) { // TODO:medyagh add free disk space on docker machine
+		if !primary {
+			out.NodeStep(name, register.CreatingContainer, style.StartingVM, "Creating {{.driver_name}} {{.machine_type}} for node {{.name}} (CPUs={{.number_of_cpus}}, Memory={{.memory_size}}MB) ...", out.V{"name": name, "driver_name": cfg.Driver, "number_of_cpus": config.NodeCPUs(cfg, n), "memory_size": config.NodeMemory(cfg, n), "machine_type": machineType})
+			return
+		}
 		register.Reg.SetStep(register.CreatingContainer)
-		out.Step(style.StartingVM, "Creating {{.driver_name}} {{.machine_type}} (CPUs={{.number_of_cpus}}, Memory={{.memory_size}}MB) ...", out.V{"driver_name": cfg.Driver, "number_of_cpus": cfg.CPUs, "memory_size": cfg.Memory, "machine_type": machineType})
+		out.Step(style.StartingVM, "Creating {{.driver_name}} {{.machine_type}} (CPUs={{.number_of_cpus}}, Memory={{.memory_size}}MB) ...", out.V{"driver_name": cfg.Driver, "number_of_cpus": config.NodeCPUs(cfg, n), "memory_size": config.NodeMemory(cfg, n), "machine_type": machineType})
+		return
+	}
+	if !primary {
+		out.NodeStep(name, register.CreatingVM, style.StartingVM, "Creating {{.driver_name}} {{.machine_type}} for node {{.name}} (CPUs={{.number_of_cpus}}, Memory={{.memory_size}}MB, Disk={{.disk_size}}MB) ...", out.V{"name": name, "driver_name": cfg.Driver, "number_of_cpus": config.NodeCPUs(cfg, n), "memory_size": config.NodeMemory(cfg, n), "disk_size": config.NodeDiskSize(cfg, n), "machine_type": machineType})
 		return
 	}
 	register.Reg.SetStep(register.CreatingVM)
-	out.Step(style.StartingVM, "Creating {{.driver_name}} {{.machine_type}} (CPUs={{.number_of_cpus}}, Memory={{.memory_size}}MB, Disk={{.disk_size}}MB) ...", out.V{"driver_name": cfg.Driver, "number_of_cpus": cfg.CPUs, "memory_size": cfg.Memory, "disk_size": cfg.DiskSize, "machine_type": machineType})
+	out.Step(style.StartingVM, "Creating {{.driver_name}} {{.machine_type}} (CPUs={{.number_of_cpus}}, Memory={{.memory_size}}MB, Disk={{.disk_size}}MB) ...", out.V{"driver_name": cfg.Driver, "number_of_cpus": config.NodeCPUs(cfg, n), "memory_size": config.NodeMemory(cfg, n), "disk_size": config.NodeDiskSize(cfg, n), "machine_type": machineType})
 }
 
 // AddHostAlias makes fine adjustments to pod resources that aren't possible via kubeadm config.
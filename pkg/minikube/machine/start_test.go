@@ -22,6 +22,10 @@ import (
 	"net"
 	"os"
 	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/tests"
 )
 
 const initialEtcHostsContent string = `127.0.0.1	localhost
@@ -97,3 +101,53 @@ func writeContentToTempFile(content string) (string, error) {
 
 	return path, nil
 }
+
+func TestAcquireMachinesLockConcurrent(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer tests.RemoveTempDir(tempDir)
+
+	// the nodes of a KIC cluster are provisioned concurrently, each with its own lock
+	r1, err := acquireMachinesLock("multinode", driver.Docker)
+	if err != nil {
+		t.Fatalf("acquireMachinesLock() error = %v", err)
+	}
+	acquired := make(chan error)
+	go func() {
+		r2, err := acquireMachinesLock("multinode-m02", driver.Docker)
+		if err == nil {
+			r2.Release()
+		}
+		acquired <- err
+	}()
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatalf("acquireMachinesLock() error = %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("acquireMachinesLock() of another KIC machine is blocked by the lock of the first")
+	}
+	r1.Release()
+
+	// the machines of a VM driver are created one at a time
+	r1, err = acquireMachinesLock("multinode", driver.KVM2)
+	if err != nil {
+		t.Fatalf("acquireMachinesLock() error = %v", err)
+	}
+	go func() {
+		r2, err := acquireMachinesLock("multinode-m02", driver.KVM2)
+		if err == nil {
+			r2.Release()
+		}
+		acquired <- err
+	}()
+	select {
+	case <-acquired:
+		t.Fatalf("acquireMachinesLock() of another VM machine was not blocked by the lock of the first")
+	case <-time.After(time.Second):
+	}
+	r1.Release()
+	if err := <-acquired; err != nil {
+		t.Fatalf("acquireMachinesLock() error = %v", err)
+	}
+}
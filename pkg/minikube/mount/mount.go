@@ -0,0 +1,260 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mount manages the background mount servers of a profile, started by
+// "minikube mount --background" and re-established by "minikube start".
+package mount
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/mitchellh/go-ps"
+	"github.com/pkg/errors"
+	"github.com/shirou/gopsutil/v3/process"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// Status is the state of a mount of a profile
+type Status struct {
+	config.MountConfig
+	PID     int `json:",omitempty"`
+	Running bool
+}
+
+// Find returns the index of the mount of target in mounts, or -1
+func Find(mounts []config.MountConfig, target string) int {
+	for i, m := range mounts {
+		if m.Target == target {
+			return i
+		}
+	}
+	return -1
+}
+
+// String returns the mount in the format of the mount command: <source directory>:<target directory>
+func String(m config.MountConfig) string {
+	return m.Source + ":" + m.Target
+}
+
+// name returns the base name of the pid and log files of the mount server of target
+func name(target string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(target)))[:12]
+}
+
+// PIDFile returns the path to the pid file of the mount server of target
+func PIDFile(profile, target string) string {
+	return filepath.Join(localpath.Mounts(profile), name(target)+".pid")
+}
+
+// LogFile returns the path to the log file of the mount server of target
+func LogFile(profile, target string) string {
+	return filepath.Join(localpath.Mounts(profile), name(target)+".log")
+}
+
+// Args returns the arguments of the minikube command which serves m in the foreground
+func Args(profile string, m config.MountConfig) []string {
+	args := []string{"mount", "--profile=" + profile}
+	if m.IP != "" {
+		args = append(args, "--ip="+m.IP)
+	}
+	if m.Type != "" {
+		args = append(args, "--type="+m.Type)
+	}
+	if m.Version != "" {
+		args = append(args, "--9p-version="+m.Version)
+	}
+	if m.UID != "" {
+		args = append(args, "--uid="+m.UID)
+	}
+	if m.GID != "" {
+		args = append(args, "--gid="+m.GID)
+	}
+	if m.MSize != 0 {
+		args = append(args, fmt.Sprintf("--msize=%d", m.MSize))
+	}
+	if m.Mode != 0 {
+		args = append(args, fmt.Sprintf("--mode=%#o", m.Mode))
+	}
+	if len(m.Options) > 0 {
+		args = append(args, "--options="+strings.Join(m.Options, ","))
+	}
+	if m.Watch {
+		args = append(args, "--watch")
+	}
+	return append(args, String(m))
+}
+
+// cmdline returns the executable and the arguments of a process
+var cmdline = func(pid int) ([]string, error) {
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return nil, err
+	}
+	return p.CmdlineSlice()
+}
+
+// running returns the pid saved in a pid file, and whether it is the running mount server of target.
+// An empty target matches the mount servers of any target of the profile.
+func running(file string, profile string, target string) (int, bool) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		klog.Warningf("invalid pid file %s: %v", file, err)
+		return 0, false
+	}
+	// os.FindProcess does not check if pid is running :(
+	entry, err := ps.FindProcess(pid)
+	if err != nil || entry == nil {
+		return pid, false
+	}
+	// the pid may have been reused by another process since the mount server exited
+	args, err := cmdline(pid)
+	if err != nil {
+		klog.Warningf("unable to get the command line of %d: %v", pid, err)
+		return pid, false
+	}
+	if !isMountServer(args, profile, target) {
+		klog.Infof("process %d is not the mount server of %s: %v", pid, file, args)
+		return pid, false
+	}
+	return pid, true
+}
+
+// isMountServer returns whether the command line is the one of a minikube mount server of target, as started by Start
+func isMountServer(args []string, profile string, target string) bool {
+	if len(args) < 4 {
+		return false
+	}
+	exe := strings.TrimSuffix(filepath.Base(args[0]), ".exe")
+	self := strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+	if exe != self && !strings.HasPrefix(exe, "minikube") {
+		return false
+	}
+	if args[1] != "mount" || args[2] != "--profile="+profile {
+		return false
+	}
+	return target == "" || strings.HasSuffix(args[len(args)-1], ":"+target)
+}
+
+// List returns the status of the mounts of a cluster
+func List(cc *config.ClusterConfig) []Status {
+	var sts []Status
+	for _, m := range cc.Mounts {
+		st := Status{MountConfig: m}
+		st.PID, st.Running = running(PIDFile(cc.Name, m.Target), cc.Name, m.Target)
+		if !st.Running {
+			st.PID = 0
+		}
+		sts = append(sts, st)
+	}
+	return sts
+}
+
+// Start starts a background mount server for m, unless one is already running, and returns its pid
+func Start(profile string, m config.MountConfig) (int, error) {
+	if p, ok := running(PIDFile(profile, m.Target), profile, m.Target); ok {
+		klog.Infof("mount server for %s is already running with pid %d", m.Target, p)
+		return p, nil
+	}
+
+	if err := os.MkdirAll(localpath.Mounts(profile), 0o755); err != nil {
+		return 0, errors.Wrap(err, "creating mounts dir")
+	}
+	logFile, err := os.Create(LogFile(profile, m.Target))
+	if err != nil {
+		return 0, errors.Wrap(err, "creating mount log")
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(os.Args[0], Args(profile, m)...)
+	cmd.Env = append(os.Environ(), constants.IsMinikubeChildProcess+"=true")
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = detached()
+	klog.Infof("starting mount server: %s", cmd.Args)
+	if err := cmd.Start(); err != nil {
+		return 0, errors.Wrap(err, "starting mount server")
+	}
+	p := cmd.Process.Pid
+	if err := ioutil.WriteFile(PIDFile(profile, m.Target), []byte(strconv.Itoa(p)), 0o600); err != nil {
+		return p, errors.Wrap(err, "writing mount pid")
+	}
+	return p, cmd.Process.Release()
+}
+
+// Stop stops the background mount server of target, which unmounts it, if it is running
+func Stop(profile, target string) error {
+	file := PIDFile(profile, target)
+	if p, ok := running(file, profile, target); ok {
+		proc, err := os.FindProcess(p)
+		if err != nil {
+			return errors.Wrap(err, "finding process")
+		}
+		klog.Infof("stopping mount server of %s with pid %d", target, p)
+		// the server unmounts on termination, which is not supported on windows
+		if err := proc.Signal(syscall.SIGTERM); err != nil {
+			klog.Infof("terminating %d failed with %v, killing it", p, err)
+			if err := proc.Kill(); err != nil {
+				return errors.Wrapf(err, "killing %d", p)
+			}
+		}
+	}
+	if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "removing mount pid")
+	}
+	return nil
+}
+
+// StopAll stops the background mount servers of a profile, including the ones no longer in its config
+func StopAll(profile string) error {
+	files, err := filepath.Glob(filepath.Join(localpath.Mounts(profile), "*.pid"))
+	if err != nil {
+		return err
+	}
+	var errs []string
+	for _, f := range files {
+		p, ok := running(f, profile, "")
+		if !ok {
+			continue
+		}
+		proc, err := os.FindProcess(p)
+		if err != nil {
+			continue
+		}
+		klog.Infof("killing mount server with pid %d", p)
+		if err := proc.Kill(); err != nil {
+			errs = append(errs, fmt.Sprintf("killing %d: %v", p, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
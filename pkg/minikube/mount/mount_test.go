@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mount
+
+import (
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+func TestArgs(t *testing.T) {
+	m := config.MountConfig{Source: "/home/team/src", Target: "/src", Type: "9p", UID: "docker", Mode: 0o755, MSize: 262144, Options: []string{"cache=fscache", "noextend"}, Watch: true}
+	got := strings.Join(Args("team", m), " ")
+	want := "mount --profile=team --type=9p --uid=docker --msize=262144 --mode=0755 --options=cache=fscache,noextend --watch /home/team/src:/src"
+	if got != want {
+		t.Errorf("Args() = %q; want %q", got, want)
+	}
+}
+
+func TestList(t *testing.T) {
+	home := os.Getenv(localpath.MinikubeHome)
+	if err := os.Setenv(localpath.MinikubeHome, t.TempDir()); err != nil {
+		t.Fatalf("unable to set %s: %v", localpath.MinikubeHome, err)
+	}
+	t.Cleanup(func() { os.Setenv(localpath.MinikubeHome, home) })
+
+	cc := &config.ClusterConfig{Name: "team", Mounts: []config.MountConfig{
+		{Source: "/home/team/src", Target: "/src"},
+		{Source: "/home/team/data", Target: "/data"},
+		{Source: "/home/team/logs", Target: "/logs"},
+	}}
+	if err := os.MkdirAll(localpath.Mounts("team"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// the test process stands in for a running mount server, and its parent for a process which reused the pid of one
+	old := cmdline
+	cmdline = func(pid int) ([]string, error) {
+		if pid == os.Getpid() {
+			return append([]string{"/usr/local/bin/minikube"}, Args("team", cc.Mounts[0])...), nil
+		}
+		return []string{"/usr/bin/vim", "notes.txt"}, nil
+	}
+	t.Cleanup(func() { cmdline = old })
+	if err := ioutil.WriteFile(PIDFile("team", "/src"), []byte(strconv.Itoa(os.Getpid())), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(PIDFile("team", "/logs"), []byte(strconv.Itoa(os.Getppid())), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(PIDFile("team", "/data"), []byte("not a pid"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	sts := List(cc)
+	if len(sts) != 3 || !sts[0].Running || sts[0].PID != os.Getpid() || sts[1].Running || sts[2].Running || sts[2].PID != 0 {
+		t.Errorf("List() = %+v; want only /src running", sts)
+	}
+	if i := Find(cc.Mounts, "/data"); i != 1 {
+		t.Errorf("Find(/data) = %d; want 1", i)
+	}
+	if i := Find(cc.Mounts, "/missing"); i != -1 {
+		t.Errorf("Find(/missing) = %d; want -1", i)
+	}
+
+	if err := Stop("team", "/data"); err != nil {
+		t.Errorf("Stop() of a stopped mount failed: %v", err)
+	}
+	if _, err := os.Stat(PIDFile("team", "/data")); !os.IsNotExist(err) {
+		t.Errorf("Stop() did not remove the pid file: %v", err)
+	}
+}
+
+func TestIsMountServer(t *testing.T) {
+	m := config.MountConfig{Source: "/home/team/src", Target: "/src"}
+	server := append([]string{"/usr/local/bin/minikube"}, Args("team", m)...)
+	tests := []struct {
+		args    []string
+		profile string
+		target  string
+		want    bool
+	}{
+		{server, "team", "/src", true},
+		{server, "team", "", true},
+		{server, "team", "/data", false},
+		{server, "other", "", false},
+		{[]string{"/usr/local/bin/minikube", "start", "--profile=team"}, "team", "", false},
+		{append([]string{"/usr/bin/sleep"}, Args("team", m)...), "team", "/src", false},
+		{[]string{"/usr/bin/vim"}, "team", "", false},
+	}
+	for _, tc := range tests {
+		if got := isMountServer(tc.args, tc.profile, tc.target); got != tc.want {
+			t.Errorf("isMountServer(%v, %q, %q) = %t; want %t", tc.args, tc.profile, tc.target, got, tc.want)
+		}
+	}
+}
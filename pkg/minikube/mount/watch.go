@@ -0,0 +1,153 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mount
+
+import (
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+const (
+	// watchBatch is how long changes on the host are collected before they are propagated
+	watchBatch = 500 * time.Millisecond
+	// maxTouchArgs is the maximum number of files touched by a command
+	maxTouchArgs = 100
+)
+
+// Watch propagates the changes of the files in the host directory source to the guest until done is closed,
+// by touching the changed files below target. 9p does not notify the guest of changes made on the host, and
+// touching them generates the inotify events that watchers in the guest, such as hot reloading servers in pods,
+// rely on. runner returns the command runner of the guest, or nil when it is not running.
+func Watch(source, target string, runner func() command.Runner, done <-chan struct{}) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrap(err, "creating watcher")
+	}
+	defer w.Close()
+	if err := watchTree(w, source); err != nil {
+		return errors.Wrapf(err, "watching %s", source)
+	}
+
+	changed := map[string]bool{}
+	tick := time.NewTicker(watchBatch)
+	defer tick.Stop()
+	for {
+		select {
+		case <-done:
+			return nil
+		case ev, ok := <-w.Events:
+			if !ok {
+				return nil
+			}
+			// touching the files in the guest changes their times on the host,
+			// ignoring attribute changes avoids touching them again
+			if ev.Op == fsnotify.Chmod {
+				continue
+			}
+			p := ev.Name
+			switch {
+			case ev.Op&(fsnotify.Remove|fsnotify.Rename) != 0:
+				// the file no longer exists, its directory is touched instead
+				p = filepath.Dir(p)
+			case ev.Op&fsnotify.Create != 0:
+				if st, err := os.Lstat(p); err == nil && st.IsDir() {
+					if err := watchTree(w, p); err != nil {
+						klog.Warningf("unable to watch %s: %v", p, err)
+					}
+				}
+			}
+			if g, ok := guestPath(source, target, p); ok {
+				changed[g] = true
+			}
+		case err, ok := <-w.Errors:
+			if !ok {
+				return nil
+			}
+			klog.Warningf("error watching %s: %v", source, err)
+		case <-tick.C:
+			if len(changed) == 0 {
+				continue
+			}
+			if r := runner(); r != nil {
+				touch(r, changed)
+			}
+			changed = map[string]bool{}
+		}
+	}
+}
+
+// watchTree adds dir and the directories below it to w
+func watchTree(w *fsnotify.Watcher, dir string) error {
+	return filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			klog.Warningf("unable to walk %s: %v", p, err)
+			return nil
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		return w.Add(p)
+	})
+}
+
+// guestPath returns the path in the guest of the host path p, if it is within source
+func guestPath(source, target, p string) (string, bool) {
+	rel, err := filepath.Rel(source, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return path.Join(target, filepath.ToSlash(rel)), true
+}
+
+// touchCmds returns the commands which touch the existing files among paths
+func touchCmds(paths map[string]bool) []*exec.Cmd {
+	var ps []string
+	for p := range paths {
+		ps = append(ps, p)
+	}
+	sort.Strings(ps)
+
+	var cmds []*exec.Cmd
+	for len(ps) > 0 {
+		n := len(ps)
+		if n > maxTouchArgs {
+			n = maxTouchArgs
+		}
+		cmds = append(cmds, exec.Command("sudo", append([]string{"touch", "-c"}, ps[:n]...)...))
+		ps = ps[n:]
+	}
+	return cmds
+}
+
+// touch touches the existing files among paths in the guest
+func touch(r command.Runner, paths map[string]bool) {
+	for _, c := range touchCmds(paths) {
+		if rr, err := r.RunCmd(c); err != nil {
+			klog.Warningf("unable to touch changed files: %v: %s", err, rr.Output())
+		}
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mount
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+// recordingRunner records the commands it runs
+type recordingRunner struct {
+	command.Runner
+	mu   sync.Mutex
+	cmds []string
+}
+
+func (r *recordingRunner) RunCmd(cmd *exec.Cmd) (*command.RunResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cmds = append(r.cmds, strings.Join(cmd.Args, " "))
+	return &command.RunResult{Args: cmd.Args}, nil
+}
+
+func (r *recordingRunner) ran(cmd string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.cmds {
+		if c == cmd {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGuestPath(t *testing.T) {
+	tests := []struct {
+		p    string
+		want string
+		ok   bool
+	}{
+		{"/home/team/src", "/src", true},
+		{"/home/team/src/cmd/main.go", "/src/cmd/main.go", true},
+		{"/home/team/srcs/main.go", "", false},
+		{"/home/team", "", false},
+	}
+	for _, tc := range tests {
+		got, ok := guestPath("/home/team/src", "/src", tc.p)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("guestPath(%q) = %q, %v; want %q, %v", tc.p, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestTouchCmds(t *testing.T) {
+	paths := map[string]bool{}
+	for i := 0; i < maxTouchArgs+1; i++ {
+		paths[filepath.Join("/src", strings.Repeat("f", i+1))] = true
+	}
+	cmds := touchCmds(paths)
+	if len(cmds) != 2 || len(cmds[0].Args) != maxTouchArgs+3 || len(cmds[1].Args) != 4 {
+		t.Fatalf("touchCmds() returned %d commands; want the paths split in 2 commands", len(cmds))
+	}
+	if got := strings.Join(cmds[1].Args[:3], " "); got != "sudo touch -c" {
+		t.Errorf("touchCmds() = %q; want sudo touch -c", got)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	source := t.TempDir()
+	if err := os.Mkdir(filepath.Join(source, "cmd"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	r := &recordingRunner{}
+	done := make(chan struct{})
+	errs := make(chan error, 1)
+	go func() { errs <- Watch(source, "/src", func() command.Runner { return r }, done) }()
+
+	// wait for the watcher to be set up
+	want := "sudo touch -c /src/cmd/main.go"
+	deadline := time.Now().Add(10 * time.Second)
+	for !r.ran(want) && time.Now().Before(deadline) {
+		if err := ioutil.WriteFile(filepath.Join(source, "cmd", "main.go"), []byte("package main"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(2 * watchBatch)
+	}
+	if !r.ran(want) {
+		t.Errorf("Watch() ran %v; want %q", r.cmds, want)
+	}
+
+	// the changes of the times made by touch are not propagated again
+	r.mu.Lock()
+	r.cmds = nil
+	r.mu.Unlock()
+	now := time.Now()
+	if err := os.Chtimes(filepath.Join(source, "cmd", "main.go"), now, now); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(3 * watchBatch)
+	if r.ran(want) {
+		t.Errorf("Watch() propagated a change of times")
+	}
+
+	close(done)
+	if err := <-errs; err != nil {
+		t.Errorf("Watch() failed: %v", err)
+	}
+}
@@ -38,10 +38,14 @@ import (
 	"k8s.io/minikube/pkg/util/lock"
 )
 
-func showVersionInfo(k8sVersion string, cr cruntime.Manager) {
+func showVersionInfo(cc config.ClusterConfig, n config.Node, apiServer bool, cr cruntime.Manager) {
 	version, _ := cr.Version()
+	if !apiServer {
+		out.NodeStep(config.MachineName(cc, n), register.PreparingKubernetes, cr.Style(), "Preparing Kubernetes {{.k8sVersion}} on {{.runtime}} {{.runtimeVersion}} for node {{.name}} ...", out.V{"k8sVersion": n.KubernetesVersion, "runtime": cr.Name(), "runtimeVersion": version, "name": config.MachineName(cc, n)})
+		return
+	}
 	register.Reg.SetStep(register.PreparingKubernetes)
-	out.Step(cr.Style(), "Preparing Kubernetes {{.k8sVersion}} on {{.runtime}} {{.runtimeVersion}} ...", out.V{"k8sVersion": k8sVersion, "runtime": cr.Name(), "runtimeVersion": version})
+	out.Step(cr.Style(), "Preparing Kubernetes {{.k8sVersion}} on {{.runtime}} {{.runtimeVersion}} ...", out.V{"k8sVersion": n.KubernetesVersion, "runtime": cr.Name(), "runtimeVersion": version})
 	for _, v := range config.DockerOpt {
 		out.Infof("opt {{.docker_option}}", out.V{"docker_option": v})
 	}
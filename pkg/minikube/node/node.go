@@ -20,9 +20,12 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"path"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
+	"golang.org/x/sync/errgroup"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
@@ -30,6 +33,10 @@ import (
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/out/register"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/minikube/vmpath"
 )
 
 // TODO: Share these between cluster and node packages
@@ -40,6 +47,57 @@ const (
 
 // Add adds a new node config to an existing cluster.
 func Add(cc *config.ClusterConfig, n config.Node, delOnFail bool) error {
+	if err := checkNodeName(cc, n); err != nil {
+		return err
+	}
+
+	if err := config.SaveNode(cc, &n); err != nil {
+		return errors.Wrap(err, "save node")
+	}
+
+	return provisionAndStart(cc, &n, delOnFail)
+}
+
+// AddNodes adds new nodes to an existing cluster, creating, provisioning and joining them concurrently.
+// The nodes are saved to the cluster config first, and saved again by each node as it starts.
+func AddNodes(cc *config.ClusterConfig, nodes []config.Node, delOnFail bool) error {
+	for i := range nodes {
+		if err := checkNodeName(cc, nodes[i]); err != nil {
+			return err
+		}
+		if err := config.SaveNode(cc, &nodes[i]); err != nil {
+			return errors.Wrap(err, "save node")
+		}
+	}
+
+	var g errgroup.Group
+	for i := range nodes {
+		n := &nodes[i]
+		// each node starts with its own copy of the cluster config, config.SaveNode keeps the nodes of the others
+		c := *cc
+		c.Nodes = append([]config.Node{}, cc.Nodes...)
+		g.Go(func() error {
+			if err := provisionAndStart(&c, n, delOnFail); err != nil {
+				return errors.Wrapf(err, "node %s", config.MachineName(c, *n))
+			}
+			out.NodeStep(config.MachineName(c, *n), register.Done, style.Check, "Node {{.name}} joined the cluster", out.V{"name": config.MachineName(c, *n)})
+			return nil
+		})
+	}
+	err := g.Wait()
+
+	for _, n := range nodes {
+		for i := range cc.Nodes {
+			if cc.Nodes[i].Name == n.Name {
+				cc.Nodes[i] = n
+			}
+		}
+	}
+	return err
+}
+
+// checkNodeName returns an error if the machine of a new node already exists in another profile
+func checkNodeName(cc *config.ClusterConfig, n config.Node) error {
 	profiles, err := config.ListValidProfiles()
 	if err != nil {
 		return err
@@ -57,12 +115,12 @@ func Add(cc *config.ClusterConfig, n config.Node, delOnFail bool) error {
 			}
 		}
 	}
+	return nil
+}
 
-	if err := config.SaveNode(cc, &n); err != nil {
-		return errors.Wrap(err, "save node")
-	}
-
-	r, p, m, h, err := Provision(cc, &n, false, delOnFail)
+// provisionAndStart provisions the machine of a new node and joins it to the cluster
+func provisionAndStart(cc *config.ClusterConfig, n *config.Node, delOnFail bool) error {
+	r, p, m, h, err := Provision(cc, n, false, delOnFail)
 	if err != nil {
 		return err
 	}
@@ -72,7 +130,7 @@ func Add(cc *config.ClusterConfig, n config.Node, delOnFail bool) error {
 		MachineAPI:     m,
 		Host:           h,
 		Cfg:            cc,
-		Node:           &n,
+		Node:           n,
 		ExistingAddons: nil,
 	}
 
@@ -133,6 +191,65 @@ func drainNode(cc config.ClusterConfig, name string) (*config.Node, error) {
 	return n, nil
 }
 
+// removeEtcdMember removes the etcd member of the control-plane node n from the cluster, through the primary control plane
+func removeEtcdMember(cc config.ClusterConfig, n config.Node) error {
+	cp, err := config.PrimaryControlPlane(&cc)
+	if err != nil {
+		return errors.Wrap(err, "control plane")
+	}
+
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		return err
+	}
+	host, err := machine.LoadHost(api, cc.Name)
+	if err != nil {
+		return err
+	}
+	runner, err := machine.CommandRunner(host)
+	if err != nil {
+		return err
+	}
+
+	kubectl := kapi.KubectlBinaryPath(cc.KubernetesConfig.KubernetesVersion)
+	etcdctl := func(args ...string) *exec.Cmd {
+		cmd := []string{"KUBECONFIG=/var/lib/minikube/kubeconfig", kubectl, "-n", "kube-system", "exec", "etcd-" + config.MachineName(cc, cp), "--",
+			"etcdctl", "--endpoints=https://127.0.0.1:2379",
+			"--cacert=" + path.Join(vmpath.GuestKubernetesCertsDir, "etcd/ca.crt"),
+			"--cert=" + path.Join(vmpath.GuestKubernetesCertsDir, "etcd/server.crt"),
+			"--key=" + path.Join(vmpath.GuestKubernetesCertsDir, "etcd/server.key")}
+		return exec.Command("sudo", append(cmd, args...)...)
+	}
+
+	rr, err := runner.RunCmd(etcdctl("member", "list"))
+	if err != nil {
+		return errors.Wrap(err, "listing etcd members")
+	}
+	m := config.MachineName(cc, n)
+	id := etcdMemberID(rr.Stdout.String(), m)
+	if id == "" {
+		klog.Infof("%s is not an etcd member", m)
+		return nil
+	}
+	if _, err := runner.RunCmd(etcdctl("member", "remove", id)); err != nil {
+		return errors.Wrapf(err, "removing etcd member %s", id)
+	}
+	klog.Infof("removed etcd member %s of %s", id, m)
+	return nil
+}
+
+// etcdMemberID returns the id of the etcd member name in the output of "etcdctl member list", such as:
+// 8e9e05c52164694d, started, minikube, https://192.168.49.2:2380, https://192.168.49.2:2379, false
+func etcdMemberID(out string, name string) string {
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) > 2 && strings.TrimSpace(fields[2]) == name {
+			return strings.TrimSpace(fields[0])
+		}
+	}
+	return ""
+}
+
 // Delete calls drainNode to remove node from cluster and deletes the host.
 func Delete(cc config.ClusterConfig, name string) (*config.Node, error) {
 	n, err := drainNode(cc, name)
@@ -140,6 +257,13 @@ func Delete(cc config.ClusterConfig, name string) (*config.Node, error) {
 		return n, err
 	}
 
+	// keep the quorum of etcd right, the member of a deleted control plane would never come back
+	if n.ControlPlane {
+		if err := removeEtcdMember(cc, *n); err != nil {
+			klog.Errorf("unable to remove the etcd member of %s: %v", name, err)
+		}
+	}
+
 	m := config.MachineName(cc, *n)
 	api, err := machine.NewAPIClient()
 	if err != nil {
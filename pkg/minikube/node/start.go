@@ -61,8 +61,11 @@ import (
 	"k8s.io/minikube/pkg/minikube/out/register"
 	"k8s.io/minikube/pkg/minikube/proxy"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/registrycache"
 	"k8s.io/minikube/pkg/minikube/style"
 	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/network"
+	"k8s.io/minikube/pkg/trace"
 	"k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/util/retry"
 )
@@ -72,6 +75,12 @@ const waitTimeout = "wait-timeout"
 var (
 	kicGroup   errgroup.Group
 	cacheGroup errgroup.Group
+	// downloadMutex guards the download groups against the nodes started concurrently
+	downloadMutex sync.Mutex
+	// joinMutex serializes the joins of the control-plane nodes, as kubeadm adds their etcd members one at a time
+	joinMutex sync.Mutex
+	// cniMutex serializes the CNI applies of the nodes started concurrently, which copy the same manifest to the control plane
+	cniMutex sync.Mutex
 )
 
 // Starter is a struct with all the necessary information to start a node
@@ -88,7 +97,9 @@ type Starter struct {
 // Start spins up a guest and starts the Kubernetes node.
 func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 	// wait for preloaded tarball to finish downloading before configuring runtimes
+	downloadMutex.Lock()
 	waitCacheRequiredImages(&cacheGroup)
+	downloadMutex.Unlock()
 
 	sv, err := util.ParseKubernetesVersion(starter.Node.KubernetesVersion)
 	if err != nil {
@@ -96,14 +107,14 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 	}
 
 	// configure the runtime (docker, containerd, crio)
-	cr := configureRuntimes(starter.Runner, *starter.Cfg, sv)
+	cr := configureRuntimes(starter.Runner, *starter.Cfg, *starter.Node, sv)
 
 	// check if installed runtime is compatible with current minikube code
 	if err = cruntime.CheckCompatibility(cr); err != nil {
 		return nil, err
 	}
 
-	showVersionInfo(starter.Node.KubernetesVersion, cr)
+	showVersionInfo(*starter.Cfg, *starter.Node, apiServer, cr)
 
 	// Add "host.minikube.internal" DNS alias (intentionally non-fatal)
 	hostIP, err := cluster.HostIP(starter.Host, starter.Cfg.Name)
@@ -155,7 +166,7 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 			return nil, errors.Wrap(err, "Failed to get bootstrapper")
 		}
 
-		if err = bs.SetupCerts(starter.Cfg.KubernetesConfig, *starter.Node); err != nil {
+		if err = bs.SetupCerts(*starter.Cfg, *starter.Node); err != nil {
 			return nil, errors.Wrap(err, "setting up certs")
 		}
 
@@ -191,6 +202,8 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 		go addons.Start(&wg, starter.Cfg, starter.ExistingAddons, addonList)
 	}
 
+	// the labels and taints are applied from the control plane, the bootstrapper of the primary one is bs
+	cpBs := bs
 	if apiServer {
 		// special ops for none , like change minikube directory.
 		// multinode super doesn't work on the none driver
@@ -199,7 +212,8 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 		}
 	} else {
 		// Make sure to use the command runner for the control plane to generate the join token
-		cpBs, cpr, err := cluster.ControlPlaneBootstrapper(starter.MachineAPI, starter.Cfg, viper.GetString(cmdcfg.Bootstrapper))
+		var cpr command.Runner
+		cpBs, cpr, err = cluster.ControlPlaneBootstrapper(starter.MachineAPI, starter.Cfg, viper.GetString(cmdcfg.Bootstrapper))
 		if err != nil {
 			return nil, errors.Wrap(err, "getting control plane bootstrapper")
 		}
@@ -213,7 +227,10 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 			return nil, errors.Wrap(err, "cni")
 		}
 
-		if err := cnm.Apply(cpr); err != nil {
+		cniMutex.Lock()
+		err = cnm.Apply(cpr)
+		cniMutex.Unlock()
+		if err != nil {
 			return nil, errors.Wrap(err, "cni apply")
 		}
 	}
@@ -222,13 +239,111 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 		return nil, errors.Wrapf(err, "wait %s for node", viper.GetDuration(waitTimeout))
 	}
 
+	// re-applied at every start, as the kubelet only sets them when it registers the node, which may not be done yet
+	label := func() error {
+		return cpBs.LabelAndTaintNode(*starter.Cfg, *starter.Node)
+	}
+	if err := retry.Expo(label, time.Second, 2*time.Minute); err != nil {
+		return nil, errors.Wrap(err, "label and taint node")
+	}
+
 	klog.Infof("waiting for startup goroutines ...")
 	wg.Wait()
 
+	if !apiServer {
+		// the other nodes may be started concurrently, with their own copies of the config
+		return kcs, config.SaveNode(starter.Cfg, starter.Node)
+	}
+
 	// Write enabled addons to the config before completion
 	return kcs, config.Write(viper.GetString(config.ProfileName), starter.Cfg)
 }
 
+// HAVIP returns a virtual IP for the control planes of a HA cluster: the last address of the network of the
+// primary control plane, which is out of the DHCP range of the networks minikube creates for VMs, and reserved
+// when the IPs of the containers are picked. It is refused when another HA cluster on the same network uses it
+func HAVIP(cc config.ClusterConfig, cp config.Node) (string, error) {
+	addr := cp.IP
+	if cc.Subnet != "" {
+		addr = cc.Subnet
+	}
+	p, err := network.Inspect(addr)
+	if err != nil {
+		return "", errors.Wrapf(err, "inspecting network of %s", addr)
+	}
+	if p.ClientMax == cp.IP {
+		return "", fmt.Errorf("the network %s has no free address", p.CIDR)
+	}
+	if owner := haVIPOwner(cc, p.ClientMax); owner != "" {
+		return "", fmt.Errorf("the virtual IP %s of the network %s is already used by the HA cluster %s", p.ClientMax, cc.Network, owner)
+	}
+	if cc.Driver == driver.KVM2 && cc.Network != "" {
+		out.WarningT("The virtual IP {{.ip}} must be out of the DHCP range of the network {{.network}}", out.V{"ip": p.ClientMax, "network": cc.Network})
+	}
+	// Bypass proxy for the virtual IP, as for the vm ip
+	if err := proxy.ExcludeIP(p.ClientMax); err != nil {
+		klog.Warningf("unable to exclude %s from the proxy: %v", p.ClientMax, err)
+	}
+	return p.ClientMax, nil
+}
+
+// haVIPOwner returns the name of the other profile on the user network of cc whose control planes announce vip, if any
+func haVIPOwner(cc config.ClusterConfig, vip string) string {
+	if cc.Network == "" {
+		return ""
+	}
+	profiles, err := config.ListValidProfiles()
+	if err != nil {
+		klog.Warningf("unable to list profiles: %v", err)
+	}
+	for _, o := range profiles {
+		if o.Name == cc.Name || o.Config.Driver != cc.Driver || o.Config.Network != cc.Network {
+			continue
+		}
+		if o.Config.KubernetesConfig.APIServerHAVIP == vip {
+			return o.Name
+		}
+	}
+	return ""
+}
+
+// etcdMember returns whether the node has the data of an etcd member, which keeps its membership across restarts
+func etcdMember(r command.Runner) bool {
+	_, err := r.RunCmd(exec.Command("sudo", "test", "-d", path.Join(bsutil.EtcdDataDir(), "member")))
+	return err == nil
+}
+
+// RestoreControlPlane starts the machine of the secondary control-plane node n of a restarted HA cluster, along with
+// its etcd member. The primary control plane waits for the API server, which needs the quorum of etcd.
+func RestoreControlPlane(cc *config.ClusterConfig, n config.Node) error {
+	r, _, api, _, err := startMachine(cc, &n, false)
+	if err != nil {
+		return err
+	}
+	if !etcdMember(r) {
+		klog.Infof("control-plane node %q has no etcd data, it will rejoin the cluster", n.Name)
+		return nil
+	}
+
+	sv, err := util.ParseKubernetesVersion(n.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "Failed to parse Kubernetes version")
+	}
+	cr := configureRuntimes(r, *cc, n, sv)
+
+	bs, err := cluster.Bootstrapper(api, viper.GetString(cmdcfg.Bootstrapper), *cc, r)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get bootstrapper")
+	}
+	if err := bs.SetupCerts(*cc, n); err != nil {
+		return errors.Wrap(err, "setting up certs")
+	}
+	if err := bs.UpdateNode(*cc, n, cr); err != nil {
+		return errors.Wrap(err, "update node")
+	}
+	return bs.RestoreControlPlane(*cc, n)
+}
+
 // joinCluster adds new or prepares and then adds existing node to the cluster.
 func joinCluster(starter Starter, cpBs bootstrapper.Bootstrapper, bs bootstrapper.Bootstrapper) error {
 	start := time.Now()
@@ -237,7 +352,28 @@ func joinCluster(starter Starter, cpBs bootstrapper.Bootstrapper, bs bootstrappe
 		klog.Infof("JoinCluster complete in %s", time.Since(start))
 	}()
 
-	joinCmd, err := cpBs.GenerateToken(*starter.Cfg)
+	if starter.Node.ControlPlane {
+		joinMutex.Lock()
+		defer joinMutex.Unlock()
+	}
+
+	if starter.PreExists && starter.Node.ControlPlane {
+		if etcdMember(starter.Runner) {
+			klog.Infof("control-plane node %q already joined the cluster, restoring it", starter.Node.Name)
+			return bs.RestoreControlPlane(*starter.Cfg, *starter.Node)
+		}
+
+		// rejoining adds a new etcd member, the stale one would break the quorum
+		klog.Infof("removing etcd member and data of control-plane node %q before attempting to rejoin cluster", starter.Node.Name)
+		if err := removeEtcdMember(*starter.Cfg, *starter.Node); err != nil {
+			klog.Errorf("error removing etcd member before rejoining cluster, will continue anyway: %v", err)
+		}
+		if _, err := starter.Runner.RunCmd(exec.Command("sudo", "rm", "-rf", bsutil.EtcdDataDir())); err != nil {
+			klog.Errorf("error removing etcd data before rejoining cluster, will continue anyway: %v", err)
+		}
+	}
+
+	joinCmd, err := cpBs.GenerateToken(*starter.Cfg, *starter.Node)
 	if err != nil {
 		return fmt.Errorf("error generating join token: %w", err)
 	}
@@ -245,11 +381,11 @@ func joinCluster(starter Starter, cpBs bootstrapper.Bootstrapper, bs bootstrappe
 	// avoid "error execution phase kubelet-start: a Node with name "<name>" and status "Ready" already exists in the cluster.
 	// You must delete the existing Node or change the name of this new joining Node"
 	if starter.PreExists {
-		klog.Infof("removing existing worker node %q before attempting to rejoin cluster: %+v", starter.Node.Name, starter.Node)
+		klog.Infof("removing existing node %q before attempting to rejoin cluster: %+v", starter.Node.Name, starter.Node)
 		if _, err := drainNode(*starter.Cfg, starter.Node.Name); err != nil {
-			klog.Errorf("error removing existing worker node before rejoining cluster, will continue anyway: %v", err)
+			klog.Errorf("error removing existing node before rejoining cluster, will continue anyway: %v", err)
 		}
-		klog.Infof("successfully removed existing worker node %q from cluster: %+v", starter.Node.Name, starter.Node)
+		klog.Infof("successfully removed existing node %q from cluster: %+v", starter.Node.Name, starter.Node)
 	}
 
 	join := func() error {
@@ -278,15 +414,22 @@ func joinCluster(starter Starter, cpBs bootstrapper.Bootstrapper, bs bootstrappe
 
 // Provision provisions the machine/container for the node
 func Provision(cc *config.ClusterConfig, n *config.Node, apiServer bool, delOnFail bool) (command.Runner, bool, libmachine.API, *host.Host, error) {
-	register.Reg.SetStep(register.StartingNode)
 	name := config.MachineName(*cc, *n)
 	if apiServer {
+		register.Reg.SetStep(register.StartingNode)
 		out.Step(style.ThumbsUp, "Starting control plane node {{.name}} in cluster {{.cluster}}", out.V{"name": name, "cluster": cc.Name})
 	} else {
-		out.Step(style.ThumbsUp, "Starting node {{.name}} in cluster {{.cluster}}", out.V{"name": name, "cluster": cc.Name})
+		out.NodeStep(name, register.StartingNode, style.ThumbsUp, "Starting node {{.name}} in cluster {{.cluster}}", out.V{"name": name, "cluster": cc.Name})
 	}
 
+	downloadMutex.Lock()
 	if driver.IsKIC(cc.Driver) {
+		if apiServer {
+			register.Reg.SetStep(register.PullingBaseImage)
+			out.Step(style.Pulling, "Pulling base image ...")
+		} else {
+			out.NodeStep(name, register.PullingBaseImage, style.Pulling, "Pulling base image for node {{.name}} ...", out.V{"name": name})
+		}
 		beginDownloadKicBaseImage(&kicGroup, cc, viper.GetBool("download-only"))
 	}
 
@@ -296,27 +439,32 @@ func Provision(cc *config.ClusterConfig, n *config.Node, apiServer bool, delOnFa
 
 	// Abstraction leakage alert: startHost requires the config to be saved, to satistfy pkg/provision/buildroot.
 	// Hence, SaveProfile must be called before startHost, and again afterwards when we know the IP.
-	if err := config.SaveProfile(viper.GetString(config.ProfileName), cc); err != nil {
+	// The other nodes may be started concurrently, with their own copies of the config.
+	var err error
+	if apiServer {
+		err = config.SaveProfile(viper.GetString(config.ProfileName), cc)
+	} else {
+		err = config.SaveNode(cc, n)
+	}
+	if err != nil {
+		downloadMutex.Unlock()
 		return nil, false, nil, nil, errors.Wrap(err, "Failed to save config")
 	}
 
 	handleDownloadOnly(&cacheGroup, &kicGroup, n.KubernetesVersion, cc.KubernetesConfig.ContainerRuntime, cc.Driver)
 	waitDownloadKicBaseImage(&kicGroup)
+	downloadMutex.Unlock()
 
-	return startMachine(cc, n, delOnFail)
+	r, preExists, api, h, err := startMachine(cc, n, delOnFail)
+	if err == nil && cc.RegistryCache {
+		startRegistryCache(cc, h)
+	}
+	return r, preExists, api, h, err
 }
 
 // ConfigureRuntimes does what needs to happen to get a runtime going.
-func configureRuntimes(runner cruntime.CommandRunner, cc config.ClusterConfig, kv semver.Version) cruntime.Manager {
-	co := cruntime.Config{
-		Type:              cc.KubernetesConfig.ContainerRuntime,
-		Socket:            cc.KubernetesConfig.CRISocket,
-		Runner:            runner,
-		ImageRepository:   cc.KubernetesConfig.ImageRepository,
-		KubernetesVersion: kv,
-		InsecureRegistry:  cc.InsecureRegistry,
-	}
-	cr, err := cruntime.New(co)
+func configureRuntimes(runner command.Runner, cc config.ClusterConfig, n config.Node, kv semver.Version) cruntime.Manager {
+	cr, err := cruntime.New(runtimeConfig(runner, cc, kv))
 	if err != nil {
 		exit.Error(reason.InternalRuntime, "Failed runtime", err)
 	}
@@ -329,7 +477,11 @@ func configureRuntimes(runner cruntime.CommandRunner, cc config.ClusterConfig, k
 	// Preload is overly invasive for bare metal, and caching is not meaningful.
 	// KIC handles preload elsewhere.
 	if driver.IsVM(cc.Driver) {
-		if err := cr.Preload(cc); err != nil {
+		span := trace.SpanName(trace.Preload, config.MachineName(cc, n))
+		trace.StartSpan(span)
+		err := cr.Preload(cc)
+		trace.EndSpan(span)
+		if err != nil {
 			switch err.(type) {
 			case *cruntime.ErrISOFeature:
 				out.ErrT(style.Tip, "Existing disk is missing new features ({{.error}}). To upgrade, run 'minikube delete'", out.V{"error": err})
@@ -343,6 +495,10 @@ func configureRuntimes(runner cruntime.CommandRunner, cc config.ClusterConfig, k
 		}
 	}
 
+	if !driver.BareMetal(cc.Driver) {
+		configureHostCerts(runner, cc)
+	}
+
 	err = cr.Enable(disableOthers, forceSystemd())
 	if err != nil {
 		exit.Error(reason.RuntimeEnable, "Failed to enable container runtime", err)
@@ -362,6 +518,49 @@ func configureRuntimes(runner cruntime.CommandRunner, cc config.ClusterConfig, k
 	return cr
 }
 
+// configureHostCerts adds the CA certificates of the host to a node if the cluster imports them, before the container runtime starts
+func configureHostCerts(runner command.Runner, cc config.ClusterConfig) {
+	var certs []byte
+	if cc.ImportHostCerts {
+		var err error
+		certs, err = bootstrapper.HostCACerts()
+		if err != nil {
+			out.WarningT("Unable to import the trust store of the host: {{.error}}", out.V{"error": err})
+			return
+		}
+	}
+	if err := bootstrapper.InstallHostCACerts(runner, certs); err != nil {
+		exit.Error(reason.GuestCert, "Failed to install the CA certificates of the host", err)
+	}
+}
+
+// runtimeConfig returns the config of the container runtime of the nodes of a cluster
+func runtimeConfig(runner cruntime.CommandRunner, cc config.ClusterConfig, kv semver.Version) cruntime.Config {
+	return cruntime.Config{
+		Type:              cc.KubernetesConfig.ContainerRuntime,
+		Socket:            cc.KubernetesConfig.CRISocket,
+		Runner:            runner,
+		ImageRepository:   cc.KubernetesConfig.ImageRepository,
+		KubernetesVersion: kv,
+		InsecureRegistry:  cc.InsecureRegistry,
+		RegistryMirror:    registrycache.Mirrors(cc),
+		Registries:        cc.Registries,
+	}
+}
+
+// ConfigureRegistries applies the registries config of a cluster to the container runtime of a running node
+func ConfigureRegistries(runner cruntime.CommandRunner, cc config.ClusterConfig) error {
+	kv, err := util.ParseKubernetesVersion(cc.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "parse kubernetes version")
+	}
+	cr, err := cruntime.New(runtimeConfig(runner, cc, kv))
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+	return cr.ConfigureRegistries()
+}
+
 func forceSystemd() bool {
 	return viper.GetBool("force-systemd") || os.Getenv(constants.MinikubeForceSystemdEnv) == "true"
 }
@@ -446,13 +645,47 @@ func setupKubeAdm(mAPI libmachine.API, cfg config.ClusterConfig, n config.Node,
 		exit.Error(reason.KubernetesInstallFailed, "Failed to update cluster", err)
 	}
 
-	if err := bs.SetupCerts(cfg.KubernetesConfig, n); err != nil {
+	if err := bs.SetupCerts(cfg, n); err != nil {
 		exit.Error(reason.GuestCert, "Failed to setup certs", err)
 	}
+	WarnExpiringCerts(r, cfg, n)
 
 	return bs
 }
 
+// WarnExpiringCerts warns about the certs of a cluster expiring within constants.CertExpiryWarning:
+// the ones of the minikube home, and the ones kubeadm manages on a control-plane node if its runner is not nil
+func WarnExpiringCerts(r command.Runner, cc config.ClusterConfig, n config.Node) {
+	certs, err := bootstrapper.LocalCerts(cc.Name)
+	if err != nil {
+		klog.Warningf("unable to read the certs of %s: %v", cc.Name, err)
+		return
+	}
+	if r != nil {
+		nc, err := bootstrapper.NodeCerts(r, config.MachineName(cc, n))
+		if err != nil {
+			klog.Warningf("unable to read the certs of %s: %v", config.MachineName(cc, n), err)
+		}
+		certs = append(certs, nc...)
+	}
+
+	expiring := bootstrapper.ExpiringCerts(certs, constants.CertExpiryWarning)
+	for _, c := range expiring {
+		name := c.Name
+		if c.Node != "" {
+			name = c.Node + ":" + c.Name
+		}
+		if time.Now().After(c.NotAfter) {
+			out.WarningT("The certificate {{.name}} expired on {{.date}}", out.V{"name": name, "date": c.NotAfter.Format(constants.TimeFormat)})
+			continue
+		}
+		out.WarningT("The certificate {{.name}} expires on {{.date}}", out.V{"name": name, "date": c.NotAfter.Format(constants.TimeFormat)})
+	}
+	if len(expiring) > 0 {
+		out.Styled(style.Tip, `To issue the certificates again, run: "{{.cmd}}"`, out.V{"cmd": mustload.ExampleCmd(cc.Name, "certs rotate")})
+	}
+}
+
 func setupKubeconfig(h *host.Host, cc *config.ClusterConfig, n *config.Node, clusterName string) *kubeconfig.Settings {
 	addr, err := apiServerURL(*h, *cc, *n)
 	if err != nil {
@@ -477,6 +710,13 @@ func setupKubeconfig(h *host.Host, cc *config.ClusterConfig, n *config.Node, clu
 	return kcs
 }
 
+// UpdateKubeconfig updates the kubeconfig entry of a cluster, keeping the current context, for it to use the certs issued again
+func UpdateKubeconfig(h *host.Host, cc config.ClusterConfig, n config.Node) error {
+	kcs := setupKubeconfig(h, &cc, &n, cc.Name)
+	kcs.KeepContext = true
+	return kubeconfig.Update(kcs)
+}
+
 func apiServerURL(h host.Host, cc config.ClusterConfig, n config.Node) (string, error) {
 	hostname, _, port, err := driver.ControlPlaneEndpoint(&cc, &n, h.DriverName)
 	if err != nil {
@@ -485,6 +725,31 @@ func apiServerURL(h host.Host, cc config.ClusterConfig, n config.Node) (string,
 	return fmt.Sprintf("https://" + net.JoinHostPort(hostname, strconv.Itoa(port))), nil
 }
 
+// startRegistryCache starts the registry cache of the host, without which the nodes pull from Docker Hub directly
+func startRegistryCache(cc *config.ClusterConfig, h *host.Host) {
+	ociBin, err := registrycache.OCIBinary(cc.Driver)
+	if err == nil {
+		err = registrycache.Start(ociBin, registryCacheAddress(cc, h))
+	}
+	if err != nil {
+		out.WarningT("Unable to start the registry cache, images will be pulled from Docker Hub: {{.error}}", out.V{"error": err})
+	}
+}
+
+// registryCacheAddress returns the address of the host the nodes reach the registry cache on: the one they reach the
+// host on, or the loopback when the ports are forwarded to the host, as the ones of the nodes
+func registryCacheAddress(cc *config.ClusterConfig, h *host.Host) string {
+	if driver.NeedsPortForward(cc.Driver) || driver.BareMetal(cc.Driver) {
+		return oci.DefaultBindIPV4
+	}
+	ip, err := cluster.HostIP(h, cc.Name)
+	if err != nil {
+		klog.Warningf("unable to get the host IP of the nodes, the registry cache is only published on %s: %v", oci.DefaultBindIPV4, err)
+		return oci.DefaultBindIPV4
+	}
+	return ip.String()
+}
+
 // StartMachine starts a VM
 func startMachine(cfg *config.ClusterConfig, node *config.Node, delOnFail bool) (runner command.Runner, preExists bool, machineAPI libmachine.API, host *host.Host, err error) {
 	m, err := machine.NewAPIClient()
@@ -99,6 +99,22 @@ func Step(st style.Enum, format string, a ...V) {
 	Styled(st, format, a...)
 }
 
+// NodeStep writes a stylized and templated message for a step of a node started concurrently with others,
+// which goes through the steps on its own
+func NodeStep(node string, step register.RegStep, st style.Enum, format string, a ...V) {
+	outStyled, spinner := stylized(st, useColor, format, a...)
+	if JSON {
+		register.PrintNodeStep(node, step, outStyled)
+		return
+	}
+	register.RecordStep(outStyled)
+	// the spinner can not be shared by the nodes, each step gets its own line
+	if spinner {
+		outStyled += "\n"
+	}
+	String(outStyled)
+}
+
 // Styled writes a stylized and templated message to stdout
 func Styled(st style.Enum, format string, a ...V) {
 	if JSON || st == style.Option {
@@ -22,6 +22,12 @@ func PrintStep(message string) {
 	printAndRecordCloudEvent(s, s.data)
 }
 
+// PrintNodeStep prints the step of a node started concurrently with others in JSON format
+func PrintNodeStep(node string, step RegStep, message string) {
+	s := NewNodeStep(node, step, message)
+	printAndRecordCloudEvent(s, s.data)
+}
+
 // RecordStep records a Step type in JSON format
 func RecordStep(message string) {
 	s := NewStep(message)
@@ -46,6 +46,32 @@ func TestPrintStep(t *testing.T) {
 	}
 }
 
+func TestPrintNodeStep(t *testing.T) {
+	Reg.SetStep(InitialSetup)
+
+	expected := `{"data":{"currentstep":"11","message":"message","name":"Preparing Kubernetes","node":"minikube-m02","totalsteps":"%v"},"datacontenttype":"application/json","id":"random-id","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.step"}`
+	expected = fmt.Sprintf(expected, Reg.totalSteps())
+	expected += "\n"
+
+	buf := bytes.NewBuffer([]byte{})
+	SetOutputFile(buf)
+	defer func() { SetOutputFile(os.Stdout) }()
+
+	GetUUID = func() string {
+		return "random-id"
+	}
+
+	PrintNodeStep("minikube-m02", PreparingKubernetes, "message")
+	actual := buf.String()
+
+	if actual != expected {
+		t.Fatalf("expected didn't match actual:\nExpected:\n%v\n\nActual:\n%v", expected, actual)
+	}
+	if Reg.currentName() != string(InitialSetup) {
+		t.Errorf("PrintNodeStep() changed the current step to %q", Reg.currentName())
+	}
+}
+
 func TestPrintInfo(t *testing.T) {
 	expected := `{"data":{"message":"info"},"datacontenttype":"application/json","id":"random-id","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.info"}`
 	expected += "\n"
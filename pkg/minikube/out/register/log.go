@@ -43,7 +43,20 @@ func NewStep(message string) *Step {
 		"totalsteps":  Reg.totalSteps(),
 		"currentstep": Reg.currentStep(),
 		"message":     strings.TrimSpace(message),
-		"name":        string(Reg.current),
+		"name":        Reg.currentName(),
+	}}
+}
+
+// NewNodeStep returns a new step type for a node started concurrently with others, which goes through the steps on its own
+func NewNodeStep(node string, step RegStep, message string) *Step {
+	Reg.mu.Lock()
+	defer Reg.mu.Unlock()
+	return &Step{data: map[string]string{
+		"totalsteps":  fmt.Sprintf("%d", len(Reg.steps[Reg.first])-1),
+		"currentstep": Reg.stepIndex(step),
+		"message":     strings.TrimSpace(message),
+		"name":        string(step),
+		"node":        node,
 	}}
 }
 
@@ -19,6 +19,7 @@ package register
 
 import (
 	"fmt"
+	"sync"
 
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/trace"
@@ -58,8 +59,9 @@ const (
 type RegStep string
 
 // Register holds all of the steps we could see in `minikube start`
-// and keeps track of the current step
+// and keeps track of the current step, which the nodes started concurrently may set
 type Register struct {
+	mu      sync.Mutex
 	steps   map[RegStep][]RegStep
 	first   RegStep
 	current RegStep
@@ -105,11 +107,27 @@ func init() {
 
 // totalSteps returns the total number of steps in the register
 func (r *Register) totalSteps() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	return fmt.Sprintf("%d", len(r.steps[r.first])-1)
 }
 
 // currentStep returns the current step we are on
 func (r *Register) currentStep() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stepIndex(r.current)
+}
+
+// currentName returns the name of the current step
+func (r *Register) currentName() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return string(r.current)
+}
+
+// stepIndex returns the index of a step within the registered steps
+func (r *Register) stepIndex(step RegStep) string {
 	if r.first == RegStep("") {
 		return ""
 	}
@@ -120,18 +138,20 @@ func (r *Register) currentStep() string {
 	}
 
 	for i, s := range r.steps[r.first] {
-		if r.current == s {
+		if step == s {
 			return fmt.Sprintf("%d", i)
 		}
 	}
 
 	// Warn, as sometimes detours happen: "start" may cause "stopping" and "deleting"
-	klog.Warningf("%q was not found within the registered steps for %q: %v", r.current, r.first, steps)
+	klog.Warningf("%q was not found within the registered steps for %q: %v", step, r.first, steps)
 	return ""
 }
 
 // SetStep sets the current step
 func (r *Register) SetStep(s RegStep) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	defer trace.StartSpan(string(s))
 	if r.first == RegStep("") {
 		_, ok := r.steps[s]
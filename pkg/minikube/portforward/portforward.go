@@ -0,0 +1,246 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package portforward manages the ports of the host forwarded to the nodes of a docker or podman
+// profile by proxy containers, added by "minikube port add" and restarted by "minikube start".
+package portforward
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// Status is the state of a forwarded port of a profile
+type Status struct {
+	config.PortForward
+	Container string
+	Running   bool
+}
+
+// Parse parses a port forward in the format [<listen address>:]<host port>:<node port>[/<protocol>]
+func Parse(spec string) (config.PortForward, error) {
+	pf := config.PortForward{Protocol: "tcp"}
+	ports := spec
+	if i := strings.LastIndex(spec, "/"); i >= 0 {
+		ports, pf.Protocol = spec[:i], strings.ToLower(spec[i+1:])
+	}
+	if pf.Protocol != "tcp" && pf.Protocol != "udp" {
+		return pf, fmt.Errorf("invalid protocol %q in %q, expected tcp or udp", pf.Protocol, spec)
+	}
+
+	// the last two fields are ports, what comes before is an address, which may be IPv6
+	i := strings.LastIndex(ports, ":")
+	if i < 0 {
+		return pf, fmt.Errorf("invalid port forward %q, expected [<listen address>:]<host port>:<node port>[/<protocol>]", spec)
+	}
+	nodePort := ports[i+1:]
+	hostPort := ports[:i]
+	if j := strings.LastIndex(hostPort, ":"); j >= 0 {
+		pf.ListenAddress = strings.Trim(hostPort[:j], "[]")
+		hostPort = hostPort[j+1:]
+		if net.ParseIP(pf.ListenAddress) == nil {
+			return pf, fmt.Errorf("invalid listen address %q in %q", pf.ListenAddress, spec)
+		}
+	}
+
+	var err error
+	if pf.HostPort, err = parsePort(hostPort); err != nil {
+		return pf, errors.Wrapf(err, "host port of %q", spec)
+	}
+	if pf.NodePort, err = parsePort(nodePort); err != nil {
+		return pf, errors.Wrapf(err, "node port of %q", spec)
+	}
+	return pf, nil
+}
+
+func parsePort(s string) (int, error) {
+	p, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid port %q", s)
+	}
+	if p < 1 || p > 65535 {
+		return 0, fmt.Errorf("port %d is out of range 1-65535", p)
+	}
+	return p, nil
+}
+
+// String returns the port forward in the format of Parse, without the defaults
+func String(pf config.PortForward) string {
+	s := fmt.Sprintf("%d:%d", pf.HostPort, pf.NodePort)
+	if pf.ListenAddress != "" {
+		s = net.JoinHostPort(pf.ListenAddress, strconv.Itoa(pf.HostPort)) + fmt.Sprintf(":%d", pf.NodePort)
+	}
+	if pf.Protocol != "tcp" {
+		s += "/" + pf.Protocol
+	}
+	return s
+}
+
+// Find returns the index of the port forward of a host port and protocol in pfs, or -1
+func Find(pfs []config.PortForward, hostPort int, protocol string) int {
+	for i, pf := range pfs {
+		if pf.HostPort == hostPort && pf.Protocol == protocol {
+			return i
+		}
+	}
+	return -1
+}
+
+// ContainerName returns the name of the proxy container of a port forward of a profile
+func ContainerName(profile string, pf config.PortForward) string {
+	return fmt.Sprintf("%s-port-%d-%s", profile, pf.HostPort, pf.Protocol)
+}
+
+// List returns the status of the port forwards of a cluster
+func List(cc *config.ClusterConfig) []Status {
+	var sts []Status
+	for _, pf := range cc.PortForwards {
+		st := Status{PortForward: pf, Container: ContainerName(cc.Name, pf)}
+		s, err := oci.ContainerStatus(cc.Driver, st.Container)
+		if err != nil {
+			klog.Infof("status of %s: %v", st.Container, err)
+		}
+		st.Running = s == state.Running
+		sts = append(sts, st)
+	}
+	return sts
+}
+
+// Start starts the proxy container of pf, creating it if it does not exist
+func Start(cc *config.ClusterConfig, pf config.PortForward) error {
+	name := ContainerName(cc.Name, pf)
+	exists, err := oci.ContainerExists(cc.Driver, name)
+	if err != nil {
+		return errors.Wrap(err, "container exists")
+	}
+	if exists {
+		klog.Infof("starting existing port forward %s", name)
+		return oci.StartContainer(cc.Driver, name)
+	}
+
+	n, err := node(cc, pf.Node)
+	if err != nil {
+		return err
+	}
+	p := oci.PortForwardParams{
+		Name:          name,
+		Profile:       cc.Name,
+		Image:         cc.KicBaseImage,
+		ListenAddress: listenAddress(cc, pf),
+		HostPort:      pf.HostPort,
+		Protocol:      pf.Protocol,
+		NodeIP:        n.IP,
+		NodePort:      pf.NodePort,
+		OCIBinary:     cc.Driver,
+	}
+	// the node is on the default network when minikube could not create the one of the cluster
+	network := cc.Network
+	if network == "" {
+		network = cc.Name
+	}
+	if oci.NetworkExists(cc.Driver, network) {
+		p.Network = network
+	}
+	klog.Infof("creating port forward %s to %s:%d", name, n.IP, pf.NodePort)
+	return oci.CreatePortForward(p)
+}
+
+// Stop stops the proxy container of pf, if it is running
+func Stop(cc *config.ClusterConfig, pf config.PortForward) error {
+	name := ContainerName(cc.Name, pf)
+	running, err := oci.ContainerRunning(cc.Driver, name)
+	if err != nil || !running {
+		return nil
+	}
+	return oci.StopContainer(cc.Driver, name)
+}
+
+// Remove deletes the proxy container of pf
+func Remove(cc *config.ClusterConfig, pf config.PortForward) error {
+	name := ContainerName(cc.Name, pf)
+	exists, err := oci.ContainerExists(cc.Driver, name)
+	if err != nil || !exists {
+		return err
+	}
+	return oci.RemoveContainer(cc.Driver, name)
+}
+
+// StopAll stops the proxy containers of the port forwards of a cluster
+func StopAll(cc *config.ClusterConfig) error {
+	var errs []string
+	for _, pf := range cc.PortForwards {
+		if err := Stop(cc, pf); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// RemoveAll deletes the proxy containers of a profile, including the ones no longer in its config
+func RemoveAll(ociBin string, profile string) error {
+	cs, err := oci.ListContainersByLabel(context.Background(), ociBin, fmt.Sprintf("%s=%s", oci.PortForwardLabelKey, profile))
+	if err != nil {
+		return err
+	}
+	var errs []string
+	for _, c := range cs {
+		if err := oci.RemoveContainer(ociBin, c); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// listenAddress returns the address of the host pf listens on, the one of the node containers by default
+func listenAddress(cc *config.ClusterConfig, pf config.PortForward) string {
+	switch {
+	case pf.ListenAddress != "":
+		return pf.ListenAddress
+	case cc.ListenAddress != "":
+		return cc.ListenAddress
+	case oci.IsExternalDaemonHost(cc.Driver):
+		return "0.0.0.0"
+	}
+	return oci.DefaultBindIPV4
+}
+
+// node returns the node of a cluster with the given name, the primary control plane if name is empty
+func node(cc *config.ClusterConfig, name string) (config.Node, error) {
+	if name == "" {
+		return config.PrimaryControlPlane(cc)
+	}
+	for _, n := range cc.Nodes {
+		if n.Name == name || config.MachineName(*cc, n) == name {
+			return n, nil
+		}
+	}
+	return config.Node{}, fmt.Errorf("node %q does not exist", name)
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portforward
+
+import (
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    config.PortForward
+		wantErr bool
+	}{
+		{"8080:80", config.PortForward{HostPort: 8080, NodePort: 80, Protocol: "tcp"}, false},
+		{"5353:53/udp", config.PortForward{HostPort: 5353, NodePort: 53, Protocol: "udp"}, false},
+		{"0.0.0.0:8443:30443/TCP", config.PortForward{ListenAddress: "0.0.0.0", HostPort: 8443, NodePort: 30443, Protocol: "tcp"}, false},
+		{"[::1]:8080:80", config.PortForward{ListenAddress: "::1", HostPort: 8080, NodePort: 80, Protocol: "tcp"}, false},
+		{"8080", config.PortForward{}, true},
+		{"8080:80/sctp", config.PortForward{}, true},
+		{"http:80", config.PortForward{}, true},
+		{"8080:70000", config.PortForward{}, true},
+		{"localhost:8080:80", config.PortForward{}, true},
+	}
+	for _, tc := range tests {
+		got, err := Parse(tc.spec)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("Parse(%q) error = %v; want error: %v", tc.spec, err, tc.wantErr)
+			continue
+		}
+		if !tc.wantErr && got != tc.want {
+			t.Errorf("Parse(%q) = %+v; want %+v", tc.spec, got, tc.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	for _, spec := range []string{"8080:80", "5353:53/udp", "0.0.0.0:8443:30443", "[::1]:8080:80"} {
+		pf, err := Parse(spec)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", spec, err)
+		}
+		if got := String(pf); got != spec {
+			t.Errorf("String(Parse(%q)) = %q", spec, got)
+		}
+	}
+}
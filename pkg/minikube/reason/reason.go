@@ -248,6 +248,16 @@ var (
 	HostCurrentUser = Kind{ID: "HOST_CURRENT_USER", ExitCode: ExHostConfig}
 	// minikube failed to delete cached images from host
 	HostDelCache = Kind{ID: "HOST_DEL_CACHE", ExitCode: ExHostError}
+	// minikube failed to read the audit log from the host
+	HostAudit = Kind{ID: "HOST_AUDIT", ExitCode: ExHostError}
+	// minikube failed to list or delete the snapshots stored on the host
+	HostSnapshot = Kind{ID: "HOST_SNAPSHOT", ExitCode: ExHostError}
+	// minikube failed to write the diagnostic bundle
+	HostDiagnose = Kind{ID: "HOST_DIAGNOSE", ExitCode: ExHostError}
+	// minikube failed to write or import an offline bundle
+	HostBundle = Kind{ID: "HOST_BUNDLE", ExitCode: ExHostError}
+	// minikube failed to read the input of a command from stdin
+	HostReadStdin = Kind{ID: "HOST_READ_STDIN", ExitCode: ExHostError}
 	// minikube failed to kill a mount process
 	HostKillMountProc = Kind{ID: "HOST_KILL_MOUNT_PROC", ExitCode: ExHostError}
 	// minikube failed to update host Kubernetes resources config
@@ -288,6 +298,16 @@ var (
 	DrvUnsupportedOS = Kind{ID: "DRV_UNSUPPORTED_OS", ExitCode: ExDriverUnsupported}
 	// the driver in use does not support the selected profile or multiple profiles
 	DrvUnsupportedProfile = Kind{ID: "DRV_UNSUPPORTED_PROFILE", ExitCode: ExDriverUnsupported}
+	// the driver in use does not support snapshots
+	DrvUnsupportedSnapshot = Kind{ID: "DRV_UNSUPPORTED_SNAPSHOT", ExitCode: ExDriverUnsupported}
+	// the driver in use does not support resizing existing machines
+	DrvUnsupportedResize = Kind{ID: "DRV_UNSUPPORTED_RESIZE", ExitCode: ExDriverUnsupported}
+	// the driver in use does not support forwarding ports of the host to running nodes
+	DrvUnsupportedPortForward = Kind{ID: "DRV_UNSUPPORTED_PORT_FORWARD", ExitCode: ExDriverUnsupported}
+	// the driver can not start clusters from the files of an offline bundle
+	DrvUnsupportedBundle = Kind{ID: "DRV_UNSUPPORTED_BUNDLE", ExitCode: ExDriverUnsupported}
+	// the driver in use does not support HA clusters, as their control planes are only reachable through forwarded ports
+	DrvUnsupportedHA = Kind{ID: "DRV_UNSUPPORTED_HA", ExitCode: ExDriverUnsupported}
 	// minikube failed to locate specified driver
 	DrvNotFound = Kind{ID: "DRV_NOT_FOUND", ExitCode: ExDriverNotFound}
 	// minikube could not find a valid driver
@@ -305,6 +325,14 @@ var (
 	GuestCacheLoad = Kind{ID: "GUEST_CACHE_LOAD", ExitCode: ExGuestError}
 	// minikube failed to setup certificates
 	GuestCert = Kind{ID: "GUEST_CERT", ExitCode: ExGuestError}
+	// minikube failed to copy files to or from a node
+	GuestCopy = Kind{ID: "GUEST_COPY", ExitCode: ExGuestError}
+	// minikube failed to save or restore a snapshot of the cluster nodes
+	GuestSnapshot = Kind{ID: "GUEST_SNAPSHOT", ExitCode: ExGuestError}
+	// minikube failed to change the resources of the cluster nodes
+	GuestResize = Kind{ID: "GUEST_RESIZE", ExitCode: ExGuestError}
+	// minikube failed to start or remove the proxy container forwarding a port of the host to a node
+	GuestPortForward = Kind{ID: "GUEST_PORT_FORWARD", ExitCode: ExGuestError}
 	// minikube failed to access the control plane
 	GuestCpConfig = Kind{ID: "GUEST_CP_CONFIG", ExitCode: ExGuestConfig}
 	// minikube failed to properly delete a resource, such as a profile
@@ -317,6 +345,14 @@ var (
 	GuestImageRemove = Kind{ID: "GUEST_IMAGE_REMOVE", ExitCode: ExGuestError}
 	// minikube failed to build an image
 	GuestImageBuild = Kind{ID: "GUEST_IMAGE_BUILD", ExitCode: ExGuestError}
+	// minikube failed to save an image
+	GuestImageSave = Kind{ID: "GUEST_IMAGE_SAVE", ExitCode: ExGuestError}
+	// minikube failed to tag an image
+	GuestImageTag = Kind{ID: "GUEST_IMAGE_TAG", ExitCode: ExGuestError}
+	// minikube failed to push an image
+	GuestImagePush = Kind{ID: "GUEST_IMAGE_PUSH", ExitCode: ExGuestError}
+	// minikube failed to pull an image
+	GuestImagePull = Kind{ID: "GUEST_IMAGE_PULL", ExitCode: ExGuestError}
 	// minikube failed to load host
 	GuestLoadHost = Kind{ID: "GUEST_LOAD_HOST", ExitCode: ExGuestError}
 	// minkube failed to create a mount
@@ -371,6 +407,8 @@ var (
 	InetCacheKubectl = Kind{ID: "INET_CACHE_KUBECTL", ExitCode: ExInternetError}
 	// minikube failed to cache required images to tar files
 	InetCacheTar = Kind{ID: "INET_CACHE_TAR", ExitCode: ExInternetError}
+	// minikube failed to download the files of an offline bundle
+	InetBundleCreate = Kind{ID: "INET_BUNDLE_CREATE", ExitCode: ExInternetError}
 	// minikube was unable to access main repository and mirrors for images
 	InetRepo = Kind{ID: "INET_REPO", ExitCode: ExInternetError}
 	// minikube was unable to access any known image repositories
@@ -384,6 +422,8 @@ var (
 	RuntimeEnable = Kind{ID: "RUNTIME_ENABLE", ExitCode: ExRuntimeError}
 	// minikube failed to cache images for the current container runtime
 	RuntimeCache = Kind{ID: "RUNTIME_CACHE", ExitCode: ExRuntimeError}
+	// minikube failed to apply the registries config to the current container runtime
+	RuntimeRegistries = Kind{ID: "RUNTIME_REGISTRIES", ExitCode: ExRuntimeError}
 
 	// service check timed out while starting minikube dashboard
 	SvcCheckTimeout = Kind{ID: "SVC_CHECK_TIMEOUT", ExitCode: ExSvcTimeout}
@@ -0,0 +1,171 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package registrycache runs a pull-through cache of Docker Hub on the host, shared by all the profiles
+// started with "minikube start --registry-cache". Their nodes pull through it as a mirror, reached at host.minikube.internal.
+package registrycache
+
+import (
+	"fmt"
+	"net"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/mutex"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/util/lock"
+)
+
+const (
+	// ContainerName is the name of the registry container of the cache
+	ContainerName = "minikube-registry-cache"
+	// Volume stores the cached images, it is kept when profiles or the container are deleted
+	Volume = "minikube-registry-cache"
+	// Image is the image of the registry, the one of the registry addon
+	Image = "registry:2.7.1@sha256:d5459fcb27aecc752520df4b492b08358a1912fcdfa454f7d2101d4b09991daa"
+	// HostPort is the port of the host the cache is published on, on the addresses the nodes reach the host on
+	HostPort = 5050
+	// remoteURL is the registry the cache proxies
+	remoteURL = "https://registry-1.docker.io"
+)
+
+// URL returns the URL of the cache from the nodes
+func URL() string {
+	return fmt.Sprintf("http://%s:%d", constants.HostAlias, HostPort)
+}
+
+// Mirrors returns the mirrors of Docker Hub of a cluster: the cache first if it is enabled,
+// then the ones of its registries config and the ones of --registry-mirror
+func Mirrors(cc config.ClusterConfig) []string {
+	var mirrors []string
+	if cc.RegistryCache {
+		mirrors = append(mirrors, URL())
+	}
+	for _, r := range cc.Registries {
+		if r.Host == cruntime.DockerHub {
+			mirrors = append(mirrors, r.Mirrors...)
+		}
+	}
+	return append(mirrors, cc.RegistryMirror...)
+}
+
+// OCIBinary returns the container runtime of the host to run the cache with:
+// the one of the nodes with the docker and podman drivers, docker or else podman with the others
+func OCIBinary(drv string) (string, error) {
+	if driver.IsKIC(drv) {
+		return drv, nil
+	}
+	for _, bin := range []string{oci.Docker, oci.Podman} {
+		if _, err := exec.LookPath(bin); err == nil {
+			return bin, nil
+		}
+	}
+	return "", fmt.Errorf("the registry cache runs in a container on the host, which requires docker or podman")
+}
+
+// Start starts the cache, published on addr: the address of the host the nodes reach, which the LAN does not.
+// The cache is shared by the profiles: it is created again when it is not published on the address of the nodes
+// of a new one, on the addresses of the others which are still on the host.
+func Start(ociBin string, addr string) error {
+	// the nodes of a cluster are started concurrently, as well as the profiles
+	spec := lock.PathMutexSpec(filepath.Join(localpath.MiniPath(), ContainerName))
+	spec.Timeout = 5 * time.Minute
+	releaser, err := mutex.Acquire(spec)
+	if err != nil {
+		return errors.Wrap(err, "acquire lock")
+	}
+	defer releaser.Release()
+
+	exists, err := oci.ContainerExists(ociBin, ContainerName)
+	if err != nil {
+		return errors.Wrap(err, "container exists")
+	}
+	var published []string
+	if exists {
+		published, err = oci.RegistryCacheAddresses(ociBin, ContainerName)
+		if err != nil {
+			return err
+		}
+		if contains(published, addr) {
+			running, err := oci.ContainerRunning(ociBin, ContainerName)
+			if err != nil {
+				return errors.Wrap(err, "container running")
+			}
+			if running {
+				return nil
+			}
+			klog.Infof("starting existing registry cache %s", ContainerName)
+			// an address of a network which was removed since can not be listened on anymore
+			if err := oci.StartContainer(ociBin, ContainerName); err == nil {
+				return nil
+			}
+			klog.Warningf("unable to start registry cache %s, creating it again: %v", ContainerName, err)
+		}
+		if err := oci.RemoveContainer(ociBin, ContainerName); err != nil {
+			return err
+		}
+	}
+
+	hostAddrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return errors.Wrap(err, "host addresses")
+	}
+	addrs := listenAddresses(published, addr, hostAddrs)
+	klog.Infof("creating registry cache %s on %v, port %d", ContainerName, addrs, HostPort)
+	return oci.CreateRegistryCache(oci.RegistryCacheParams{
+		Name:            ContainerName,
+		Image:           Image,
+		Volume:          Volume,
+		HostPort:        HostPort,
+		ListenAddresses: addrs,
+		RemoteURL:       remoteURL,
+		OCIBinary:       ociBin,
+	})
+}
+
+// listenAddresses returns addr, followed by the published addresses which are still addresses of the host
+func listenAddresses(published []string, addr string, hostAddrs []net.Addr) []string {
+	addrs := []string{addr}
+	for _, p := range published {
+		if contains(addrs, p) {
+			continue
+		}
+		for _, a := range hostAddrs {
+			if ipnet, ok := a.(*net.IPNet); ok && ipnet.IP.Equal(net.ParseIP(p)) {
+				addrs = append(addrs, p)
+				break
+			}
+		}
+	}
+	return addrs
+}
+
+func contains(addrs []string, addr string) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
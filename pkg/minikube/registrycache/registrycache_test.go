@@ -0,0 +1,64 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registrycache
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestMirrors(t *testing.T) {
+	tests := []struct {
+		cc   config.ClusterConfig
+		want []string
+	}{
+		{config.ClusterConfig{}, nil},
+		{config.ClusterConfig{RegistryMirror: []string{"https://mirror.gcr.io"}}, []string{"https://mirror.gcr.io"}},
+		{config.ClusterConfig{RegistryCache: true}, []string{"http://host.minikube.internal:5050"}},
+		{config.ClusterConfig{RegistryCache: true, RegistryMirror: []string{"https://mirror.gcr.io"}}, []string{"http://host.minikube.internal:5050", "https://mirror.gcr.io"}},
+		{config.ClusterConfig{RegistryCache: true, RegistryMirror: []string{"https://mirror.gcr.io"}, Registries: []config.Registry{{Host: "docker.io", Mirrors: []string{"https://hub.example.com"}}, {Host: "quay.io", Mirrors: []string{"https://quay.example.com"}}}}, []string{"http://host.minikube.internal:5050", "https://hub.example.com", "https://mirror.gcr.io"}},
+	}
+	for _, tc := range tests {
+		if got := Mirrors(tc.cc); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Mirrors(cache=%v, mirrors=%v, registries=%v) = %v; want %v", tc.cc.RegistryCache, tc.cc.RegistryMirror, tc.cc.Registries, got, tc.want)
+		}
+	}
+}
+
+func TestListenAddresses(t *testing.T) {
+	hostAddrs := []net.Addr{
+		&net.IPNet{IP: net.ParseIP("127.0.0.1"), Mask: net.CIDRMask(8, 32)},
+		&net.IPNet{IP: net.ParseIP("192.168.49.1"), Mask: net.CIDRMask(24, 32)},
+	}
+	tests := []struct {
+		published []string
+		addr      string
+		want      []string
+	}{
+		{nil, "192.168.49.1", []string{"192.168.49.1"}},
+		{[]string{"192.168.49.1"}, "192.168.58.1", []string{"192.168.58.1", "192.168.49.1"}},
+		{[]string{"192.168.67.1", "192.168.49.1"}, "192.168.49.1", []string{"192.168.49.1"}},
+	}
+	for _, tc := range tests {
+		if got := listenAddresses(tc.published, tc.addr, hostAddrs); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("listenAddresses(%v, %q) = %v; want %v", tc.published, tc.addr, got, tc.want)
+		}
+	}
+}
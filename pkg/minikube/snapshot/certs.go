@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/otiai10/copy"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// saveCerts copies the certificates of the profile and the shared CA certificates to the snapshot dir,
+// the CA is only saved to check that it did not change when the snapshot is restored
+func saveCerts(profile string, dir string) error {
+	certs, err := profileCerts(localpath.Profile(profile))
+	if err != nil {
+		return err
+	}
+	for _, c := range certs {
+		if err := copy.Copy(filepath.Join(localpath.Profile(profile), c), filepath.Join(dir, certsDir, "profile", c)); err != nil {
+			return errors.Wrapf(err, "copying %s", c)
+		}
+	}
+	for _, c := range caFiles {
+		src := filepath.Join(localpath.MiniPath(), c)
+		if _, err := os.Stat(src); os.IsNotExist(err) {
+			continue
+		}
+		if err := copy.Copy(src, filepath.Join(dir, certsDir, "ca", c)); err != nil {
+			return errors.Wrapf(err, "copying %s", c)
+		}
+	}
+	return nil
+}
+
+// restoreCerts copies the certificates of the profile saved in the snapshot dir back to the profile.
+// The shared CA is never restored, as the other profiles depend on it: checkCA refuses the restore instead.
+func restoreCerts(profile string, dir string) error {
+	src := filepath.Join(dir, certsDir, "profile")
+	dst := localpath.Profile(profile)
+	certs, err := profileCerts(src)
+	if err != nil {
+		return err
+	}
+	for _, c := range certs {
+		klog.Infof("restoring %s to %s", c, dst)
+		if err := copy.Copy(filepath.Join(src, c), filepath.Join(dst, c)); err != nil {
+			return errors.Wrapf(err, "restoring %s", c)
+		}
+	}
+	return nil
+}
+
+// checkCA returns an error if the shared CA certificates differ from the ones saved in the snapshot dir,
+// the certificates of the snapshot would then not be trusted by the cluster clients
+func checkCA(dir string) error {
+	for _, c := range caFiles {
+		if filepath.Ext(c) != ".crt" {
+			continue
+		}
+		saved, err := ioutil.ReadFile(filepath.Join(dir, certsDir, "ca", c))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "reading saved %s", c)
+		}
+		current, err := ioutil.ReadFile(filepath.Join(localpath.MiniPath(), c))
+		if err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "reading %s", c)
+		}
+		if !bytes.Equal(saved, current) {
+			return fmt.Errorf("the snapshot was signed by a different %s than the one shared by all profiles, which is not restored", c)
+		}
+	}
+	return nil
+}
+
+// profileCerts returns the names of the certificates and keys in dir
+func profileCerts(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", dir)
+	}
+	var certs []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if ext := filepath.Ext(e.Name()); ext == ".crt" || ext == ".key" {
+			certs = append(certs, e.Name())
+		}
+	}
+	return certs, nil
+}
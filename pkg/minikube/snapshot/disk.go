@@ -0,0 +1,214 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// diskDriverRe matches the format of the disk of a libvirt domain, libvirt also adds a driver to the cdrom
+var diskDriverRe = regexp.MustCompile(`(<disk type=['"]file['"] device=['"]disk['"]>\s*<driver name=['"]qemu['"] type=['"])(\w+)`)
+
+// diskImage is the part of the output of "qemu-img info --output=json" which is used
+type diskImage struct {
+	Format        string `json:"format"`
+	BackingFile   string `json:"full-backing-filename"`
+	BackingFormat string `json:"backing-filename-format"`
+}
+
+// diskPath returns the path of the disk of a kvm2 machine, which is a qcow2 overlay once the machine has been snapshotted
+func diskPath(machineName string) string {
+	return filepath.Join(localpath.MachinePath(machineName), fmt.Sprintf("%s.rawdisk", machineName))
+}
+
+// snapshotDiskPath returns the path of the disk of a machine in a snapshot, the disks of the later snapshots and of the machine are backed by it
+func snapshotDiskPath(machineName string, dir string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s.disk", machineName))
+}
+
+// saveDisk moves the disk of a stopped machine into the snapshot, and replaces it with a qcow2 overlay backed by it
+func saveDisk(uri string, machineName string, dir string) error {
+	live := diskPath(machineName)
+	snap := snapshotDiskPath(machineName, dir)
+	img, err := diskImageInfo(live)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(live, snap); err != nil {
+		return errors.Wrap(err, "moving disk")
+	}
+	if err := createOverlay(snap, img.Format, live); err != nil {
+		if rerr := os.Rename(snap, live); rerr != nil {
+			klog.Errorf("unable to move %s back to %s: %v", snap, live, rerr)
+		}
+		return err
+	}
+	return setDiskFormat(uri, machineName, "qcow2")
+}
+
+// restoreDisk replaces the disk of a stopped machine with a new qcow2 overlay backed by its disk in the snapshot
+func restoreDisk(uri string, machineName string, dir string) error {
+	live := diskPath(machineName)
+	snap := snapshotDiskPath(machineName, dir)
+	img, err := diskImageInfo(snap)
+	if err != nil {
+		return err
+	}
+	tmp := live + ".restore"
+	if err := createOverlay(snap, img.Format, tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, live); err != nil {
+		os.Remove(tmp)
+		return errors.Wrap(err, "replacing disk")
+	}
+	return setDiskFormat(uri, machineName, "qcow2")
+}
+
+// detachDisk rebases the disks backed by the disk of a machine in a snapshot onto its own backing file, so that the snapshot can be removed
+func detachDisk(machineName string, dir string, dependents []string) error {
+	snap := snapshotDiskPath(machineName, dir)
+	if _, err := os.Stat(snap); err != nil {
+		return nil
+	}
+	base, err := diskImageInfo(snap)
+	if err != nil {
+		return err
+	}
+	for _, d := range dependents {
+		if _, err := os.Stat(d); err != nil {
+			continue
+		}
+		img, err := diskImageInfo(d)
+		if err != nil {
+			return err
+		}
+		if filepath.Clean(img.BackingFile) != filepath.Clean(snap) {
+			continue
+		}
+		klog.Infof("rebasing %s onto %q", d, base.BackingFile)
+		// the data of the snapshot which the backing file does not have is copied into the dependent disk
+		args := []string{"rebase", "-f", img.Format, "-b", base.BackingFile}
+		if base.BackingFile != "" {
+			args = append(args, "-F", base.BackingFormat)
+		}
+		if _, err := qemuImg(append(args, d)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createOverlay creates a qcow2 disk image at path, backed by base
+func createOverlay(base string, baseFormat string, path string) error {
+	base, err := filepath.Abs(base)
+	if err != nil {
+		return err
+	}
+	_, err = qemuImg("create", "-f", "qcow2", "-F", baseFormat, "-b", base, path)
+	return err
+}
+
+// diskImageInfo returns the format and the backing file of a disk image
+func diskImageInfo(path string) (*diskImage, error) {
+	out, err := qemuImg("info", "--output=json", path)
+	if err != nil {
+		return nil, err
+	}
+	return parseDiskImage(out)
+}
+
+// parseDiskImage parses the output of "qemu-img info --output=json"
+func parseDiskImage(b []byte) (*diskImage, error) {
+	img := &diskImage{}
+	if err := json.Unmarshal(b, img); err != nil {
+		return nil, errors.Wrap(err, "parsing qemu-img info")
+	}
+	return img, nil
+}
+
+// qemuImg runs qemu-img with the given arguments, and returns its output
+func qemuImg(args ...string) ([]byte, error) {
+	if _, err := exec.LookPath("qemu-img"); err != nil {
+		return nil, errors.Wrap(err, "qemu-img is required to snapshot kvm2 machines")
+	}
+	var stdout, stderr bytes.Buffer
+	c := exec.Command("qemu-img", args...)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	klog.Infof("Run: %v", c.Args)
+	if err := c.Run(); err != nil {
+		return nil, errors.Wrapf(err, "qemu-img %v: %s", args, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// setDiskFormat sets the format libvirt opens the disk of a kvm2 machine with.
+// virsh is used, as the kvm2 driver runs as a plugin which does not expose this.
+func setDiskFormat(uri string, machineName string, format string) error {
+	if uri == "" {
+		uri = "qemu:///system"
+	}
+	out, err := exec.Command("virsh", "-c", uri, "dumpxml", "--inactive", machineName).Output()
+	if err != nil {
+		return errors.Wrap(err, "virsh dumpxml")
+	}
+	xml, err := withDiskFormat(string(out), format)
+	if err != nil {
+		return err
+	}
+	if xml == string(out) {
+		return nil
+	}
+
+	f, err := ioutil.TempFile("", machineName+"-*.xml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(xml); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if out, err := exec.Command("virsh", "-c", uri, "define", f.Name()).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "virsh define: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// withDiskFormat returns the domain xml with the format of its disk replaced
+func withDiskFormat(xml string, format string) (string, error) {
+	if !diskDriverRe.MatchString(xml) {
+		return "", fmt.Errorf("no disk driver in the domain")
+	}
+	return diskDriverRe.ReplaceAllString(xml, "${1}"+format), nil
+}
@@ -0,0 +1,348 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package snapshot saves the state of a cluster, and restores a cluster to a saved state.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+const (
+	metadataFile = "snapshot.json"
+	configFile   = "config.json"
+	certsDir     = "certs"
+)
+
+// caFiles are the certificates shared between profiles, which are saved along with the certificates of the profile
+var caFiles = []string{"ca.crt", "ca.key", "proxy-client-ca.crt", "proxy-client-ca.key"}
+
+// validName matches the snapshot names, which are used in image tags and volume names
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+
+// ErrNotFound is returned when a snapshot does not exist
+var ErrNotFound = errors.New("snapshot not found")
+
+// Snapshot is the saved state of a cluster
+type Snapshot struct {
+	Name              string `json:"name"`
+	Profile           string `json:"profile"`
+	Driver            string `json:"driver"`
+	KubernetesVersion string `json:"kubernetesVersion"`
+	// Machines are the names of the machines of the nodes which were saved
+	Machines []string  `json:"machines"`
+	Created  time.Time `json:"created"`
+}
+
+// ValidateName checks that name can be used as a snapshot name
+func ValidateName(name string) error {
+	if !validName.MatchString(name) || len(name) > 63 {
+		return fmt.Errorf("invalid snapshot name %q: must be at most 63 lowercase letters, digits, '_', '.' or '-', starting with a letter or digit", name)
+	}
+	return nil
+}
+
+// Supported returns whether snapshots are supported for the driver
+func Supported(driverName string) bool {
+	return driver.IsKIC(driverName) || driverName == driver.KVM2
+}
+
+// Dir returns the directory a snapshot of a profile is stored in
+func Dir(profile string, name string) string {
+	return filepath.Join(localpath.Snapshots(profile), name)
+}
+
+// Save captures the disks of the nodes of the cluster, along with its configuration and certificates
+func Save(api libmachine.API, cc *config.ClusterConfig, name string) (*Snapshot, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	if !Supported(cc.Driver) {
+		return nil, fmt.Errorf("snapshots are not supported by the %s driver", cc.Driver)
+	}
+	dir := Dir(cc.Name, name)
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("snapshot %q already exists for profile %q", name, cc.Name)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrap(err, "creating snapshot dir")
+	}
+
+	s := &Snapshot{
+		Name:              name,
+		Profile:           cc.Name,
+		Driver:            cc.Driver,
+		KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
+		Created:           time.Now(),
+	}
+	for _, n := range cc.Nodes {
+		s.Machines = append(s.Machines, config.MachineName(*cc, n))
+	}
+
+	err := save(api, cc, s, dir)
+	if err != nil {
+		if derr := deleteSnapshot(s, dir); derr != nil {
+			klog.Warningf("unable to clean up snapshot %q: %v", name, derr)
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// save captures the disks of the nodes, then writes the configuration, the certificates and the metadata of the snapshot
+func save(api libmachine.API, cc *config.ClusterConfig, s *Snapshot, dir string) error {
+	for _, m := range s.Machines {
+		st, err := machine.Status(api, m)
+		if err != nil {
+			return errors.Wrapf(err, "getting status of %s", m)
+		}
+		// virtual machine disks are only consistent while the machine is stopped
+		if !driver.IsKIC(cc.Driver) && st != state.Stopped.String() {
+			return fmt.Errorf("node %s is %s: the cluster must be stopped to snapshot it with the %s driver", m, st, cc.Driver)
+		}
+		klog.Infof("saving snapshot %q of %s", s.Name, m)
+		if err := saveMachine(cc, m, s.Name, dir); err != nil {
+			return errors.Wrapf(err, "saving %s", m)
+		}
+	}
+
+	b, err := json.MarshalIndent(cc, "", "    ")
+	if err != nil {
+		return errors.Wrap(err, "marshalling config")
+	}
+	// as the config of the profile, it holds the credentials of the registries
+	if err := ioutil.WriteFile(filepath.Join(dir, configFile), b, 0600); err != nil {
+		return errors.Wrap(err, "writing config")
+	}
+	if err := saveCerts(cc.Name, dir); err != nil {
+		return errors.Wrap(err, "saving certificates")
+	}
+
+	b, err = json.MarshalIndent(s, "", "    ")
+	if err != nil {
+		return errors.Wrap(err, "marshalling snapshot")
+	}
+	return ioutil.WriteFile(filepath.Join(dir, metadataFile), b, 0644)
+}
+
+// Restore stops the cluster, and restores the disks of its nodes, its configuration and certificates from a snapshot.
+// The cluster has to be started again afterwards.
+func Restore(api libmachine.API, cc *config.ClusterConfig, name string) (*Snapshot, error) {
+	s, err := Load(cc.Name, name)
+	if err != nil {
+		return nil, err
+	}
+	if s.Driver != cc.Driver {
+		return nil, fmt.Errorf("snapshot %q was saved with the %s driver, but profile %q uses the %s driver", name, s.Driver, cc.Name, cc.Driver)
+	}
+	var machines []string
+	for _, n := range cc.Nodes {
+		machines = append(machines, config.MachineName(*cc, n))
+	}
+	if !sameMachines(machines, s.Machines) {
+		return nil, fmt.Errorf("snapshot %q was saved with the nodes %v, but profile %q has the nodes %v", name, s.Machines, cc.Name, machines)
+	}
+	dir := Dir(cc.Name, name)
+	if err := checkCA(dir); err != nil {
+		return nil, errors.Wrapf(err, "cannot restore snapshot %q", name)
+	}
+
+	for _, m := range s.Machines {
+		st, err := machine.Status(api, m)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting status of %s", m)
+		}
+		if st != state.Stopped.String() {
+			if err := machine.StopHost(api, m); err != nil {
+				return nil, errors.Wrapf(err, "stopping %s", m)
+			}
+		}
+	}
+
+	for _, m := range s.Machines {
+		klog.Infof("restoring snapshot %q of %s", name, m)
+		if err := restoreMachine(cc, m, name, dir); err != nil {
+			return nil, errors.Wrapf(err, "restoring %s", m)
+		}
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, configFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading config")
+	}
+	saved := &config.ClusterConfig{}
+	if err := json.Unmarshal(b, saved); err != nil {
+		return nil, errors.Wrap(err, "parsing config")
+	}
+	if err := config.SaveProfile(cc.Name, saved); err != nil {
+		return nil, errors.Wrap(err, "saving config")
+	}
+	if err := restoreCerts(cc.Name, dir); err != nil {
+		return nil, errors.Wrap(err, "restoring certificates")
+	}
+	return s, nil
+}
+
+// Load returns the snapshot of a profile with the given name
+func Load(profile string, name string) (*Snapshot, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(filepath.Join(Dir(profile, name), metadataFile))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "reading snapshot")
+	}
+	s := &Snapshot{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, errors.Wrapf(err, "parsing snapshot %q", name)
+	}
+	return s, nil
+}
+
+// List returns the snapshots of a profile, oldest first
+func List(profile string) ([]Snapshot, error) {
+	entries, err := ioutil.ReadDir(localpath.Snapshots(profile))
+	if os.IsNotExist(err) {
+		return []Snapshot{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "reading snapshots")
+	}
+
+	snapshots := []Snapshot{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		s, err := Load(profile, e.Name())
+		if err != nil {
+			klog.Warningf("skipping snapshot %q: %v", e.Name(), err)
+			continue
+		}
+		snapshots = append(snapshots, *s)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Created.Before(snapshots[j].Created) })
+	return snapshots, nil
+}
+
+// Delete removes a snapshot of a profile
+func Delete(profile string, name string) error {
+	s, err := Load(profile, name)
+	if err != nil {
+		return err
+	}
+	return deleteSnapshot(s, Dir(profile, name))
+}
+
+// DeleteAll removes all the snapshots of a profile, with the images and volumes holding them
+func DeleteAll(profile string) error {
+	snapshots, err := List(profile)
+	if err != nil {
+		return err
+	}
+	// the newest snapshots go first, so that no disk has to be rebased onto an older snapshot which is deleted next
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		s := snapshots[i]
+		if err := deleteSnapshot(&s, Dir(profile, s.Name)); err != nil {
+			return errors.Wrapf(err, "deleting snapshot %q", s.Name)
+		}
+	}
+	// snapshots which could not be loaded only have files left
+	return os.RemoveAll(localpath.Snapshots(profile))
+}
+
+// deleteSnapshot removes the saved disks of the nodes, and the directory of the snapshot.
+// The disks of the machines and of the other snapshots which are backed by its disks are rebased first.
+func deleteSnapshot(s *Snapshot, dir string) error {
+	if driver.IsKIC(s.Driver) {
+		for _, m := range s.Machines {
+			if err := oci.DeleteSnapshot(s.Driver, m, s.Name); err != nil {
+				return errors.Wrapf(err, "deleting snapshot of %s", m)
+			}
+		}
+		return os.RemoveAll(dir)
+	}
+
+	others, err := ioutil.ReadDir(localpath.Snapshots(s.Profile))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, m := range s.Machines {
+		dependents := []string{diskPath(m)}
+		for _, o := range others {
+			if o.IsDir() && o.Name() != filepath.Base(dir) {
+				dependents = append(dependents, snapshotDiskPath(m, filepath.Join(localpath.Snapshots(s.Profile), o.Name())))
+			}
+		}
+		if err := detachDisk(m, dir, dependents); err != nil {
+			return errors.Wrapf(err, "detaching snapshot of %s", m)
+		}
+	}
+	return os.RemoveAll(dir)
+}
+
+// saveMachine captures the disk of a machine
+func saveMachine(cc *config.ClusterConfig, machineName string, name string, dir string) error {
+	if driver.IsKIC(cc.Driver) {
+		return oci.SaveSnapshot(cc.Driver, machineName, name)
+	}
+	return saveDisk(cc.KVMQemuURI, machineName, dir)
+}
+
+// restoreMachine restores the disk of a stopped machine
+func restoreMachine(cc *config.ClusterConfig, machineName string, name string, dir string) error {
+	if driver.IsKIC(cc.Driver) {
+		return oci.RestoreSnapshot(cc.Driver, machineName, name)
+	}
+	return restoreDisk(cc.KVMQemuURI, machineName, dir)
+}
+
+// sameMachines returns whether both lists contain the same machine names
+func sameMachines(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	names := map[string]bool{}
+	for _, m := range a {
+		names[m] = true
+	}
+	for _, m := range b {
+		if !names[m] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// setMinikubeHome points the minikube home to a temporary directory for the duration of the test
+func setMinikubeHome(t *testing.T) {
+	old := os.Getenv(localpath.MinikubeHome)
+	if err := os.Setenv(localpath.MinikubeHome, t.TempDir()); err != nil {
+		t.Fatalf("unable to set %s: %v", localpath.MinikubeHome, err)
+	}
+	t.Cleanup(func() { os.Setenv(localpath.MinikubeHome, old) })
+}
+
+func writeSnapshot(t *testing.T, s Snapshot) {
+	dir := Dir(s.Profile, s.Name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, metadataFile), b, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"known-good", true},
+		{"v1.20.2_base", true},
+		{"", false},
+		{"-leading-dash", false},
+		{"UpperCase", false},
+		{"with space", false},
+		{"a/b", false},
+	}
+	for _, tc := range tests {
+		err := ValidateName(tc.name)
+		if (err == nil) != tc.valid {
+			t.Errorf("ValidateName(%q) = %v; want valid %t", tc.name, err, tc.valid)
+		}
+	}
+}
+
+func TestListDelete(t *testing.T) {
+	setMinikubeHome(t)
+
+	now := time.Now()
+	writeSnapshot(t, Snapshot{Name: "second", Profile: "p1", Driver: "kvm2", Machines: []string{"p1"}, Created: now})
+	writeSnapshot(t, Snapshot{Name: "first", Profile: "p1", Driver: "kvm2", Machines: []string{"p1"}, Created: now.Add(-time.Hour)})
+	writeSnapshot(t, Snapshot{Name: "other", Profile: "p2", Driver: "kvm2", Machines: []string{"p2"}, Created: now})
+
+	snapshots, err := List("p1")
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Name != "first" || snapshots[1].Name != "second" {
+		t.Errorf("List() = %+v; want the snapshots first and second", snapshots)
+	}
+
+	if err := Delete("p1", "first"); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if _, err := Load("p1", "first"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load() of deleted snapshot = %v; want %v", err, ErrNotFound)
+	}
+	if err := Delete("p1", "first"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete() of deleted snapshot = %v; want %v", err, ErrNotFound)
+	}
+
+	snapshots, err = List("missing")
+	if err != nil || len(snapshots) != 0 {
+		t.Errorf("List() of profile without snapshots = %v, %v; want no snapshots", snapshots, err)
+	}
+	if err := DeleteAll("p1"); err != nil {
+		t.Fatalf("DeleteAll() failed: %v", err)
+	}
+	if _, err := os.Stat(localpath.Snapshots("p1")); !os.IsNotExist(err) {
+		t.Errorf("snapshots of p1 still exist after DeleteAll(): %v", err)
+	}
+	if snapshots, err := List("p2"); err != nil || len(snapshots) != 1 {
+		t.Errorf("List() of other profile after DeleteAll() = %v, %v; want its snapshot", snapshots, err)
+	}
+}
+
+func TestSaveRestoreCerts(t *testing.T) {
+	setMinikubeHome(t)
+
+	files := map[string]string{
+		filepath.Join(localpath.Profile("p1"), "client.crt"):    "client cert",
+		filepath.Join(localpath.Profile("p1"), "apiserver.key"): "apiserver key",
+		filepath.Join(localpath.Profile("p1"), "config.json"):   "{}",
+		filepath.Join(localpath.MiniPath(), "ca.crt"):           "ca cert",
+	}
+	for p, content := range files {
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir := Dir("p1", "certs")
+	if err := saveCerts("p1", dir); err != nil {
+		t.Fatalf("saveCerts() failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, certsDir, "profile", "config.json")); !os.IsNotExist(err) {
+		t.Errorf("saveCerts() saved config.json, want only certificates and keys")
+	}
+
+	// certificates changed after the snapshot are restored to their saved content
+	for p := range files {
+		if err := ioutil.WriteFile(p, []byte("changed"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := restoreCerts("p1", dir); err != nil {
+		t.Fatalf("restoreCerts() failed: %v", err)
+	}
+	for p, content := range files {
+		want := content
+		// the shared CA is not restored
+		if filepath.Base(p) == "config.json" || filepath.Base(p) == "ca.crt" {
+			want = "changed"
+		}
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != want {
+			t.Errorf("%s = %q after restore; want %q", p, b, want)
+		}
+	}
+}
+
+func TestCheckCA(t *testing.T) {
+	setMinikubeHome(t)
+
+	ca := filepath.Join(localpath.MiniPath(), "ca.crt")
+	if err := os.MkdirAll(localpath.MiniPath(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(ca, []byte("ca cert"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dir := Dir("p1", "ca")
+	if err := saveCerts("p1", dir); err != nil {
+		t.Fatalf("saveCerts() failed: %v", err)
+	}
+	if err := checkCA(dir); err != nil {
+		t.Errorf("checkCA() with the same CA failed: %v", err)
+	}
+
+	if err := ioutil.WriteFile(ca, []byte("new ca cert"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkCA(dir); err == nil {
+		t.Errorf("checkCA() with a different CA succeeded, want an error")
+	}
+}
+
+func TestSameMachines(t *testing.T) {
+	tests := []struct {
+		a, b []string
+		want bool
+	}{
+		{[]string{"p1", "p1-m02"}, []string{"p1-m02", "p1"}, true},
+		{[]string{"p1"}, []string{"p1", "p1-m02"}, false},
+		{[]string{"p1", "p1-m02"}, []string{"p1", "p1-m03"}, false},
+	}
+	for _, tc := range tests {
+		if got := sameMachines(tc.a, tc.b); got != tc.want {
+			t.Errorf("sameMachines(%v, %v) = %t; want %t", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestParseDiskImage(t *testing.T) {
+	out := `{
+    "virtual-size": 20971520000,
+    "filename": "/home/user/.minikube/machines/p1/p1.rawdisk",
+    "format": "qcow2",
+    "backing-filename": "/home/user/.minikube/snapshots/p1/s1/p1.disk",
+    "full-backing-filename": "/home/user/.minikube/snapshots/p1/s1/p1.disk",
+    "backing-filename-format": "raw",
+    "dirty-flag": false
+}`
+	img, err := parseDiskImage([]byte(out))
+	if err != nil {
+		t.Fatalf("parseDiskImage() failed: %v", err)
+	}
+	want := diskImage{Format: "qcow2", BackingFile: "/home/user/.minikube/snapshots/p1/s1/p1.disk", BackingFormat: "raw"}
+	if *img != want {
+		t.Errorf("parseDiskImage() = %+v; want %+v", *img, want)
+	}
+}
+
+func TestWithDiskFormat(t *testing.T) {
+	xml := `<domain type='kvm'>
+  <devices>
+    <disk type='file' device='cdrom'>
+      <driver name='qemu' type='raw'/>
+      <source file='/home/user/.minikube/machines/p1/boot2docker.iso'/>
+    </disk>
+    <disk type='file' device='disk'>
+      <driver name='qemu' type='raw' cache='default' io='threads'/>
+      <source file='/home/user/.minikube/machines/p1/p1.rawdisk'/>
+    </disk>
+  </devices>
+</domain>`
+	got, err := withDiskFormat(xml, "qcow2")
+	if err != nil {
+		t.Fatalf("withDiskFormat() failed: %v", err)
+	}
+	if !strings.Contains(got, `<driver name='qemu' type='qcow2' cache='default' io='threads'/>`) {
+		t.Errorf("withDiskFormat() did not set the format of the disk:\n%s", got)
+	}
+	if !strings.Contains(got, `<driver name='qemu' type='raw'/>`) {
+		t.Errorf("withDiskFormat() changed the format of the cdrom:\n%s", got)
+	}
+	if _, err := withDiskFormat("<domain/>", "qcow2"); err == nil {
+		t.Errorf("withDiskFormat() of a domain without disk succeeded, want an error")
+	}
+}
@@ -64,7 +64,8 @@ type Parameters struct {
 	CIDR      string // CIDR format ('a.b.c.d/n')
 	Gateway   string // taken from network interface address or assumed as first network IP address from given addr
 	ClientMin string // second IP address
-	ClientMax string // last IP address before broadcast
+	ClientMax string // last IP address before broadcast, reserved for the virtual IP of HA clusters
+	DHCPMax   string // last IP address handed out by the DHCP servers of the networks minikube creates
 	Broadcast string // last IP address
 	Interface
 }
@@ -185,9 +186,34 @@ func inspect(addr string) (*Parameters, error) {
 	binary.BigEndian.PutUint32(max, broadcastIP-1) // clients-from: last network IP address before broadcast
 	n.ClientMax = max.String()
 
+	dhcpMax := make(net.IP, 4)
+	binary.BigEndian.PutUint32(dhcpMax, broadcastIP-2) // last client address before the reserved one
+	n.DHCPMax = dhcpMax.String()
+
 	return n, nil
 }
 
+// Inspect returns the IPv4 network parameters of addr, see inspect.
+func Inspect(addr string) (*Parameters, error) {
+	return inspect(addr)
+}
+
+// IsPrivate returns whether ip is in a private network.
+func IsPrivate(ip net.IP) bool {
+	return isSubnetPrivate(ip.String())
+}
+
+// IPOffset returns the IPv4 address n addresses after ip, or nil if ip is not an IPv4 address.
+func IPOffset(ip net.IP, n int) net.IP {
+	v4 := ip.To4()
+	if v4 == nil {
+		return nil
+	}
+	next := make(net.IP, 4)
+	binary.BigEndian.PutUint32(next, binary.BigEndian.Uint32(v4)+uint32(n))
+	return next
+}
+
 // isSubnetTaken returns if local network subnet exists and any error occurred.
 // If will return false in case of an error.
 func isSubnetTaken(subnet string) (bool, error) {
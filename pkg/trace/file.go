@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// FileEnvVar is the name of the env variable with the path of the file spans are written to
+const FileEnvVar = "MINIKUBE_TRACE_FILE"
+
+// fileExporter appends spans to a file, as JSON lines
+type fileExporter struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// fileSpan is a span, as written to the trace file
+type fileSpan struct {
+	Name         string                 `json:"name"`
+	TraceID      string                 `json:"traceId"`
+	SpanID       string                 `json:"spanId"`
+	ParentSpanID string                 `json:"parentSpanId,omitempty"`
+	StartTime    time.Time              `json:"startTime"`
+	EndTime      time.Time              `json:"endTime"`
+	DurationMS   int64                  `json:"durationMs"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	Status       string                 `json:"status,omitempty"`
+}
+
+// initFileTracer returns a tracer appending its spans to the trace file
+func initFileTracer() (*otelTracer, error) {
+	path := os.Getenv(FileEnvVar)
+	if path == "" {
+		path = localpath.TraceLog()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, errors.Wrap(err, "creating trace directory")
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening trace file")
+	}
+	return newExporterTracer(&fileExporter{f: f}), nil
+}
+
+// ExportSpans writes a line for each span to the file
+func (e *fileExporter) ExportSpans(ctx context.Context, ss []*export.SpanSnapshot) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	enc := json.NewEncoder(e.f)
+	for _, s := range ss {
+		if err := enc.Encode(newFileSpan(s)); err != nil {
+			return errors.Wrap(err, "writing span")
+		}
+	}
+	return nil
+}
+
+// Shutdown closes the file
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.f.Close()
+}
+
+// newFileSpan converts a span to its file representation
+func newFileSpan(s *export.SpanSnapshot) fileSpan {
+	fs := fileSpan{
+		Name:       s.Name,
+		TraceID:    s.SpanContext.TraceID.String(),
+		SpanID:     s.SpanContext.SpanID.String(),
+		StartTime:  s.StartTime,
+		EndTime:    s.EndTime,
+		DurationMS: s.EndTime.Sub(s.StartTime).Milliseconds(),
+		Status:     s.StatusMessage,
+	}
+	if s.ParentSpanID.IsValid() {
+		fs.ParentSpanID = s.ParentSpanID.String()
+	}
+	if len(s.Attributes) > 0 {
+		fs.Attributes = map[string]interface{}{}
+		for _, kv := range s.Attributes {
+			fs.Attributes[string(kv.Key)] = kv.Value.AsInterface()
+		}
+	}
+	return fs
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/label"
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// this is the name of the parent span to help identify it
+	// in the tracing UI.
+	parentSpanName = "minikube start"
+	// serviceName is the name of the service reported to the exporters
+	serviceName = "minikube"
+)
+
+// otelTracer is a minikubeTracer which records spans with OpenTelemetry,
+// all spans are children of the parent span
+type otelTracer struct {
+	parentCtx context.Context
+	trace.Tracer
+	mu      sync.Mutex
+	spans   map[string]trace.Span
+	cleanup func()
+}
+
+// StartSpan starts a span for the next step of `minikube start`
+func (t *otelTracer) StartSpan(name string) {
+	_, span := t.Tracer.Start(t.parentCtx, name)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans[name] = span
+}
+
+// EndSpan ends the span with the given name, indicating
+// that one step of `minikube start` has completed
+func (t *otelTracer) EndSpan(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span, ok := t.spans[name]
+	if !ok {
+		klog.Warningf("cannot end span %s as it was never started", name)
+		return
+	}
+	span.End()
+	if name != parentSpanName {
+		delete(t.spans, name)
+	}
+}
+
+// Cleanup ends the parent span and flushes all data
+func (t *otelTracer) Cleanup() {
+	t.mu.Lock()
+	span, ok := t.spans[parentSpanName]
+	t.mu.Unlock()
+	if ok {
+		span.End()
+	}
+	t.cleanup()
+}
+
+// newOTelTracer starts the parent span with the tracer
+func newOTelTracer(t trace.Tracer, cleanup func()) *otelTracer {
+	ctx, span := t.Start(context.Background(), parentSpanName)
+	return &otelTracer{
+		parentCtx: ctx,
+		cleanup:   cleanup,
+		Tracer:    t,
+		spans: map[string]trace.Span{
+			parentSpanName: span,
+		},
+	}
+}
+
+// newExporterTracer returns a tracer sending its spans to the exporter
+func newExporterTracer(exp export.SpanExporter) *otelTracer {
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithConfig(sdktrace.Config{
+			DefaultSampler: sdktrace.AlwaysSample(),
+		}),
+		sdktrace.WithResource(resource.NewWithAttributes(label.String("service.name", serviceName))),
+		sdktrace.WithBatcher(exp),
+	)
+	cleanup := func() {
+		if err := tp.Shutdown(context.Background()); err != nil {
+			klog.Warningf("failed to flush traces: %v", err)
+		}
+	}
+	return newOTelTracer(tp.Tracer(parentSpanName), cleanup)
+}
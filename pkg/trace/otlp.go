@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlphttp"
+)
+
+const (
+	// OTLPEndpointEnvVar is the name of the env variable with the base URL of the OTLP/HTTP collector
+	OTLPEndpointEnvVar = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	// OTLPTracesEndpointEnvVar is the name of the env variable with the full URL traces are sent to, it has priority over OTLPEndpointEnvVar
+	OTLPTracesEndpointEnvVar = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	// defaultOTLPEndpoint is the default base URL of a local OTLP/HTTP collector
+	defaultOTLPEndpoint = "http://localhost:4318"
+	// otlpTracesPath is the path traces are sent to, relative to the base URL
+	otlpTracesPath = "/v1/traces"
+)
+
+// otlpTracesURL returns the URL traces are sent to, based on the OTLP env variables
+func otlpTracesURL() string {
+	if u := os.Getenv(OTLPTracesEndpointEnvVar); u != "" {
+		return u
+	}
+	base := defaultOTLPEndpoint
+	if u := os.Getenv(OTLPEndpointEnvVar); u != "" {
+		base = u
+	}
+	return strings.TrimSuffix(base, "/") + otlpTracesPath
+}
+
+// otlpDriverOptions returns the options of the OTLP/HTTP driver sending traces to the URL
+func otlpDriverOptions(traces string) ([]otlphttp.Option, error) {
+	u, err := url.Parse(traces)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", traces)
+	}
+	if u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid OTLP traces URL %q, expected http(s)://host:port/path", traces)
+	}
+
+	opts := []otlphttp.Option{otlphttp.WithEndpoint(u.Host)}
+	if u.Path != "" {
+		opts = append(opts, otlphttp.WithTracesURLPath(u.Path))
+	}
+	if u.Scheme == "http" {
+		opts = append(opts, otlphttp.WithInsecure())
+	}
+	return opts, nil
+}
+
+// initOTLPTracer returns a tracer sending its spans to an OTLP/HTTP collector
+func initOTLPTracer() (*otelTracer, error) {
+	opts, err := otlpDriverOptions(otlpTracesURL())
+	if err != nil {
+		return nil, err
+	}
+	exp, err := otlp.NewExporter(context.Background(), otlphttp.NewDriver(opts...))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating OTLP exporter")
+	}
+	return newExporterTracer(exp), nil
+}
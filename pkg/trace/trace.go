@@ -26,6 +26,22 @@ var (
 	tracer minikubeTracer
 )
 
+// Names of the spans covering the phases of `minikube start`, which are suffixed with
+// the machine or addon name, as some phases run concurrently
+const (
+	DriverCreate    = "driver create"
+	Provision       = "provision"
+	PreloadDownload = "preload download"
+	Preload         = "preload"
+	KubeadmInit     = "kubeadm init"
+	AddonEnable     = "addon enable"
+)
+
+// SpanName returns the name of the span for the phase of the named object, such as a machine or an addon
+func SpanName(phase string, name string) string {
+	return fmt.Sprintf("%s: %s", phase, name)
+}
+
 type minikubeTracer interface {
 	StartSpan(string)
 	EndSpan(string)
@@ -46,10 +62,14 @@ func getTracer(t string) (minikubeTracer, error) {
 	switch t {
 	case "gcp":
 		return initGCPTracer()
+	case "otlp":
+		return initOTLPTracer()
+	case "file":
+		return initFileTracer()
 	case "":
 		return nil, nil
 	}
-	return nil, fmt.Errorf("%s is not a valid tracer, valid tracers include: [gcp]", t)
+	return nil, fmt.Errorf("%s is not a valid tracer, valid tracers include: [gcp, otlp, file]", t)
 }
 
 // StartSpan starts a span with the given name
@@ -0,0 +1,178 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// setenv sets the env variable for the duration of the test
+func setenv(t *testing.T, key string, value string) {
+	old, ok := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+// traceStart records spans the way `minikube start` does, and flushes them
+func traceStart(t *testing.T, name string) {
+	t.Helper()
+	if err := Initialize(name); err != nil {
+		t.Fatalf("Initialize(%q): %v", name, err)
+	}
+	defer func() { tracer = nil }()
+
+	StartSpan("Creating Container")
+	span := SpanName(DriverCreate, "minikube")
+	StartSpan(span)
+	EndSpan(span)
+	EndSpan("Creating Container")
+	Cleanup()
+}
+
+func TestOTLPTracer(t *testing.T) {
+	var mu sync.Mutex
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != otlpTracesPath {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-protobuf" {
+			t.Errorf("unexpected content type %s", ct)
+		}
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading request: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		body = append(body, b...)
+	}))
+	defer server.Close()
+
+	setenv(t, OTLPTracesEndpointEnvVar, "")
+	setenv(t, OTLPEndpointEnvVar, server.URL)
+	traceStart(t, "otlp")
+
+	mu.Lock()
+	defer mu.Unlock()
+	// the names of the spans are strings of the protobuf request
+	for _, name := range []string{"Creating Container", "driver create: minikube", "minikube start"} {
+		if !bytes.Contains(body, []byte(name)) {
+			t.Errorf("span %q was not received", name)
+		}
+	}
+}
+
+func TestOTLPDriverOptions(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"http://localhost:4318/v1/traces", false},
+		{"https://collector:4318/traces", false},
+		{"localhost:4318", true},
+		{"ftp://collector/v1/traces", true},
+	}
+	for _, tc := range tests {
+		_, err := otlpDriverOptions(tc.url)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("otlpDriverOptions(%q) error = %v, want error %v", tc.url, err, tc.wantErr)
+		}
+	}
+}
+
+func TestOTLPTracesURL(t *testing.T) {
+	tests := []struct {
+		endpoint       string
+		tracesEndpoint string
+		want           string
+	}{
+		{"", "", "http://localhost:4318/v1/traces"},
+		{"http://collector:4318/", "", "http://collector:4318/v1/traces"},
+		{"http://collector:4318", "http://other:4318/traces", "http://other:4318/traces"},
+	}
+	for _, tc := range tests {
+		setenv(t, OTLPEndpointEnvVar, tc.endpoint)
+		setenv(t, OTLPTracesEndpointEnvVar, tc.tracesEndpoint)
+		if got := otlpTracesURL(); got != tc.want {
+			t.Errorf("otlpTracesURL() = %q, want %q", got, tc.want)
+		}
+	}
+}
+
+func TestFileTracer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "trace.json")
+	setenv(t, FileEnvVar, path)
+	traceStart(t, "file")
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("opening trace file: %v", err)
+	}
+	defer f.Close()
+
+	spans := map[string]fileSpan{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var s fileSpan
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			t.Fatalf("decoding %q: %v", scanner.Text(), err)
+		}
+		spans[s.Name] = s
+	}
+
+	parent, ok := spans[parentSpanName]
+	if !ok {
+		t.Fatalf("parent span missing from %v", spans)
+	}
+	for _, name := range []string{"Creating Container", "driver create: minikube"} {
+		s, ok := spans[name]
+		if !ok {
+			t.Errorf("span %q missing from %v", name, spans)
+			continue
+		}
+		if s.TraceID != parent.TraceID || s.ParentSpanID != parent.SpanID {
+			t.Errorf("span %q is not a child of the parent span: %+v", name, s)
+		}
+		if s.EndTime.Before(s.StartTime) {
+			t.Errorf("span %q ends before it starts: %+v", name, s)
+		}
+	}
+}
+
+func TestInvalidTracer(t *testing.T) {
+	if err := Initialize("zipkin"); err == nil {
+		t.Errorf("expected an error for an invalid tracer")
+	}
+}
@@ -18,6 +18,7 @@ package util
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -28,6 +29,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -64,8 +66,8 @@ func GenerateCACert(certPath, keyPath string, name string) error {
 // If the certificate or key files already exist, they will be overwritten.
 // Any parent directories of the certPath or keyPath will be created as needed with file mode 0755.
 
-// GenerateSignedCert generates a signed certificate and key
-func GenerateSignedCert(certPath, keyPath, cn string, ips []net.IP, alternateDNS []string, signerCertPath, signerKeyPath string) error {
+// GenerateSignedCert generates a signed certificate and key, valid for the expiration duration
+func GenerateSignedCert(certPath, keyPath, cn string, ips []net.IP, alternateDNS []string, signerCertPath, signerKeyPath string, expiration time.Duration) error {
 	klog.Infof("Generating cert %s with IP's: %s", certPath, ips)
 	signerCertBytes, err := ioutil.ReadFile(signerCertPath)
 	if err != nil {
@@ -83,13 +85,9 @@ func GenerateSignedCert(certPath, keyPath, cn string, ips []net.IP, alternateDNS
 	if err != nil {
 		return errors.Wrap(err, "Error reading file: signerKeyPath")
 	}
-	decodedSignerKey, _ := pem.Decode(signerKeyBytes)
-	if decodedSignerKey == nil {
-		return errors.New("Unable to decode key")
-	}
-	signerKey, err := x509.ParsePKCS1PrivateKey(decodedSignerKey.Bytes)
+	signerKey, err := parsePrivateKey(signerKeyBytes)
 	if err != nil {
-		return errors.Wrap(err, "Error parsing private key: decodedSignerKey.Bytes")
+		return errors.Wrap(err, "Error parsing private key: signerKeyPath")
 	}
 
 	template := x509.Certificate{
@@ -99,7 +97,7 @@ func GenerateSignedCert(certPath, keyPath, cn string, ips []net.IP, alternateDNS
 			Organization: []string{"system:masters"},
 		},
 		NotBefore: time.Now().Add(time.Hour * -24),
-		NotAfter:  time.Now().Add(time.Hour * 24 * 365),
+		NotAfter:  time.Now().Add(expiration),
 
 		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
@@ -117,6 +115,140 @@ func GenerateSignedCert(certPath, keyPath, cn string, ips []net.IP, alternateDNS
 	return writeCertsAndKeys(&template, certPath, priv, keyPath, signerCert, signerKey)
 }
 
+// parseFirstCert parses the first PEM encoded certificate of data
+func parseFirstCert(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM encoded certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// IssuedBy returns whether the first PEM encoded certificate of data is signed by the one of signerCert
+func IssuedBy(data []byte, signerCert []byte) bool {
+	cert, err := parseFirstCert(data)
+	if err != nil {
+		return false
+	}
+	signer, err := parseFirstCert(signerCert)
+	if err != nil {
+		return false
+	}
+	return cert.CheckSignatureFrom(signer) == nil
+}
+
+// ResignCert signs the first PEM encoded certificate of data again with the PEM encoded signer which issued it,
+// valid for the expiration duration. Its subject, SANs, usages and public key are kept.
+func ResignCert(data []byte, signerCert []byte, signerKey []byte, expiration time.Duration) ([]byte, error) {
+	cert, err := parseFirstCert(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing certificate")
+	}
+	signer, err := parseFirstCert(signerCert)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing signer certificate")
+	}
+	key, err := parsePrivateKey(signerKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing signer key")
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+	if err != nil {
+		return nil, err
+	}
+
+	template := *cert
+	template.SerialNumber = serial
+	template.NotBefore = time.Now().Add(time.Hour * -24)
+	template.NotAfter = time.Now().Add(expiration)
+	// the extensions are generated again from the fields of the template
+	template.ExtraExtensions = nil
+	der, err := x509.CreateCertificate(rand.Reader, &template, signer, cert.PublicKey, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "signing certificate")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
+
+// CheckCACert checks that the first certificate of a PEM file is a CA valid now, which can sign certificates with a PEM encoded key
+func CheckCACert(certPath, keyPath string) error {
+	certBytes, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return err
+	}
+	decodedCert, _ := pem.Decode(certBytes)
+	if decodedCert == nil || decodedCert.Type != "CERTIFICATE" {
+		return errors.Errorf("%s does not contain a PEM encoded certificate", certPath)
+	}
+	cert, err := x509.ParseCertificate(decodedCert.Bytes)
+	if err != nil {
+		return errors.Wrapf(err, "parse %s", certPath)
+	}
+	if !cert.IsCA || (cert.KeyUsage != 0 && cert.KeyUsage&x509.KeyUsageCertSign == 0) {
+		return errors.Errorf("%s is not a CA which can sign certificates", certPath)
+	}
+	if now := time.Now(); now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return errors.Errorf("%s is only valid from %s to %s", certPath, cert.NotBefore, cert.NotAfter)
+	}
+
+	keyBytes, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return err
+	}
+	key, err := parsePrivateKey(keyBytes)
+	if err != nil {
+		return errors.Wrapf(err, "parse %s", keyPath)
+	}
+	pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !pub.Equal(cert.PublicKey) {
+		return errors.Errorf("the key %s does not match the certificate %s", keyPath, certPath)
+	}
+	return nil
+}
+
+// FirstCertificate returns the first PEM encoded certificate of a file, without the rest of the file such as the chain
+func FirstCertificate(certPath string) ([]byte, error) {
+	certBytes, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return nil, err
+	}
+	decodedCert, _ := pem.Decode(certBytes)
+	if decodedCert == nil || decodedCert.Type != "CERTIFICATE" {
+		return nil, errors.Errorf("%s does not contain a PEM encoded certificate", certPath)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: decodedCert.Bytes}), nil
+}
+
+// parsePrivateKey parses the first PEM encoded private key of data, in PKCS #1, SEC 1 or PKCS #8 form
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	for {
+		block, rest := pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("Unable to decode key")
+		}
+		data = rest
+		if !strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			continue
+		}
+
+		if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+			return key, nil
+		}
+		if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+			return key, nil
+		}
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error parsing private key")
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.Errorf("unsupported private key type %T", key)
+		}
+		return signer, nil
+	}
+}
+
 func loadOrGeneratePrivateKey(keyPath string) (*rsa.PrivateKey, error) {
 	keyBytes, err := ioutil.ReadFile(keyPath)
 	if err == nil {
@@ -135,7 +267,7 @@ func loadOrGeneratePrivateKey(keyPath string) (*rsa.PrivateKey, error) {
 	return priv, nil
 }
 
-func writeCertsAndKeys(template *x509.Certificate, certPath string, signeeKey *rsa.PrivateKey, keyPath string, parent *x509.Certificate, signingKey *rsa.PrivateKey) error {
+func writeCertsAndKeys(template *x509.Certificate, certPath string, signeeKey *rsa.PrivateKey, keyPath string, parent *x509.Certificate, signingKey crypto.Signer) error {
 	derBytes, err := x509.CreateCertificate(rand.Reader, template, parent, &signeeKey.PublicKey, signingKey)
 	if err != nil {
 		return errors.Wrap(err, "Error creating certificate")
@@ -17,13 +17,20 @@ limitations under the License.
 package util
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/pem"
 	"io/ioutil"
+	"math/big"
 	"net"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"k8s.io/minikube/pkg/minikube/constants"
 )
@@ -140,7 +147,7 @@ func TestGenerateSignedCert(t *testing.T) {
 		t.Run(test.description, func(t *testing.T) {
 			err := GenerateSignedCert(
 				certPath, keyPath, "minikube", ips, alternateDNS, test.signerCertPath,
-				test.signerKeyPath,
+				test.signerKeyPath, constants.DefaultCertExpiration,
 			)
 			if err != nil && !test.err {
 				t.Errorf("GenerateSignedCert() error = %v", err)
@@ -154,12 +161,147 @@ func TestGenerateSignedCert(t *testing.T) {
 					t.Errorf("Error reading cert data: %v", err)
 				}
 				data, _ := pem.Decode(certBytes)
-				_, err = x509.ParseCertificate(data.Bytes)
+				cert, err := x509.ParseCertificate(data.Bytes)
 				if err != nil {
-					t.Errorf("Error parsing certificate: %v", err)
+					t.Fatalf("Error parsing certificate: %v", err)
+				}
+				if expiry := time.Now().Add(constants.DefaultCertExpiration); cert.NotAfter.After(expiry) || cert.NotAfter.Before(expiry.Add(-time.Minute)) {
+					t.Errorf("Certificate expires on %s, want %s", cert.NotAfter, expiry)
 				}
 			}
 
 		})
 	}
 }
+
+// writeECCACert writes an ECDSA CA certificate and its key in PKCS #8 form, as organizations may provide them
+func writeECCACert(t *testing.T, certPath, keyPath string) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Example Intermediate CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour * 24),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckCACert(t *testing.T) {
+	dir := t.TempDir()
+	caCert, caKey := filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")
+	if err := GenerateCACert(caCert, caKey, "minikubeCA"); err != nil {
+		t.Fatal(err)
+	}
+	otherCert, otherKey := filepath.Join(dir, "other.crt"), filepath.Join(dir, "other.key")
+	if err := GenerateCACert(otherCert, otherKey, "otherCA"); err != nil {
+		t.Fatal(err)
+	}
+	ecCert, ecKey := filepath.Join(dir, "ec.crt"), filepath.Join(dir, "ec.key")
+	writeECCACert(t, ecCert, ecKey)
+	leafCert, leafKey := filepath.Join(dir, "leaf.crt"), filepath.Join(dir, "leaf.key")
+	if err := GenerateSignedCert(leafCert, leafKey, "minikube", nil, nil, ecCert, ecKey, time.Hour); err != nil {
+		t.Fatalf("GenerateSignedCert() with an ECDSA PKCS #8 signer error = %v", err)
+	}
+
+	tests := []struct {
+		description string
+		cert        string
+		key         string
+		valid       bool
+	}{
+		{"RSA CA", caCert, caKey, true},
+		{"ECDSA CA", ecCert, ecKey, true},
+		{"key of another CA", caCert, otherKey, false},
+		{"not a CA", leafCert, leafKey, false},
+		{"key as certificate", caKey, caKey, false},
+		{"missing key", caCert, filepath.Join(dir, "missing.key"), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			err := CheckCACert(tc.cert, tc.key)
+			if (err == nil) != tc.valid {
+				t.Errorf("CheckCACert() error = %v; want valid: %v", err, tc.valid)
+			}
+		})
+	}
+}
+
+func TestResignCert(t *testing.T) {
+	dir := t.TempDir()
+	caCert, caKey := filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")
+	if err := GenerateCACert(caCert, caKey, "etcd-ca"); err != nil {
+		t.Fatal(err)
+	}
+	otherCert, otherKey := filepath.Join(dir, "other.crt"), filepath.Join(dir, "other.key")
+	if err := GenerateCACert(otherCert, otherKey, "otherCA"); err != nil {
+		t.Fatal(err)
+	}
+	leafCert, leafKey := filepath.Join(dir, "peer.crt"), filepath.Join(dir, "peer.key")
+	if err := GenerateSignedCert(leafCert, leafKey, "minikube", []net.IP{net.ParseIP("192.168.49.2")}, []string{"localhost"}, caCert, caKey, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	read := func(f string) []byte {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	if !IssuedBy(read(leafCert), read(caCert)) {
+		t.Errorf("IssuedBy() of the CA of the cert = false; want true")
+	}
+	if IssuedBy(read(leafCert), read(otherCert)) {
+		t.Errorf("IssuedBy() of another CA = true; want false")
+	}
+
+	resigned, err := ResignCert(read(leafCert), read(caCert), read(caKey), 3*365*24*time.Hour)
+	if err != nil {
+		t.Fatalf("ResignCert() error = %v", err)
+	}
+	if !IssuedBy(resigned, read(caCert)) {
+		t.Errorf("the resigned cert is not issued by the CA")
+	}
+	old, err := parseFirstCert(read(leafCert))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := parseFirstCert(resigned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Until(cert.NotAfter) < 2*365*24*time.Hour {
+		t.Errorf("the resigned cert expires on %s; want in 3 years", cert.NotAfter)
+	}
+	if cert.Subject.CommonName != "minikube" || len(cert.IPAddresses) != 1 || len(cert.DNSNames) != 1 || cert.DNSNames[0] != "localhost" {
+		t.Errorf("the resigned cert has subject %s and SANs %v %v; want the ones of the original", cert.Subject, cert.IPAddresses, cert.DNSNames)
+	}
+	if len(cert.ExtKeyUsage) != len(old.ExtKeyUsage) || cert.KeyUsage != old.KeyUsage {
+		t.Errorf("the resigned cert has usages %v %v; want %v %v", cert.KeyUsage, cert.ExtKeyUsage, old.KeyUsage, old.ExtKeyUsage)
+	}
+	if !cert.PublicKey.(interface{ Equal(crypto.PublicKey) bool }).Equal(old.PublicKey) {
+		t.Errorf("the resigned cert has another public key")
+	}
+	if _, err := ResignCert(read(leafCert), read(otherCert), read(otherKey), time.Hour); err != nil {
+		t.Errorf("ResignCert() with another CA error = %v", err)
+	}
+}
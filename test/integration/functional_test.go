@@ -19,12 +19,15 @@ limitations under the License.
 package integration
 
 import (
+	"archive/tar"
 	"bufio"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -33,6 +36,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -101,6 +105,7 @@ func TestFunctional(t *testing.T) {
 			{"ComponentHealth", validateComponentHealth},
 			{"LogsCmd", validateLogsCmd},
 			{"LogsFileCmd", validateLogsFileCmd},
+			{"DiagnoseCmd", validateDiagnoseCmd},
 		}
 		for _, tc := range tests {
 			tc := tc
@@ -136,6 +141,7 @@ func TestFunctional(t *testing.T) {
 			{"InternationalLanguage", validateInternationalLanguage},
 			{"StatusCmd", validateStatusCmd},
 			{"MountCmd", validateMountCmd},
+			{"BackgroundMountCmd", validateBackgroundMountCmd},
 			{"ProfileCmd", validateProfileCmd},
 			{"ServiceCmd", validateServiceCmd},
 			{"AddonsCmd", validateAddonsCmd},
@@ -143,6 +149,7 @@ func TestFunctional(t *testing.T) {
 			{"TunnelCmd", validateTunnelCmd},
 			{"SSHCmd", validateSSHCmd},
 			{"CpCmd", validateCpCmd},
+			{"PortCmd", validatePortCmd},
 			{"MySQL", validateMySQL},
 			{"FileSync", validateFileSync},
 			{"CertSync", validateCertSync},
@@ -154,6 +161,8 @@ func TestFunctional(t *testing.T) {
 			{"RemoveImage", validateRemoveImage},
 			{"BuildImage", validateBuildImage},
 			{"ListImages", validateListImages},
+			{"SaveImage", validateSaveImage},
+			{"TagImage", validateTagImage},
 			{"NonActiveRuntimeDisabled", validateNotActiveRuntimeDisabled},
 			{"Version", validateVersionCmd},
 		}
@@ -188,6 +197,11 @@ func cleanupUnwantedImages(ctx context.Context, t *testing.T, profile string) {
 			if err != nil {
 				t.Logf("failed to remove image busybox from docker images. args %q: %v", rr.Command(), err)
 			}
+			newImage = fmt.Sprintf("docker.io/library/busybox:save-%s", profile)
+			rr, err = Run(t, exec.CommandContext(ctx, "docker", "rmi", "-f", newImage))
+			if err != nil {
+				t.Logf("failed to remove image busybox from docker images. args %q: %v", rr.Command(), err)
+			}
 		})
 		t.Run("delete my-image image", func(t *testing.T) {
 			newImage := fmt.Sprintf("localhost/my-image:%s", profile)
@@ -311,6 +325,103 @@ func validateRemoveImage(ctx context.Context, t *testing.T, profile string) {
 
 }
 
+// validateSaveImage makes sure that `minikube image save` works as expected
+func validateSaveImage(ctx context.Context, t *testing.T, profile string) {
+	if NoneDriver() {
+		t.Skip("save image not available on none driver")
+	}
+	if GithubActionRunner() && runtime.GOOS == "darwin" {
+		t.Skip("skipping on github actions and darwin, as this test requires a running docker daemon")
+	}
+	defer PostMortemLogs(t, profile)
+
+	// pull busybox
+	busyboxImage := "busybox:1.33"
+	rr, err := Run(t, exec.CommandContext(ctx, "docker", "pull", busyboxImage))
+	if err != nil {
+		t.Fatalf("failed to setup test (pull image): %v\n%s", err, rr.Output())
+	}
+
+	// tag busybox
+	newImage := fmt.Sprintf("docker.io/library/busybox:save-%s", profile)
+	rr, err = Run(t, exec.CommandContext(ctx, "docker", "tag", busyboxImage, newImage))
+	if err != nil {
+		t.Fatalf("failed to setup test (tag image) : %v\n%s", err, rr.Output())
+	}
+
+	// try to load the new image into minikube
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "image", "load", newImage))
+	if err != nil {
+		t.Fatalf("loading image into minikube: %v\n%s", err, rr.Output())
+	}
+
+	// try to save the image from minikube to a tarball on the host
+	archive := filepath.Join(t.TempDir(), "busybox.tar")
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "image", "save", newImage, archive))
+	if err != nil {
+		t.Fatalf("saving image from minikube: %v\n%s", err, rr.Output())
+	}
+
+	// make sure the tarball was written
+	fi, err := os.Stat(archive)
+	if err != nil {
+		t.Fatalf("expected %s to be saved from minikube: %v", newImage, err)
+	}
+	if fi.Size() == 0 {
+		t.Fatalf("expected %s to be saved from minikube but the archive is empty", newImage)
+	}
+
+	// remove the image, and load it back from the tarball
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "image", "rm", newImage))
+	if err != nil {
+		t.Fatalf("removing image from minikube: %v\n%s", err, rr.Output())
+	}
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "image", "load", archive))
+	if err != nil {
+		t.Fatalf("loading image into minikube from file: %v\n%s", err, rr.Output())
+	}
+
+	// make sure the image was correctly loaded
+	rr, err = inspectImage(ctx, t, profile, newImage)
+	if err != nil {
+		t.Fatalf("listing images: %v\n%s", err, rr.Output())
+	}
+	if !strings.Contains(rr.Output(), fmt.Sprintf("busybox:save-%s", profile)) {
+		t.Fatalf("expected %s to be loaded into minikube but the image is not there", newImage)
+	}
+}
+
+// validateTagImage makes sure that `minikube image pull` and `minikube image tag` work as expected
+func validateTagImage(ctx context.Context, t *testing.T, profile string) {
+	if NoneDriver() {
+		t.Skip("tag image not available on none driver")
+	}
+	defer PostMortemLogs(t, profile)
+
+	// pull busybox with the container runtime
+	busyboxImage := "docker.io/library/busybox:1.34"
+	rr, err := Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "image", "pull", busyboxImage))
+	if err != nil {
+		t.Fatalf("pulling image with minikube: %v\n%s", err, rr.Output())
+	}
+
+	// tag busybox
+	newImage := fmt.Sprintf("docker.io/library/busybox:tag-%s", profile)
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "image", "tag", busyboxImage, newImage))
+	if err != nil {
+		t.Fatalf("tagging image with minikube: %v\n%s", err, rr.Output())
+	}
+
+	// make sure the image was tagged
+	rr, err = inspectImage(ctx, t, profile, newImage)
+	if err != nil {
+		t.Fatalf("listing images: %v\n%s", err, rr.Output())
+	}
+	if !strings.Contains(rr.Output(), fmt.Sprintf("busybox:tag-%s", profile)) {
+		t.Fatalf("expected %s to be tagged in minikube but the image is not there", newImage)
+	}
+}
+
 func inspectImage(ctx context.Context, t *testing.T, profile string, image string) (*RunResult, error) {
 	var cmd *exec.Cmd
 	if ContainerRuntime() == "docker" {
@@ -417,6 +528,33 @@ func validateListImages(ctx context.Context, t *testing.T, profile string) {
 			t.Fatalf("expected %s to be listed with minikube but the image is not there", theImage)
 		}
 	}
+
+	// the json output should hold structured records, with the nodes holding each image
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "image", "ls", "--format", "json", "--filter", "reference=k8s.gcr.io/pause:*"))
+	if err != nil {
+		t.Fatalf("listing image with minikube in json format: %v\n%s", err, rr.Output())
+	}
+	var images []struct {
+		ID       string   `json:"id"`
+		RepoTags []string `json:"repoTags"`
+		Nodes    []string `json:"nodes"`
+	}
+	if err := json.Unmarshal(rr.Stdout.Bytes(), &images); err != nil {
+		t.Fatalf("failed to decode json from image ls: %v\n%s", err, rr.Stdout)
+	}
+	if len(images) == 0 {
+		t.Fatalf("expected k8s.gcr.io/pause to be listed in json format, got: %s", rr.Stdout)
+	}
+	for _, img := range images {
+		if img.ID == "" || len(img.Nodes) == 0 {
+			t.Errorf("expected image ID and nodes to be set, got: %+v", img)
+		}
+		for _, tag := range img.RepoTags {
+			if !strings.HasPrefix(tag, "k8s.gcr.io/pause:") {
+				t.Errorf("expected only k8s.gcr.io/pause images to be listed, got: %s", tag)
+			}
+		}
+	}
 }
 
 // check functionality of minikube after evaluating docker-env
@@ -559,7 +697,7 @@ func validateStartWithCustomCerts(ctx context.Context, t *testing.T, profile str
 	startMinikubeWithProxy(ctx, t, profile, "HTTPS_PROXY", "127.0.0.1:8080")
 }
 
-// validateAuditAfterStart makes sure the audit log contains the correct logging after minikube start
+// validateAuditAfterStart makes sure the audit log contains the correct logging after minikube start, and that minikube audit can filter it
 func validateAuditAfterStart(ctx context.Context, t *testing.T, profile string) {
 	got, err := auditContains(profile)
 	if err != nil {
@@ -568,6 +706,30 @@ func validateAuditAfterStart(ctx context.Context, t *testing.T, profile string)
 	if !got {
 		t.Errorf("audit.json does not contain the profile %q", profile)
 	}
+
+	rr, err := Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "audit", "--command", "start", "--output", "json"))
+	if err != nil {
+		t.Fatalf("failed to run minikube audit: args %q: %v", rr.Command(), err)
+	}
+	var entries []struct {
+		Command  string `json:"command"`
+		Profile  string `json:"profile"`
+		ExitCode int    `json:"exitCode"`
+	}
+	if err := json.Unmarshal(rr.Stdout.Bytes(), &entries); err != nil {
+		t.Fatalf("failed to decode minikube audit output: %v\n%s", err, rr.Stdout)
+	}
+	if len(entries) == 0 {
+		t.Fatalf("expected minikube audit to show the start of profile %q", profile)
+	}
+	for _, e := range entries {
+		if e.Command != "start" || e.Profile != profile {
+			t.Errorf("expected minikube audit to only show starts of profile %q, got %+v", profile, e)
+		}
+	}
+	if last := entries[len(entries)-1]; last.ExitCode != 0 {
+		t.Errorf("expected the last start of profile %q to succeed, got exit code %d", profile, last.ExitCode)
+	}
 }
 
 // validateSoftStart validates that after minikube already started, a "minikube start" should not change the configs.
@@ -1144,6 +1306,39 @@ func validateLogsFileCmd(ctx context.Context, t *testing.T, profile string) {
 	checkSaneLogs(t, string(logs))
 }
 
+// validateDiagnoseCmd asserts "diagnose" writes a bundle with the cluster config and the node logs
+func validateDiagnoseCmd(ctx context.Context, t *testing.T, profile string) {
+	bundle := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	rr, err := Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "diagnose", "--output", bundle))
+	if err != nil {
+		t.Fatalf("%s failed: %v", rr.Command(), err)
+	}
+
+	f, err := os.Open(bundle)
+	if err != nil {
+		t.Fatalf("failed to open bundle: %v", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("bundle is not gzipped: %v", err)
+	}
+	found := map[string]bool{}
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err != nil {
+			break
+		}
+		found[h.Name] = true
+	}
+	for _, want := range []string{"cluster/config.json", "host/driver.txt", fmt.Sprintf("nodes/%s/kubelet.log", profile), "kubernetes/node-conditions.json"} {
+		if !found[want] {
+			t.Errorf("expected bundle to contain %s, got: %v", want, found)
+		}
+	}
+}
+
 // validateProfileCmd asserts "profile" command functionality
 func validateProfileCmd(ctx context.Context, t *testing.T, profile string) {
 	t.Run("profile_not_create", func(t *testing.T) {
@@ -1476,6 +1671,70 @@ func validateSSHCmd(ctx context.Context, t *testing.T, profile string) {
 	}
 }
 
+// validatePortCmd forwards a port of the host to the ssh port of the control plane with "minikube port", then removes it
+func validatePortCmd(ctx context.Context, t *testing.T, profile string) {
+	if !KicDriver() {
+		t.Skipf("skipping: port is only supported by the docker and podman drivers")
+	}
+	defer PostMortemLogs(t, profile)
+
+	port, err := freeport.GetFreePort()
+	if err != nil {
+		t.Fatalf("failed to get a free port: %v", err)
+	}
+	rr, err := Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "port", "add", fmt.Sprintf("%d:22", port)))
+	if err != nil {
+		t.Fatalf("failed to add a port forward. args %q : %v", rr.Command(), err)
+	}
+
+	// the ssh server of the node greets the clients of the forwarded port
+	banner := func() error {
+		conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port), 5*time.Second)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+			return err
+		}
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(line, "SSH-") {
+			return fmt.Errorf("unexpected banner %q", line)
+		}
+		return nil
+	}
+	if err := retry.Expo(banner, time.Second, Seconds(30)); err != nil {
+		t.Errorf("failed to reach the ssh server of the node through port %d: %v", port, err)
+	}
+
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "port", "list", "-o", "json"))
+	if err != nil {
+		t.Fatalf("failed to list the port forwards. args %q : %v", rr.Command(), err)
+	}
+	var pfs []struct {
+		HostPort int
+		Running  bool
+	}
+	if err := json.Unmarshal(rr.Stdout.Bytes(), &pfs); err != nil {
+		t.Fatalf("failed to decode json from port list: args %q: %v", rr.Command(), err)
+	}
+	if len(pfs) != 1 || pfs[0].HostPort != port || !pfs[0].Running {
+		t.Errorf("expected a running port forward of port %d, but got %+v", port, pfs)
+	}
+
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "port", "remove", strconv.Itoa(port)))
+	if err != nil {
+		t.Fatalf("failed to remove the port forward. args %q : %v", rr.Command(), err)
+	}
+	if conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port), 5*time.Second); err == nil {
+		conn.Close()
+		t.Errorf("expected port %d to be closed after removing its port forward", port)
+	}
+}
+
 // validateCpCmd asserts basic "cp" command functionality
 func validateCpCmd(ctx context.Context, t *testing.T, profile string) {
 	if NoneDriver() {
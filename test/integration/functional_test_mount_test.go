@@ -21,6 +21,7 @@ package integration
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -204,3 +205,65 @@ func validateMountCmd(ctx context.Context, t *testing.T, profile string) { // no
 		t.Errorf("failed to remove file %q: %v", p, err)
 	}
 }
+
+// validateBackgroundMountCmd verifies that "minikube mount --background" serves a mount which is listed and stopped by "minikube mount list|stop"
+func validateBackgroundMountCmd(ctx context.Context, t *testing.T, profile string) { // nolint
+	if NoneDriver() {
+		t.Skip("skipping: none driver does not support mount")
+	}
+	if HyperVDriver() {
+		t.Skip("skipping: mount broken on hyperv: https://github.com/kubernetes/minikube/issues/5029")
+	}
+	if runtime.GOOS != "linux" {
+		t.Skip("skipping: background mount is only tested on linux")
+	}
+
+	const target = "/mount-background"
+	tempDir := t.TempDir()
+	testMarker := fmt.Sprintf("test-%d", time.Now().UnixNano())
+	if err := ioutil.WriteFile(filepath.Join(tempDir, testMarker), []byte(testMarker), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	rr, err := Run(t, exec.CommandContext(ctx, Target(), "mount", "-p", profile, "--background", fmt.Sprintf("%s:%s", tempDir, target)))
+	if err != nil {
+		t.Fatalf("failed to start background mount. args %q: %v", rr.Command(), err)
+	}
+	defer func() {
+		if rr, err := Run(t, exec.Command(Target(), "mount", "-p", profile, "stop", "--all")); err != nil {
+			t.Logf("%q: %v", rr.Command(), err)
+		}
+	}()
+
+	checkMount := func() error {
+		_, err := Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "ssh", "cat", path.Join(target, testMarker)))
+		return err
+	}
+	if err := retry.Expo(checkMount, time.Millisecond*500, Seconds(30)); err != nil {
+		t.Fatalf("%s did not appear: %v", target, err)
+	}
+
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), "mount", "-p", profile, "list", "-o", "json"))
+	if err != nil {
+		t.Fatalf("failed to list mounts. args %q: %v", rr.Command(), err)
+	}
+	var mounts []struct {
+		Target  string
+		Running bool
+	}
+	if err := json.Unmarshal(rr.Stdout.Bytes(), &mounts); err != nil {
+		t.Fatalf("failed to decode mount list %q: %v", rr.Stdout, err)
+	}
+	if len(mounts) != 1 || mounts[0].Target != target || !mounts[0].Running {
+		t.Errorf("mount list = %+v; want %s running", mounts, target)
+	}
+
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), "mount", "-p", profile, "stop", target))
+	if err != nil {
+		t.Fatalf("failed to stop mount. args %q: %v", rr.Command(), err)
+	}
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "ssh", "findmnt", "-M", target))
+	if err == nil {
+		t.Errorf("%s is still mounted after mount stop: %s", target, rr.Stdout)
+	}
+}
@@ -0,0 +1,181 @@
+// +build integration
+
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// TestHA tests clusters with multiple control planes behind a virtual IP
+func TestHA(t *testing.T) {
+	if NoneDriver() {
+		t.Skip("none driver does not support multinode")
+	}
+
+	type validatorFunc func(context.Context, *testing.T, string)
+	profile := UniqueProfileName("ha")
+	ctx, cancel := context.WithTimeout(context.Background(), Minutes(40))
+	defer CleanupWithLogs(t, profile, cancel)
+
+	t.Run("serial", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			validator validatorFunc
+		}{
+			{"StartCluster", validateHAStart},
+			{"KubeconfigUsesVIP", validateHAKubeconfig},
+			{"AddControlPlane", validateHAAddControlPlane},
+			{"StopControlPlane", validateHAStopControlPlane},
+			{"RestartControlPlane", validateHARestartControlPlane},
+			{"RestartCluster", validateHARestartCluster},
+		}
+		for _, tc := range tests {
+			tc := tc
+			if ctx.Err() == context.DeadlineExceeded {
+				t.Fatalf("Unable to run more tests (deadline exceeded)")
+			}
+			t.Run(tc.name, func(t *testing.T) {
+				defer PostMortemLogs(t, profile)
+				tc.validator(ctx, t, profile)
+			})
+		}
+	})
+}
+
+// haStatus checks the number of control planes with a running API server, as reported by minikube status
+func haStatus(ctx context.Context, t *testing.T, profile string, running int) {
+	rr, err := Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "status", "--alsologtostderr"))
+	if err != nil && rr.ExitCode != 7 {
+		t.Fatalf("failed to run minikube status. args %q : %v", rr.Command(), err)
+	}
+	if got := strings.Count(rr.Stdout.String(), "apiserver: Running"); got != running {
+		t.Errorf("status shows %d running API servers, want %d: args %q: %v", got, running, rr.Command(), rr.Stdout.String())
+	}
+}
+
+// haNodes checks that the API server of the cluster answers, and lists n ready nodes
+func haNodes(ctx context.Context, t *testing.T, profile string, n int) {
+	rr, err := Run(t, exec.CommandContext(ctx, "kubectl", "--context", profile, "get", "nodes", "-o", `go-template={{range .items}}{{range .status.conditions}}{{if eq .type "Ready"}}{{.status}}{{"\n"}}{{end}}{{end}}{{end}}`))
+	if err != nil {
+		t.Fatalf("failed to list nodes through the virtual IP. args %q : %v", rr.Command(), err)
+	}
+	if got := strings.Count(rr.Stdout.String(), "True"); got != n {
+		t.Errorf("expected %d ready nodes, got %d: args %q: %v", n, got, rr.Command(), rr.Stdout.String())
+	}
+}
+
+// validateHAStart makes sure --ha starts 3 control planes
+func validateHAStart(ctx context.Context, t *testing.T, profile string) {
+	startArgs := append([]string{"start", "-p", profile, "--wait=true", "--memory=2200", "--ha", "-v=7", "--alsologtostderr"}, StartArgs()...)
+	rr, err := Run(t, exec.CommandContext(ctx, Target(), startArgs...))
+	if err != nil {
+		t.Fatalf("failed to start HA cluster. args %q : %v", rr.Command(), err)
+	}
+
+	haStatus(ctx, t, profile, 3)
+	haNodes(ctx, t, profile, 3)
+}
+
+// validateHAKubeconfig makes sure the kubeconfig points at the virtual IP, unless the driver forwards the ports
+func validateHAKubeconfig(ctx context.Context, t *testing.T, profile string) {
+	rr, err := Run(t, exec.CommandContext(ctx, Target(), "profile", "list", "--output", "json"))
+	if err != nil {
+		t.Fatalf("failed to list profiles with json format. args %q: %v", rr.Command(), err)
+	}
+	var profiles map[string][]config.Profile
+	if err := json.Unmarshal(rr.Stdout.Bytes(), &profiles); err != nil {
+		t.Fatalf("failed to decode json from profile list: args %q: %v", rr.Command(), err)
+	}
+	vip := ""
+	for _, p := range profiles["valid"] {
+		if p.Name == profile {
+			vip = p.Config.KubernetesConfig.APIServerHAVIP
+		}
+	}
+	if vip == "" {
+		t.Fatalf("expected profile %q to have a virtual IP: %s", profile, rr.Stdout.String())
+	}
+
+	if NeedsPortForward() {
+		t.Skipf("the virtual IP is not reachable from the host with port forwarding")
+	}
+	rr, err = Run(t, exec.CommandContext(ctx, "kubectl", "--context", profile, "config", "view", "--minify", "-o", "jsonpath={.clusters[0].cluster.server}"))
+	if err != nil {
+		t.Fatalf("failed to get the server of the kubeconfig. args %q: %v", rr.Command(), err)
+	}
+	if !strings.Contains(rr.Stdout.String(), vip) {
+		t.Errorf("expected the kubeconfig to point at %s, got %q", vip, rr.Stdout.String())
+	}
+}
+
+// validateHAAddControlPlane uses minikube node add --control-plane to add a fourth control plane
+func validateHAAddControlPlane(ctx context.Context, t *testing.T, profile string) {
+	rr, err := Run(t, exec.CommandContext(ctx, Target(), "node", "add", "-p", profile, "--control-plane", "-v=7", "--alsologtostderr"))
+	if err != nil {
+		t.Fatalf("failed to add control-plane node. args %q : %v", rr.Command(), err)
+	}
+
+	haStatus(ctx, t, profile, 4)
+	haNodes(ctx, t, profile, 4)
+}
+
+// validateHAStopControlPlane makes sure the cluster still answers without one of its control planes
+func validateHAStopControlPlane(ctx context.Context, t *testing.T, profile string) {
+	rr, err := Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "node", "stop", SecondNodeName, "-v=7", "--alsologtostderr"))
+	if err != nil {
+		t.Fatalf("node stop returned an error. args %q: %v", rr.Command(), err)
+	}
+
+	haStatus(ctx, t, profile, 3)
+	haNodes(ctx, t, profile, 3)
+}
+
+// validateHARestartControlPlane makes sure a stopped control plane comes back
+func validateHARestartControlPlane(ctx context.Context, t *testing.T, profile string) {
+	rr, err := Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "node", "start", SecondNodeName, "-v=7", "--alsologtostderr"))
+	if err != nil {
+		t.Fatalf("node start returned an error. args %q: %v", rr.Command(), err)
+	}
+
+	haStatus(ctx, t, profile, 4)
+	haNodes(ctx, t, profile, 4)
+}
+
+// validateHARestartCluster makes sure a stopped HA cluster restarts with all of its control planes
+func validateHARestartCluster(ctx context.Context, t *testing.T, profile string) {
+	rr, err := Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "stop"))
+	if err != nil {
+		t.Fatalf("failed to stop cluster. args %q: %v", rr.Command(), err)
+	}
+
+	startArgs := append([]string{"start", "-p", profile, "--wait=true", "-v=7", "--alsologtostderr"}, StartArgs()...)
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), startArgs...))
+	if err != nil {
+		t.Fatalf("failed to restart HA cluster. args %q : %v", rr.Command(), err)
+	}
+
+	haStatus(ctx, t, profile, 4)
+	haNodes(ctx, t, profile, 4)
+}
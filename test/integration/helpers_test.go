@@ -559,6 +559,28 @@ func testCpCmd(ctx context.Context, t *testing.T, profile string, node string) {
 	if diff := cmp.Diff(string(expected), rr.Stdout.String()); diff != "" {
 		t.Errorf("/testdata/cp-test.txt content mismatch (-want +got):\n%s", diff)
 	}
+
+	// copy the file back out of the node
+	nodeName := node
+	if nodeName == "" {
+		nodeName = profile
+	}
+	localPath := filepath.Join(t.TempDir(), "cp-test.txt")
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "cp", fmt.Sprintf("%s:%s", nodeName, dstPath), localPath))
+	if ctx.Err() == context.DeadlineExceeded {
+		t.Errorf("failed to run command by deadline. exceeded timeout : %s", rr.Command())
+	}
+	if err != nil {
+		t.Errorf("failed to run an cp command. args %q : %v", rr.Command(), err)
+	}
+
+	got, err := ioutil.ReadFile(localPath)
+	if err != nil {
+		t.Errorf("failed to read the file copied from the node: %v", err)
+	}
+	if diff := cmp.Diff(string(expected), string(got)); diff != "" {
+		t.Errorf("%s content mismatch (-want +got):\n%s", localPath, diff)
+	}
 }
 
 // CopyFile copies the specified source file to the specified destination file.
@@ -74,7 +74,7 @@ func TestKicExistingNetwork(t *testing.T) {
 	}
 	// create custom network
 	networkName := "existing-network"
-	if _, err := oci.CreateNetwork(oci.Docker, networkName); err != nil {
+	if _, err := oci.CreateNetwork(oci.Docker, networkName, ""); err != nil {
 		t.Fatalf("error creating network: %v", err)
 	}
 	defer func() {
@@ -96,6 +96,94 @@ func TestKicExistingNetwork(t *testing.T) {
 	}
 }
 
+// TestKicCustomSubnet verifies the docker driver creates the network of the cluster with the subnet and static IP requested
+func TestKicCustomSubnet(t *testing.T) {
+	if !KicDriver() {
+		t.Skip("only runs with docker driver")
+	}
+
+	profile := UniqueProfileName("custom-subnet")
+	ctx, cancel := context.WithTimeout(context.Background(), Minutes(5))
+	defer Cleanup(t, profile, cancel)
+
+	subnet := "192.168.60.0/24"
+	startArgs := []string{"start", "-p", profile, fmt.Sprintf("--subnet=%s", subnet), "--static-ip=192.168.60.10"}
+	c := exec.CommandContext(ctx, Target(), startArgs...)
+	rr, err := Run(t, c)
+	if err != nil {
+		t.Fatalf("%v failed: %v\n%v", rr.Command(), err, rr.Output())
+	}
+
+	verifyNetworkSubnet(ctx, t, profile, subnet)
+
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "ip"))
+	if err != nil {
+		t.Fatalf("%v failed: %v\n%v", rr.Command(), err, rr.Output())
+	}
+	if got := strings.TrimSpace(rr.Stdout.String()); got != "192.168.60.10" {
+		t.Errorf("expected the node to have the static IP 192.168.60.10, got %q", got)
+	}
+}
+
+// TestKicSharedNetwork verifies that the nodes of two clusters attached to a shared network can reach each other
+func TestKicSharedNetwork(t *testing.T) {
+	if !KicDriver() {
+		t.Skip("only runs with docker driver")
+	}
+
+	networkName := UniqueProfileName("shared-network")
+	if _, err := oci.CreateNetwork(oci.Docker, networkName, "192.168.70.0/24"); err != nil {
+		t.Fatalf("error creating network: %v", err)
+	}
+	defer func() {
+		if err := oci.RemoveNetwork(oci.Docker, networkName); err != nil {
+			t.Logf("error deleting network %s, may need to delete manually: %v", networkName, err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), Minutes(10))
+	defer cancel()
+
+	first := UniqueProfileName("shared-a")
+	second := UniqueProfileName("shared-b")
+	defer Cleanup(t, first, func() {})
+	defer Cleanup(t, second, func() {})
+
+	for _, profile := range []string{first, second} {
+		startArgs := []string{"start", "-p", profile, fmt.Sprintf("--extra-network=%s", networkName)}
+		rr, err := Run(t, exec.CommandContext(ctx, Target(), startArgs...))
+		if err != nil {
+			t.Fatalf("%v failed: %v\n%v", rr.Command(), err, rr.Output())
+		}
+	}
+
+	// the nodes keep the address of the network of their cluster
+	rr, err := Run(t, exec.CommandContext(ctx, Target(), "-p", second, "ip"))
+	if err != nil {
+		t.Fatalf("%v failed: %v\n%v", rr.Command(), err, rr.Output())
+	}
+	if ip := strings.TrimSpace(rr.Stdout.String()); strings.HasPrefix(ip, "192.168.70.") {
+		t.Errorf("expected the node to keep its address on the network of the cluster, got %s", ip)
+	}
+
+	// containers attached to the same user-defined network resolve each other by name
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), "ssh", "-p", first, "--", "ping", "-c", "1", second))
+	if err != nil {
+		t.Errorf("expected %s to reach %s over the shared network: %v\n%v", first, second, err, rr.Output())
+	}
+}
+
+func verifyNetworkSubnet(ctx context.Context, t *testing.T, networkName string, subnet string) {
+	c := exec.CommandContext(ctx, "docker", "network", "inspect", networkName, "--format", "{{range .IPAM.Config}}{{.Subnet}}{{end}}")
+	rr, err := Run(t, c)
+	if err != nil {
+		t.Fatalf("%v failed: %v\n%v", rr.Command(), err, rr.Output())
+	}
+	if got := strings.TrimSpace(rr.Stdout.String()); got != subnet {
+		t.Errorf("expected network %s to have subnet %s, got %q", networkName, subnet, got)
+	}
+}
+
 func verifyNetworkExists(ctx context.Context, t *testing.T, networkName string) {
 	c := exec.CommandContext(ctx, "docker", "network", "ls", "--format", "{{.Name}}")
 	rr, err := Run(t, c)
@@ -53,6 +53,7 @@ func TestMultiNode(t *testing.T) {
 			{"AddNode", validateAddNodeToMultiNode},
 			{"ProfileList", validateProfileListWithMultiNode},
 			{"CopyFile", validateCopyFileWithMultiNode},
+			{"RunOnAllNodes", validateRunOnAllNodes},
 			{"StopNode", validateStopRunningNode},
 			{"StartAfterStop", validateStartNodeAfterStop},
 			{"RestartKeepsNodes", validateRestartKeepsNodes},
@@ -185,6 +186,41 @@ func validateCopyFileWithMultiNode(ctx context.Context, t *testing.T, profile st
 	}
 }
 
+// validateRunOnAllNodes runs commands on all the nodes with minikube ssh --all-nodes and minikube exec
+func validateRunOnAllNodes(ctx context.Context, t *testing.T, profile string) {
+	if NoneDriver() {
+		t.Skipf("skipping: ssh is unsupported by none driver")
+	}
+
+	// each node prints its hostname, which is its name, prefixed by its name
+	rr, err := Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "ssh", "--all-nodes", "--", "cat /etc/hostname"))
+	if err != nil {
+		t.Fatalf("failed to run a command on all nodes. args %q : %v", rr.Command(), err)
+	}
+	for _, name := range []string{profile, fmt.Sprintf("%s-m02", profile), fmt.Sprintf("%s-m03", profile)} {
+		if want := fmt.Sprintf("[%s] %s", name, name); !strings.Contains(rr.Stdout.String(), want) {
+			t.Errorf("expected the output of %q to contain %q, but got *%q*", rr.Command(), want, rr.Stdout.String())
+		}
+	}
+
+	// the exit status is the one of the nodes
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "exec", "--all-nodes", "--", "false"))
+	if err == nil || rr.ExitCode != 1 {
+		t.Errorf("expected %q to exit with code 1, but got %d: %v", rr.Command(), rr.ExitCode, err)
+	}
+
+	// stdin is passed to the command
+	c := exec.CommandContext(ctx, Target(), "-p", profile, "exec", "-n", fmt.Sprintf("%s-m02", profile), "-i", "--", "cat")
+	c.Stdin = strings.NewReader("hello\n")
+	rr, err = Run(t, c)
+	if err != nil {
+		t.Fatalf("failed to run a command with stdin. args %q : %v", rr.Command(), err)
+	}
+	if strings.TrimSpace(rr.Stdout.String()) != "hello" {
+		t.Errorf("expected the output of %q to be \"hello\", but got *%q*", rr.Command(), rr.Stdout.String())
+	}
+}
+
 // validateStopRunningNode tests the minikube node stop command
 func validateStopRunningNode(ctx context.Context, t *testing.T, profile string) {
 	// Run minikube node stop on that node
@@ -0,0 +1,150 @@
+// +build integration
+
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestSnapshot tests saving a snapshot of a cluster, and restoring the cluster to it
+func TestSnapshot(t *testing.T) {
+	if !KicDriver() {
+		t.Skip("snapshots are only tested with the docker and podman drivers")
+	}
+	MaybeParallel(t)
+
+	type validateFunc func(context.Context, *testing.T, string)
+	profile := UniqueProfileName("snapshot")
+	ctx, cancel := context.WithTimeout(context.Background(), Minutes(20))
+	defer Cleanup(t, profile, cancel)
+
+	t.Run("serial", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			validator validateFunc
+		}{
+			{"Start", validateFreshStart},
+			{"Save", validateSnapshotSave},
+			{"List", validateSnapshotList},
+			{"ModifyCluster", validateSnapshotModify},
+			{"Restore", validateSnapshotRestore},
+			{"VerifyRestored", validateSnapshotRestored},
+			{"Delete", validateSnapshotDelete},
+		}
+		for _, tc := range tests {
+			tc := tc
+
+			if ctx.Err() == context.DeadlineExceeded {
+				t.Fatalf("Unable to run more tests (deadline exceeded)")
+			}
+
+			t.Run(tc.name, func(t *testing.T) {
+				tc.validator(ctx, t, profile)
+				if t.Failed() && *postMortemLogs {
+					PostMortemLogs(t, profile)
+				}
+			})
+		}
+	})
+}
+
+// validateSnapshotSave saves a snapshot of the running cluster
+func validateSnapshotSave(ctx context.Context, t *testing.T, profile string) {
+	defer PostMortemLogs(t, profile)
+
+	rr, err := Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "snapshot", "save", "known-good"))
+	if err != nil {
+		t.Fatalf("failed to save snapshot: args %q: %v", rr.Command(), err)
+	}
+}
+
+// validateSnapshotList makes sure the saved snapshot is listed
+func validateSnapshotList(ctx context.Context, t *testing.T, profile string) {
+	rr, err := Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "snapshot", "list", "--output", "json"))
+	if err != nil {
+		t.Fatalf("failed to list snapshots: args %q: %v", rr.Command(), err)
+	}
+	var snapshots []struct {
+		Name    string `json:"name"`
+		Profile string `json:"profile"`
+	}
+	if err := json.Unmarshal(rr.Stdout.Bytes(), &snapshots); err != nil {
+		t.Fatalf("failed to decode snapshot list: %v\n%s", err, rr.Stdout)
+	}
+	if len(snapshots) != 1 || snapshots[0].Name != "known-good" || snapshots[0].Profile != profile {
+		t.Errorf("expected snapshot list to only contain known-good, got %+v", snapshots)
+	}
+}
+
+// validateSnapshotModify creates a configmap, which should be gone after restoring the snapshot
+func validateSnapshotModify(ctx context.Context, t *testing.T, profile string) {
+	rr, err := Run(t, exec.CommandContext(ctx, "kubectl", "--context", profile, "create", "configmap", "after-snapshot", "--from-literal=key=value"))
+	if err != nil {
+		t.Fatalf("failed to create configmap: args %q: %v", rr.Command(), err)
+	}
+}
+
+// validateSnapshotRestore restores the snapshot, and starts the restored cluster
+func validateSnapshotRestore(ctx context.Context, t *testing.T, profile string) {
+	defer PostMortemLogs(t, profile)
+
+	rr, err := Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "snapshot", "restore", "known-good"))
+	if err != nil {
+		t.Fatalf("failed to restore snapshot: args %q: %v", rr.Command(), err)
+	}
+
+	args := append([]string{"start", "-p", profile, "--wait=all"}, StartArgs()...)
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), args...))
+	if err != nil {
+		t.Fatalf("failed to start restored cluster: args %q: %v", rr.Command(), err)
+	}
+}
+
+// validateSnapshotRestored makes sure the etcd contents were restored, so the configmap created after the snapshot is gone
+func validateSnapshotRestored(ctx context.Context, t *testing.T, profile string) {
+	defer PostMortemLogs(t, profile)
+
+	rr, err := Run(t, exec.CommandContext(ctx, "kubectl", "--context", profile, "get", "configmaps", "-o", "name"))
+	if err != nil {
+		t.Fatalf("failed to get configmaps: args %q: %v", rr.Command(), err)
+	}
+	if strings.Contains(rr.Stdout.String(), "after-snapshot") {
+		t.Errorf("expected the configmap created after the snapshot to be gone, got:\n%s", rr.Stdout)
+	}
+}
+
+// validateSnapshotDelete deletes the snapshot, and makes sure it is no longer listed
+func validateSnapshotDelete(ctx context.Context, t *testing.T, profile string) {
+	rr, err := Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "snapshot", "delete", "known-good"))
+	if err != nil {
+		t.Fatalf("failed to delete snapshot: args %q: %v", rr.Command(), err)
+	}
+
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "snapshot", "list", "--output", "json"))
+	if err != nil {
+		t.Fatalf("failed to list snapshots: args %q: %v", rr.Command(), err)
+	}
+	if strings.Contains(rr.Stdout.String(), "known-good") {
+		t.Errorf("expected known-good to be deleted, got:\n%s", rr.Stdout)
+	}
+}
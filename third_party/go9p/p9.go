@@ -101,14 +101,20 @@ const (
 	NOUID uint32 = 0xFFFFFFFF // no uid specified
 )
 
-// Error values
+// Error values, the Linux errno values
 const (
 	EPERM   = 1
 	ENOENT  = 2
 	EIO     = 5
+	E2BIG   = 7
+	EBADF   = 9
 	EEXIST  = 17
 	ENOTDIR = 20
+	EISDIR  = 21
 	EINVAL  = 22
+	ERANGE  = 34
+	ELOOP   = 40
+	ENOTSUP = 95
 )
 
 // Error represents a 9P2000 (and 9P2000.u) error
@@ -162,7 +168,7 @@ type Fcall struct {
 	Uname   string   // user name (used by Tauth, Tattach)
 	Aname   string   // attach name (used by Tauth, Tattach)
 	Perm    uint32   // file permission (mode) (used by Tcreate)
-	Name    string   // file name (used by Tcreate)
+	Name    string   // file name (used by Tcreate, 9P2000.L messages that name a file)
 	Mode    uint8    // open mode (used by Topen, Tcreate)
 	Newfid  uint32   // the fid that represents the file walked to (used by Twalk)
 	Wname   []string // list of names to walk (used by Twalk)
@@ -174,9 +180,23 @@ type Fcall struct {
 
 	/* 9P2000.u extensions */
 	Errornum uint32 // error code, 9P2000.u only (used by Rerror)
-	Ext      string // special file description, 9P2000.u only (used by Tcreate)
+	Ext      string // special file description, 9P2000.u only (used by Tcreate), or symbolic link target (used by Tsymlink, Rreadlink)
 	Unamenum uint32 // user ID, 9P2000.u only (used by Tauth, Tattach)
 
+	/* 9P2000.L extensions */
+	Flags     uint32 // flags (used by Tlopen, Tlcreate, Txattrcreate, Tunlinkat, Tfsync)
+	Gid       uint32 // group ID of new files (used by Tlcreate, Tsymlink, Tmknod, Tmkdir)
+	Major     uint32 // major device number (used by Tmknod)
+	Minor     uint32 // minor device number (used by Tmknod)
+	Ofid      uint32 // the other fid (used by Trename, Tlink, Trenameat)
+	Newname   string // new file name (used by Trenameat)
+	Mask      uint64 // requested attributes (used by Tgetattr)
+	Xattrsize uint64 // size of an extended attribute (used by Txattrcreate, Rxattrwalk)
+	Status    uint8  // lock status (used by Rlock)
+	Attr      Attr   // file attributes (used by Rgetattr, Tsetattr)
+	Statfs    Statfs // file system description (used by Rstatfs)
+	Flock     Flock  // lock description (used by Tlock, Tgetlock, Rgetlock)
+
 	Pkt []uint8 // raw packet data
 	Buf []uint8 // buffer to put the raw data in
 }
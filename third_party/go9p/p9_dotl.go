@@ -0,0 +1,200 @@
+// Copyright 2009 The Go9p Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package go9p
+
+// 9P2000.L message types. The connection keeps using the 9P2000 messages
+// for version, auth, attach, flush, walk, read, write, clunk and remove.
+const (
+	Tlerror      = 6
+	Rlerror      = 7
+	Tstatfs      = 8
+	Rstatfs      = 9
+	Tlopen       = 12
+	Rlopen       = 13
+	Tlcreate     = 14
+	Rlcreate     = 15
+	Tsymlink     = 16
+	Rsymlink     = 17
+	Tmknod       = 18
+	Rmknod       = 19
+	Trename      = 20
+	Rrename      = 21
+	Treadlink    = 22
+	Rreadlink    = 23
+	Tgetattr     = 24
+	Rgetattr     = 25
+	Tsetattr     = 26
+	Rsetattr     = 27
+	Txattrwalk   = 30
+	Rxattrwalk   = 31
+	Txattrcreate = 32
+	Rxattrcreate = 33
+	Treaddir     = 40
+	Rreaddir     = 41
+	Tfsync       = 50
+	Rfsync       = 51
+	Tlock        = 52
+	Rlock        = 53
+	Tgetlock     = 54
+	Rgetlock     = 55
+	Tlink        = 70
+	Rlink        = 71
+	Tmkdir       = 72
+	Rmkdir       = 73
+	Trenameat    = 74
+	Rrenameat    = 75
+	Tunlinkat    = 76
+	Runlinkat    = 77
+)
+
+// Flags for the flags field in Tlopen and Tlcreate messages, the values
+// of the Linux open flags
+const (
+	LRDONLY    = 00000000
+	LWRONLY    = 00000001
+	LRDWR      = 00000002
+	LCREATE    = 00000100
+	LEXCL      = 00000200
+	LTRUNC     = 00001000
+	LAPPEND    = 00002000
+	LDIRECTORY = 00200000
+)
+
+// Bits of the mask of Tgetattr and of the valid field of Rgetattr
+const (
+	GETATTRMODE   = 0x00000001
+	GETATTRNLINK  = 0x00000002
+	GETATTRUID    = 0x00000004
+	GETATTRGID    = 0x00000008
+	GETATTRRDEV   = 0x00000010
+	GETATTRATIME  = 0x00000020
+	GETATTRMTIME  = 0x00000040
+	GETATTRCTIME  = 0x00000080
+	GETATTRINO    = 0x00000100
+	GETATTRSIZE   = 0x00000200
+	GETATTRBLOCKS = 0x00000400
+	GETATTRBASIC  = 0x000007ff
+)
+
+// Bits of the valid field of Tsetattr
+const (
+	SETATTRMODE     = 0x00000001
+	SETATTRUID      = 0x00000002
+	SETATTRGID      = 0x00000004
+	SETATTRSIZE     = 0x00000008
+	SETATTRATIME    = 0x00000010
+	SETATTRMTIME    = 0x00000020
+	SETATTRCTIME    = 0x00000040
+	SETATTRATIMESET = 0x00000080
+	SETATTRMTIMESET = 0x00000100
+)
+
+// Lock types, flags and statuses of Tlock, Rlock, Tgetlock and Rgetlock
+const (
+	LOCKTYPERDLCK = 0
+	LOCKTYPEWRLCK = 1
+	LOCKTYPEUNLCK = 2
+
+	LOCKFLAGSBLOCK   = 1
+	LOCKFLAGSRECLAIM = 2
+
+	LOCKSUCCESS = 0
+	LOCKBLOCKED = 1
+	LOCKERROR   = 2
+	LOCKGRACE   = 3
+)
+
+// Flags of Txattrcreate, the values of the Linux setxattr flags
+const (
+	XATTRCREATE  = 1
+	XATTRREPLACE = 2
+)
+
+// XATTRSIZEMAX is the largest value of an extended attribute
+const XATTRSIZEMAX = 65536
+
+// ATREMOVEDIR is the flag of Tunlinkat that removes a directory
+const ATREMOVEDIR = 0x200
+
+// Types of the entries returned by Rreaddir, the Linux DT_* values
+const (
+	DTUNKNOWN = 0
+	DTFIFO    = 1
+	DTCHR     = 2
+	DTDIR     = 4
+	DTBLK     = 6
+	DTREG     = 8
+	DTLNK     = 10
+	DTSOCK    = 12
+)
+
+// V9FSMAGIC is the file system type reported by Rstatfs
+const V9FSMAGIC = 0x01021997
+
+// Attr describes the attributes of a file (9P2000.L)
+type Attr struct {
+	Valid     uint64 // mask of the valid fields
+	Qid              // file's Qid
+	Mode      uint32 // protection and file type
+	Uid       uint32 // owner ID
+	Gid       uint32 // group ID
+	Nlink     uint64 // number of hard links
+	Rdev      uint64 // device ID, for special files
+	Size      uint64 // file length in bytes
+	Blksize   uint64 // block size for I/O
+	Blocks    uint64 // number of 512 byte blocks allocated
+	AtimeSec  uint64 // last access time
+	AtimeNsec uint64
+	MtimeSec  uint64 // last modification time
+	MtimeNsec uint64
+	CtimeSec  uint64 // last status change time
+	CtimeNsec uint64
+}
+
+// Statfs describes a file system (9P2000.L)
+type Statfs struct {
+	Type    uint32 // type of file system
+	Bsize   uint32 // block size
+	Blocks  uint64 // total blocks
+	Bfree   uint64 // free blocks
+	Bavail  uint64 // free blocks available to unprivileged users
+	Files   uint64 // total file nodes
+	Ffree   uint64 // free file nodes
+	Fsid    uint64 // file system ID
+	Namelen uint32 // maximum length of file names
+}
+
+// Flock describes a POSIX record lock (9P2000.L)
+type Flock struct {
+	Type     uint8  // LOCKTYPE*
+	Flags    uint32 // LOCKFLAGS* (used by Tlock)
+	Start    uint64 // starting offset
+	Length   uint64 // number of bytes, 0 locks to the end of the file
+	ProcId   uint32 // process ID of the owner
+	ClientId string // client ID of the owner
+}
+
+// minimum size of the body of a 9P2000.L message for a type
+var minFclsize = map[uint8]uint32{
+	Tstatfs:      4,  /* fid[4] */
+	Tlopen:       8,  /* fid[4] flags[4] */
+	Tlcreate:     18, /* fid[4] name[s] flags[4] mode[4] gid[4] */
+	Tsymlink:     12, /* fid[4] name[s] symtgt[s] gid[4] */
+	Tmknod:       22, /* dfid[4] name[s] mode[4] major[4] minor[4] gid[4] */
+	Trename:      10, /* fid[4] dfid[4] name[s] */
+	Treadlink:    4,  /* fid[4] */
+	Tgetattr:     12, /* fid[4] request_mask[8] */
+	Tsetattr:     60, /* fid[4] valid[4] mode[4] uid[4] gid[4] size[8] atime[16] mtime[16] */
+	Txattrwalk:   10, /* fid[4] newfid[4] name[s] */
+	Txattrcreate: 18, /* fid[4] name[s] attr_size[8] flags[4] */
+	Treaddir:     16, /* fid[4] offset[8] count[4] */
+	Tfsync:       4,  /* fid[4] (datasync[4]) */
+	Tlock:        31, /* fid[4] type[1] flags[4] start[8] length[8] proc_id[4] client_id[s] */
+	Tgetlock:     27, /* fid[4] type[1] start[8] length[8] proc_id[4] client_id[s] */
+	Tlink:        10, /* dfid[4] fid[4] name[s] */
+	Tmkdir:       14, /* dfid[4] name[s] mode[4] gid[4] */
+	Trenameat:    12, /* olddirfid[4] oldname[s] newdirfid[4] newname[s] */
+	Tunlinkat:    10, /* dirfd[4] name[s] flags[4] */
+}
@@ -0,0 +1,201 @@
+// Copyright 2009 The Go9p Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package go9p
+
+// Create a Rlerror message in the specified Fcall.
+func PackRlerror(fc *Fcall, errornum uint32) error {
+	p, err := packCommon(fc, 4, Rlerror) /* ecode[4] */
+	if err != nil {
+		return err
+	}
+
+	fc.Errornum = errornum
+	p = pint32(errornum, p)
+	return nil
+}
+
+// Create a Rstatfs message in the specified Fcall.
+func PackRstatfs(fc *Fcall, st *Statfs) error {
+	size := 4 + 4 + 8 + 8 + 8 + 8 + 8 + 8 + 4 /* type[4] bsize[4] blocks[8] bfree[8] bavail[8] files[8] ffree[8] fsid[8] namelen[4] */
+	p, err := packCommon(fc, size, Rstatfs)
+	if err != nil {
+		return err
+	}
+
+	fc.Statfs = *st
+	p = pint32(st.Type, p)
+	p = pint32(st.Bsize, p)
+	p = pint64(st.Blocks, p)
+	p = pint64(st.Bfree, p)
+	p = pint64(st.Bavail, p)
+	p = pint64(st.Files, p)
+	p = pint64(st.Ffree, p)
+	p = pint64(st.Fsid, p)
+	p = pint32(st.Namelen, p)
+	return nil
+}
+
+// Create a Rlopen message in the specified Fcall.
+func PackRlopen(fc *Fcall, qid *Qid, iounit uint32) error {
+	return packQidIounit(fc, Rlopen, qid, iounit)
+}
+
+// Create a Rlcreate message in the specified Fcall.
+func PackRlcreate(fc *Fcall, qid *Qid, iounit uint32) error {
+	return packQidIounit(fc, Rlcreate, qid, iounit)
+}
+
+func packQidIounit(fc *Fcall, id uint8, qid *Qid, iounit uint32) error {
+	p, err := packCommon(fc, 13+4, id) /* qid[13] iounit[4] */
+	if err != nil {
+		return err
+	}
+
+	fc.Qid = *qid
+	fc.Iounit = iounit
+	p = pqid(qid, p)
+	p = pint32(iounit, p)
+	return nil
+}
+
+// Create a Rsymlink message in the specified Fcall.
+func PackRsymlink(fc *Fcall, qid *Qid) error { return packQid(fc, Rsymlink, qid) }
+
+// Create a Rmknod message in the specified Fcall.
+func PackRmknod(fc *Fcall, qid *Qid) error { return packQid(fc, Rmknod, qid) }
+
+// Create a Rmkdir message in the specified Fcall.
+func PackRmkdir(fc *Fcall, qid *Qid) error { return packQid(fc, Rmkdir, qid) }
+
+func packQid(fc *Fcall, id uint8, qid *Qid) error {
+	p, err := packCommon(fc, 13, id) /* qid[13] */
+	if err != nil {
+		return err
+	}
+
+	fc.Qid = *qid
+	p = pqid(qid, p)
+	return nil
+}
+
+// Create a Rreadlink message in the specified Fcall.
+func PackRreadlink(fc *Fcall, target string) error {
+	p, err := packCommon(fc, 2+len(target), Rreadlink) /* target[s] */
+	if err != nil {
+		return err
+	}
+
+	fc.Ext = target
+	p = pstr(target, p)
+	return nil
+}
+
+// Create a Rgetattr message in the specified Fcall.
+func PackRgetattr(fc *Fcall, a *Attr) error {
+	size := 8 + 13 + 4 + 4 + 4 + 8*15 /* valid[8] qid[13] mode[4] uid[4] gid[4] nlink[8] rdev[8] size[8] blksize[8] blocks[8] atime[16] mtime[16] ctime[16] btime[16] gen[8] data_version[8] */
+	p, err := packCommon(fc, size, Rgetattr)
+	if err != nil {
+		return err
+	}
+
+	fc.Attr = *a
+	p = pint64(a.Valid, p)
+	p = pqid(&a.Qid, p)
+	p = pint32(a.Mode, p)
+	p = pint32(a.Uid, p)
+	p = pint32(a.Gid, p)
+	p = pint64(a.Nlink, p)
+	p = pint64(a.Rdev, p)
+	p = pint64(a.Size, p)
+	p = pint64(a.Blksize, p)
+	p = pint64(a.Blocks, p)
+	p = pint64(a.AtimeSec, p)
+	p = pint64(a.AtimeNsec, p)
+	p = pint64(a.MtimeSec, p)
+	p = pint64(a.MtimeNsec, p)
+	p = pint64(a.CtimeSec, p)
+	p = pint64(a.CtimeNsec, p)
+	/* btime, gen and data_version are reserved */
+	for i := 0; i < 4; i++ {
+		p = pint64(0, p)
+	}
+	return nil
+}
+
+// Create a Rxattrwalk message in the specified Fcall.
+func PackRxattrwalk(fc *Fcall, size uint64) error {
+	p, err := packCommon(fc, 8, Rxattrwalk) /* size[8] */
+	if err != nil {
+		return err
+	}
+
+	fc.Xattrsize = size
+	p = pint64(size, p)
+	return nil
+}
+
+// Initializes the specified Fcall value to contain Rreaddir message.
+// The user should copy the entries packed by PackDirent to fc.Data
+// and call SetRreadCount to update the data size to the actual value.
+func InitRreaddir(fc *Fcall, count uint32) error {
+	size := int(4 + count) /* count[4] data[count] */
+	p, err := packCommon(fc, size, Rreaddir)
+	if err != nil {
+		return err
+	}
+
+	fc.Count = count
+	fc.Data = p[4 : fc.Count+4]
+	p = pint32(count, p)
+	return nil
+}
+
+// Converts a directory entry to its on-the-wire representation in
+// Rreaddir. Offset is the offset of the next entry.
+func PackDirent(qid *Qid, offset uint64, typ uint8, name string) []byte {
+	buf := make([]byte, 13+8+1+2+len(name)) /* qid[13] offset[8] type[1] name[s] */
+	p := pqid(qid, buf)
+	p = pint64(offset, p)
+	p = pint8(typ, p)
+	p = pstr(name, p)
+	return buf
+}
+
+// Create a Rlock message in the specified Fcall.
+func PackRlock(fc *Fcall, status uint8) error {
+	p, err := packCommon(fc, 1, Rlock) /* status[1] */
+	if err != nil {
+		return err
+	}
+
+	fc.Status = status
+	p = pint8(status, p)
+	return nil
+}
+
+// Create a Rgetlock message in the specified Fcall.
+func PackRgetlock(fc *Fcall, lk *Flock) error {
+	size := 1 + 8 + 8 + 4 + 2 + len(lk.ClientId) /* type[1] start[8] length[8] proc_id[4] client_id[s] */
+	p, err := packCommon(fc, size, Rgetlock)
+	if err != nil {
+		return err
+	}
+
+	fc.Flock = *lk
+	p = pint8(lk.Type, p)
+	p = pint64(lk.Start, p)
+	p = pint64(lk.Length, p)
+	p = pint32(lk.ProcId, p)
+	p = pstr(lk.ClientId, p)
+	return nil
+}
+
+// Create an empty 9P2000.L response, one of Rrename, Rsetattr,
+// Rxattrcreate, Rfsync, Rlink, Rrenameat and Runlinkat, in the
+// specified Fcall.
+func PackRempty(fc *Fcall, id uint8) error {
+	_, err := packCommon(fc, 0, id)
+	return err
+}
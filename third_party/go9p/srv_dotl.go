@@ -0,0 +1,259 @@
+// Copyright 2009 The Go9p Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package go9p
+
+// SrvReqOpsDotl operations. File servers that implement them, in addition
+// to SrvReqOps, and set Srv.Dotl speak 9P2000.L with the clients that ask
+// for it. The operations correspond directly to the 9P2000.L message types.
+type SrvReqOpsDotl interface {
+	Statfs(*SrvReq)
+	Lopen(*SrvReq)
+	Lcreate(*SrvReq)
+	Symlink(*SrvReq)
+	Mknod(*SrvReq)
+	Rename(*SrvReq)
+	Readlink(*SrvReq)
+	Getattr(*SrvReq)
+	Setattr(*SrvReq)
+	Xattrwalk(*SrvReq)
+	Xattrcreate(*SrvReq)
+	Readdir(*SrvReq)
+	Fsync(*SrvReq)
+	Setlock(*SrvReq) // Tlock
+	Getlock(*SrvReq)
+	Link(*SrvReq)
+	Mkdir(*SrvReq)
+	Renameat(*SrvReq)
+	Unlinkat(*SrvReq)
+}
+
+// processDotl performs the default processing of the 9P2000.L requests
+func (req *SrvReq) processDotl() {
+	conn := req.Conn
+	tc := req.Tc
+	ops := (conn.Srv.ops).(SrvReqOpsDotl)
+
+	switch tc.Type {
+	case Trename, Tlink, Trenameat:
+		req.Ofid = conn.FidGet(tc.Ofid)
+		if req.Ofid == nil {
+			req.RespondError(Eunknownfid)
+			return
+		}
+	}
+
+	switch tc.Type {
+	default:
+		req.RespondError(&Error{"unknown message type", EINVAL})
+
+	case Tstatfs:
+		ops.Statfs(req)
+
+	case Tlopen:
+		fid := req.Fid
+		if fid.opened {
+			req.RespondError(Eopen)
+			return
+		}
+
+		mode := uint8(tc.Flags & 3)
+		if (fid.Type&QTDIR) != 0 && mode != OREAD {
+			req.RespondError(&Error{"is a directory", EISDIR})
+			return
+		}
+
+		fid.Omode = mode
+		ops.Lopen(req)
+
+	case Tlcreate:
+		fid := req.Fid
+		if fid.opened {
+			req.RespondError(Eopen)
+			return
+		}
+
+		if (fid.Type & QTDIR) == 0 {
+			req.RespondError(Enotdir)
+			return
+		}
+
+		fid.Omode = uint8(tc.Flags & 3)
+		ops.Lcreate(req)
+
+	case Tsymlink, Tmknod, Tmkdir, Tlink, Trenameat, Tunlinkat:
+		if (req.Fid.Type & QTDIR) == 0 {
+			req.RespondError(Enotdir)
+			return
+		}
+
+		switch tc.Type {
+		case Tsymlink:
+			ops.Symlink(req)
+		case Tmknod:
+			ops.Mknod(req)
+		case Tmkdir:
+			ops.Mkdir(req)
+		case Tlink:
+			ops.Link(req)
+		case Trenameat:
+			ops.Renameat(req)
+		case Tunlinkat:
+			ops.Unlinkat(req)
+		}
+
+	case Trename:
+		if (req.Ofid.Type & QTDIR) == 0 {
+			req.RespondError(Enotdir)
+			return
+		}
+
+		ops.Rename(req)
+
+	case Treadlink:
+		ops.Readlink(req)
+
+	case Tgetattr:
+		ops.Getattr(req)
+
+	case Tsetattr:
+		ops.Setattr(req)
+
+	case Txattrwalk:
+		if tc.Fid != tc.Newfid {
+			req.Newfid = conn.FidNew(tc.Newfid)
+			if req.Newfid == nil {
+				req.RespondError(Einuse)
+				return
+			}
+
+			req.Newfid.User = req.Fid.User
+		} else {
+			req.Newfid = req.Fid
+			req.Newfid.IncRef()
+		}
+
+		ops.Xattrwalk(req)
+
+	case Txattrcreate:
+		if req.Fid.opened {
+			req.RespondError(Eopen)
+			return
+		}
+
+		ops.Xattrcreate(req)
+
+	case Treaddir:
+		fid := req.Fid
+		if !fid.opened || (fid.Type&QTDIR) == 0 {
+			req.RespondError(Ebaduse)
+			return
+		}
+
+		if tc.Count+IOHDRSZ > conn.Msize {
+			req.RespondError(Etoolarge)
+			return
+		}
+
+		ops.Readdir(req)
+
+	case Tfsync:
+		ops.Fsync(req)
+
+	case Tlock:
+		ops.Setlock(req)
+
+	case Tgetlock:
+		ops.Getlock(req)
+	}
+}
+
+// postProcessDotl performs the post processing of the 9P2000.L requests
+// that change the state of their fids
+func (req *SrvReq) postProcessDotl() {
+	rc := req.Rc
+	if rc == nil {
+		return
+	}
+
+	switch req.Tc.Type {
+	case Tlopen:
+		req.Fid.opened = rc.Type == Rlopen
+
+	case Tlcreate:
+		if rc.Type == Rlcreate {
+			req.Fid.Type = rc.Qid.Type
+			req.Fid.opened = true
+		}
+
+	case Txattrwalk:
+		// the new fid reads the value of the attribute
+		if rc.Type == Rxattrwalk && req.Newfid != nil {
+			req.Newfid.Type = QTFILE
+			req.Newfid.Omode = OREAD
+			req.Newfid.opened = true
+			if req.Newfid != req.Fid {
+				req.Newfid.IncRef()
+			}
+		}
+
+	case Txattrcreate:
+		// the fid writes the value of the attribute, which is set
+		// when the fid is clunked
+		if rc.Type == Rxattrcreate {
+			req.Fid.Type = QTFILE
+			req.Fid.Omode = OWRITE
+			req.Fid.opened = true
+		}
+	}
+}
+
+func (req *SrvReq) respond(err error) {
+	if err != nil {
+		req.RespondError(err)
+	} else {
+		req.Respond()
+	}
+}
+
+// Respond to the request with Rstatfs message
+func (req *SrvReq) RespondRstatfs(st *Statfs) { req.respond(PackRstatfs(req.Rc, st)) }
+
+// Respond to the request with Rlopen message
+func (req *SrvReq) RespondRlopen(qid *Qid, iounit uint32) {
+	req.respond(PackRlopen(req.Rc, qid, iounit))
+}
+
+// Respond to the request with Rlcreate message
+func (req *SrvReq) RespondRlcreate(qid *Qid, iounit uint32) {
+	req.respond(PackRlcreate(req.Rc, qid, iounit))
+}
+
+// Respond to the request with Rsymlink message
+func (req *SrvReq) RespondRsymlink(qid *Qid) { req.respond(PackRsymlink(req.Rc, qid)) }
+
+// Respond to the request with Rmknod message
+func (req *SrvReq) RespondRmknod(qid *Qid) { req.respond(PackRmknod(req.Rc, qid)) }
+
+// Respond to the request with Rmkdir message
+func (req *SrvReq) RespondRmkdir(qid *Qid) { req.respond(PackRmkdir(req.Rc, qid)) }
+
+// Respond to the request with Rreadlink message
+func (req *SrvReq) RespondRreadlink(target string) { req.respond(PackRreadlink(req.Rc, target)) }
+
+// Respond to the request with Rgetattr message
+func (req *SrvReq) RespondRgetattr(a *Attr) { req.respond(PackRgetattr(req.Rc, a)) }
+
+// Respond to the request with Rxattrwalk message
+func (req *SrvReq) RespondRxattrwalk(size uint64) { req.respond(PackRxattrwalk(req.Rc, size)) }
+
+// Respond to the request with Rlock message
+func (req *SrvReq) RespondRlock(status uint8) { req.respond(PackRlock(req.Rc, status)) }
+
+// Respond to the request with Rgetlock message
+func (req *SrvReq) RespondRgetlock(lk *Flock) { req.respond(PackRgetlock(req.Rc, lk)) }
+
+// Respond to the request with the empty response to its message: Rrename,
+// Rsetattr, Rxattrcreate, Rfsync, Rlink, Rrenameat or Runlinkat
+func (req *SrvReq) RespondRempty() { req.respond(PackRempty(req.Rc, req.Tc.Type+1)) }
@@ -19,9 +19,15 @@ func (srv *Srv) version(req *SrvReq) {
 		conn.Msize = tc.Msize
 	}
 
-	conn.Dotu = tc.Version == "9P2000.u" && srv.Dotu
+	_, dotl := (srv.ops).(SrvReqOpsDotl)
+	conn.Dotl = tc.Version == "9P2000.L" && srv.Dotl && dotl
+	// clients asking for 9P2000.L accept 9P2000.u
+	conn.Dotu = ((tc.Version == "9P2000.u" || tc.Version == "9P2000.L") && srv.Dotu) || conn.Dotl
 	ver := "9P2000"
-	if conn.Dotu {
+	switch {
+	case conn.Dotl:
+		ver = "9P2000.L"
+	case conn.Dotu:
 		ver = "9P2000.u"
 	}
 
@@ -333,11 +339,13 @@ func (srv *Srv) read(req *SrvReq) {
 	}
 
 	if (fid.Type & QTDIR) != 0 {
+		fid.Lock()
 		if tc.Offset == 0 {
 			fid.Diroffset = 0
 		} else if tc.Offset != fid.Diroffset {
 			fid.Diroffset = tc.Offset
 		}
+		fid.Unlock()
 	}
 
 	(req.Conn.Srv.ops).(SrvReqOps).Read(req)
@@ -345,7 +353,9 @@ func (srv *Srv) read(req *SrvReq) {
 
 func (srv *Srv) readPost(req *SrvReq) {
 	if req.Rc != nil && req.Rc.Type == Rread && (req.Fid.Type&QTDIR) != 0 {
+		req.Fid.Lock()
 		req.Fid.Diroffset += uint64(req.Rc.Count)
+		req.Fid.Unlock()
 	}
 }
 
@@ -398,7 +408,8 @@ func (srv *Srv) clunk(req *SrvReq) {
 }
 
 func (srv *Srv) clunkPost(req *SrvReq) {
-	if req.Rc != nil && req.Rc.Type == Rclunk && req.Fid != nil {
+	// the fid is released even if the clunk failed
+	if req.Rc != nil && req.Fid != nil {
 		req.Fid.DecRef()
 	}
 }
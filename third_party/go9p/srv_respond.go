@@ -21,15 +21,25 @@ type SrvReqOps interface {
 	Wstat(*SrvReq)
 }
 
-// Respond to the request with Rerror message
+// Respond to the request with Rerror message, or Rlerror for 9P2000.L
 func (req *SrvReq) RespondError(err interface{}) {
+	ename, errornum := "", uint32(EIO)
 	switch e := err.(type) {
 	case *Error:
-		PackRerror(req.Rc, e.Error(), uint32(e.Errornum), req.Conn.Dotu)
+		ename, errornum = e.Error(), e.Errornum
 	case error:
-		PackRerror(req.Rc, e.Error(), uint32(EIO), req.Conn.Dotu)
+		ename = e.Error()
 	default:
-		PackRerror(req.Rc, fmt.Sprintf("%v", e), uint32(EIO), req.Conn.Dotu)
+		ename = fmt.Sprintf("%v", e)
+	}
+
+	if req.Conn.Dotl {
+		if errornum == 0 {
+			errornum = EIO
+		}
+		PackRlerror(req.Rc, errornum)
+	} else {
+		PackRerror(req.Rc, ename, errornum, req.Conn.Dotu)
 	}
 
 	req.Respond()
@@ -119,6 +119,7 @@ type Srv struct {
 	Id         string // Used for debugging and stats
 	Msize      uint32 // Maximum size of the 9P2000 messages supported by the server
 	Dotu       bool   // If true, the server supports the 9P2000.u extension
+	Dotl       bool   // If true, the server supports the 9P2000.L extension, ops must implement SrvReqOpsDotl
 	Debuglevel int    // debug level
 	Upool      Users  // Interface for finding users and groups known to the file server
 	Maxpend    int    // Maximum pending outgoing requests
@@ -133,7 +134,8 @@ type Conn struct {
 	sync.Mutex
 	Srv        *Srv
 	Msize      uint32 // maximum size of 9P2000 messages for the connection
-	Dotu       bool   // if true, both the client and the server speak 9P2000.u
+	Dotu       bool   // if true, both the client and the server speak 9P2000.u (or 9P2000.L)
+	Dotl       bool   // if true, both the client and the server speak 9P2000.L
 	Id         string // used for debugging and stats
 	Debuglevel int
 
@@ -185,7 +187,8 @@ type SrvReq struct {
 	Rc     *Fcall  // Outgoing 9P2000 response
 	Fid    *SrvFid // The SrvFid value for all messages that contain fid[4]
 	Afid   *SrvFid // The SrvFid value for the messages that contain afid[4] (Tauth and Tattach)
-	Newfid *SrvFid // The SrvFid value for the messages that contain newfid[4] (Twalk)
+	Newfid *SrvFid // The SrvFid value for the messages that contain newfid[4] (Twalk, Txattrwalk)
+	Ofid   *SrvFid // The SrvFid value for the other fid of Trename, Tlink and Trenameat
 	Conn   *Conn   // Connection that the request belongs to
 
 	status     reqStatus
@@ -276,6 +279,11 @@ func (req *SrvReq) Process() {
 
 	switch req.Tc.Type {
 	default:
+		if conn.Dotl {
+			req.processDotl()
+			return
+		}
+
 		req.RespondError(&Error{"unknown message type", EINVAL})
 
 	case Tversion:
@@ -354,6 +362,11 @@ func (req *SrvReq) PostProcess() {
 
 	case Tremove:
 		srv.removePost(req)
+
+	default:
+		if req.Conn.Dotl {
+			req.postProcessDotl()
+		}
 	}
 
 	if req.Fid != nil {
@@ -370,6 +383,11 @@ func (req *SrvReq) PostProcess() {
 		req.Newfid.DecRef()
 		req.Newfid = nil
 	}
+
+	if req.Ofid != nil {
+		req.Ofid.DecRef()
+		req.Ofid = nil
+	}
 }
 
 // The Respond method sends response back to the client. The req.Rc value
@@ -5,6 +5,7 @@
 package go9p
 
 import (
+	"errors"
 	"io"
 	"log"
 	"os"
@@ -12,10 +13,17 @@ import (
 	"path"
 	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"syscall"
+	"time"
 )
 
 type ufsFid struct {
+	// requests on different fids are processed concurrently, the
+	// mutex serializes the requests on the same fid
+	sync.Mutex
+	cache      *statCache
 	path       string
 	file       *os.File
 	dirs       []os.FileInfo
@@ -23,19 +31,39 @@ type ufsFid struct {
 	dirents    []byte
 	diroffset  uint64
 	st         os.FileInfo
+	xattr      *ufsXattr
+	// flock is the flock operation of the lock held by the fid, where
+	// locks are flock locks
+	flock int
+}
+
+// ufsXattr is an extended attribute read or written through a fid (9P2000.L)
+type ufsXattr struct {
+	name   string
+	flags  uint32 // XATTRCREATE, XATTRREPLACE
+	create bool   // the value is written, and set when the fid is clunked
+	value  []byte // the value, or the names of the attributes if name is empty
 }
 
 type Ufs struct {
 	Srv
 	Root string
+	// CacheTTL is how long the stats and the entries of directories are
+	// cached, changes made on the host are seen after it. Zero disables
+	// the cache.
+	CacheTTL time.Duration
+
+	cacheOnce sync.Once
+	cache     *statCache
 }
 
 func toError(err error) *Error {
 	var ecode uint32
 
 	ename := err.Error()
-	if e, ok := err.(syscall.Errno); ok {
-		ecode = uint32(e)
+	var e syscall.Errno
+	if errors.As(err, &e) {
+		ecode = linuxErrno(e)
 	} else {
 		ecode = EIO
 	}
@@ -46,7 +74,7 @@ func toError(err error) *Error {
 func (fid *ufsFid) stat() *Error {
 	var err error
 
-	fid.st, err = os.Lstat(fid.path)
+	fid.st, err = fid.cache.lstat(fid.path)
 	if err != nil {
 		return toError(err)
 	}
@@ -54,6 +82,32 @@ func (fid *ufsFid) stat() *Error {
 	return nil
 }
 
+// within reports whether p is root or a path below it
+func within(root, p string) bool {
+	root = path.Clean(root)
+	p = path.Clean(p)
+	if root == "/" || p == root {
+		return true
+	}
+	return strings.HasPrefix(p, root+"/")
+}
+
+// destPath returns the path a fid is renamed to by a wstat. Names starting
+// with / are relative to the root, others to the directory of the fid.
+func (ufs *Ufs) destPath(fid *ufsFid, name string) (string, *Error) {
+	var dest string
+	if name[0] == '/' {
+		dest = path.Join(ufs.Root, name)
+	} else {
+		dir, _ := path.Split(fid.path)
+		dest = path.Join(dir, name)
+	}
+	if !within(ufs.Root, dest) {
+		return "", &Error{"permission denied", EPERM}
+	}
+	return dest, nil
+}
+
 func omode2uflags(mode uint8) int {
 	ret := int(0)
 	switch mode & 3 {
@@ -167,12 +221,23 @@ func (ufs *Ufs) Attach(req *SrvReq) {
 		return
 	}
 
+	ufs.cacheOnce.Do(func() {
+		if ufs.CacheTTL > 0 {
+			ufs.cache = newStatCache(ufs.CacheTTL)
+		}
+	})
+
 	tc := req.Tc
 	fid := new(ufsFid)
+	fid.cache = ufs.cache
 	// You can think of the ufs.Root as a 'chroot' of a sort.
 	// clients attach are not allowed to go outside the
 	// directory represented by ufs.Root
 	fid.path = path.Join(ufs.Root, tc.Aname)
+	if !within(ufs.Root, fid.path) {
+		req.RespondError(&Error{"permission denied", EPERM})
+		return
+	}
 
 	req.Fid.Aux = fid
 	err := fid.stat()
@@ -187,8 +252,10 @@ func (ufs *Ufs) Attach(req *SrvReq) {
 
 func (*Ufs) Flush(req *SrvReq) {}
 
-func (*Ufs) Walk(req *SrvReq) {
+func (ufs *Ufs) Walk(req *SrvReq) {
 	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
 	tc := req.Tc
 
 	err := fid.stat()
@@ -198,19 +265,38 @@ func (*Ufs) Walk(req *SrvReq) {
 	}
 
 	if req.Newfid.Aux == nil {
-		req.Newfid.Aux = new(ufsFid)
+		req.Newfid.Aux = &ufsFid{cache: fid.cache}
 	}
 
 	nfid := req.Newfid.Aux.(*ufsFid)
 	wqids := make([]Qid, len(tc.Wname))
-	path := fid.path
+	cur, st := fid.path, fid.st
 	i := 0
 	for ; i < len(tc.Wname); i++ {
-		p := path + "/" + tc.Wname[i]
-		st, err := os.Lstat(p)
-		if err != nil {
+		// the client resolves symbolic links, walking through
+		// one could leave the root
+		if st.Mode()&os.ModeSymlink != 0 || !st.IsDir() {
 			if i == 0 {
-				req.RespondError(Enoent)
+				req.RespondError(&Error{"not a directory", ENOTDIR})
+				return
+			}
+
+			break
+		}
+
+		p := cur + "/" + tc.Wname[i]
+		if tc.Wname[i] == ".." {
+			p = path.Dir(cur)
+			if !within(ufs.Root, p) {
+				p = cur
+			}
+		}
+
+		var e error
+		st, e = fid.cache.lstat(p)
+		if e != nil {
+			if i == 0 {
+				req.RespondError(toError(e))
 				return
 			}
 
@@ -218,15 +304,17 @@ func (*Ufs) Walk(req *SrvReq) {
 		}
 
 		wqids[i] = *dir2Qid(st)
-		path = p
+		cur = p
 	}
 
-	nfid.path = path
+	nfid.path = cur
 	req.RespondRwalk(wqids[0:i])
 }
 
 func (*Ufs) Open(req *SrvReq) {
 	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
 	tc := req.Tc
 	err := fid.stat()
 	if err != nil {
@@ -240,12 +328,17 @@ func (*Ufs) Open(req *SrvReq) {
 		req.RespondError(toError(e))
 		return
 	}
+	if tc.Mode&OTRUNC != 0 {
+		fid.cache.invalidate(fid.path)
+	}
 
 	req.RespondRopen(dir2Qid(fid.st), 0)
 }
 
 func (*Ufs) Create(req *SrvReq) {
 	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
 	tc := req.Tc
 	err := fid.stat()
 	if err != nil {
@@ -305,6 +398,7 @@ func (*Ufs) Create(req *SrvReq) {
 		return
 	}
 
+	fid.cache.invalidate(path)
 	fid.path = path
 	fid.file = file
 	err = fid.stat()
@@ -318,8 +412,27 @@ func (*Ufs) Create(req *SrvReq) {
 
 func (*Ufs) Read(req *SrvReq) {
 	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
 	tc := req.Tc
 	rc := req.Rc
+	if fid.xattr != nil {
+		if fid.xattr.create {
+			req.RespondError(Ebaduse)
+			return
+		}
+
+		var data []byte
+		if tc.Offset < uint64(len(fid.xattr.value)) {
+			data = fid.xattr.value[tc.Offset:]
+		}
+		if len(data) > int(tc.Count) {
+			data = data[:tc.Count]
+		}
+		req.RespondRread(data)
+		return
+	}
+
 	err := fid.stat()
 	if err != nil {
 		req.RespondError(err)
@@ -331,32 +444,40 @@ func (*Ufs) Read(req *SrvReq) {
 	var e error
 	if fid.st.IsDir() {
 		if tc.Offset == 0 {
-			var e error
-			// If we got here, it was open. Can't really seek
-			// in most cases, just close and reopen it.
-			fid.file.Close()
-			if fid.file, e = os.OpenFile(fid.path, omode2uflags(req.Fid.Omode), 0); e != nil {
-				req.RespondError(toError(e))
-				return
-			}
+			if dirents, direntends, ok := fid.cache.dirents(fid.path, req.Conn.Dotu); ok {
+				fid.dirents = dirents
+				fid.direntends = direntends
+			} else {
+				var e error
+				// If we got here, it was open. Can't really seek
+				// in most cases, just close and reopen it.
+				fid.file.Close()
+				if fid.file, e = os.OpenFile(fid.path, omode2uflags(req.Fid.Omode), 0); e != nil {
+					req.RespondError(toError(e))
+					return
+				}
 
-			if fid.dirs, e = fid.file.Readdir(-1); e != nil {
-				req.RespondError(toError(e))
-				return
-			}
+				if fid.dirs, e = fid.file.Readdir(-1); e != nil {
+					req.RespondError(toError(e))
+					return
+				}
 
-			fid.dirents = nil
-			fid.direntends = nil
-			for i := 0; i < len(fid.dirs); i++ {
-				path := fid.path + "/" + fid.dirs[i].Name()
-				st, _ := dir2Dir(path, fid.dirs[i], req.Conn.Dotu, req.Conn.Srv.Upool)
-				if st == nil {
-					continue
+				fid.dirents = nil
+				fid.direntends = nil
+				for i := 0; i < len(fid.dirs); i++ {
+					path := fid.path + "/" + fid.dirs[i].Name()
+					// clients usually stat the entries next
+					fid.cache.put(path, fid.dirs[i])
+					st, _ := dir2Dir(path, fid.dirs[i], req.Conn.Dotu, req.Conn.Srv.Upool)
+					if st == nil {
+						continue
+					}
+					b := PackDir(st, req.Conn.Dotu)
+					fid.dirents = append(fid.dirents, b...)
+					count += len(b)
+					fid.direntends = append(fid.direntends, count)
 				}
-				b := PackDir(st, req.Conn.Dotu)
-				fid.dirents = append(fid.dirents, b...)
-				count += len(b)
-				fid.direntends = append(fid.direntends, count)
+				fid.cache.putDirents(fid.path, req.Conn.Dotu, fid.dirents, fid.direntends)
 			}
 		}
 
@@ -403,7 +524,22 @@ func (*Ufs) Read(req *SrvReq) {
 
 func (*Ufs) Write(req *SrvReq) {
 	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
 	tc := req.Tc
+	if fid.xattr != nil {
+		// the size of the value was given by Txattrcreate
+		size := uint64(len(fid.xattr.value))
+		if tc.Offset > size || uint64(len(tc.Data)) > size-tc.Offset {
+			req.RespondError(&Error{"value larger than its declared size", ERANGE})
+			return
+		}
+
+		copy(fid.xattr.value[tc.Offset:], tc.Data)
+		req.RespondRwrite(uint32(len(tc.Data)))
+		return
+	}
+
 	err := fid.stat()
 	if err != nil {
 		req.RespondError(err)
@@ -411,6 +547,7 @@ func (*Ufs) Write(req *SrvReq) {
 	}
 
 	n, e := fid.file.WriteAt(tc.Data, int64(tc.Offset))
+	fid.cache.invalidate(fid.path)
 	if e != nil {
 		req.RespondError(toError(e))
 		return
@@ -419,10 +556,25 @@ func (*Ufs) Write(req *SrvReq) {
 	req.RespondRwrite(uint32(n))
 }
 
-func (*Ufs) Clunk(req *SrvReq) { req.RespondRclunk() }
+func (*Ufs) Clunk(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	if x := fid.xattr; x != nil && x.create {
+		if err := setXattr(fid.path, x); err != nil {
+			req.RespondError(toError(err))
+			return
+		}
+		fid.cache.invalidate(fid.path)
+	}
+
+	req.RespondRclunk()
+}
 
 func (*Ufs) Remove(req *SrvReq) {
 	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
 	err := fid.stat()
 	if err != nil {
 		req.RespondError(err)
@@ -430,6 +582,7 @@ func (*Ufs) Remove(req *SrvReq) {
 	}
 
 	e := os.Remove(fid.path)
+	fid.cache.invalidate(fid.path)
 	if e != nil {
 		req.RespondError(toError(e))
 		return
@@ -440,6 +593,8 @@ func (*Ufs) Remove(req *SrvReq) {
 
 func (*Ufs) Stat(req *SrvReq) {
 	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
 	err := fid.stat()
 	if err != nil {
 		req.RespondError(err)
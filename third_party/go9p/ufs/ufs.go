@@ -7,15 +7,28 @@ package ufs
 import (
 	"fmt"
 	"log"
+	"path/filepath"
+	"time"
 
 	"k8s.io/minikube/third_party/go9p"
 )
 
+// cacheTTL is how long the server caches stats and directory entries
+const cacheTTL = time.Second
+
 func StartServer(addrVal string, debugVal int, rootVal string) {
+	// clients may not walk through symbolic links, so the root must not be one
+	if root, err := filepath.EvalSymlinks(rootVal); err == nil {
+		rootVal = root
+	}
+
 	ufs := new(go9p.Ufs)
 	ufs.Dotu = true
+	// 9P2000.L is served where the ufs implements it, not on windows
+	ufs.Dotl = true
 	ufs.Id = "ufs"
 	ufs.Root = rootVal
+	ufs.CacheTTL = cacheTTL
 	ufs.Debuglevel = debugVal
 	ufs.Start(ufs)
 
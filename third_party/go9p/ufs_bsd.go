@@ -0,0 +1,102 @@
+// +build darwin freebsd
+
+// Copyright 2009 The go9p Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package go9p
+
+import (
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// linuxErrnos are the Linux values of the errors of the host that differ,
+// the clients are Linux guests
+var linuxErrnos = map[syscall.Errno]uint32{
+	syscall.EDEADLK:      35,
+	syscall.EAGAIN:       11,
+	syscall.ELOOP:        40,
+	syscall.ENAMETOOLONG: 36,
+	syscall.ENOTEMPTY:    39,
+	syscall.EDQUOT:       122,
+	syscall.ESTALE:       116,
+	syscall.ENOLCK:       37,
+	syscall.ENOSYS:       38,
+	syscall.ENOTSUP:      95,
+	syscall.ETIMEDOUT:    110,
+	syscall.EOVERFLOW:    75,
+	syscall.ECANCELED:    125,
+	syscall.EILSEQ:       84,
+	syscall.ENOATTR:      61, // ENODATA
+}
+
+// linuxErrno returns the Linux value of an error of the host
+func linuxErrno(e syscall.Errno) uint32 {
+	if n, ok := linuxErrnos[e]; ok {
+		return n
+	}
+	return uint32(e)
+}
+
+// flockHow returns the flock operation of a lock type
+func flockHow(typ int16) int {
+	switch typ {
+	case unix.F_RDLCK:
+		return unix.LOCK_SH
+	case unix.F_WRLCK:
+		return unix.LOCK_EX
+	}
+	return unix.LOCK_UN
+}
+
+// setlock sets a flock lock on the whole file. The POSIX locks of darwin and
+// freebsd belong to the server process, so they would never conflict between
+// clients, while flock locks belong to the open file of a fid. Unlocking any
+// range releases the lock of the whole file.
+func setlock(fid *ufsFid, flk *unix.Flock_t) error {
+	how := flockHow(flk.Type)
+	if err := unix.Flock(int(fid.file.Fd()), how|unix.LOCK_NB); err != nil {
+		return err
+	}
+	fid.flock = how
+	if how == unix.LOCK_UN {
+		fid.flock = 0
+	}
+	return nil
+}
+
+// getlock returns the flock lock of another open file which conflicts with
+// flk, as a lock on the whole file. A shared lock of the fid itself can not be
+// told apart from the ones of the other clients when probing for a write lock.
+func getlock(fid *ufsFid, flk *unix.Flock_t) error {
+	how := flockHow(flk.Type)
+	if how == unix.LOCK_UN || fid.flock == unix.LOCK_EX || (fid.flock == unix.LOCK_SH && how == unix.LOCK_SH) {
+		flk.Type = unix.F_UNLCK
+		return nil
+	}
+
+	f, err := os.Open(fid.path)
+	if err != nil {
+		return unix.ENOLCK
+	}
+	defer f.Close()
+	switch err := unix.Flock(int(f.Fd()), how|unix.LOCK_NB); err {
+	case nil:
+		flk.Type = unix.F_UNLCK
+	case unix.EWOULDBLOCK:
+		// a write lock is only blocked by a read lock of another file if none holds the write lock
+		flk.Type = unix.F_WRLCK
+		if how == unix.LOCK_EX && unix.Flock(int(f.Fd()), unix.LOCK_SH|unix.LOCK_NB) == nil {
+			flk.Type = unix.F_RDLCK
+		}
+		flk.Start = 0
+		flk.Len = 0
+		flk.Pid = 0
+	default:
+		return err
+	}
+	return nil
+}
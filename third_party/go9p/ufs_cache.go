@@ -0,0 +1,155 @@
+// Copyright 2009 The go9p Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package go9p
+
+import (
+	"os"
+	"path"
+	"sync"
+	"time"
+)
+
+// maxCacheEntries bounds the number of paths in a statCache
+const maxCacheEntries = 100000
+
+type statEntry struct {
+	st      os.FileInfo
+	expires time.Time
+}
+
+type dirKey struct {
+	path string
+	dotu bool
+}
+
+type direntsEntry struct {
+	dirents    []byte
+	direntends []int
+	expires    time.Time
+}
+
+// statCache caches the results of Lstat and the packed entries of directories
+// for a short time, which saves system calls when clients walk large trees of
+// small files. Changes made through the server invalidate the cache, changes
+// made on the host are seen after the ttl.
+type statCache struct {
+	sync.Mutex
+	ttl   time.Duration
+	stats map[string]statEntry
+	dirs  map[dirKey]direntsEntry
+}
+
+func newStatCache(ttl time.Duration) *statCache {
+	return &statCache{
+		ttl:   ttl,
+		stats: make(map[string]statEntry),
+		dirs:  make(map[dirKey]direntsEntry),
+	}
+}
+
+// lstat returns the cached os.Lstat of p. A nil cache does not cache.
+func (c *statCache) lstat(p string) (os.FileInfo, error) {
+	if c == nil {
+		return os.Lstat(p)
+	}
+
+	c.Lock()
+	e, ok := c.stats[p]
+	c.Unlock()
+	if ok && time.Now().Before(e.expires) {
+		return e.st, nil
+	}
+
+	st, err := os.Lstat(p)
+	if err != nil {
+		return nil, err
+	}
+	c.put(p, st)
+	return st, nil
+}
+
+// put caches st as the Lstat of p
+func (c *statCache) put(p string, st os.FileInfo) {
+	if c == nil {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	if len(c.stats) >= maxCacheEntries {
+		c.purge()
+	}
+	c.stats[p] = statEntry{st, time.Now().Add(c.ttl)}
+}
+
+// dirents returns the cached packed entries of the directory p
+func (c *statCache) dirents(p string, dotu bool) ([]byte, []int, bool) {
+	if c == nil {
+		return nil, nil, false
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	e, ok := c.dirs[dirKey{p, dotu}]
+	if !ok || !time.Now().Before(e.expires) {
+		return nil, nil, false
+	}
+	return e.dirents, e.direntends, true
+}
+
+// putDirents caches the packed entries of the directory p
+func (c *statCache) putDirents(p string, dotu bool, dirents []byte, direntends []int) {
+	if c == nil {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	if len(c.dirs) >= maxCacheEntries {
+		c.purge()
+	}
+	c.dirs[dirKey{p, dotu}] = direntsEntry{dirents, direntends, time.Now().Add(c.ttl)}
+}
+
+// invalidate removes the cached Lstat of each path, and the cached entries
+// of each path and of its parent directory
+func (c *statCache) invalidate(paths ...string) {
+	if c == nil {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	for _, p := range paths {
+		parent := path.Dir(p)
+		delete(c.stats, p)
+		delete(c.stats, parent)
+		for _, dotu := range []bool{false, true} {
+			delete(c.dirs, dirKey{p, dotu})
+			delete(c.dirs, dirKey{parent, dotu})
+		}
+	}
+}
+
+// purge removes the expired entries, or all of them if none expired.
+// The cache must be locked.
+func (c *statCache) purge() {
+	now := time.Now()
+	n := len(c.stats) + len(c.dirs)
+	for p, e := range c.stats {
+		if !now.Before(e.expires) {
+			delete(c.stats, p)
+		}
+	}
+	for k, e := range c.dirs {
+		if !now.Before(e.expires) {
+			delete(c.dirs, k)
+		}
+	}
+	if len(c.stats)+len(c.dirs) == n {
+		c.stats = make(map[string]statEntry)
+		c.dirs = make(map[dirKey]direntsEntry)
+	}
+}
@@ -0,0 +1,723 @@
+// +build !windows
+
+// Copyright 2009 The go9p Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package go9p
+
+import (
+	"io"
+	"os"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// The 9P2000.L operations of the ufs. The client resolves symbolic links
+// itself, files are opened and created without following them so that
+// clients can't leave the root.
+
+// pathOf returns the path of a fid that is not locked by the request
+func pathOf(sfid *SrvFid) string {
+	fid := sfid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	return fid.path
+}
+
+// child returns the path of the entry name in the directory dir
+func child(dir, name string) (string, *Error) {
+	if name == "" || name == "." || name == ".." || strings.Contains(name, "/") {
+		return "", &Error{"invalid file name", EINVAL}
+	}
+
+	return dir + "/" + name, nil
+}
+
+// lflags2uflags converts the Linux open flags of Tlopen and Tlcreate. The
+// client sends the offsets of the writes, so O_APPEND is dropped.
+func lflags2uflags(flags uint32) int {
+	ret := syscall.O_NOFOLLOW
+	switch flags & 3 {
+	case LRDONLY:
+		ret |= os.O_RDONLY
+	case LWRONLY:
+		ret |= os.O_WRONLY
+	case LRDWR:
+		ret |= os.O_RDWR
+	}
+
+	if flags&LTRUNC != 0 {
+		ret |= os.O_TRUNC
+	}
+
+	if flags&LEXCL != 0 {
+		ret |= os.O_EXCL
+	}
+
+	return ret
+}
+
+func mode2FileMode(mode uint32) os.FileMode {
+	ret := os.FileMode(mode & 0777)
+	if mode&syscall.S_ISUID != 0 {
+		ret |= os.ModeSetuid
+	}
+
+	if mode&syscall.S_ISGID != 0 {
+		ret |= os.ModeSetgid
+	}
+
+	if mode&syscall.S_ISVTX != 0 {
+		ret |= os.ModeSticky
+	}
+
+	return ret
+}
+
+func dir2DirentType(d os.FileInfo) uint8 {
+	mode := d.Mode()
+	switch {
+	case mode.IsDir():
+		return DTDIR
+	case mode&os.ModeSymlink != 0:
+		return DTLNK
+	case mode&os.ModeNamedPipe != 0:
+		return DTFIFO
+	case mode&os.ModeSocket != 0:
+		return DTSOCK
+	case mode&os.ModeCharDevice != 0:
+		return DTCHR
+	case mode&os.ModeDevice != 0:
+		return DTBLK
+	case mode.IsRegular():
+		return DTREG
+	}
+
+	return DTUNKNOWN
+}
+
+// respondQid responds to a request that created the file p with its qid
+func respondQid(req *SrvReq, fid *ufsFid, p string, respond func(*Qid)) {
+	fid.cache.invalidate(p)
+	st, e := fid.cache.lstat(p)
+	if e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	respond(dir2Qid(st))
+}
+
+func (*Ufs) Statfs(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	st, e := fsStat(fid.path)
+	if e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	req.RespondRstatfs(st)
+}
+
+func (*Ufs) Lopen(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	tc := req.Tc
+	err := fid.stat()
+	if err != nil {
+		req.RespondError(err)
+		return
+	}
+
+	var e error
+	fid.file, e = os.OpenFile(fid.path, lflags2uflags(tc.Flags), 0)
+	if e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+	if tc.Flags&LTRUNC != 0 {
+		fid.cache.invalidate(fid.path)
+	}
+
+	req.RespondRlopen(dir2Qid(fid.st), 0)
+}
+
+func (*Ufs) Lcreate(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	tc := req.Tc
+	p, err := child(fid.path, tc.Name)
+	if err != nil {
+		req.RespondError(err)
+		return
+	}
+
+	// the files are created by the user of the server, the gid is ignored
+	file, e := os.OpenFile(p, lflags2uflags(tc.Flags)|os.O_CREATE, mode2FileMode(tc.Perm))
+	if e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	fid.cache.invalidate(p)
+	fid.path = p
+	fid.file = file
+	err = fid.stat()
+	if err != nil {
+		req.RespondError(err)
+		return
+	}
+
+	req.RespondRlcreate(dir2Qid(fid.st), 0)
+}
+
+func (*Ufs) Symlink(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	tc := req.Tc
+	p, err := child(fid.path, tc.Name)
+	if err != nil {
+		req.RespondError(err)
+		return
+	}
+
+	if e := os.Symlink(tc.Ext, p); e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	respondQid(req, fid, p, req.RespondRsymlink)
+}
+
+func (*Ufs) Mknod(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	tc := req.Tc
+	p, err := child(fid.path, tc.Name)
+	if err != nil {
+		req.RespondError(err)
+		return
+	}
+
+	var e error
+	switch tc.Perm & syscall.S_IFMT {
+	case syscall.S_IFIFO:
+		e = syscall.Mkfifo(p, tc.Perm&07777)
+
+	case 0, syscall.S_IFREG:
+		var file *os.File
+		file, e = os.OpenFile(p, os.O_CREATE|os.O_EXCL|syscall.O_NOFOLLOW, mode2FileMode(tc.Perm))
+		if e == nil {
+			file.Close()
+		}
+
+	default:
+		// devices and sockets of the client can't be files of the host
+		req.RespondError(Eperm)
+		return
+	}
+
+	if e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	respondQid(req, fid, p, req.RespondRmknod)
+}
+
+func (*Ufs) Mkdir(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	tc := req.Tc
+	p, err := child(fid.path, tc.Name)
+	if err != nil {
+		req.RespondError(err)
+		return
+	}
+
+	if e := os.Mkdir(p, mode2FileMode(tc.Perm)); e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	respondQid(req, fid, p, req.RespondRmkdir)
+}
+
+func (*Ufs) Rename(req *SrvReq) {
+	dir := pathOf(req.Ofid)
+	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	p, err := child(dir, req.Tc.Name)
+	if err != nil {
+		req.RespondError(err)
+		return
+	}
+
+	if e := os.Rename(fid.path, p); e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	fid.cache.invalidate(fid.path, p)
+	fid.path = p
+	req.RespondRempty()
+}
+
+func (*Ufs) Renameat(req *SrvReq) {
+	newdir := pathOf(req.Ofid)
+	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	tc := req.Tc
+	oldpath, err := child(fid.path, tc.Name)
+	if err != nil {
+		req.RespondError(err)
+		return
+	}
+
+	newpath, err := child(newdir, tc.Newname)
+	if err != nil {
+		req.RespondError(err)
+		return
+	}
+
+	e := os.Rename(oldpath, newpath)
+	fid.cache.invalidate(oldpath, newpath)
+	if e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	req.RespondRempty()
+}
+
+func (*Ufs) Unlinkat(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	tc := req.Tc
+	p, err := child(fid.path, tc.Name)
+	if err != nil {
+		req.RespondError(err)
+		return
+	}
+
+	var e error
+	if tc.Flags&ATREMOVEDIR != 0 {
+		e = syscall.Rmdir(p)
+	} else {
+		e = syscall.Unlink(p)
+	}
+	fid.cache.invalidate(p)
+	if e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	req.RespondRempty()
+}
+
+func (*Ufs) Link(req *SrvReq) {
+	target := pathOf(req.Ofid)
+	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	p, err := child(fid.path, req.Tc.Name)
+	if err != nil {
+		req.RespondError(err)
+		return
+	}
+
+	e := os.Link(target, p)
+	fid.cache.invalidate(target, p)
+	if e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	req.RespondRempty()
+}
+
+func (*Ufs) Readlink(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	target, e := os.Readlink(fid.path)
+	if e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	req.RespondRreadlink(target)
+}
+
+func (*Ufs) Getattr(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	err := fid.stat()
+	if err != nil {
+		req.RespondError(err)
+		return
+	}
+
+	req.RespondRgetattr(dir2Attr(fid.st))
+}
+
+func (*Ufs) Setattr(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	defer func() { fid.cache.invalidate(fid.path) }()
+	st, e := os.Lstat(fid.path)
+	if e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	a := &req.Tc.Attr
+	// chmod, truncate and utimes follow symbolic links
+	if st.Mode()&os.ModeSymlink != 0 && a.Valid&(SETATTRMODE|SETATTRSIZE|SETATTRATIME|SETATTRMTIME) != 0 {
+		req.RespondError(&Error{"not supported on symbolic links", ENOTSUP})
+		return
+	}
+
+	if a.Valid&SETATTRMODE != 0 {
+		if e := syscall.Chmod(fid.path, a.Mode&07777); e != nil {
+			req.RespondError(toError(e))
+			return
+		}
+	}
+
+	if a.Valid&(SETATTRUID|SETATTRGID) != 0 {
+		uid, gid := -1, -1
+		if a.Valid&SETATTRUID != 0 {
+			uid = int(a.Uid)
+		}
+		if a.Valid&SETATTRGID != 0 {
+			gid = int(a.Gid)
+		}
+		if e := os.Lchown(fid.path, uid, gid); e != nil {
+			req.RespondError(toError(e))
+			return
+		}
+	}
+
+	if a.Valid&SETATTRSIZE != 0 {
+		if e := os.Truncate(fid.path, int64(a.Size)); e != nil {
+			req.RespondError(toError(e))
+			return
+		}
+	}
+
+	// the times not set by the client are kept, the ones set without a
+	// value are set to the current time
+	if a.Valid&(SETATTRATIME|SETATTRMTIME) != 0 {
+		cur := dir2Attr(st)
+		at := time.Unix(int64(cur.AtimeSec), int64(cur.AtimeNsec))
+		mt := time.Unix(int64(cur.MtimeSec), int64(cur.MtimeNsec))
+		now := time.Now()
+		if a.Valid&SETATTRATIME != 0 {
+			at = now
+			if a.Valid&SETATTRATIMESET != 0 {
+				at = time.Unix(int64(a.AtimeSec), int64(a.AtimeNsec))
+			}
+		}
+		if a.Valid&SETATTRMTIME != 0 {
+			mt = now
+			if a.Valid&SETATTRMTIMESET != 0 {
+				mt = time.Unix(int64(a.MtimeSec), int64(a.MtimeNsec))
+			}
+		}
+		if e := os.Chtimes(fid.path, at, mt); e != nil {
+			req.RespondError(toError(e))
+			return
+		}
+	}
+
+	req.RespondRempty()
+}
+
+func (ufs *Ufs) Readdir(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	tc := req.Tc
+	rc := req.Rc
+	err := fid.stat()
+	if err != nil {
+		req.RespondError(err)
+		return
+	}
+
+	if tc.Offset == 0 || fid.direntends == nil {
+		if e := ufs.readDirents(fid); e != nil {
+			req.RespondError(toError(e))
+			return
+		}
+	}
+
+	// the offset of an entry is the number of entries before it
+	InitRreaddir(rc, tc.Count)
+	count := 0
+	if tc.Offset < uint64(len(fid.direntends)) {
+		start := 0
+		if tc.Offset > 0 {
+			start = fid.direntends[tc.Offset-1]
+		}
+		end := start
+		for _, e := range fid.direntends[tc.Offset:] {
+			if e-start > int(tc.Count) {
+				break
+			}
+			end = e
+		}
+		count = copy(rc.Data, fid.dirents[start:end])
+	}
+
+	SetRreadCount(rc, uint32(count))
+	req.Respond()
+}
+
+// readDirents packs the entries of the directory of fid for Rreaddir
+func (ufs *Ufs) readDirents(fid *ufsFid) error {
+	f, e := os.Open(fid.path)
+	if e != nil {
+		return e
+	}
+	defer f.Close()
+
+	dirs, e := f.Readdir(-1)
+	if e != nil {
+		return e
+	}
+
+	fid.dirents = nil
+	fid.direntends = nil
+	add := func(name string, st os.FileInfo) {
+		b := PackDirent(dir2Qid(st), uint64(len(fid.direntends)+1), dir2DirentType(st), name)
+		fid.dirents = append(fid.dirents, b...)
+		fid.direntends = append(fid.direntends, len(fid.dirents))
+	}
+
+	add(".", fid.st)
+	parent := fid.st
+	if p := path.Dir(fid.path); within(ufs.Root, p) {
+		if st, e := fid.cache.lstat(p); e == nil {
+			parent = st
+		}
+	}
+	add("..", parent)
+	for _, st := range dirs {
+		// clients usually stat the entries next
+		fid.cache.put(fid.path+"/"+st.Name(), st)
+		add(st.Name(), st)
+	}
+
+	return nil
+}
+
+func (*Ufs) Fsync(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	if fid.file == nil {
+		req.RespondError(&Error{"fid not opened", EBADF})
+		return
+	}
+
+	if e := fid.file.Sync(); e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	req.RespondRempty()
+}
+
+func flock2Unix(lk *Flock) (*unix.Flock_t, *Error) {
+	flk := &unix.Flock_t{Whence: io.SeekStart, Start: int64(lk.Start), Len: int64(lk.Length)}
+	switch lk.Type {
+	case LOCKTYPERDLCK:
+		flk.Type = unix.F_RDLCK
+	case LOCKTYPEWRLCK:
+		flk.Type = unix.F_WRLCK
+	case LOCKTYPEUNLCK:
+		flk.Type = unix.F_UNLCK
+	default:
+		return nil, &Error{"invalid lock type", EINVAL}
+	}
+
+	return flk, nil
+}
+
+// Setlock sets or clears a lock on the host file of an open fid.
+// It never blocks, the client retries the blocking locks.
+func (*Ufs) Setlock(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	if fid.file == nil {
+		req.RespondError(&Error{"fid not opened", EBADF})
+		return
+	}
+
+	flk, err := flock2Unix(&req.Tc.Flock)
+	if err != nil {
+		req.RespondError(err)
+		return
+	}
+
+	var status uint8 = LOCKSUCCESS
+	switch e := setlock(fid, flk); e {
+	case nil:
+	case unix.EAGAIN, unix.EACCES:
+		status = LOCKBLOCKED
+	default:
+		status = LOCKERROR
+	}
+
+	req.RespondRlock(status)
+}
+
+// Getlock returns the lock of the host file that conflicts with the one
+// described by the request, or the request with type LOCKTYPEUNLCK
+func (*Ufs) Getlock(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	if fid.file == nil {
+		req.RespondError(&Error{"fid not opened", EBADF})
+		return
+	}
+
+	lk := req.Tc.Flock
+	flk, err := flock2Unix(&lk)
+	if err != nil {
+		req.RespondError(err)
+		return
+	}
+
+	if e := getlock(fid, flk); e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	lk.Type = LOCKTYPEUNLCK
+	if flk.Type != unix.F_UNLCK {
+		lk.Type = LOCKTYPEWRLCK
+		if flk.Type == unix.F_RDLCK {
+			lk.Type = LOCKTYPERDLCK
+		}
+		lk.Start = uint64(flk.Start)
+		lk.Length = uint64(flk.Len)
+		lk.ProcId = 0
+		if flk.Pid > 0 {
+			lk.ProcId = uint32(flk.Pid)
+		}
+	}
+
+	req.RespondRgetlock(&lk)
+}
+
+func (*Ufs) Xattrwalk(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	tc := req.Tc
+	var value []byte
+	var e error
+	if tc.Name == "" {
+		value, e = readXattr(func(b []byte) (int, error) { return unix.Llistxattr(fid.path, b) })
+	} else {
+		value, e = readXattr(func(b []byte) (int, error) { return unix.Lgetxattr(fid.path, tc.Name, b) })
+	}
+	if e != nil {
+		req.RespondError(toError(e))
+		return
+	}
+
+	x := &ufsXattr{name: tc.Name, value: value}
+	if req.Newfid == req.Fid {
+		fid.xattr = x
+	} else {
+		req.Newfid.Aux = &ufsFid{cache: fid.cache, path: fid.path, xattr: x}
+	}
+
+	req.RespondRxattrwalk(uint64(len(value)))
+}
+
+// readXattr calls get with a buffer large enough for the value it returns
+func readXattr(get func([]byte) (int, error)) ([]byte, error) {
+	for {
+		n, e := get(nil)
+		if e != nil {
+			return nil, e
+		}
+
+		b := make([]byte, n)
+		if n == 0 {
+			return b, nil
+		}
+
+		n, e = get(b)
+		// the value grew in between
+		if e == unix.ERANGE {
+			continue
+		}
+		if e != nil {
+			return nil, e
+		}
+
+		return b[:n], nil
+	}
+}
+
+func (*Ufs) Xattrcreate(req *SrvReq) {
+	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	tc := req.Tc
+	if tc.Name == "" || tc.Flags&^(XATTRCREATE|XATTRREPLACE) != 0 {
+		req.RespondError(&Error{"invalid argument", EINVAL})
+		return
+	}
+
+	if tc.Xattrsize > XATTRSIZEMAX {
+		req.RespondError(&Error{"attribute value too large", E2BIG})
+		return
+	}
+
+	fid.xattr = &ufsXattr{name: tc.Name, flags: tc.Flags, create: true, value: make([]byte, tc.Xattrsize)}
+	req.RespondRempty()
+}
+
+// setXattr sets the extended attribute written through a fid. Clients
+// remove attributes by replacing them with an empty value.
+func setXattr(p string, x *ufsXattr) error {
+	if len(x.value) == 0 && x.flags&XATTRREPLACE != 0 {
+		return unix.Lremovexattr(p, x.name)
+	}
+
+	flags := 0
+	if x.flags&XATTRCREATE != 0 {
+		flags |= xattrCreate
+	}
+	if x.flags&XATTRREPLACE != 0 {
+		flags |= xattrReplace
+	}
+
+	return unix.Lsetxattr(p, x.name, x.value, flags)
+}
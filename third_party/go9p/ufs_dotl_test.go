@@ -0,0 +1,378 @@
+// +build !windows
+
+// Copyright 2009 The go9p Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package go9p
+
+import (
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// dotlClnt sends raw 9P2000.L messages to a ufs
+type dotlClnt struct {
+	t    *testing.T
+	conn net.Conn
+}
+
+// startUfs serves root with a ufs and returns its address
+func startUfs(t *testing.T, root string, dotl bool) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	ufs := new(Ufs)
+	ufs.Dotu = true
+	ufs.Dotl = dotl
+	ufs.Id = "ufs"
+	ufs.Root = root
+	ufs.CacheTTL = time.Minute
+	ufs.Start(ufs)
+	go ufs.StartListener(l) // nolint: errcheck
+	return l.Addr().String()
+}
+
+// dial connects to addr and negotiates version, it returns the version
+// of the server
+func dial(t *testing.T, addr, version string) (*dotlClnt, string) {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	c := &dotlClnt{t, conn}
+	typ, body := c.rpc(Tversion, uint32(8192), version)
+	if typ != Rversion {
+		t.Fatalf("Tversion returned message %d", typ)
+	}
+	_, body = gint32(body)
+	ver, _ := gstr(body)
+	return c, ver
+}
+
+// mountUfsDotl serves root with a ufs and returns a 9P2000.L client
+// attached to it with fid 1
+func mountUfsDotl(t *testing.T, root string) *dotlClnt {
+	t.Helper()
+	c, ver := dial(t, startUfs(t, root, true), "9P2000.L")
+	if ver != "9P2000.L" {
+		t.Fatalf("version = %q; want 9P2000.L", ver)
+	}
+	c.ok(Rattach, Tattach, uint32(1), NOFID, "", "", uint32(os.Getuid()))
+	return c
+}
+
+// rpc sends a message made of args and returns the type and the body of
+// the response
+func (c *dotlClnt) rpc(typ uint8, args ...interface{}) (uint8, []byte) {
+	c.t.Helper()
+	body := []byte{}
+	for _, a := range args {
+		switch v := a.(type) {
+		case uint8:
+			body = append(body, v)
+		case uint16:
+			body = append(body, 0, 0)
+			binary.LittleEndian.PutUint16(body[len(body)-2:], v)
+		case uint32:
+			body = append(body, 0, 0, 0, 0)
+			binary.LittleEndian.PutUint32(body[len(body)-4:], v)
+		case uint64:
+			body = append(body, 0, 0, 0, 0, 0, 0, 0, 0)
+			binary.LittleEndian.PutUint64(body[len(body)-8:], v)
+		case string:
+			body = append(body, 0, 0)
+			binary.LittleEndian.PutUint16(body[len(body)-2:], uint16(len(v)))
+			body = append(body, v...)
+		case []byte:
+			body = append(body, v...)
+		default:
+			c.t.Fatalf("bad argument %T", a)
+		}
+	}
+
+	msg := make([]byte, 7, 7+len(body))
+	binary.LittleEndian.PutUint32(msg, uint32(7+len(body)))
+	msg[4] = typ
+	// Tversion flushes the requests with other tags
+	tag := uint16(1)
+	if typ == Tversion {
+		tag = NOTAG
+	}
+	binary.LittleEndian.PutUint16(msg[5:], tag)
+	if _, err := c.conn.Write(append(msg, body...)); err != nil {
+		c.t.Fatal(err)
+	}
+
+	hdr := make([]byte, 7)
+	if _, err := io.ReadFull(c.conn, hdr); err != nil {
+		c.t.Fatal(err)
+	}
+	resp := make([]byte, binary.LittleEndian.Uint32(hdr)-7)
+	if _, err := io.ReadFull(c.conn, resp); err != nil {
+		c.t.Fatal(err)
+	}
+	return hdr[4], resp
+}
+
+// ok sends a message and fails unless it returns a response of type want
+func (c *dotlClnt) ok(want uint8, typ uint8, args ...interface{}) []byte {
+	c.t.Helper()
+	rtyp, body := c.rpc(typ, args...)
+	if rtyp == Rlerror {
+		ecode, _ := gint32(body)
+		c.t.Fatalf("message %d failed: %v", typ, syscall.Errno(ecode))
+	}
+	if rtyp != want {
+		c.t.Fatalf("message %d returned %d; want %d", typ, rtyp, want)
+	}
+	return body
+}
+
+// errno sends a message and returns the error it failed with
+func (c *dotlClnt) errno(typ uint8, args ...interface{}) uint32 {
+	c.t.Helper()
+	rtyp, body := c.rpc(typ, args...)
+	if rtyp != Rlerror {
+		c.t.Fatalf("message %d returned %d; want an error", typ, rtyp)
+	}
+	ecode, _ := gint32(body)
+	return ecode
+}
+
+// walk walks fid 1 to newfid
+func (c *dotlClnt) walk(newfid uint32, names ...string) {
+	c.t.Helper()
+	args := []interface{}{uint32(1), newfid, uint16(len(names))}
+	for _, n := range names {
+		args = append(args, n)
+	}
+	c.ok(Rwalk, Twalk, args...)
+}
+
+// readdir returns the sorted names of the directory opened with fid
+func (c *dotlClnt) readdir(fid uint32, count uint32) string {
+	c.t.Helper()
+	var names []string
+	offset := uint64(0)
+	for {
+		body := c.ok(Rreaddir, Treaddir, fid, offset, count)
+		n, p := gint32(body)
+		if n == 0 {
+			break
+		}
+		p = p[:n]
+		for len(p) > 0 {
+			var qid Qid
+			var name string
+			p = gqid(p, &qid)
+			offset, p = gint64(p)
+			_, p = gint8(p)
+			name, p = gstr(p)
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+func TestUfsVersion(t *testing.T) {
+	root := t.TempDir()
+	if _, ver := dial(t, startUfs(t, root, false), "9P2000.L"); ver != "9P2000.u" {
+		t.Errorf("version without 9P2000.L = %q; want 9P2000.u", ver)
+	}
+	addr := startUfs(t, root, true)
+	if _, ver := dial(t, addr, "9P2000.u"); ver != "9P2000.u" {
+		t.Errorf("version for a 9P2000.u client = %q; want 9P2000.u", ver)
+	}
+	if _, ver := dial(t, addr, "9P2000.L"); ver != "9P2000.L" {
+		t.Errorf("version for a 9P2000.L client = %q; want 9P2000.L", ver)
+	}
+}
+
+func TestUfsDotlFiles(t *testing.T) {
+	root := t.TempDir()
+	c := mountUfsDotl(t, root)
+
+	// create and write a file
+	c.walk(2)
+	c.ok(Rlcreate, Tlcreate, uint32(2), "file", uint32(LRDWR), uint32(0644), uint32(0))
+	body := c.ok(Rwrite, Twrite, uint32(2), uint64(0), uint32(5), []byte("hello"))
+	if n, _ := gint32(body); n != 5 {
+		t.Errorf("write count = %d; want 5", n)
+	}
+	c.ok(Rfsync, Tfsync, uint32(2), uint32(0))
+	body = c.ok(Rgetattr, Tgetattr, uint32(2), uint64(GETATTRBASIC))
+	var a Attr
+	a.Valid, body = gint64(body)
+	body = gqid(body, &a.Qid)
+	a.Mode, body = gint32(body)
+	body = body[4+4+8+8:]
+	a.Size, _ = gint64(body)
+	if a.Mode&syscall.S_IFMT != syscall.S_IFREG || a.Size != 5 {
+		t.Errorf("getattr of file = mode %o size %d; want a regular file of 5 bytes", a.Mode, a.Size)
+	}
+
+	// setattr
+	c.walk(3, "file")
+	c.ok(Rsetattr, Tsetattr, uint32(3), uint32(SETATTRMODE|SETATTRSIZE), uint32(0600), uint32(0), uint32(0),
+		uint64(2), uint64(0), uint64(0), uint64(0), uint64(0))
+	st, err := os.Stat(filepath.Join(root, "file"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Mode().Perm() != 0600 || st.Size() != 2 {
+		t.Errorf("file after setattr = mode %o size %d; want mode 600 size 2", st.Mode().Perm(), st.Size())
+	}
+
+	// directories and symbolic links
+	body = c.ok(Rmkdir, Tmkdir, uint32(1), "dir", uint32(0755), uint32(0))
+	var qid Qid
+	gqid(body, &qid)
+	if qid.Type&QTDIR == 0 {
+		t.Errorf("qid of the new directory = %v; want a directory", &qid)
+	}
+	c.ok(Rsymlink, Tsymlink, uint32(1), "link", "file", uint32(0))
+	c.walk(4, "link")
+	body = c.ok(Rreadlink, Treadlink, uint32(4))
+	if target, _ := gstr(body); target != "file" {
+		t.Errorf("readlink = %q; want file", target)
+	}
+	c.ok(Rlink, Tlink, uint32(1), uint32(3), "hardlink")
+
+	// read the directory in small pieces
+	c.walk(5)
+	c.ok(Rlopen, Tlopen, uint32(5), uint32(LRDONLY))
+	if got := c.readdir(5, 60); got != ".,..,dir,file,hardlink,link" {
+		t.Errorf("entries of / = %q; want .,..,dir,file,hardlink,link", got)
+	}
+
+	// rename and remove
+	c.ok(Rrenameat, Trenameat, uint32(1), "file", uint32(1), "moved")
+	c.ok(Runlinkat, Tunlinkat, uint32(1), "dir", uint32(ATREMOVEDIR))
+	c.ok(Runlinkat, Tunlinkat, uint32(1), "hardlink", uint32(0))
+	if got := c.readdir(5, 8192-IOHDRSZ); got != ".,..,link,moved" {
+		t.Errorf("entries of / after rename and remove = %q; want .,..,link,moved", got)
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(root, "moved")); err != nil || string(b) != "he" {
+		t.Errorf("moved = %q, %v; want he", b, err)
+	}
+
+	// errors
+	if e := c.errno(Twalk, uint32(1), uint32(6), uint16(1), "missing"); e != ENOENT {
+		t.Errorf("walk to a missing file failed with %d; want ENOENT", e)
+	}
+	if e := c.errno(Tmkdir, uint32(1), "..", uint32(0755), uint32(0)); e != EINVAL {
+		t.Errorf("mkdir of .. failed with %d; want EINVAL", e)
+	}
+	if e := c.errno(Tunlinkat, uint32(1), "../"+filepath.Base(root), uint32(ATREMOVEDIR)); e != EINVAL {
+		t.Errorf("unlinkat of a path failed with %d; want EINVAL", e)
+	}
+	// opening the link would follow it
+	if e := c.errno(Tlopen, uint32(4), uint32(LRDONLY)); e != ELOOP {
+		t.Errorf("lopen of a symbolic link failed with %d; want ELOOP", e)
+	}
+}
+
+func TestUfsDotlLocks(t *testing.T) {
+	root := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(root, "file"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c := mountUfsDotl(t, root)
+
+	c.walk(2, "file")
+	c.ok(Rlopen, Tlopen, uint32(2), uint32(LRDWR))
+	c.walk(3, "file")
+	c.ok(Rlopen, Tlopen, uint32(3), uint32(LRDWR))
+
+	lock := func(fid uint32, typ uint8) uint8 {
+		body := c.ok(Rlock, Tlock, fid, typ, uint32(0), uint64(0), uint64(0), uint32(1), "client")
+		status, _ := gint8(body)
+		return status
+	}
+	getlock := func(fid uint32) uint8 {
+		body := c.ok(Rgetlock, Tgetlock, fid, uint8(LOCKTYPEWRLCK), uint64(0), uint64(0), uint32(1), "client")
+		typ, _ := gint8(body)
+		return typ
+	}
+
+	if s := lock(2, LOCKTYPEWRLCK); s != LOCKSUCCESS {
+		t.Fatalf("lock of fid 2 = %d; want success", s)
+	}
+	if typ := getlock(2); typ != LOCKTYPEUNLCK {
+		t.Errorf("getlock of fid 2 = %d; want its own lock not to conflict", typ)
+	}
+	if typ := getlock(3); typ != LOCKTYPEWRLCK {
+		t.Errorf("getlock of fid 3 = %d; want the write lock of fid 2", typ)
+	}
+	if s := lock(3, LOCKTYPEWRLCK); s != LOCKBLOCKED {
+		t.Errorf("lock of fid 3 = %d; want blocked", s)
+	}
+	if s := lock(2, LOCKTYPEUNLCK); s != LOCKSUCCESS {
+		t.Errorf("unlock of fid 2 = %d; want success", s)
+	}
+	if s := lock(3, LOCKTYPEWRLCK); s != LOCKSUCCESS {
+		t.Errorf("lock of fid 3 after unlock = %d; want success", s)
+	}
+}
+
+func TestUfsDotlXattrs(t *testing.T) {
+	root := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(root, "file"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	c := mountUfsDotl(t, root)
+
+	c.walk(2, "file")
+	c.ok(Rxattrcreate, Txattrcreate, uint32(2), "user.test", uint64(3), uint32(0))
+	c.ok(Rwrite, Twrite, uint32(2), uint64(0), uint32(3), []byte("abc"))
+	if typ, body := c.rpc(Tclunk, uint32(2)); typ == Rlerror {
+		if ecode, _ := gint32(body); ecode == ENOTSUP {
+			t.Skip("the file system does not support extended attributes")
+		}
+		t.Fatalf("setting the attribute failed: %v", body)
+	}
+
+	read := func(name string) string {
+		c.walk(3, "file")
+		body := c.ok(Rxattrwalk, Txattrwalk, uint32(3), uint32(4), name)
+		size, _ := gint64(body)
+		body = c.ok(Rread, Tread, uint32(4), uint64(0), uint32(size))
+		c.ok(Rclunk, Tclunk, uint32(4))
+		c.ok(Rclunk, Tclunk, uint32(3))
+		n, p := gint32(body)
+		return string(p[:n])
+	}
+	if v := read("user.test"); v != "abc" {
+		t.Errorf("user.test = %q; want abc", v)
+	}
+	if names := read(""); !strings.Contains(names, "user.test\x00") {
+		t.Errorf("attributes = %q; want user.test", names)
+	}
+
+	// an empty value that replaces the attribute removes it
+	c.walk(2, "file")
+	c.ok(Rxattrcreate, Txattrcreate, uint32(2), "user.test", uint64(0), uint32(XATTRREPLACE))
+	c.ok(Rclunk, Tclunk, uint32(2))
+	c.walk(3, "file")
+	if e := c.errno(Txattrwalk, uint32(3), uint32(4), "user.test"); e != 61 {
+		t.Errorf("reading the removed attribute failed with %d; want ENODATA", e)
+	}
+}
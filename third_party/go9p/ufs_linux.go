@@ -4,11 +4,12 @@ import (
 	"fmt"
 	"os"
 	"os/user"
-	"path"
 	"strconv"
 	"strings"
 	"syscall"
 	"time"
+
+	"golang.org/x/sys/unix"
 )
 
 func atime(stat *syscall.Stat_t) time.Time {
@@ -119,6 +120,9 @@ func (dir *ufsDir) dotu(path string, d os.FileInfo, upool Users, sysMode *syscal
 
 func (u *Ufs) Wstat(req *SrvReq) {
 	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	defer func() { fid.cache.invalidate(fid.path) }()
 	err := fid.stat()
 	if err != nil {
 		req.RespondError(err)
@@ -176,24 +180,16 @@ func (u *Ufs) Wstat(req *SrvReq) {
 	}
 
 	if dir.Name != "" {
-		fmt.Printf("Rename %s to %s\n", fid.path, dir.Name)
-		// if first char is / it is relative to root, else relative to
-		// cwd.
-		var destpath string
-		if dir.Name[0] == '/' {
-			destpath = path.Join(u.Root, dir.Name)
-			fmt.Printf("/ results in %s\n", destpath)
-		} else {
-			fiddir, _ := path.Split(fid.path)
-			destpath = path.Join(fiddir, dir.Name)
-			fmt.Printf("rel  results in %s\n", destpath)
-		}
-		err := os.Rename(fid.path, destpath)
-		fmt.Printf("rename %s to %s gets %v\n", fid.path, destpath, err)
+		destpath, err := u.destPath(fid, dir.Name)
 		if err != nil {
-			req.RespondError(toError(err))
+			req.RespondError(err)
+			return
+		}
+		if e := os.Rename(fid.path, destpath); e != nil {
+			req.RespondError(toError(e))
 			return
 		}
+		fid.cache.invalidate(fid.path, destpath)
 		fid.path = destpath
 	}
 
@@ -231,3 +227,64 @@ func (u *Ufs) Wstat(req *SrvReq) {
 
 	req.RespondRwstat()
 }
+
+// linuxErrno returns the Linux value of an error of the host
+func linuxErrno(e syscall.Errno) uint32 { return uint32(e) }
+
+// flags of setxattr
+const (
+	xattrCreate  = unix.XATTR_CREATE
+	xattrReplace = unix.XATTR_REPLACE
+)
+
+// setlock sets an open file description lock, which belongs to the open
+// file of a fid, not to the server process
+func setlock(fid *ufsFid, flk *unix.Flock_t) error {
+	return unix.FcntlFlock(fid.file.Fd(), unix.F_OFD_SETLK, flk)
+}
+
+// getlock returns the open file description lock which conflicts with flk
+func getlock(fid *ufsFid, flk *unix.Flock_t) error {
+	return unix.FcntlFlock(fid.file.Fd(), unix.F_OFD_GETLK, flk)
+}
+
+func dir2Attr(d os.FileInfo) *Attr {
+	stat := d.Sys().(*syscall.Stat_t)
+	return &Attr{
+		Valid:     GETATTRBASIC,
+		Qid:       *dir2Qid(d),
+		Mode:      uint32(stat.Mode),
+		Uid:       stat.Uid,
+		Gid:       stat.Gid,
+		Nlink:     uint64(stat.Nlink),
+		Rdev:      uint64(stat.Rdev),
+		Size:      uint64(stat.Size),
+		Blksize:   uint64(stat.Blksize),
+		Blocks:    uint64(stat.Blocks),
+		AtimeSec:  uint64(stat.Atim.Sec),
+		AtimeNsec: uint64(stat.Atim.Nsec),
+		MtimeSec:  uint64(stat.Mtim.Sec),
+		MtimeNsec: uint64(stat.Mtim.Nsec),
+		CtimeSec:  uint64(stat.Ctim.Sec),
+		CtimeNsec: uint64(stat.Ctim.Nsec),
+	}
+}
+
+func fsStat(path string) (*Statfs, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return nil, err
+	}
+
+	return &Statfs{
+		Type:    V9FSMAGIC,
+		Bsize:   uint32(st.Bsize),
+		Blocks:  st.Blocks,
+		Bfree:   st.Bfree,
+		Bavail:  st.Bavail,
+		Files:   st.Files,
+		Ffree:   st.Ffree,
+		Fsid:    uint64(uint32(st.Fsid.X__val[0])) | uint64(uint32(st.Fsid.X__val[1]))<<32,
+		Namelen: uint32(st.Namelen),
+	}, nil
+}
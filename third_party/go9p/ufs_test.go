@@ -0,0 +1,193 @@
+// +build !windows
+
+// Copyright 2009 The go9p Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package go9p
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mountUfs serves root with a ufs and returns a client attached to it
+func mountUfs(t *testing.T, root string) *Clnt {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	ufs := new(Ufs)
+	ufs.Dotu = true
+	ufs.Id = "ufs"
+	ufs.Root = root
+	ufs.CacheTTL = time.Minute
+	ufs.Start(ufs)
+	go ufs.StartListener(l) // nolint: errcheck
+
+	clnt, err := Mount("tcp", l.Addr().String(), "", 8192, OsUsers.Uid2User(os.Getuid()))
+	if err != nil {
+		t.Fatalf("mount failed: %v", err)
+	}
+	t.Cleanup(clnt.Unmount)
+	return clnt
+}
+
+// readNames returns the sorted names of the entries of dir
+func readNames(clnt *Clnt, dir string) (string, error) {
+	f, err := clnt.FOpen(dir, OREAD)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	var ns []string
+	for {
+		ds, err := f.Readdir(0)
+		if err != nil || len(ds) == 0 {
+			break
+		}
+		for _, d := range ds {
+			ns = append(ns, d.Name)
+		}
+	}
+	sort.Strings(ns)
+	return strings.Join(ns, ","), nil
+}
+
+func names(t *testing.T, clnt *Clnt, dir string) string {
+	t.Helper()
+	ns, err := readNames(clnt, dir)
+	if err != nil {
+		t.Fatalf("reading %s failed: %v", dir, err)
+	}
+	return ns
+}
+
+func TestUfsStaysInRoot(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "root")
+	if err := os.MkdirAll(filepath.Join(root, "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(parent, "secret"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(parent, filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	clnt := mountUfs(t, root)
+
+	if got := names(t, clnt, "/.."); got != "dir,escape" {
+		t.Errorf("entries of /.. = %q; want the entries of the root", got)
+	}
+	if got := names(t, clnt, "/dir/../.."); got != "dir,escape" {
+		t.Errorf("entries of /dir/../.. = %q; want the entries of the root", got)
+	}
+	if _, err := clnt.FStat("/escape/secret"); err == nil {
+		t.Errorf("stat of /escape/secret succeeded; want walking through the symbolic link to fail")
+	}
+
+	d, err := clnt.FStat("/escape")
+	if err != nil {
+		t.Fatalf("stat of /escape failed: %v", err)
+	}
+	if d.Mode&DMSYMLINK == 0 || d.Ext != parent {
+		t.Errorf("stat of /escape = mode %o, ext %q; want a symbolic link to %s", d.Mode, d.Ext, parent)
+	}
+
+	// rename out of the root
+	f, err := clnt.FWalk("/dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := &Dir{Name: "../../moved", Mode: ^uint32(0), Length: ^uint64(0), Atime: ^uint32(0), Mtime: ^uint32(0), Uidnum: NOUID, Gidnum: NOUID, Muidnum: NOUID}
+	if err := clnt.Wstat(f, dir); err == nil {
+		t.Errorf("rename to ../../moved succeeded; want it denied")
+	}
+	if _, err := os.Stat(filepath.Join(parent, "moved")); err == nil {
+		t.Errorf("dir was moved out of the root")
+	}
+}
+
+func TestUfsCacheInvalidation(t *testing.T) {
+	root := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(root, "a"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	clnt := mountUfs(t, root)
+
+	if got := names(t, clnt, "/"); got != "a" {
+		t.Fatalf("entries of / = %q; want a", got)
+	}
+	f, err := clnt.FCreate("/b", 0644, OWRITE)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if _, err := f.Write([]byte("hello")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	f.Close()
+	if got := names(t, clnt, "/"); got != "a,b" {
+		t.Errorf("entries of / after create = %q; want a,b", got)
+	}
+	d, err := clnt.FStat("/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Length != 5 {
+		t.Errorf("length of /b after write = %d; want 5", d.Length)
+	}
+	if err := clnt.FRemove("/a"); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	if got := names(t, clnt, "/"); got != "b" {
+		t.Errorf("entries of / after remove = %q; want b", got)
+	}
+}
+
+func TestUfsConcurrentReads(t *testing.T) {
+	root := t.TempDir()
+	for _, n := range []string{"a", "b", "c", "d"} {
+		if err := ioutil.WriteFile(filepath.Join(root, n), []byte(n), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	clnt := mountUfs(t, root)
+
+	f, err := clnt.FOpen("/", OREAD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// reads at offset 0 of the same fid reload its entries
+			if _, err := clnt.Read(f.Fid, 0, 8192); err != nil {
+				errs <- err
+			}
+			if got, err := readNames(clnt, "/"); err != nil || got != "a,b,c,d" {
+				errs <- &Error{"unexpected entries: " + got, EIO}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -7,7 +7,6 @@ package go9p
 import (
 	"fmt"
 	"os"
-	"path"
 	"strings"
 	"syscall"
 	"time"
@@ -135,6 +134,9 @@ func (dir *ufsDir) dotu(path string, d os.FileInfo, upool Users, sysMode *syscal
 
 func (u *Ufs) Wstat(req *SrvReq) {
 	fid := req.Fid.Aux.(*ufsFid)
+	fid.Lock()
+	defer fid.Unlock()
+	defer func() { fid.cache.invalidate(fid.path) }()
 	err := fid.stat()
 	if err != nil {
 		req.RespondError(err)
@@ -192,24 +194,16 @@ func (u *Ufs) Wstat(req *SrvReq) {
 	}
 
 	if dir.Name != "" {
-		fmt.Printf("Rename %s to %s\n", fid.path, dir.Name)
-		// if first char is / it is relative to root, else relative to
-		// cwd.
-		var destpath string
-		if dir.Name[0] == '/' {
-			destpath = path.Join(u.Root, dir.Name)
-			fmt.Printf("/ results in %s\n", destpath)
-		} else {
-			fiddir, _ := path.Split(fid.path)
-			destpath = path.Join(fiddir, dir.Name)
-			fmt.Printf("rel  results in %s\n", destpath)
-		}
-		err := os.Rename(fid.path, destpath)
-		fmt.Printf("rename %s to %s gets %v\n", fid.path, destpath, err)
+		destpath, err := u.destPath(fid, dir.Name)
 		if err != nil {
-			req.RespondError(toError(err))
+			req.RespondError(err)
 			return
 		}
+		if e := os.Rename(fid.path, destpath); e != nil {
+			req.RespondError(toError(e))
+			return
+		}
+		fid.cache.invalidate(fid.path, destpath)
 		fid.path = destpath
 	}
 
@@ -247,3 +241,9 @@ func (u *Ufs) Wstat(req *SrvReq) {
 
 	req.RespondRwstat()
 }
+
+// linuxErrno returns the Linux value of an error of the host
+func linuxErrno(e syscall.Errno) uint32 { return uint32(e) }
+
+// setXattr is not called, the server does not speak 9P2000.L on windows
+func setXattr(p string, x *ufsXattr) error { return syscall.EWINDOWS }
@@ -38,12 +38,14 @@ func Unpack(buf []byte, dotu bool) (fc *Fcall, err error, fcsz int) {
 	p = p[0 : fc.Size-7]
 	fc.Pkt = buf[0:fc.Size]
 	fcsz = int(fc.Size)
-	if fc.Type < Tversion || fc.Type >= Tlast {
-		return nil, &Error{"invalid id", EINVAL}, 0
-	}
-
 	var sz uint32
-	if dotu {
+	if fc.Type < Tversion || fc.Type >= Tlast {
+		var ok bool
+		if sz, ok = minFclsize[fc.Type]; !ok {
+			return nil, &Error{"invalid id", EINVAL}, 0
+		}
+		sz += 7 /* size[4] type[1] tag[2] */
+	} else if dotu {
 		sz = minFcsize[fc.Type-Tversion]
 	} else {
 		sz = minFcusize[fc.Type-Tversion]
@@ -212,6 +214,152 @@ func Unpack(buf []byte, dotu bool) (fc *Fcall, err error, fcsz int) {
 		p, _ = gstat(p, &fc.Dir, dotu)
 
 	case Rflush, Rclunk, Rremove, Rwstat:
+
+	/* 9P2000.L */
+	case Tstatfs, Treadlink:
+		fc.Fid, p = gint32(p)
+
+	case Tlopen:
+		fc.Fid, p = gint32(p)
+		fc.Flags, p = gint32(p)
+
+	case Tlcreate:
+		fc.Fid, p = gint32(p)
+		fc.Name, p = gstr(p)
+		if p == nil || len(p) < 12 {
+			goto szerror
+		}
+		fc.Flags, p = gint32(p)
+		fc.Perm, p = gint32(p)
+		fc.Gid, p = gint32(p)
+
+	case Tsymlink:
+		fc.Fid, p = gint32(p)
+		fc.Name, p = gstr(p)
+		if p == nil {
+			goto szerror
+		}
+		fc.Ext, p = gstr(p)
+		if p == nil || len(p) < 4 {
+			goto szerror
+		}
+		fc.Gid, p = gint32(p)
+
+	case Tmknod:
+		fc.Fid, p = gint32(p)
+		fc.Name, p = gstr(p)
+		if p == nil || len(p) < 16 {
+			goto szerror
+		}
+		fc.Perm, p = gint32(p)
+		fc.Major, p = gint32(p)
+		fc.Minor, p = gint32(p)
+		fc.Gid, p = gint32(p)
+
+	case Trename:
+		fc.Fid, p = gint32(p)
+		fc.Ofid, p = gint32(p)
+		fc.Name, p = gstr(p)
+		if p == nil {
+			goto szerror
+		}
+
+	case Tgetattr:
+		fc.Fid, p = gint32(p)
+		fc.Mask, p = gint64(p)
+
+	case Tsetattr:
+		fc.Fid, p = gint32(p)
+		var valid uint32
+		valid, p = gint32(p)
+		fc.Attr.Valid = uint64(valid)
+		fc.Attr.Mode, p = gint32(p)
+		fc.Attr.Uid, p = gint32(p)
+		fc.Attr.Gid, p = gint32(p)
+		fc.Attr.Size, p = gint64(p)
+		fc.Attr.AtimeSec, p = gint64(p)
+		fc.Attr.AtimeNsec, p = gint64(p)
+		fc.Attr.MtimeSec, p = gint64(p)
+		fc.Attr.MtimeNsec, p = gint64(p)
+
+	case Txattrwalk:
+		fc.Fid, p = gint32(p)
+		fc.Newfid, p = gint32(p)
+		fc.Name, p = gstr(p)
+		if p == nil {
+			goto szerror
+		}
+
+	case Txattrcreate:
+		fc.Fid, p = gint32(p)
+		fc.Name, p = gstr(p)
+		if p == nil || len(p) < 12 {
+			goto szerror
+		}
+		fc.Xattrsize, p = gint64(p)
+		fc.Flags, p = gint32(p)
+
+	case Treaddir:
+		fc.Fid, p = gint32(p)
+		fc.Offset, p = gint64(p)
+		fc.Count, p = gint32(p)
+
+	case Tfsync:
+		fc.Fid, p = gint32(p)
+		if len(p) >= 4 {
+			fc.Flags, p = gint32(p)
+		}
+
+	case Tlock, Tgetlock:
+		fc.Fid, p = gint32(p)
+		fc.Flock.Type, p = gint8(p)
+		if fc.Type == Tlock {
+			fc.Flock.Flags, p = gint32(p)
+		}
+		fc.Flock.Start, p = gint64(p)
+		fc.Flock.Length, p = gint64(p)
+		fc.Flock.ProcId, p = gint32(p)
+		fc.Flock.ClientId, p = gstr(p)
+		if p == nil {
+			goto szerror
+		}
+
+	case Tlink:
+		fc.Fid, p = gint32(p)
+		fc.Ofid, p = gint32(p)
+		fc.Name, p = gstr(p)
+		if p == nil {
+			goto szerror
+		}
+
+	case Tmkdir:
+		fc.Fid, p = gint32(p)
+		fc.Name, p = gstr(p)
+		if p == nil || len(p) < 8 {
+			goto szerror
+		}
+		fc.Perm, p = gint32(p)
+		fc.Gid, p = gint32(p)
+
+	case Trenameat:
+		fc.Fid, p = gint32(p)
+		fc.Name, p = gstr(p)
+		if p == nil || len(p) < 4 {
+			goto szerror
+		}
+		fc.Ofid, p = gint32(p)
+		fc.Newname, p = gstr(p)
+		if p == nil {
+			goto szerror
+		}
+
+	case Tunlinkat:
+		fc.Fid, p = gint32(p)
+		fc.Name, p = gstr(p)
+		if p == nil || len(p) < 4 {
+			goto szerror
+		}
+		fc.Flags, p = gint32(p)
 	}
 
 	if len(p) > 0 {